@@ -0,0 +1,43 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"reflect"
+	"unsafe"
+
+	"github.com/gomlx/gopjrt/dtypes"
+)
+
+// memoryAlignment in bytes of the flat data of the buffers allocated by the backend and of the GEMM packing
+// buffers: it is the size of a cache line, and a multiple of the size of the NEON, AVX2, AVX-512 and SME vectors,
+// so SIMD loads never cross cache lines.
+//
+// The GEMM packing layouts keep the alignment within the buffer: each row of a packed B sliver has gemmNR float32
+// values (64 bytes).
+const memoryAlignment = 64
+
+// alignedBytes allocates numBytes with its first byte aligned to memoryAlignment.
+func alignedBytes(numBytes int) unsafe.Pointer {
+	raw := make([]byte, numBytes+memoryAlignment)
+	offset := -uintptr(unsafe.Pointer(unsafe.SliceData(raw))) & (memoryAlignment - 1)
+	return unsafe.Pointer(&raw[offset])
+}
+
+// makeAligned is like make([]T, length, capacity), but with the first element aligned to memoryAlignment.
+//
+// T must not contain pointers, since the memory is allocated as bytes.
+func makeAligned[T SupportedTypesConstraints](length, capacity int) []T {
+	var zero T
+	ptr := alignedBytes(capacity * int(unsafe.Sizeof(zero)))
+	return unsafe.Slice((*T)(ptr), capacity)[:length]
+}
+
+// makeAlignedFlat is like makeAligned, but for the Go type of dtype, given at runtime.
+func makeAlignedFlat(dtype dtypes.DType, length, capacity int) reflect.Value {
+	goType := dtype.GoType()
+	ptr := alignedBytes(capacity * int(goType.Size()))
+	return reflect.SliceAt(goType, ptr, capacity).Slice(0, length)
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"github.com/gomlx/gopjrt/dtypes"
+)
+
+// argMinMaxSIMDMinSize is the minimum dimension of the reduced axis for which the SIMD kernels are used by ArgMinMax.
+// Below it the scalar loop is as fast.
+const argMinMaxSIMDMinSize = 32
+
+func init() {
+	argMinMaxDTypeMap.Register(dtypes.Float32, execArgMinMaxFloat32)
+}
+
+// execArgMinMaxFloat32 implements ArgMinMax for float32. If reducing over the last axis (suffixSize == 1), as in
+// greedy decoding over the logits of a large vocabulary, it uses the SIMD kernels (AVX2 or NEON) if available.
+// Otherwise, it falls back to execArgMinMaxGeneric.
+func execArgMinMaxFloat32(
+	backend *Backend, operand *Buffer, copyIntsFn func(flatIdx int, values []int32), prefixSize, reduceSize, suffixSize int, isMin bool) {
+	if suffixSize != 1 || reduceSize < argMinMaxSIMDMinSize || !(hasAVX2 || hasNEON) {
+		execArgMinMaxGeneric[float32](backend, operand, copyIntsFn, prefixSize, reduceSize, suffixSize, isMin)
+		return
+	}
+	operandFlat := operand.flat.([]float32)
+	argBestBuffer := backend.getBuffer(dtypes.Int32, prefixSize)
+	argBest := argBestBuffer.flat.([]int32)
+	for prefixIdx := range prefixSize {
+		row := operandFlat[prefixIdx*reduceSize : (prefixIdx+1)*reduceSize]
+		argBest[prefixIdx] = int32(argMinMaxFloat32SIMD(row, isMin))
+	}
+	copyIntsFn(0, argBest)
+	backend.putBuffer(argBestBuffer)
+}
+
+// argMinMaxFloat32SIMD returns the index of the max (or min if isMin) value of x, with the same semantics as
+// execArgMinMaxGeneric: the first index in case of ties, and the last NaN if there are any.
+//
+// It requires len(x) >= 8, and either hasAVX2 or hasNEON.
+func argMinMaxFloat32SIMD(x []float32, isMin bool) int {
+	var best float32
+	var hasNaN bool
+	var n int
+	if hasAVX2 {
+		best, hasNaN, n = minMaxFloat32AVX2(x, isMin)
+	} else {
+		best, hasNaN, n = minMaxFloat32NEON(x, isMin)
+	}
+	if hasNaN {
+		return argMinMaxFloat32WithNaN(x)
+	}
+	for _, value := range x[n:] {
+		if value != value {
+			return argMinMaxFloat32WithNaN(x)
+		}
+		if (isMin && value < best) || (!isMin && value > best) {
+			best = value
+		}
+	}
+
+	// Find the first occurrence of best: the SIMD kernels return the start of the group of values that contains it.
+	var start int
+	if hasAVX2 {
+		start = findFloat32AVX2(x, best)
+	} else {
+		start = findFloat32NEON(x, best)
+	}
+	for idx := start; idx < len(x); idx++ {
+		if x[idx] == best {
+			return idx
+		}
+	}
+	// Not reachable: best is one of the values of x.
+	return 0
+}
+
+// argMinMaxFloat32WithNaN returns the index of the last NaN in x, which is the result of ArgMinMax if there are any.
+func argMinMaxFloat32WithNaN(x []float32) int {
+	for idx := len(x) - 1; idx > 0; idx-- {
+		if x[idx] != x[idx] {
+			return idx
+		}
+	}
+	return 0
+}
@@ -0,0 +1,55 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !noasm && amd64
+
+package simplego
+
+import (
+	"runtime"
+	"unsafe"
+)
+
+// maxFloat32_avx2_asm and minFloat32_avx2_asm are implemented in argminmax_avx_amd64.s.
+// They return the max (or min) of n values (a multiple of 8, at least 8), and whether any of them is NaN -- in which
+// case the value returned is meaningless.
+//
+//go:noescape
+func maxFloat32_avx2_asm(x unsafe.Pointer, n int64) (value float32, hasNaN bool)
+
+//go:noescape
+func minFloat32_avx2_asm(x unsafe.Pointer, n int64) (value float32, hasNaN bool)
+
+// findFloat32_avx2_asm is implemented in argminmax_avx_amd64.s.
+// It returns the start of the first group of 8 values (n must be a multiple of 8) that contains value, or n if none
+// does.
+//
+//go:noescape
+func findFloat32_avx2_asm(x unsafe.Pointer, n int64, value float32) int64
+
+// minMaxFloat32AVX2 returns the max (or min if isMin) of the first n values of x, where n is the largest multiple of 8
+// (it must be at least 8), and whether any of them is NaN.
+func minMaxFloat32AVX2(x []float32, isMin bool) (value float32, hasNaN bool, n int) {
+	n = len(x) &^ 7
+	_ = x[n-1]
+	if isMin {
+		value, hasNaN = minFloat32_avx2_asm(unsafe.Pointer(&x[0]), int64(n))
+	} else {
+		value, hasNaN = maxFloat32_avx2_asm(unsafe.Pointer(&x[0]), int64(n))
+	}
+	runtime.KeepAlive(x)
+	return
+}
+
+// findFloat32AVX2 returns the start of the first group of 8 values of x that contains value. The search stops at the
+// largest multiple of 8 of len(x), which is returned if value is not found.
+func findFloat32AVX2(x []float32, value float32) int {
+	n := len(x) &^ 7
+	if n == 0 {
+		return 0
+	}
+	idx := findFloat32_avx2_asm(unsafe.Pointer(&x[0]), int64(n), value)
+	runtime.KeepAlive(x)
+	return int(idx)
+}
@@ -0,0 +1,147 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !noasm && amd64
+
+#include "textflag.h"
+
+// AVX2 float32 kernels used by ArgMinMax over the last axis: see argMinMaxFloat32SIMD.
+//
+// The arg-max (or arg-min) is found in 2 passes: the first finds the maximum (or minimum) value, with 4 independent
+// accumulators, and the second finds its first occurrence. Both are much faster than a scalar compare-and-branch scan.
+
+// The max (or min) reductions below process n values (a multiple of 8, at least 8) from SI, with 4 accumulators
+// (Y0 to Y3) initialized with the first 8 values. VMAXPS and VMINPS don't propagate NaNs, so Y8 accumulates a mask
+// of the unordered (NaN) values.
+
+#define MINMAX_INIT      \
+	VMOVUPS (SI), Y0       \
+	VMOVAPS Y0, Y1         \
+	VMOVAPS Y0, Y2         \
+	VMOVAPS Y0, Y3         \
+	VCMPPS  $3, Y0, Y0, Y8
+
+// MINMAX_32 reduces 32 values with OP, which is VMAXPS or VMINPS.
+#define MINMAX_32(OP)          \
+	VMOVUPS (SI), Y4           \
+	VMOVUPS 32(SI), Y5         \
+	VMOVUPS 64(SI), Y6         \
+	VMOVUPS 96(SI), Y7         \
+	VCMPPS  $3, Y4, Y5, Y9     \
+	VCMPPS  $3, Y6, Y7, Y10    \
+	VORPS   Y9, Y8, Y8         \
+	VORPS   Y10, Y8, Y8        \
+	OP      Y4, Y0, Y0         \
+	OP      Y5, Y1, Y1         \
+	OP      Y6, Y2, Y2         \
+	OP      Y7, Y3, Y3
+
+// MINMAX_8 reduces 8 values with OP.
+#define MINMAX_8(OP)       \
+	VMOVUPS (SI), Y4       \
+	VCMPPS  $3, Y4, Y4, Y9 \
+	VORPS   Y9, Y8, Y8     \
+	OP      Y4, Y0, Y0
+
+// MINMAX_REDUCE reduces the 4 accumulators with OP into the first lane of X0.
+#define MINMAX_REDUCE(OP)         \
+	OP           Y1, Y0, Y0       \
+	OP           Y3, Y2, Y2       \
+	OP           Y2, Y0, Y0       \
+	VEXTRACTF128 $1, Y0, X1       \
+	OP           X1, X0, X0       \
+	VPERMILPS    $0x4e, X0, X1    \
+	OP           X1, X0, X0       \
+	VPERMILPS    $0xb1, X0, X1    \
+	OP           X1, X0, X0
+
+// func maxFloat32_avx2_asm(x unsafe.Pointer, n int64) (value float32, hasNaN bool)
+TEXT ·maxFloat32_avx2_asm(SB), NOSPLIT, $0-21
+	MOVQ x+0(FP), SI
+	MOVQ n+8(FP), CX
+	MINMAX_INIT
+	ADDQ $32, SI
+	SUBQ $8, CX
+
+max_loop32:
+	CMPQ CX, $32
+	JL   max_loop8
+	MINMAX_32(VMAXPS)
+	ADDQ $128, SI
+	SUBQ $32, CX
+	JMP  max_loop32
+
+max_loop8:
+	TESTQ CX, CX
+	JZ    max_reduce
+	MINMAX_8(VMAXPS)
+	ADDQ  $32, SI
+	SUBQ  $8, CX
+	JMP   max_loop8
+
+max_reduce:
+	MINMAX_REDUCE(VMAXPS)
+	MOVSS     X0, value+16(FP)
+	VMOVMSKPS Y8, AX
+	TESTL     AX, AX
+	SETNE     hasNaN+20(FP)
+	VZEROUPPER
+	RET
+
+// func minFloat32_avx2_asm(x unsafe.Pointer, n int64) (value float32, hasNaN bool)
+TEXT ·minFloat32_avx2_asm(SB), NOSPLIT, $0-21
+	MOVQ x+0(FP), SI
+	MOVQ n+8(FP), CX
+	MINMAX_INIT
+	ADDQ $32, SI
+	SUBQ $8, CX
+
+min_loop32:
+	CMPQ CX, $32
+	JL   min_loop8
+	MINMAX_32(VMINPS)
+	ADDQ $128, SI
+	SUBQ $32, CX
+	JMP  min_loop32
+
+min_loop8:
+	TESTQ CX, CX
+	JZ    min_reduce
+	MINMAX_8(VMINPS)
+	ADDQ  $32, SI
+	SUBQ  $8, CX
+	JMP   min_loop8
+
+min_reduce:
+	MINMAX_REDUCE(VMINPS)
+	MOVSS     X0, value+16(FP)
+	VMOVMSKPS Y8, AX
+	TESTL     AX, AX
+	SETNE     hasNaN+20(FP)
+	VZEROUPPER
+	RET
+
+// func findFloat32_avx2_asm(x unsafe.Pointer, n int64, value float32) int64
+//
+// Returns the start of the first group of 8 values (n must be a multiple of 8) that contains value, or n if none does.
+TEXT ·findFloat32_avx2_asm(SB), NOSPLIT, $0-32
+	MOVQ         x+0(FP), SI
+	MOVQ         n+8(FP), CX
+	VBROADCASTSS value+16(FP), Y1
+	XORQ         AX, AX
+
+find_loop:
+	CMPQ      AX, CX
+	JGE       find_done
+	VCMPPS    $0, (SI)(AX*4), Y1, Y2 // Y2: x == value, for 8 values.
+	VMOVMSKPS Y2, DX
+	TESTL     DX, DX
+	JNZ       find_done
+	ADDQ      $8, AX
+	JMP       find_loop
+
+find_done:
+	MOVQ AX, ret+24(FP)
+	VZEROUPPER
+	RET
@@ -0,0 +1,17 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build noasm || !amd64
+
+package simplego
+
+// minMaxFloat32AVX2 stub for non-AMD64 platforms.
+func minMaxFloat32AVX2(x []float32, isMin bool) (value float32, hasNaN bool, n int) {
+	panic("AVX2 not available")
+}
+
+// findFloat32AVX2 stub for non-AMD64 platforms.
+func findFloat32AVX2(x []float32, value float32) int {
+	panic("AVX2 not available")
+}
@@ -0,0 +1,54 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !noasm && arm64
+
+package simplego
+
+import (
+	"runtime"
+	"unsafe"
+)
+
+// maxFloat32_neon_asm and minFloat32_neon_asm are implemented in argminmax_neon_arm64.s.
+// They return the max (or min) of n values (a multiple of 4, at least 4), or NaN if any of them is NaN.
+//
+//go:noescape
+func maxFloat32_neon_asm(x unsafe.Pointer, n int64) float32
+
+//go:noescape
+func minFloat32_neon_asm(x unsafe.Pointer, n int64) float32
+
+// findFloat32_neon_asm is implemented in argminmax_neon_arm64.s.
+// It returns the start of the first group of 4 values (n must be a multiple of 4) that contains value, or n if none
+// does.
+//
+//go:noescape
+func findFloat32_neon_asm(x unsafe.Pointer, n int64, value float32) int64
+
+// minMaxFloat32NEON returns the max (or min if isMin) of the first n values of x, where n is the largest multiple of 4
+// (it must be at least 4), and whether any of them is NaN.
+func minMaxFloat32NEON(x []float32, isMin bool) (value float32, hasNaN bool, n int) {
+	n = len(x) &^ 3
+	_ = x[n-1]
+	if isMin {
+		value = minFloat32_neon_asm(unsafe.Pointer(&x[0]), int64(n))
+	} else {
+		value = maxFloat32_neon_asm(unsafe.Pointer(&x[0]), int64(n))
+	}
+	runtime.KeepAlive(x)
+	return value, value != value, n
+}
+
+// findFloat32NEON returns the start of the first group of 4 values of x that contains value. The search stops at the
+// largest multiple of 4 of len(x), which is returned if value is not found.
+func findFloat32NEON(x []float32, value float32) int {
+	n := len(x) &^ 3
+	if n == 0 {
+		return 0
+	}
+	idx := findFloat32_neon_asm(unsafe.Pointer(&x[0]), int64(n), value)
+	runtime.KeepAlive(x)
+	return int(idx)
+}
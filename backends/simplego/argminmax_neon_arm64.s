@@ -0,0 +1,114 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !noasm && arm64
+
+#include "textflag.h"
+
+// NEON float32 kernels used by ArgMinMax over the last axis: see argMinMaxFloat32SIMD.
+//
+// The arg-max (or arg-min) is found in 2 passes: the first finds the maximum (or minimum) value, with 4 independent
+// accumulators, and the second finds its first occurrence. FMAX and FMIN propagate NaNs, so the first pass returns
+// NaN if any of the values is NaN.
+
+// func maxFloat32_neon_asm(x unsafe.Pointer, n int64) float32
+//
+// Returns the max of n values, where n is a multiple of 4 (at least 4).
+TEXT ·maxFloat32_neon_asm(SB), NOSPLIT, $0-20
+	MOVD x+0(FP), R0
+	MOVD n+8(FP), R1
+	WORD $0x4cdf7800 // ld1 {v0.4s}, [x0], #16
+	WORD $0x4ea01c01 // mov v1.16b, v0.16b
+	WORD $0x4ea01c02 // mov v2.16b, v0.16b
+	WORD $0x4ea01c03 // mov v3.16b, v0.16b
+	SUB  $4, R1
+
+max_loop16:
+	CMP  $16, R1
+	BLT  max_loop4
+	WORD $0x4cdf2804 // ld1 {v4.4s, v5.4s, v6.4s, v7.4s}, [x0], #64
+	WORD $0x4e24f400 // fmax v0.4s, v0.4s, v4.4s
+	WORD $0x4e25f421 // fmax v1.4s, v1.4s, v5.4s
+	WORD $0x4e26f442 // fmax v2.4s, v2.4s, v6.4s
+	WORD $0x4e27f463 // fmax v3.4s, v3.4s, v7.4s
+	SUB  $16, R1
+	B    max_loop16
+
+max_loop4:
+	CBZ  R1, max_reduce
+	WORD $0x4cdf7804 // ld1 {v4.4s}, [x0], #16
+	WORD $0x4e24f400 // fmax v0.4s, v0.4s, v4.4s
+	SUB  $4, R1
+	B    max_loop4
+
+max_reduce:
+	WORD  $0x4e21f400 // fmax v0.4s, v0.4s, v1.4s
+	WORD  $0x4e23f442 // fmax v2.4s, v2.4s, v3.4s
+	WORD  $0x4e22f400 // fmax v0.4s, v0.4s, v2.4s
+	WORD  $0x6e30f800 // fmaxv s0, v0.4s
+	FMOVS F0, ret+16(FP)
+	RET
+
+// func minFloat32_neon_asm(x unsafe.Pointer, n int64) float32
+//
+// Returns the min of n values, where n is a multiple of 4 (at least 4).
+TEXT ·minFloat32_neon_asm(SB), NOSPLIT, $0-20
+	MOVD x+0(FP), R0
+	MOVD n+8(FP), R1
+	WORD $0x4cdf7800 // ld1 {v0.4s}, [x0], #16
+	WORD $0x4ea01c01 // mov v1.16b, v0.16b
+	WORD $0x4ea01c02 // mov v2.16b, v0.16b
+	WORD $0x4ea01c03 // mov v3.16b, v0.16b
+	SUB  $4, R1
+
+min_loop16:
+	CMP  $16, R1
+	BLT  min_loop4
+	WORD $0x4cdf2804 // ld1 {v4.4s, v5.4s, v6.4s, v7.4s}, [x0], #64
+	WORD $0x4ea4f400 // fmin v0.4s, v0.4s, v4.4s
+	WORD $0x4ea5f421 // fmin v1.4s, v1.4s, v5.4s
+	WORD $0x4ea6f442 // fmin v2.4s, v2.4s, v6.4s
+	WORD $0x4ea7f463 // fmin v3.4s, v3.4s, v7.4s
+	SUB  $16, R1
+	B    min_loop16
+
+min_loop4:
+	CBZ  R1, min_reduce
+	WORD $0x4cdf7804 // ld1 {v4.4s}, [x0], #16
+	WORD $0x4ea4f400 // fmin v0.4s, v0.4s, v4.4s
+	SUB  $4, R1
+	B    min_loop4
+
+min_reduce:
+	WORD  $0x4ea1f400 // fmin v0.4s, v0.4s, v1.4s
+	WORD  $0x4ea3f442 // fmin v2.4s, v2.4s, v3.4s
+	WORD  $0x4ea2f400 // fmin v0.4s, v0.4s, v2.4s
+	WORD  $0x6eb0f800 // fminv s0, v0.4s
+	FMOVS F0, ret+16(FP)
+	RET
+
+// func findFloat32_neon_asm(x unsafe.Pointer, n int64, value float32) int64
+//
+// Returns the start of the first group of 4 values (n must be a multiple of 4) that contains value, or n if none does.
+TEXT ·findFloat32_neon_asm(SB), NOSPLIT, $0-32
+	MOVD  x+0(FP), R0
+	MOVD  n+8(FP), R1
+	FMOVS value+16(FP), F1
+	WORD  $0x4e040421 // dup v1.4s, v1.s[0]
+	MOVD  $0, R2
+
+find_loop:
+	CMP   R1, R2
+	BGE   find_done
+	WORD  $0x4cdf7802 // ld1 {v2.4s}, [x0], #16
+	WORD  $0x4e21e443 // fcmeq v3.4s, v2.4s, v1.4s
+	WORD  $0x6eb0a863 // umaxv s3, v3.4s
+	WORD  $0x1e260064 // fmov w4, s3
+	CBNZW R4, find_done
+	ADD   $4, R2
+	B     find_loop
+
+find_done:
+	MOVD R2, ret+24(FP)
+	RET
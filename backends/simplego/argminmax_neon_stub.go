@@ -0,0 +1,17 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build noasm || !arm64
+
+package simplego
+
+// minMaxFloat32NEON stub for non-ARM64 platforms.
+func minMaxFloat32NEON(x []float32, isMin bool) (value float32, hasNaN bool, n int) {
+	panic("NEON not available")
+}
+
+// findFloat32NEON stub for non-ARM64 platforms.
+func findFloat32NEON(x []float32, value float32) int {
+	panic("NEON not available")
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/gomlx/gomlx/pkg/core/graph"
+	"github.com/gomlx/gomlx/pkg/core/shapes"
+	"github.com/gomlx/gopjrt/dtypes"
+	"github.com/stretchr/testify/require"
+)
+
+// argMinMaxFloat32Reference is the scalar ArgMinMax over a row, with the semantics of execArgMinMaxGeneric.
+func argMinMaxFloat32Reference(x []float32, isMin bool) int32 {
+	best, argBest := x[0], 0
+	for idx, value := range x[1:] {
+		if (isMin && value < best) || (!isMin && value > best) || value != value {
+			best, argBest = value, idx+1
+		}
+	}
+	return int32(argBest)
+}
+
+func TestArgMinMaxFloat32SIMD(t *testing.T) {
+	nan := float32(math.NaN())
+	rng := rand.New(rand.NewSource(42))
+	for _, size := range []int{32, 37, 64, 100, 1031} {
+		for _, simd := range []string{SIMDAuto, SIMDOff} {
+			t.Run(fmt.Sprintf("%s/size=%d", simd, size), func(t *testing.T) {
+				require.NoError(t, SetSIMD(simd))
+				defer func() { require.NoError(t, SetSIMD(SIMDAuto)) }()
+
+				// Each row exercises a different case.
+				const numRows = 6
+				rows := make([][]float32, numRows)
+				for ii := range rows {
+					rows[ii] = make([]float32, size)
+					for jj := range rows[ii] {
+						rows[ii][jj] = float32(rng.NormFloat64())
+					}
+				}
+				rows[1][size-1] = 100 // Max in the tail.
+				rows[1][0] = -100     // Min at the start.
+				for _, idx := range []int{size / 3, size / 2, 2} {
+					rows[2][idx] = 50 // Ties: the first must be selected.
+					rows[2][idx+1] = -50
+				}
+				rows[3][size/2], rows[3][size/3] = nan, nan // NaNs: the last must be selected.
+				rows[4][size-2] = nan
+				clear(rows[5]) // All equal.
+
+				for _, isMin := range []bool{false, true} {
+					got := graph.MustExecOnce(backend, func(x *graph.Node) *graph.Node {
+						if isMin {
+							return graph.ArgMin(x, -1)
+						}
+						return graph.ArgMax(x, -1)
+					}, rows).Value().([]int32)
+					for ii, row := range rows {
+						require.Equalf(t, argMinMaxFloat32Reference(row, isMin), got[ii], "isMin=%v, row %d", isMin, ii)
+					}
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkArgMaxFloat32(b *testing.B) {
+	be, ok := backend.(*Backend)
+	if !ok {
+		b.Skip("Skipping benchmark because backend is not a SimpleGo Backend")
+	}
+	const batchSize, vocabSize = 8, 128 * 1024
+	operand := be.NewBuffer(shapes.Make(dtypes.Float32, batchSize, vocabSize))
+	operandFlat := operand.flat.([]float32)
+	for ii := range operandFlat {
+		operandFlat[ii] = float32((ii*7919)%vocabSize) / vocabSize
+	}
+	output := make([]int32, batchSize)
+	copyIntsFn := func(flatIdx int, values []int32) { copy(output[flatIdx:], values) }
+	for _, simd := range []string{SIMDAuto, SIMDOff} {
+		b.Run(simd, func(b *testing.B) {
+			require.NoError(b, SetSIMD(simd))
+			defer func() { require.NoError(b, SetSIMD(SIMDAuto)) }()
+			for range b.N {
+				execArgMinMaxFloat32(be, operand, copyIntsFn, batchSize, vocabSize, 1, false)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"math/bits"
+	"reflect"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gomlx/gomlx/pkg/core/shapes"
+	"github.com/gomlx/gopjrt/dtypes"
+)
+
+// DefaultBufferPoolMaxBytes is the default limit of memory held by the pool of free buffers of a Backend.
+// It can be changed with the "buffer_pool_max_bytes=#bytes" configuration, see New.
+const DefaultBufferPoolMaxBytes = 1 << 30
+
+// bufferMinClassLength is the capacity of the smallest size class of the buffer pool.
+const bufferMinClassLength = 64
+
+// bufferPool holds the buffers freed by the executions (intermediary values, outputs finalized by the user), to
+// be reused by the next ones: e.g. the steps of a decoder, that execute the same computation over and over.
+//
+// The buffers are grouped by dtype and size class: the size classes are 4 per power of 2 (64, 80, 96, 112, 128,
+// 160, ...), so a buffer can be reused by any request up to 25% smaller. Differently from a sync.Pool, the free
+// buffers are not dropped on garbage collection, instead their total is limited to maxBytes.
+type bufferPool struct {
+	// freeLists maps bufferPoolKey (with length set to the class capacity) to *bufferFreeList.
+	freeLists sync.Map
+
+	maxBytes, pooledBytes atomic.Int64
+
+	// numaNodes to spread the memory of new large buffers over, if > 1, see NUMALocal.
+	numaNodes int
+
+	// hugePages defines whether new large buffers are backed by huge pages, see HugePagesPolicy.
+	hugePages HugePagesPolicy
+
+	// Statistics, see Backend.BufferPoolStats.
+	gets, hits, puts, drops, hugePagesAllocs atomic.Int64
+}
+
+type bufferFreeList struct {
+	mu      sync.Mutex
+	buffers []*Buffer
+}
+
+// BufferPoolStats are the statistics of the pool of buffers of a Backend, see Backend.BufferPoolStats.
+type BufferPoolStats struct {
+	// Gets is the number of buffers requested, and Hits is how many of those were reused from the pool (the
+	// others were allocated).
+	Gets, Hits int64
+
+	// Puts is the number of buffers returned to the pool, and Drops is how many of those were left to the
+	// garbage collector instead, because the pool was full (see DefaultBufferPoolMaxBytes) or they were too small.
+	Puts, Drops int64
+
+	// PooledBytes is the memory currently held by the free buffers in the pool.
+	PooledBytes int64
+
+	// HugePagesAllocs is the number of buffers allocated backed by huge pages, see HugePagesPolicy.
+	HugePagesAllocs int64
+}
+
+// HitRate returns the fraction of the buffer requests served from the pool.
+func (s BufferPoolStats) HitRate() float64 {
+	if s.Gets == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Gets)
+}
+
+// BufferPoolStats returns the statistics of the pool of buffers of the backend, used to reuse the memory of the
+// buffers across executions.
+func (b *Backend) BufferPoolStats() BufferPoolStats {
+	return BufferPoolStats{
+		Gets:            b.bufferPool.gets.Load(),
+		Hits:            b.bufferPool.hits.Load(),
+		Puts:            b.bufferPool.puts.Load(),
+		Drops:           b.bufferPool.drops.Load(),
+		PooledBytes:     b.bufferPool.pooledBytes.Load(),
+		HugePagesAllocs: b.bufferPool.hugePagesAllocs.Load(),
+	}
+}
+
+// bufferClassIndex returns the index of the smallest size class that fits length.
+func bufferClassIndex(length int) int {
+	if length <= bufferMinClassLength {
+		return 0
+	}
+	// length-1 = mantissa << exp, with mantissa in [4, 7].
+	exp := bits.Len(uint(length-1)) - 3
+	mantissa := (length - 1) >> exp
+	return (exp-4)*4 + mantissa + 1 - 4
+}
+
+// bufferFloorClassIndex returns the index of the largest size class that fits in capacity, or -1 if capacity is
+// smaller than all classes.
+func bufferFloorClassIndex(capacity int) int {
+	if capacity < bufferMinClassLength {
+		return -1
+	}
+	exp := bits.Len(uint(capacity)) - 3
+	mantissa := capacity >> exp
+	return (exp-4)*4 + mantissa - 4
+}
+
+// bufferClassLength returns the capacity of the buffers of the size class.
+func bufferClassLength(classIdx int) int {
+	return (4 + classIdx%4) << (classIdx/4 + 4)
+}
+
+func (p *bufferPool) freeList(dtype dtypes.DType, classIdx int) *bufferFreeList {
+	key := bufferPoolKey{dtype: dtype, length: bufferClassLength(classIdx)}
+	list, ok := p.freeLists.Load(key)
+	if !ok {
+		list, _ = p.freeLists.LoadOrStore(key, &bufferFreeList{})
+	}
+	return list.(*bufferFreeList)
+}
+
+// get a buffer with flat of the given length, reused from the pool if possible.
+// Its shape is only set if it doesn't match the dtype and length.
+func (p *bufferPool) get(dtype dtypes.DType, length int) *Buffer {
+	p.gets.Add(1)
+	classIdx := bufferClassIndex(length)
+	list := p.freeList(dtype, classIdx)
+	var buf *Buffer
+	list.mu.Lock()
+	if n := len(list.buffers); n > 0 {
+		buf = list.buffers[n-1]
+		list.buffers[n-1] = nil
+		list.buffers = list.buffers[:n-1]
+	}
+	list.mu.Unlock()
+
+	var flat reflect.Value
+	if buf != nil {
+		p.hits.Add(1)
+		flat = reflect.ValueOf(buf.flat)
+		p.pooledBytes.Add(-int64(flat.Cap() * dtype.Size()))
+		flat = flat.Slice(0, length)
+	} else {
+		buf = &Buffer{}
+		capacity := bufferClassLength(classIdx)
+		if p.hugePages != HugePagesOff && capacity*dtype.Size() >= hugePagesMinBufferBytes {
+			var release func()
+			var ok bool
+			flat, release, ok = makeHugePagesFlat(p.hugePages, dtype, length, capacity)
+			if ok {
+				p.hugePagesAllocs.Add(1)
+				if release != nil {
+					// The memory is outside the Go heap: it is released when the buffer is garbage collected.
+					runtime.AddCleanup(buf, func(release func()) { release() }, release)
+				}
+			}
+		}
+		if !flat.IsValid() {
+			flat = makeAlignedFlat(dtype, length, capacity)
+		}
+		if numBytes := flat.Cap() * dtype.Size(); p.numaNodes > 1 && numBytes >= numaMinBufferBytes {
+			distributeOverNUMANodes(flat.UnsafePointer(), numBytes, p.numaNodes)
+		}
+	}
+	buf.flat = flat.Interface()
+	if buf.shape.DType != dtype || buf.shape.Size() != length {
+		buf.shape = shapes.Make(dtype, length)
+	}
+	return buf
+}
+
+// put the buffer in the pool, or leave it to the garbage collector if the pool is full.
+func (p *bufferPool) put(buffer *Buffer) {
+	p.puts.Add(1)
+	dtype := buffer.shape.DType
+	capacity := reflect.ValueOf(buffer.flat).Cap()
+	classIdx := bufferFloorClassIndex(capacity)
+	if classIdx < 0 {
+		p.drops.Add(1)
+		return
+	}
+	numBytes := int64(capacity * dtype.Size())
+	if p.pooledBytes.Add(numBytes) > p.maxBytes.Load() {
+		p.pooledBytes.Add(-numBytes)
+		p.drops.Add(1)
+		return
+	}
+	list := p.freeList(dtype, classIdx)
+	list.mu.Lock()
+	list.buffers = append(list.buffers, buffer)
+	list.mu.Unlock()
+}
+
+// clear drops all the free buffers.
+func (p *bufferPool) clear() {
+	p.freeLists.Range(func(_, listAny any) bool {
+		list := listAny.(*bufferFreeList)
+		list.mu.Lock()
+		for _, buffer := range list.buffers {
+			p.pooledBytes.Add(-int64(reflect.ValueOf(buffer.flat).Cap() * buffer.shape.DType.Size()))
+		}
+		list.buffers = nil
+		list.mu.Unlock()
+		return true
+	})
+}
@@ -3,7 +3,6 @@ package simplego
 import (
 	"reflect"
 	"strings"
-	"sync"
 	"unsafe"
 
 	"github.com/gomlx/gopjrt/dtypes"
@@ -34,23 +33,6 @@ type bufferPoolKey struct {
 	length int
 }
 
-// getBufferPool for given dtype/length.
-func (b *Backend) getBufferPool(dtype dtypes.DType, length int) *sync.Pool {
-	key := bufferPoolKey{dtype: dtype, length: length}
-	poolInterface, ok := b.bufferPools.Load(key)
-	if !ok {
-		poolInterface, _ = b.bufferPools.LoadOrStore(key, &sync.Pool{
-			New: func() interface{} {
-				return &Buffer{
-					flat:  reflect.MakeSlice(reflect.SliceOf(dtype.GoType()), length, length).Interface(),
-					shape: shapes.Make(dtype, length),
-				}
-			},
-		})
-	}
-	return poolInterface.(*sync.Pool)
-}
-
 // getBuffer from the backend pool of buffers.
 // Important: it's not necessarily initialized with zero, since it can reuse old buffers.
 //
@@ -59,8 +41,7 @@ func (b *Backend) getBuffer(dtype dtypes.DType, length int) *Buffer {
 	if b.isFinalized {
 		return nil
 	}
-	pool := b.getBufferPool(dtype, length)
-	buf := pool.Get().(*Buffer)
+	buf := b.bufferPool.get(dtype, length)
 	buf.valid = true
 	// buf.randomize() // Useful to find where zero-initialized is needed but missing.
 	return buf
@@ -95,8 +76,7 @@ func (b *Backend) putBuffer(buffer *Buffer) {
 		return
 	}
 	buffer.valid = false
-	pool := b.getBufferPool(buffer.shape.DType, buffer.shape.Size())
-	pool.Put(buffer)
+	b.bufferPool.put(buffer)
 }
 
 // copyFlat assumes both flat slices are of the same underlying type.
@@ -104,6 +84,13 @@ func copyFlat(flatDst, flatSrc any) {
 	reflect.Copy(reflect.ValueOf(flatDst), reflect.ValueOf(flatSrc))
 }
 
+// reinterpretFlat returns a flat slice of the Go type of dtype over the same memory as the given flat, whose
+// elements must be of the same size.
+func reinterpretFlat(flat any, dtype dtypes.DType) any {
+	flatV := reflect.ValueOf(flat)
+	return reflect.SliceAt(dtype.GoType(), flatV.UnsafePointer(), flatV.Cap()).Slice(0, flatV.Len()).Interface()
+}
+
 // mutableBytes returns the slice of the bytes used by the flat given -- it works with any of the supported data types for buffers.
 func (b *Buffer) mutableBytes() []byte {
 	fn := mutableBytesDTypeMap.Get(b.shape.DType).(func(b *Buffer) []byte)
@@ -238,6 +225,9 @@ func (b *Backend) BufferFinalize(backendBuffer backends.Buffer) error {
 	if b.preBlockedWeightCache != nil {
 		b.preBlockedWeightCache.Invalidate(buffer)
 	}
+	if b.packedWeightCache != nil {
+		b.packedWeightCache.Invalidate(buffer)
+	}
 
 	b.putBuffer(buffer)
 	return nil
@@ -1,6 +1,7 @@
 package simplego
 
 import (
+	"reflect"
 	"runtime"
 	"testing"
 
@@ -35,3 +36,73 @@ func TestBuffers_Fill(t *testing.T) {
 	buf.Zeros()
 	require.Equal(t, []int32{0, 0, 0}, buf.flat.([]int32))
 }
+
+func TestBufferPool(t *testing.T) {
+	// Size classes.
+	for length := 1; length < 100_000; length++ {
+		classIdx := bufferClassIndex(length)
+		require.GreaterOrEqual(t, bufferClassLength(classIdx), length)
+		require.True(t, classIdx == 0 || bufferClassLength(classIdx-1) < length, "length=%d", length)
+		require.LessOrEqual(t, bufferClassLength(classIdx), max(bufferMinClassLength, length*5/4+1))
+		floorIdx := bufferFloorClassIndex(length)
+		if length < bufferMinClassLength {
+			require.Equal(t, -1, floorIdx)
+			continue
+		}
+		require.LessOrEqual(t, bufferClassLength(floorIdx), length)
+		require.Greater(t, bufferClassLength(floorIdx+1), length)
+	}
+
+	backendIface, err := New("buffer_pool_max_bytes=4096")
+	require.NoError(t, err)
+	be := backendIface.(*Backend)
+	defer be.Finalize()
+
+	// Buffers are reused by requests of any length in the same size class.
+	buf := be.getBuffer(dtypes.Float32, 1000)
+	require.Len(t, buf.flat.([]float32), 1000)
+	require.Equal(t, 1000, buf.shape.Size())
+	be.putBuffer(buf)
+	reused := be.getBuffer(dtypes.Float32, 900)
+	require.Len(t, reused.flat.([]float32), 900)
+	require.Equal(t, 900, reused.shape.Size())
+	require.Same(t, buf, reused)
+	require.Equal(t, BufferPoolStats{Gets: 2, Hits: 1, Puts: 1}, be.BufferPoolStats())
+	require.InDelta(t, 0.5, be.BufferPoolStats().HitRate(), 1e-6)
+
+	// Different dtypes and larger size classes are not reused, and the pool is limited to 4096 bytes.
+	other := be.getBuffer(dtypes.Int32, 900)
+	require.NotSame(t, reused, other)
+	be.putBuffer(reused)
+	be.putBuffer(other)
+	stats := be.BufferPoolStats()
+	require.Equal(t, int64(1), stats.Drops)
+	require.Equal(t, int64(1024*4), stats.PooledBytes)
+
+	be.Finalize()
+	require.Zero(t, be.BufferPoolStats().PooledBytes)
+}
+
+func TestBuffers_Alignment(t *testing.T) {
+	isAligned := func(flat any) bool {
+		return reflect.ValueOf(flat).Pointer()%memoryAlignment == 0
+	}
+	be := backend.(*Backend)
+	for _, dtype := range []dtypes.DType{dtypes.Bool, dtypes.Int8, dtypes.BFloat16, dtypes.Float32, dtypes.Float64} {
+		for _, length := range []int{1, 3, 100, 1000, 100_000} {
+			buf := be.getBuffer(dtype, length)
+			require.Equal(t, length, reflect.ValueOf(buf.flat).Len())
+			require.Truef(t, isAligned(buf.flat), "buffer of %d x %s not aligned", length, dtype)
+			be.putBuffer(buf)
+		}
+	}
+	for _, size := range []int{1, 17, 4096} {
+		packed := getGemmPackedBuffer(size)
+		require.True(t, isAligned(*packed))
+		gemmPackedPool.Put(packed)
+		require.True(t, isAligned(makeAligned[float64](size, size)))
+	}
+	for _, panel := range packWeightForGEMM(defaultGEMMBlockSizes, make([]float32, 300*70), 300, 70).panels {
+		require.True(t, isAligned(panel))
+	}
+}
@@ -63,6 +63,9 @@ func (b *Builder) Compile(outputs []backends.Op, shardings []*backends.ShardingS
 			)
 		}
 	}
+	if !b.backend.dotGeneralNoFusion {
+		b.fuseDotGeneralEpilogues()
+	}
 	b.compiled = true
 	return newExecutable(b), nil
 }
@@ -177,9 +177,11 @@ func execConvGeneral(backend *Backend, node *Node, inputs []*Buffer, _ []bool) (
 	var convFn func(convGeneralExecPlan) error
 	if params.hasInputDilations || params.hasKernelDilations || params.channelGroupCount > 1 || params.batchGroupCount > 1 {
 		// Full version.
+		profileKernel(backend, backends.OpTypeConvGeneral, outputShape, "generic")
 		convFn = convDTypeMap.Get(dtype).(func(convGeneralExecPlan) error)
 	} else {
 		// Faster, but no dilation or grouping version.
+		profileKernel(backend, backends.OpTypeConvGeneral, outputShape, "no_dilation")
 		convFn = convNoDilationDTypeMap.Get(dtype).(func(plan convGeneralExecPlan) error)
 	}
 	err := convFn(plan)
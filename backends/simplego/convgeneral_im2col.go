@@ -0,0 +1,179 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"github.com/gomlx/gomlx/backends"
+	"github.com/gomlx/gopjrt/dtypes"
+)
+
+func init() {
+	convNoDilationDTypeMap.Register(dtypes.Float32, execConvIm2ColFloat32)
+}
+
+// convIm2ColChunkRows is the number of output positions whose input patches are gathered (im2col) at a time, in a
+// buffer of convIm2ColChunkRows × (kernel spatial size × input channels) values, multiplied by the kernel with GEMM.
+const convIm2ColChunkRows = 128
+
+// execConvIm2ColFloat32 implements ConvGeneral for float32 without dilations or grouping, by lowering it to a
+// matrix multiplication (im2col + GEMM), as used by the audio and vision front-ends (e.g. Whisper's 1D convolutions
+// or ViT's patch embedding):
+//
+//   - The kernel is rearranged as a matrix W[kernelSpatial × inputChannels, outputChannels], packed for the GEMM.
+//   - For each output position, the input values under the kernel (the "patch", with zeros for the padding) are
+//     gathered as one row of the matrix col[outputPositions, kernelSpatial × inputChannels].
+//   - The output is col·W, computed by gemmFloat32PackedB and then scattered to the output layout.
+//
+// The output positions (of all batch examples) are split among the backend workers, and they are processed in
+// chunks of convIm2ColChunkRows, so the col matrix is never materialized in full.
+func execConvIm2ColFloat32(plan convGeneralExecPlan) error {
+	backend := plan.backend.(*Backend)
+	inputFlat := plan.inputFlat.([]float32)
+	kernelFlat := plan.kernelFlat.([]float32)
+	outputFlat := plan.outputFlat.([]float32)
+	inputShape, kernelShape, outputShape := plan.inputShape, plan.kernelShape, plan.outputShape
+	params := plan.params
+	axes := params.axes
+	if outputShape.Size() == 0 {
+		return nil
+	}
+	profileKernel(backend, backends.OpTypeConvGeneral, outputShape, "im2col")
+
+	// Dimensions of the matrix multiplication.
+	spatialRank := len(axes.OutputSpatial)
+	batchSize := outputShape.Dimensions[axes.OutputBatch]
+	numInputChannels := inputShape.Dimensions[axes.InputChannels]
+	numOutputChannels := outputShape.Dimensions[axes.OutputChannels]
+	outputSpatialDims := make([]int, spatialRank)
+	kernelSpatialDims := make([]int, spatialRank)
+	numPositions, kernelSpatialSize := 1, 1
+	for spatialIdx := range spatialRank {
+		outputSpatialDims[spatialIdx] = outputShape.Dimensions[axes.OutputSpatial[spatialIdx]]
+		kernelSpatialDims[spatialIdx] = kernelShape.Dimensions[axes.KernelSpatial[spatialIdx]]
+		numPositions *= outputSpatialDims[spatialIdx]
+		kernelSpatialSize *= kernelSpatialDims[spatialIdx]
+	}
+	patchSize := kernelSpatialSize * numInputChannels
+
+	// Offsets of each kernel spatial position, and the kernel as the matrix W[patchSize, numOutputChannels].
+	kernelStrides := kernelShape.Strides()
+	kernelOffsets := make([]int, kernelSpatialSize*spatialRank)
+	weightsBuf := getGemmPackedBuffer(patchSize * numOutputChannels)
+	weights := *weightsBuf
+	kernelIndices := make([]int, spatialRank)
+	for kernelPos := range kernelSpatialSize {
+		kernelFlatIdx := 0
+		for spatialIdx := range spatialRank {
+			kernelOffsets[kernelPos*spatialRank+spatialIdx] = kernelIndices[spatialIdx]
+			kernelFlatIdx += kernelIndices[spatialIdx] * kernelStrides[axes.KernelSpatial[spatialIdx]]
+		}
+		for inputChannel := range numInputChannels {
+			row := weights[(kernelPos*numInputChannels+inputChannel)*numOutputChannels:]
+			channelFlatIdx := kernelFlatIdx + inputChannel*kernelStrides[axes.KernelInputChannels]
+			for outputChannel := range numOutputChannels {
+				row[outputChannel] = kernelFlat[channelFlatIdx+outputChannel*kernelStrides[axes.KernelOutputChannels]]
+			}
+		}
+		incrementIndices(kernelIndices, kernelSpatialDims)
+	}
+	packedWeights := packWeightForGEMM(backend.gemmBlockSizes, weights, patchSize, numOutputChannels)
+	gemmPackedPool.Put(weightsBuf)
+
+	inputStrides := inputShape.Strides()
+	inputChannelStride := inputStrides[axes.InputChannels]
+	inputSpatialDims := params.dilatedInputSpatialDims // Same as the input dimensions, since there are no dilations.
+	outputStrides := outputShape.Strides()
+	outputChannelStride := outputStrides[axes.OutputChannels]
+
+	parallelizeDotGeneral(backend, batchSize, numPositions, patchSize*numOutputChannels, func(batchIdx, posStart, posEnd int) {
+		numRows := min(convIm2ColChunkRows, posEnd-posStart)
+		colBuf := getGemmPackedBuffer(numRows * patchSize)
+		chunkOutputBuf := getGemmPackedBuffer(numRows * numOutputChannels)
+		defer gemmPackedPool.Put(colBuf)
+		defer gemmPackedPool.Put(chunkOutputBuf)
+		col, chunkOutput := *colBuf, *chunkOutputBuf
+		inputBatchFlatIdx := batchIdx * inputStrides[axes.InputBatch]
+		outputBatchFlatIdx := batchIdx * outputStrides[axes.OutputBatch]
+
+		outputIndices := make([]int, spatialRank)
+		for chunkStart := posStart; chunkStart < posEnd; chunkStart += convIm2ColChunkRows {
+			chunkEnd := min(chunkStart+convIm2ColChunkRows, posEnd)
+			chunkRows := chunkEnd - chunkStart
+
+			// im2col: gather the patches of the chunk of output positions.
+			positionToIndices(chunkStart, outputSpatialDims, outputIndices)
+			for row := range chunkRows {
+				patch := col[row*patchSize : (row+1)*patchSize]
+				for kernelPos := range kernelSpatialSize {
+					values := patch[kernelPos*numInputChannels : (kernelPos+1)*numInputChannels]
+					inputFlatIdx := inputBatchFlatIdx
+					inPadding := false
+					for spatialIdx := range spatialRank {
+						inputIdx := outputIndices[spatialIdx]*params.strides[spatialIdx] +
+							kernelOffsets[kernelPos*spatialRank+spatialIdx] - params.paddings[spatialIdx][0]
+						if inputIdx < 0 || inputIdx >= inputSpatialDims[spatialIdx] {
+							inPadding = true
+							break
+						}
+						inputFlatIdx += inputIdx * params.inputSpatialStrides[spatialIdx]
+					}
+					switch {
+					case inPadding:
+						clear(values)
+					case inputChannelStride == 1:
+						copy(values, inputFlat[inputFlatIdx:inputFlatIdx+numInputChannels])
+					default:
+						for inputChannel := range values {
+							values[inputChannel] = inputFlat[inputFlatIdx+inputChannel*inputChannelStride]
+						}
+					}
+				}
+				incrementIndices(outputIndices, outputSpatialDims)
+			}
+
+			// GEMM: chunkOutput = col · W.
+			clear(chunkOutput[:chunkRows*numOutputChannels])
+			gemmFloat32PackedB(chunkRows, col, 0, patchSize, packedWeights, chunkOutput, 0, numOutputChannels)
+
+			// Scatter the chunk to the output layout.
+			positionToIndices(chunkStart, outputSpatialDims, outputIndices)
+			for row := range chunkRows {
+				outputFlatIdx := outputBatchFlatIdx
+				for spatialIdx, outputAxis := range axes.OutputSpatial {
+					outputFlatIdx += outputIndices[spatialIdx] * outputStrides[outputAxis]
+				}
+				values := chunkOutput[row*numOutputChannels : (row+1)*numOutputChannels]
+				if outputChannelStride == 1 {
+					copy(outputFlat[outputFlatIdx:outputFlatIdx+numOutputChannels], values)
+				} else {
+					for outputChannel, value := range values {
+						outputFlat[outputFlatIdx+outputChannel*outputChannelStride] = value
+					}
+				}
+				incrementIndices(outputIndices, outputSpatialDims)
+			}
+		}
+	})
+	return nil
+}
+
+// incrementIndices increments the row-major indices into the given dimensions, wrapping around at the end.
+func incrementIndices(indices, dimensions []int) {
+	for axis := len(indices) - 1; axis >= 0; axis-- {
+		indices[axis]++
+		if indices[axis] < dimensions[axis] {
+			return
+		}
+		indices[axis] = 0
+	}
+}
+
+// positionToIndices converts the row-major flat position into the given dimensions to indices.
+func positionToIndices(position int, dimensions, indices []int) {
+	for axis := len(dimensions) - 1; axis >= 0; axis-- {
+		indices[axis] = position % dimensions[axis]
+		position /= dimensions[axis]
+	}
+}
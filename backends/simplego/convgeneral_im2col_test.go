@@ -0,0 +1,144 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/gomlx/gomlx/backends"
+	"github.com/gomlx/gomlx/pkg/core/shapes"
+	"github.com/gomlx/gopjrt/dtypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvIm2ColFloat32(t *testing.T) {
+	be, ok := backend.(*Backend)
+	if !ok {
+		t.Skip("Skipping test because backend is not a SimpleGo Backend")
+	}
+	channelsFirst2D := backends.ConvolveAxesConfig{
+		InputBatch: 0, InputChannels: 1, InputSpatial: []int{2, 3},
+		KernelInputChannels: 1, KernelOutputChannels: 0, KernelSpatial: []int{2, 3},
+		OutputBatch: 0, OutputChannels: 1, OutputSpatial: []int{2, 3},
+	}
+	channelsLast2D := backends.ConvolveAxesConfig{
+		InputBatch: 0, InputChannels: 3, InputSpatial: []int{1, 2},
+		KernelInputChannels: 2, KernelOutputChannels: 3, KernelSpatial: []int{0, 1},
+		OutputBatch: 0, OutputChannels: 3, OutputSpatial: []int{1, 2},
+	}
+	channelsFirst1D := backends.ConvolveAxesConfig{
+		InputBatch: 0, InputChannels: 1, InputSpatial: []int{2},
+		KernelInputChannels: 1, KernelOutputChannels: 0, KernelSpatial: []int{2},
+		OutputBatch: 0, OutputChannels: 1, OutputSpatial: []int{2},
+	}
+	testCases := []struct {
+		name          string
+		input, kernel shapes.Shape
+		axes          backends.ConvolveAxesConfig
+		strides       []int
+		paddings      [][2]int
+	}{
+		{"2D channels-first", shapes.Make(dtypes.Float32, 2, 3, 9, 7), shapes.Make(dtypes.Float32, 5, 3, 3, 3),
+			channelsFirst2D, []int{1, 1}, [][2]int{{1, 1}, {1, 1}}},
+		{"2D channels-last strided", shapes.Make(dtypes.Float32, 2, 17, 15, 4), shapes.Make(dtypes.Float32, 3, 2, 4, 20),
+			channelsLast2D, []int{2, 3}, [][2]int{{0, 2}, {1, 0}}},
+		{"2D patchify", shapes.Make(dtypes.Float32, 1, 32, 32, 3), shapes.Make(dtypes.Float32, 8, 8, 3, 16),
+			channelsLast2D, []int{8, 8}, nil},
+		{"1D Whisper-like", shapes.Make(dtypes.Float32, 1, 8, 300), shapes.Make(dtypes.Float32, 24, 8, 3),
+			channelsFirst1D, []int{2}, [][2]int{{1, 1}}},
+		{"1D kernel larger than input", shapes.Make(dtypes.Float32, 3, 2, 2), shapes.Make(dtypes.Float32, 4, 2, 5),
+			channelsFirst1D, []int{1}, [][2]int{{2, 2}}},
+	}
+	rng := rand.New(rand.NewSource(42))
+	for _, tc := range testCases {
+		for _, simd := range []string{SIMDAuto, SIMDOff} {
+			t.Run(fmt.Sprintf("%s/%s", tc.name, simd), func(t *testing.T) {
+				require.NoError(t, SetSIMD(simd))
+				defer func() { require.NoError(t, SetSIMD(SIMDAuto)) }()
+
+				builder := be.Builder("im2col").(*Builder)
+				inputOp, err := builder.Parameter("input", tc.input, nil)
+				require.NoError(t, err)
+				kernelOp, err := builder.Parameter("kernel", tc.kernel, nil)
+				require.NoError(t, err)
+				convOp, err := builder.ConvGeneral(inputOp, kernelOp, tc.axes, tc.strides, tc.paddings, nil, nil, 1, 1)
+				require.NoError(t, err)
+				node := convOp.(*Node)
+
+				input := be.NewBuffer(tc.input)
+				for ii, flat := 0, input.flat.([]float32); ii < len(flat); ii++ {
+					flat[ii] = rng.Float32()*2 - 1
+				}
+				kernel := be.NewBuffer(tc.kernel)
+				for ii, flat := 0, kernel.flat.([]float32); ii < len(flat); ii++ {
+					flat[ii] = rng.Float32()*2 - 1
+				}
+				newPlan := func() convGeneralExecPlan {
+					output := be.NewBuffer(node.shape)
+					output.Zeros()
+					return convGeneralExecPlan{
+						backend:     be,
+						dtype:       dtypes.Float32,
+						inputFlat:   input.flat,
+						inputShape:  input.shape,
+						kernelFlat:  kernel.flat,
+						kernelShape: kernel.shape,
+						outputFlat:  output.flat,
+						outputShape: node.shape,
+						params:      node.data.(*convNode),
+					}
+				}
+				want, got := newPlan(), newPlan()
+				require.NoError(t, execConvNoDilationGeneric[float32](want))
+				require.NoError(t, execConvIm2ColFloat32(got))
+				require.InDeltaSlice(t, want.outputFlat, got.outputFlat, 1e-4)
+			})
+		}
+	}
+}
+
+func BenchmarkConvIm2ColFloat32(b *testing.B) {
+	be, ok := backend.(*Backend)
+	if !ok {
+		b.Skip("Skipping benchmark because backend is not a SimpleGo Backend")
+	}
+	// Second convolution of the Whisper (tiny) encoder: [1, 384, 3000] → [1, 384, 1500], kernel 3, stride 2.
+	axes := backends.ConvolveAxesConfig{
+		InputBatch: 0, InputChannels: 1, InputSpatial: []int{2},
+		KernelInputChannels: 1, KernelOutputChannels: 0, KernelSpatial: []int{2},
+		OutputBatch: 0, OutputChannels: 1, OutputSpatial: []int{2},
+	}
+	builder := be.Builder("im2col").(*Builder)
+	inputOp, err := builder.Parameter("input", shapes.Make(dtypes.Float32, 1, 384, 3000), nil)
+	require.NoError(b, err)
+	kernelOp, err := builder.Parameter("kernel", shapes.Make(dtypes.Float32, 384, 384, 3), nil)
+	require.NoError(b, err)
+	convOp, err := builder.ConvGeneral(inputOp, kernelOp, axes, []int{2}, [][2]int{{1, 1}}, nil, nil, 1, 1)
+	require.NoError(b, err)
+	node := convOp.(*Node)
+	input, kernel := be.NewBuffer(inputOp.(*Node).shape), be.NewBuffer(kernelOp.(*Node).shape)
+	input.Zeros()
+	kernel.Zeros()
+	output := be.NewBuffer(node.shape)
+	plan := convGeneralExecPlan{
+		backend: be, dtype: dtypes.Float32,
+		inputFlat: input.flat, inputShape: input.shape,
+		kernelFlat: kernel.flat, kernelShape: kernel.shape,
+		outputFlat: output.flat, outputShape: node.shape,
+		params: node.data.(*convNode),
+	}
+	b.Run("im2col", func(b *testing.B) {
+		for range b.N {
+			require.NoError(b, execConvIm2ColFloat32(plan))
+		}
+	})
+	b.Run("generic", func(b *testing.B) {
+		for range b.N {
+			require.NoError(b, execConvNoDilationGeneric[float32](plan))
+		}
+	})
+}
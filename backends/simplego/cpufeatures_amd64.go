@@ -0,0 +1,100 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !noasm && amd64
+
+package simplego
+
+// cpuid is implemented in cpufeatures_amd64.s.
+func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+
+// xgetbv is implemented in cpufeatures_amd64.s.
+// It returns the XCR0 register, with the CPU states enabled by the OS.
+func xgetbv() (eax, edx uint32)
+
+// amd64Features lists the optional AMD64 CPU features used (or usable) by the SIMD kernels.
+//
+// A feature is only reported if the OS also supports it, that is, if it saves the corresponding registers
+// on context switches.
+type amd64Features struct {
+	HasSSE42, HasAVX, HasFMA, HasF16C, HasAVX2, HasAVXVNNI                          bool
+	HasAVX512F, HasAVX512DQ, HasAVX512BW, HasAVX512VL, HasAVX512VNNI, HasAVX512BF16 bool
+}
+
+// cpuFeaturesAMD64 holds the features of the CPU, as reported by CPUID and XGETBV.
+var cpuFeaturesAMD64 = detectAMD64Features()
+
+// CPUID bits, see the Intel® 64 and IA-32 Architectures Software Developer's Manual, Vol. 2A, CPUID.
+const (
+	// CPUID.(EAX=1):ECX
+	cpuidSSE42   = 1 << 20
+	cpuidFMA     = 1 << 12
+	cpuidOSXSAVE = 1 << 27
+	cpuidAVX     = 1 << 28
+	cpuidF16C    = 1 << 29
+
+	// CPUID.(EAX=7,ECX=0):EBX
+	cpuidAVX2     = 1 << 5
+	cpuidAVX512F  = 1 << 16
+	cpuidAVX512DQ = 1 << 17
+	cpuidAVX512BW = 1 << 30
+	cpuidAVX512VL = 1 << 31
+
+	// CPUID.(EAX=7,ECX=0):ECX
+	cpuidAVX512VNNI = 1 << 11
+
+	// CPUID.(EAX=7,ECX=1):EAX
+	cpuidAVXVNNI    = 1 << 4
+	cpuidAVX512BF16 = 1 << 5
+
+	// XCR0 states.
+	xcr0YMM    = 0x6  // SSE and AVX states.
+	xcr0AVX512 = 0xE0 // Opmask, ZMM_Hi256 and Hi16_ZMM states.
+)
+
+// detectAMD64Features queries CPUID and XGETBV.
+//
+// Notice macOS enables the AVX-512 state lazily, on the first use of the instructions, so AVX-512 is not
+// reported there until then.
+func detectAMD64Features() (f amd64Features) {
+	maxLeaf, _, _, _ := cpuid(0, 0)
+	if maxLeaf < 1 {
+		return
+	}
+	_, _, ecx1, _ := cpuid(1, 0)
+	f.HasSSE42 = ecx1&cpuidSSE42 != 0
+	if ecx1&(cpuidOSXSAVE|cpuidAVX) != cpuidOSXSAVE|cpuidAVX {
+		return
+	}
+	xcr0, _ := xgetbv()
+	if xcr0&xcr0YMM != xcr0YMM {
+		return
+	}
+	f.HasAVX = true
+	f.HasFMA = ecx1&cpuidFMA != 0
+	f.HasF16C = ecx1&cpuidF16C != 0
+	if maxLeaf < 7 {
+		return
+	}
+	maxSubLeaf7, ebx7, ecx7, _ := cpuid(7, 0)
+	var eax71 uint32
+	if maxSubLeaf7 >= 1 {
+		eax71, _, _, _ = cpuid(7, 1)
+	}
+	f.HasAVX2 = ebx7&cpuidAVX2 != 0
+	f.HasAVXVNNI = eax71&cpuidAVXVNNI != 0
+	if xcr0&xcr0AVX512 != xcr0AVX512 {
+		return
+	}
+	f.HasAVX512F = ebx7&cpuidAVX512F != 0
+	if !f.HasAVX512F {
+		return
+	}
+	f.HasAVX512DQ = ebx7&cpuidAVX512DQ != 0
+	f.HasAVX512BW = ebx7&cpuidAVX512BW != 0
+	f.HasAVX512VL = ebx7&cpuidAVX512VL != 0
+	f.HasAVX512VNNI = ecx7&cpuidAVX512VNNI != 0
+	f.HasAVX512BF16 = eax71&cpuidAVX512BF16 != 0
+	return
+}
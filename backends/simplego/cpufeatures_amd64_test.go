@@ -0,0 +1,52 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !noasm && amd64
+
+package simplego
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectAMD64Features(t *testing.T) {
+	f := cpuFeaturesAMD64
+	t.Logf("CPU features: %+v", f)
+	if f.HasAVX2 {
+		require.True(t, f.HasAVX)
+	}
+	if f.HasAVX512F {
+		require.True(t, f.HasAVX2)
+	}
+
+	// Cross-check with the flags reported by Linux.
+	cpuInfo, err := os.ReadFile("/proc/cpuinfo")
+	if err != nil {
+		t.Skipf("Skipping cross-check with /proc/cpuinfo: %v", err)
+	}
+	var flags []string
+	for _, line := range strings.Split(string(cpuInfo), "\n") {
+		if strings.HasPrefix(line, "flags") {
+			flags = strings.Fields(line[strings.Index(line, ":")+1:])
+			break
+		}
+	}
+	for flag, has := range map[string]bool{
+		"sse4_2":      f.HasSSE42,
+		"avx":         f.HasAVX,
+		"fma":         f.HasFMA,
+		"avx2":        f.HasAVX2,
+		"avx512f":     f.HasAVX512F,
+		"avx512bw":    f.HasAVX512BW,
+		"avx512vl":    f.HasAVX512VL,
+		"avx512_vnni": f.HasAVX512VNNI,
+	} {
+		require.Equalf(t, slices.Contains(flags, flag), has, "flag %q", flag)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux && arm64
+
+package simplego
+
+import (
+	_ "unsafe" // For go:linkname.
+)
+
+// runtimeGetAuxv returns the auxiliary vector passed by the kernel to the process: the same values returned by
+// the C library getauxval(), but without requiring cgo.
+//
+// The Go runtime exposes it for golang.org/x/sys/cpu, see go.dev/issue/57336.
+//
+//go:linkname runtimeGetAuxv runtime.getAuxv
+func runtimeGetAuxv() []uintptr
+
+// Linux ARM64 hardware capabilities bits, reported in the AT_HWCAP and AT_HWCAP2 entries of the auxiliary vector.
+// See https://docs.kernel.org/arch/arm64/elf_hwcaps.html
+const (
+	atHWCap  = 16
+	atHWCap2 = 26
+
+	hwcapASIMDHP  = 1 << 10 // FEAT_FP16: half-precision arithmetic.
+	hwcapASIMDDP  = 1 << 20 // FEAT_DotProd: SDOT/UDOT.
+	hwcapSVE      = 1 << 22 // FEAT_SVE.
+	hwcapASIMDFHM = 1 << 23 // FEAT_FHM: FMLAL/FMLAL2.
+
+	hwcap2SVE2 = 1 << 1  // FEAT_SVE2.
+	hwcap2I8MM = 1 << 13 // FEAT_I8MM: SMMLA/USDOT.
+	hwcap2BF16 = 1 << 14 // FEAT_BF16: BFDOT/BFMLALB/BFMLALT.
+	hwcap2SME  = 1 << 23 // FEAT_SME.
+)
+
+// arm64Features lists the optional ARM64 CPU features used (or usable) by the SIMD kernels.
+type arm64Features struct {
+	HasASIMDHP, HasFHM, HasDotProd, HasI8MM, HasBF16, HasSVE, HasSVE2, HasSME bool
+}
+
+// cpuFeaturesARM64 holds the features of the CPU, as reported by the Linux kernel.
+var cpuFeaturesARM64 = detectARM64Features(runtimeGetAuxv())
+
+// detectARM64Features parses the auxiliary vector, a list of (key, value) pairs.
+func detectARM64Features(auxv []uintptr) (f arm64Features) {
+	var hwcap, hwcap2 uint64
+	for ii := 0; ii+1 < len(auxv); ii += 2 {
+		switch auxv[ii] {
+		case atHWCap:
+			hwcap = uint64(auxv[ii+1])
+		case atHWCap2:
+			hwcap2 = uint64(auxv[ii+1])
+		}
+	}
+	f.HasASIMDHP = hwcap&hwcapASIMDHP != 0
+	f.HasFHM = hwcap&hwcapASIMDFHM != 0
+	f.HasDotProd = hwcap&hwcapASIMDDP != 0
+	f.HasSVE = hwcap&hwcapSVE != 0
+	f.HasSVE2 = hwcap2&hwcap2SVE2 != 0
+	f.HasI8MM = hwcap2&hwcap2I8MM != 0
+	f.HasBF16 = hwcap2&hwcap2BF16 != 0
+	f.HasSME = hwcap2&hwcap2SME != 0
+	return
+}
@@ -0,0 +1,26 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux && arm64
+
+package simplego
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectARM64Features(t *testing.T) {
+	t.Logf("CPU features: %+v", cpuFeaturesARM64)
+
+	f := detectARM64Features([]uintptr{
+		3, 0x40, // AT_PHDR, ignored.
+		atHWCap, hwcapASIMDFHM | hwcapSVE,
+		atHWCap2, hwcap2BF16 | hwcap2SME,
+		0, 0, // AT_NULL.
+	})
+	require.Equal(t, arm64Features{HasFHM: true, HasSVE: true, HasBF16: true, HasSME: true}, f)
+	require.Equal(t, arm64Features{}, detectARM64Features(nil))
+}
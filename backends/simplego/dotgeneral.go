@@ -24,6 +24,9 @@ type dotGeneralNodeData struct {
 	rhsContractingAxes, rhsBatchAxes                       []int
 	batchSize, lhsCrossSize, rhsCrossSize, contractingSize int
 	lhsBlockedShape, rhsBlockedShape, outputBlockedShape   shapes.Shape
+
+	// epilogue is set if the addition of a bias and/or an activation was fused into the DotGeneral.
+	epilogue *dotGeneralEpilogue
 }
 
 // adjustAxisToRank returns a positive axis, adjusting negative numbers to the correct rank.
@@ -66,12 +69,16 @@ func (b *Builder) DotGeneral(lhsOp backends.Op, lhsContractingAxes, lhsBatchAxes
 	lhsDType := lhs.shape.DType
 	rhsDType := rhs.shape.DType
 
+	dtype := lhsDType
 	if lhsDType != rhsDType {
-		return nil, errors.Errorf("DotGeneral lhs (left-hand-side) and rhs operands don't match data types: %s and %s", lhsDType, rhsDType)
+		if !isMixedPrecisionDotGeneral(lhsDType, rhsDType) {
+			return nil, errors.Errorf("DotGeneral lhs (left-hand-side) and rhs operands don't match data types: %s and %s", lhsDType, rhsDType)
+		}
+		// Mixed precision (e.g. float32 activations and Float16 weights): the operands are multiplied and
+		// accumulated in float32, and the output is Float32. See isMixedPrecisionDotGeneral.
+		dtype = dtypes.Float32
 	}
 
-	dtype := lhsDType
-
 	// Determine the final output dtype for the node.
 	// For int8/uint8: output is int32 (widened accumulation type that's also the final output).
 	// For bfloat16/float16: output remains the input dtype (internal accumulation uses float32 but converts back).
@@ -230,39 +237,99 @@ const (
 	checkProblemSize
 )
 
+// String returns the name of the problem size, used as the kernel name in profiles, see ProfileLabelKernel.
+func (p dotGeneralProblemSizeType) String() string {
+	switch p {
+	case smallProblemSize:
+		return "small"
+	case largeProblemSize:
+		return "large"
+	case checkProblemSize:
+		return "check"
+	default:
+		return "unknown"
+	}
+}
+
 // execDotGeneral executes the DotGeneral by first normalizing and repackaging the tensors into blocks.
+//
+// If the node has a fused epilogue (see dotGeneralEpilogue), it is applied to the output, which is then given the
+// shape of the node.
 func execDotGeneral(backend *Backend, node *Node, inputs []*Buffer, _ []bool) (*Buffer, error) {
+	output, err := execDotGeneralWithoutReshape(backend, node, inputs)
+	if err != nil {
+		return nil, err
+	}
+	output.shape = node.shape
+	return output, nil
+}
+
+// execDotGeneralWithoutReshape implements execDotGeneral, returning the output with the normalized shape
+// [batchSize, lhsCrossSize, rhsCrossSize].
+func execDotGeneralWithoutReshape(backend *Backend, node *Node, inputs []*Buffer) (*Buffer, error) {
 	lhs, rhs := inputs[0], inputs[1]
 	params := node.data.(*dotGeneralNodeData)
 	outputShape := node.shape
-	dtype := lhs.shape.DType
+	if params.epilogue != nil {
+		outputShape = params.epilogue.outputShape
+	}
 	output := backend.getBufferForShape(outputShape)
 	output.Zeros()
+	epilogue := newFloat32Epilogue(backend, params, inputs)
+
+	compensated := useCompensatedSummation(backend, params, outputShape.DType)
+
+	// Mixed precision: half-precision operands are converted to float32 (see isMixedPrecisionDotGeneral).
+	rhsConverted := false
+	if lhs.shape.DType != rhs.shape.DType {
+		if ok, rhsTransposed := canUseMixedFastPath(lhs, rhs, params); ok && !compensated {
+			profileDotGeneralKernel(backend, output, "mixed")
+			execDotGeneralMixedFastPath(backend, lhs, rhs, params, output, epilogue, rhsTransposed)
+			return output, nil
+		}
+		if converted := convertMixedOperandToFloat32(backend, lhs); converted != nil {
+			lhs = converted
+			defer backend.putBuffer(converted)
+		}
+		if converted := convertMixedOperandToFloat32(backend, rhs); converted != nil {
+			rhs = converted
+			rhsConverted = true
+			defer backend.putBuffer(converted)
+		}
+	}
+	dtype := lhs.shape.DType
+
+	// Long float32 contractions, see Backend.SetDotGeneralCompensatedSummation.
+	if compensated {
+		profileDotGeneralKernel(backend, output, "compensated")
+		execDotGeneralCompensated(backend, lhs, rhs, params, output)
+		epilogue.apply(output.flat.([]float32), 0)
+		return output, nil
+	}
 
 	// Try the fast path first for standard matrix multiplication patterns.
 	// This avoids the normalization overhead for the most common cases.
-	if execDotGeneralFastPath(backend, lhs, rhs, params, output) {
+	if execDotGeneralFastPath(backend, lhs, rhs, params, output, epilogue) {
 		return output, nil
 	}
 
 	// Try using pre-blocked weights for large matrix multiplications.
-	// This avoids blocking the RHS (weights) on every matmul call.
-	if TryExecDotGeneralWithPreBlockedWeights(backend, lhs, rhs, params, output) {
+	// This avoids blocking the RHS (weights) on every matmul call. Temporary float32 copies of the RHS are
+	// never cached, since their memory is reused.
+	if !rhsConverted && TryExecDotGeneralWithPreBlockedWeights(backend, lhs, rhs, params, output) {
+		if epilogue != nil {
+			epilogue.apply(output.flat.([]float32), 0)
+		}
 		return output, nil
 	}
 
-	// Problem size (per example of the batch):
-	crossesSize := params.rhsCrossSize * params.lhsCrossSize
-	blockDim := 1 << DotGeneralTargetBlockLog2Dim[dtype]
-	blockSize := blockDim * blockDim
+	// Small or large (blocked) algorithm, according to the cost model.
 	var err error
-	problemSize := smallProblemSize
-	if crossesSize > 16*blockSize {
-		problemSize = largeProblemSize
-	}
+	problemSize := selectDotGeneralProblemSize(dtype, params)
 	if backend.dotGeneralForceProblemSize != unknownProblemSize {
 		problemSize = backend.dotGeneralForceProblemSize
 	}
+	profileDotGeneralKernel(backend, output, problemSize.String())
 	switch problemSize {
 	case largeProblemSize:
 		err = execDotGeneralLarge(backend, lhs, rhs, params, output)
@@ -288,6 +355,9 @@ func execDotGeneral(backend *Backend, node *Node, inputs []*Buffer, _ []bool) (*
 		backend.putBuffer(output)
 		return nil, err
 	}
+	if epilogue != nil {
+		epilogue.apply(output.flat.([]float32), 0)
+	}
 	return output, nil
 }
 
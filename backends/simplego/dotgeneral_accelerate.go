@@ -0,0 +1,48 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"github.com/gomlx/gomlx/backends/simplego/internal/cblas"
+	"github.com/pkg/errors"
+)
+
+// DotGeneralAccelerateMinWork is the minimum amount of work, measured in multiply-adds (M*N*K of each batch
+// example), of the matrix multiplications routed to Apple's Accelerate framework, when enabled with
+// Backend.SetDotGeneralAccelerate. Below it, the cost of the call (and of the conversions, for Float16) is
+// larger than the gains.
+var DotGeneralAccelerateMinWork = 1 << 20
+
+// SetDotGeneralAccelerate enables or disables routing the large float32 and float16 matrix multiplications
+// ([M, K] × [K, N] and [M, K] × [N, K], optionally batched) to the cblas_sgemm of Apple's Accelerate framework,
+// which uses the AMX matrix units of Apple Silicon. It can also be enabled with the "dotgeneral_accelerate"
+// configuration, see New.
+//
+// It requires macOS and building with cgo and the "accelerate" build tag (go build -tags accelerate), so the
+// default build stays pure Go: it returns an error otherwise (see AccelerateAvailable).
+//
+// Float16 operands are converted to float32, multiplied and the result converted back to Float16.
+func (b *Backend) SetDotGeneralAccelerate(enabled bool) error {
+	if enabled && !hasAccelerate {
+		return errors.New("SetDotGeneralAccelerate: Apple Accelerate is not available, it requires macOS and " +
+			"building with cgo and the \"accelerate\" build tag")
+	}
+	b.dotGeneralAccelerate = enabled
+	return nil
+}
+
+// hasAccelerate indicates whether Apple's Accelerate framework is linked, see Backend.SetDotGeneralAccelerate.
+var hasAccelerate = cblas.Library == cblas.Accelerate
+
+// AccelerateAvailable returns whether the Apple Accelerate matrix multiplication is available, see
+// Backend.SetDotGeneralAccelerate.
+func AccelerateAvailable() bool {
+	return hasAccelerate
+}
+
+// useAccelerate returns whether a matrix multiplication [m, k] × [k, n] should use Accelerate.
+func useAccelerate(backend *Backend, m, n, k int) bool {
+	return backend.dotGeneralAccelerate && m*n*k >= DotGeneralAccelerateMinWork
+}
@@ -0,0 +1,28 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDotGeneral_Accelerate(t *testing.T) {
+	if !AccelerateAvailable() {
+		_, err := New("dotgeneral_accelerate")
+		require.Error(t, err)
+		require.Error(t, backend.(*Backend).SetDotGeneralAccelerate(true))
+		require.NoError(t, backend.(*Backend).SetDotGeneralAccelerate(false))
+		t.Skip("Accelerate not available: it requires macOS and building with cgo and -tags accelerate")
+	}
+	accelerateBackend, err := New("dotgeneral_accelerate")
+	require.NoError(t, err)
+	defer accelerateBackend.Finalize()
+	defer func(minWork int) { DotGeneralAccelerateMinWork = minWork }(DotGeneralAccelerateMinWork)
+	DotGeneralAccelerateMinWork = 1
+
+	requireDotGeneralMatches(t, backend, accelerateBackend)
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !noasm && amd64
+
+package simplego
+
+import (
+	"runtime"
+	"unsafe"
+)
+
+// hasAVX2 and hasAVX512 indicate whether the AVX2 (with FMA) and AVX-512F optimizations are available,
+// both in the CPU and enabled by the OS.
+//
+// The kernels are selected in order of preference: AVX-512 → AVX2 → scalar. They can be disabled with SetSIMD.
+var (
+	hasAVX2   = cpuFeaturesAMD64.HasAVX2 && cpuFeaturesAMD64.HasFMA
+	hasAVX512 = cpuFeaturesAMD64.HasAVX512F && cpuFeaturesAMD64.HasFMA
+)
+
+var (
+	_ = registerSIMD(SIMDAVX2, &hasAVX2)
+	_ = registerSIMD(SIMDAVX512, &hasAVX512)
+)
+
+// dotProduct_avx512_asm is implemented in dotgeneral_avx_amd64.s
+// It computes a single dot product of n float32 values using AVX-512F.
+//
+//go:noescape
+func dotProduct_avx512_asm(a, b unsafe.Pointer, n int64) float32
+
+// dotProductGroup4_avx512_asm is implemented in dotgeneral_avx_amd64.s
+// It computes 4 dot products simultaneously sharing the same LHS vector.
+// b_stride is the stride in elements (float32) between the start of each RHS vector.
+//
+//go:noescape
+func dotProductGroup4_avx512_asm(a, b unsafe.Pointer, b_stride, n int64) (r0, r1, r2, r3 float32)
+
+// dotProduct_avx2_asm is implemented in dotgeneral_avx_amd64.s
+// It computes a single dot product of n float32 values using AVX2 and FMA.
+//
+//go:noescape
+func dotProduct_avx2_asm(a, b unsafe.Pointer, n int64) float32
+
+// dotProductGroup4_avx2_asm is implemented in dotgeneral_avx_amd64.s
+// Same as dotProductGroup4_avx512_asm, using AVX2 and FMA.
+//
+//go:noescape
+func dotProductGroup4_avx2_asm(a, b unsafe.Pointer, b_stride, n int64) (r0, r1, r2, r3 float32)
+
+// dotProduct_avx512 computes dot product using AVX-512F and keeps the source slices alive.
+func dotProduct_avx512(aSlice, bSlice []float32, aIdx, bIdx int, n int64) float32 {
+	result := dotProduct_avx512_asm(
+		unsafe.Pointer(&aSlice[aIdx]),
+		unsafe.Pointer(&bSlice[bIdx]),
+		n)
+	runtime.KeepAlive(aSlice)
+	runtime.KeepAlive(bSlice)
+	return result
+}
+
+// dotProduct_avx2 computes dot product using AVX2 and keeps the source slices alive.
+func dotProduct_avx2(aSlice, bSlice []float32, aIdx, bIdx int, n int64) float32 {
+	result := dotProduct_avx2_asm(
+		unsafe.Pointer(&aSlice[aIdx]),
+		unsafe.Pointer(&bSlice[bIdx]),
+		n)
+	runtime.KeepAlive(aSlice)
+	runtime.KeepAlive(bSlice)
+	return result
+}
+
+// dotProductInnerLoopAVX512 uses AVX-512F to accelerate the inner dot product loop of buildDotGeneralKernel:
+// it computes the 4 dot products of the LHS row with 4 consecutive RHS rows of the block, and adds them to the
+// current output values.
+//
+// See dotProductInnerLoopNEON for the ARM64 equivalent.
+func dotProductInnerLoopAVX512(lhsFlat, rhsFlat, outputFlat []float32,
+	lhsIdx, rhsIdx, outputIdx, blockDim int) (sum0, sum1, sum2, sum3 float32) {
+	// Bounds-check the 4 RHS rows and the output once, before handing over the pointers to the assembly.
+	_ = lhsFlat[lhsIdx+blockDim-1]
+	_ = rhsFlat[rhsIdx+4*blockDim-1]
+	_ = outputFlat[outputIdx+3]
+	r0, r1, r2, r3 := dotProductGroup4_avx512_asm(
+		unsafe.Pointer(&lhsFlat[lhsIdx]),
+		unsafe.Pointer(&rhsFlat[rhsIdx]),
+		int64(blockDim), // stride in elements
+		int64(blockDim)) // length n
+	runtime.KeepAlive(lhsFlat)
+	runtime.KeepAlive(rhsFlat)
+	return outputFlat[outputIdx] + r0, outputFlat[outputIdx+1] + r1,
+		outputFlat[outputIdx+2] + r2, outputFlat[outputIdx+3] + r3
+}
+
+// dotProductInnerLoopAVX2 is the same as dotProductInnerLoopAVX512, using AVX2 and FMA.
+func dotProductInnerLoopAVX2(lhsFlat, rhsFlat, outputFlat []float32,
+	lhsIdx, rhsIdx, outputIdx, blockDim int) (sum0, sum1, sum2, sum3 float32) {
+	_ = lhsFlat[lhsIdx+blockDim-1]
+	_ = rhsFlat[rhsIdx+4*blockDim-1]
+	_ = outputFlat[outputIdx+3]
+	r0, r1, r2, r3 := dotProductGroup4_avx2_asm(
+		unsafe.Pointer(&lhsFlat[lhsIdx]),
+		unsafe.Pointer(&rhsFlat[rhsIdx]),
+		int64(blockDim), // stride in elements
+		int64(blockDim)) // length n
+	runtime.KeepAlive(lhsFlat)
+	runtime.KeepAlive(rhsFlat)
+	return outputFlat[outputIdx] + r0, outputFlat[outputIdx+1] + r1,
+		outputFlat[outputIdx+2] + r2, outputFlat[outputIdx+3] + r3
+}
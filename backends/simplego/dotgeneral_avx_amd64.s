@@ -0,0 +1,350 @@
+//go:build !noasm && amd64
+
+// AVX-512F and AVX2 (+FMA) accelerated dot products for AMD64.
+// AVX-512F uses 512-bit vectors (16 x float32), AVX2 uses 256-bit vectors (8 x float32).
+// Both use multiple independent accumulators to hide the FMA latency.
+
+#include "textflag.h"
+
+// REDUCE_Z sums the 16 lanes of zmm register z into the lowest lane of its xmm register x,
+// using Y15/X15 as scratch.
+#define REDUCE_Z(z, y, x) \
+	VEXTRACTF64X4 $1, z, Y15; \
+	VADDPS        Y15, y, y;  \
+	VEXTRACTF128  $1, y, X15; \
+	VADDPS        X15, x, x;  \
+	VHADDPS       x, x, x;    \
+	VHADDPS       x, x, x
+
+// REDUCE_Y sums the 8 lanes of ymm register y into the lowest lane of its xmm register x,
+// using X15 as scratch.
+#define REDUCE_Y(y, x) \
+	VEXTRACTF128 $1, y, X15; \
+	VADDPS       X15, x, x;  \
+	VHADDPS      x, x, x;    \
+	VHADDPS      x, x, x
+
+// func dotProduct_avx512_asm(a, b unsafe.Pointer, n int64) float32
+TEXT ·dotProduct_avx512_asm(SB), NOSPLIT, $0-28
+	MOVQ a+0(FP), SI
+	MOVQ b+8(FP), DI
+	MOVQ n+16(FP), CX
+
+	// 4 accumulators.
+	VPXORD Z0, Z0, Z0
+	VPXORD Z1, Z1, Z1
+	VPXORD Z2, Z2, Z2
+	VPXORD Z3, Z3, Z3
+
+	CMPQ CX, $64
+	JL   loop16
+
+loop64:
+	// 64 floats per iteration, 16 per accumulator.
+	VMOVUPS     (SI), Z4
+	VMOVUPS     64(SI), Z5
+	VMOVUPS     128(SI), Z6
+	VMOVUPS     192(SI), Z7
+	VFMADD231PS (DI), Z4, Z0
+	VFMADD231PS 64(DI), Z5, Z1
+	VFMADD231PS 128(DI), Z6, Z2
+	VFMADD231PS 192(DI), Z7, Z3
+	ADDQ        $256, SI
+	ADDQ        $256, DI
+	SUBQ        $64, CX
+	CMPQ        CX, $64
+	JGE         loop64
+
+loop16:
+	CMPQ        CX, $16
+	JL          reduce
+	VMOVUPS     (SI), Z4
+	VFMADD231PS (DI), Z4, Z0
+	ADDQ        $64, SI
+	ADDQ        $64, DI
+	SUBQ        $16, CX
+	JMP         loop16
+
+reduce:
+	VADDPS Z1, Z0, Z0
+	VADDPS Z3, Z2, Z2
+	VADDPS Z2, Z0, Z0
+	REDUCE_Z(Z0, Y0, X0)
+
+	// Remaining 0-15 elements.
+	TESTQ CX, CX
+	JZ    done
+
+tail:
+	VMOVSS      (SI), X1
+	VFMADD231SS (DI), X1, X0
+	ADDQ        $4, SI
+	ADDQ        $4, DI
+	DECQ        CX
+	JNZ         tail
+
+done:
+	VZEROUPPER
+	MOVSS X0, ret+24(FP)
+	RET
+
+// func dotProductGroup4_avx512_asm(a, b unsafe.Pointer, b_stride, n int64) (r0, r1, r2, r3 float32)
+// Calculates 4 dot products sharing the same LHS (a).
+// b is the pointer to the first RHS vector. b_stride is the stride *in elements* (4 bytes) between RHS vectors.
+TEXT ·dotProductGroup4_avx512_asm(SB), NOSPLIT, $0-48
+	MOVQ a+0(FP), SI
+	MOVQ b+8(FP), DI
+	MOVQ b_stride+16(FP), DX
+	MOVQ n+24(FP), CX
+
+	// Pointers to b1, b2, b3.
+	SHLQ $2, DX
+	LEAQ (DI)(DX*1), R8
+	LEAQ (R8)(DX*1), R9
+	LEAQ (R9)(DX*1), R10
+
+	// 8 accumulators: Z0-Z3 for even and Z4-Z7 for odd 16-float chunks.
+	VPXORD Z0, Z0, Z0
+	VPXORD Z1, Z1, Z1
+	VPXORD Z2, Z2, Z2
+	VPXORD Z3, Z3, Z3
+	VPXORD Z4, Z4, Z4
+	VPXORD Z5, Z5, Z5
+	VPXORD Z6, Z6, Z6
+	VPXORD Z7, Z7, Z7
+
+	CMPQ CX, $32
+	JL   group4_loop16
+
+group4_loop32:
+	VMOVUPS     (SI), Z8
+	VMOVUPS     64(SI), Z9
+	VFMADD231PS (DI), Z8, Z0
+	VFMADD231PS (R8), Z8, Z1
+	VFMADD231PS (R9), Z8, Z2
+	VFMADD231PS (R10), Z8, Z3
+	VFMADD231PS 64(DI), Z9, Z4
+	VFMADD231PS 64(R8), Z9, Z5
+	VFMADD231PS 64(R9), Z9, Z6
+	VFMADD231PS 64(R10), Z9, Z7
+	ADDQ        $128, SI
+	ADDQ        $128, DI
+	ADDQ        $128, R8
+	ADDQ        $128, R9
+	ADDQ        $128, R10
+	SUBQ        $32, CX
+	CMPQ        CX, $32
+	JGE         group4_loop32
+
+group4_loop16:
+	CMPQ        CX, $16
+	JL          group4_reduce
+	VMOVUPS     (SI), Z8
+	VFMADD231PS (DI), Z8, Z0
+	VFMADD231PS (R8), Z8, Z1
+	VFMADD231PS (R9), Z8, Z2
+	VFMADD231PS (R10), Z8, Z3
+	ADDQ        $64, SI
+	ADDQ        $64, DI
+	ADDQ        $64, R8
+	ADDQ        $64, R9
+	ADDQ        $64, R10
+	SUBQ        $16, CX
+
+group4_reduce:
+	VADDPS Z4, Z0, Z0
+	VADDPS Z5, Z1, Z1
+	VADDPS Z6, Z2, Z2
+	VADDPS Z7, Z3, Z3
+	REDUCE_Z(Z0, Y0, X0)
+	REDUCE_Z(Z1, Y1, X1)
+	REDUCE_Z(Z2, Y2, X2)
+	REDUCE_Z(Z3, Y3, X3)
+
+	// Remaining 0-15 elements.
+	TESTQ CX, CX
+	JZ    group4_done
+
+group4_tail:
+	VMOVSS      (SI), X8
+	VFMADD231SS (DI), X8, X0
+	VFMADD231SS (R8), X8, X1
+	VFMADD231SS (R9), X8, X2
+	VFMADD231SS (R10), X8, X3
+	ADDQ        $4, SI
+	ADDQ        $4, DI
+	ADDQ        $4, R8
+	ADDQ        $4, R9
+	ADDQ        $4, R10
+	DECQ        CX
+	JNZ         group4_tail
+
+group4_done:
+	VZEROUPPER
+	MOVSS X0, r0+32(FP)
+	MOVSS X1, r1+36(FP)
+	MOVSS X2, r2+40(FP)
+	MOVSS X3, r3+44(FP)
+	RET
+
+// func dotProduct_avx2_asm(a, b unsafe.Pointer, n int64) float32
+TEXT ·dotProduct_avx2_asm(SB), NOSPLIT, $0-28
+	MOVQ a+0(FP), SI
+	MOVQ b+8(FP), DI
+	MOVQ n+16(FP), CX
+
+	// 4 accumulators.
+	VXORPS Y0, Y0, Y0
+	VXORPS Y1, Y1, Y1
+	VXORPS Y2, Y2, Y2
+	VXORPS Y3, Y3, Y3
+
+	CMPQ CX, $32
+	JL   avx2_loop8
+
+avx2_loop32:
+	// 32 floats per iteration, 8 per accumulator.
+	VMOVUPS     (SI), Y4
+	VMOVUPS     32(SI), Y5
+	VMOVUPS     64(SI), Y6
+	VMOVUPS     96(SI), Y7
+	VFMADD231PS (DI), Y4, Y0
+	VFMADD231PS 32(DI), Y5, Y1
+	VFMADD231PS 64(DI), Y6, Y2
+	VFMADD231PS 96(DI), Y7, Y3
+	ADDQ        $128, SI
+	ADDQ        $128, DI
+	SUBQ        $32, CX
+	CMPQ        CX, $32
+	JGE         avx2_loop32
+
+avx2_loop8:
+	CMPQ        CX, $8
+	JL          avx2_reduce
+	VMOVUPS     (SI), Y4
+	VFMADD231PS (DI), Y4, Y0
+	ADDQ        $32, SI
+	ADDQ        $32, DI
+	SUBQ        $8, CX
+	JMP         avx2_loop8
+
+avx2_reduce:
+	VADDPS Y1, Y0, Y0
+	VADDPS Y3, Y2, Y2
+	VADDPS Y2, Y0, Y0
+	REDUCE_Y(Y0, X0)
+
+	// Remaining 0-7 elements.
+	TESTQ CX, CX
+	JZ    avx2_done
+
+avx2_tail:
+	VMOVSS      (SI), X1
+	VFMADD231SS (DI), X1, X0
+	ADDQ        $4, SI
+	ADDQ        $4, DI
+	DECQ        CX
+	JNZ         avx2_tail
+
+avx2_done:
+	VZEROUPPER
+	MOVSS X0, ret+24(FP)
+	RET
+
+// func dotProductGroup4_avx2_asm(a, b unsafe.Pointer, b_stride, n int64) (r0, r1, r2, r3 float32)
+// Same as dotProductGroup4_avx512_asm, using 256-bit vectors.
+TEXT ·dotProductGroup4_avx2_asm(SB), NOSPLIT, $0-48
+	MOVQ a+0(FP), SI
+	MOVQ b+8(FP), DI
+	MOVQ b_stride+16(FP), DX
+	MOVQ n+24(FP), CX
+
+	// Pointers to b1, b2, b3.
+	SHLQ $2, DX
+	LEAQ (DI)(DX*1), R8
+	LEAQ (R8)(DX*1), R9
+	LEAQ (R9)(DX*1), R10
+
+	// 8 accumulators: Y0-Y3 for even and Y4-Y7 for odd 8-float chunks.
+	VXORPS Y0, Y0, Y0
+	VXORPS Y1, Y1, Y1
+	VXORPS Y2, Y2, Y2
+	VXORPS Y3, Y3, Y3
+	VXORPS Y4, Y4, Y4
+	VXORPS Y5, Y5, Y5
+	VXORPS Y6, Y6, Y6
+	VXORPS Y7, Y7, Y7
+
+	CMPQ CX, $16
+	JL   avx2_group4_loop8
+
+avx2_group4_loop16:
+	VMOVUPS     (SI), Y8
+	VMOVUPS     32(SI), Y9
+	VFMADD231PS (DI), Y8, Y0
+	VFMADD231PS (R8), Y8, Y1
+	VFMADD231PS (R9), Y8, Y2
+	VFMADD231PS (R10), Y8, Y3
+	VFMADD231PS 32(DI), Y9, Y4
+	VFMADD231PS 32(R8), Y9, Y5
+	VFMADD231PS 32(R9), Y9, Y6
+	VFMADD231PS 32(R10), Y9, Y7
+	ADDQ        $64, SI
+	ADDQ        $64, DI
+	ADDQ        $64, R8
+	ADDQ        $64, R9
+	ADDQ        $64, R10
+	SUBQ        $16, CX
+	CMPQ        CX, $16
+	JGE         avx2_group4_loop16
+
+avx2_group4_loop8:
+	CMPQ        CX, $8
+	JL          avx2_group4_reduce
+	VMOVUPS     (SI), Y8
+	VFMADD231PS (DI), Y8, Y0
+	VFMADD231PS (R8), Y8, Y1
+	VFMADD231PS (R9), Y8, Y2
+	VFMADD231PS (R10), Y8, Y3
+	ADDQ        $32, SI
+	ADDQ        $32, DI
+	ADDQ        $32, R8
+	ADDQ        $32, R9
+	ADDQ        $32, R10
+	SUBQ        $8, CX
+
+avx2_group4_reduce:
+	VADDPS Y4, Y0, Y0
+	VADDPS Y5, Y1, Y1
+	VADDPS Y6, Y2, Y2
+	VADDPS Y7, Y3, Y3
+	REDUCE_Y(Y0, X0)
+	REDUCE_Y(Y1, X1)
+	REDUCE_Y(Y2, X2)
+	REDUCE_Y(Y3, X3)
+
+	// Remaining 0-7 elements.
+	TESTQ CX, CX
+	JZ    avx2_group4_done
+
+avx2_group4_tail:
+	VMOVSS      (SI), X8
+	VFMADD231SS (DI), X8, X0
+	VFMADD231SS (R8), X8, X1
+	VFMADD231SS (R9), X8, X2
+	VFMADD231SS (R10), X8, X3
+	ADDQ        $4, SI
+	ADDQ        $4, DI
+	ADDQ        $4, R8
+	ADDQ        $4, R9
+	ADDQ        $4, R10
+	DECQ        CX
+	JNZ         avx2_group4_tail
+
+avx2_group4_done:
+	VZEROUPPER
+	MOVSS X0, r0+32(FP)
+	MOVSS X1, r1+36(FP)
+	MOVSS X2, r2+40(FP)
+	MOVSS X3, r3+44(FP)
+	RET
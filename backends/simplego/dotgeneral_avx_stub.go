@@ -0,0 +1,38 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build noasm || !amd64
+
+package simplego
+
+// hasAVX2 and hasAVX512 indicate whether the AVX2 and AVX-512F optimizations are available.
+// They are only available on AMD64, so they are always false on other platforms.
+const (
+	hasAVX2   = false
+	hasAVX512 = false
+)
+
+// dotProduct_avx512 stub for non-AMD64 platforms.
+func dotProduct_avx512(aSlice, bSlice []float32, aIdx, bIdx int, n int64) float32 {
+	panic("AVX-512 not available")
+}
+
+// dotProduct_avx2 stub for non-AMD64 platforms.
+func dotProduct_avx2(aSlice, bSlice []float32, aIdx, bIdx int, n int64) float32 {
+	panic("AVX2 not available")
+}
+
+// dotProductInnerLoopAVX512 stub for non-AMD64 platforms.
+func dotProductInnerLoopAVX512(lhsFlat, rhsFlat, outputFlat []float32,
+	lhsIdx, rhsIdx, outputIdx, blockDim int) (sum0, sum1, sum2, sum3 float32) {
+	// Should never be called since hasAVX512 will be false
+	panic("AVX-512 not available")
+}
+
+// dotProductInnerLoopAVX2 stub for non-AMD64 platforms.
+func dotProductInnerLoopAVX2(lhsFlat, rhsFlat, outputFlat []float32,
+	lhsIdx, rhsIdx, outputIdx, blockDim int) (sum0, sum1, sum2, sum3 float32) {
+	// Should never be called since hasAVX2 will be false
+	panic("AVX2 not available")
+}
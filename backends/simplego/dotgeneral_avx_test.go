@@ -0,0 +1,111 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !noasm && amd64
+
+package simplego
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// TestAVXDetection logs the AVX features detected.
+func TestAVXDetection(t *testing.T) {
+	t.Logf("AVX2=%v, AVX-512F=%v", hasAVX2, hasAVX512)
+	if hasAVX512 && !hasAVX2 {
+		t.Errorf("AVX-512F detected without AVX2")
+	}
+}
+
+// avxKernel holds the dot product kernels of one AVX level.
+type avxKernel struct {
+	dotProduct func(aSlice, bSlice []float32, aIdx, bIdx int, n int64) float32
+	innerLoop  func(lhsFlat, rhsFlat, outputFlat []float32, lhsIdx, rhsIdx, outputIdx, blockDim int) (sum0, sum1, sum2, sum3 float32)
+}
+
+// avxKernels returns the available AVX kernels, keyed by name.
+func avxKernels() map[string]avxKernel {
+	kernels := make(map[string]avxKernel)
+	if hasAVX2 {
+		kernels["AVX2"] = avxKernel{dotProduct_avx2, dotProductInnerLoopAVX2}
+	}
+	if hasAVX512 {
+		kernels["AVX512"] = avxKernel{dotProduct_avx512, dotProductInnerLoopAVX512}
+	}
+	return kernels
+}
+
+func requireClose(t *testing.T, name string, got, want float32) {
+	t.Helper()
+	tolerance := max(float32(math.Abs(float64(want)))*1e-5, 1e-4)
+	if diff := got - want; diff > tolerance || diff < -tolerance {
+		t.Errorf("%s: got %f, want %f", name, got, want)
+	}
+}
+
+// TestDotProductAVX tests the AVX dot products with sizes that exercise the unrolled, the vector and the tail loops.
+func TestDotProductAVX(t *testing.T) {
+	kernels := avxKernels()
+	if len(kernels) == 0 {
+		t.Skip("AVX2 not available on this system")
+	}
+	rng := rand.New(rand.NewSource(42))
+	for name, kernel := range kernels {
+		for _, size := range []int{1, 3, 7, 8, 15, 16, 17, 31, 32, 33, 63, 64, 65, 100, 128, 1000, 4096} {
+			t.Run(fmt.Sprintf("%s/size_%d", name, size), func(t *testing.T) {
+				// Use offsets to test unaligned access.
+				const offset = 3
+				a := make([]float32, size+offset)
+				b := make([]float32, size+offset)
+				for i := range a {
+					a[i] = rng.Float32()*2 - 1
+					b[i] = rng.Float32()*2 - 1
+				}
+				var want float32
+				for i := offset; i < size+offset; i++ {
+					want += a[i] * b[i]
+				}
+				requireClose(t, "dotProduct", kernel.dotProduct(a, b, offset, offset, int64(size)), want)
+			})
+		}
+	}
+}
+
+// TestDotProductInnerLoopAVX tests the Group4 kernels used by buildDotGeneralKernel.
+func TestDotProductInnerLoopAVX(t *testing.T) {
+	kernels := avxKernels()
+	if len(kernels) == 0 {
+		t.Skip("AVX2 not available on this system")
+	}
+	rng := rand.New(rand.NewSource(42))
+	for name, kernel := range kernels {
+		for _, blockDim := range []int{16, 24, 32, 40, 64, 128} {
+			t.Run(fmt.Sprintf("%s/blockDim_%d", name, blockDim), func(t *testing.T) {
+				lhs := make([]float32, blockDim)
+				rhs := make([]float32, 4*blockDim)
+				output := []float32{1, 2, 3, 4}
+				for i := range lhs {
+					lhs[i] = rng.Float32()
+				}
+				for i := range rhs {
+					rhs[i] = rng.Float32()
+				}
+				want := make([]float32, 4)
+				for vecIdx := range 4 {
+					want[vecIdx] = output[vecIdx]
+					for i := range blockDim {
+						want[vecIdx] += lhs[i] * rhs[vecIdx*blockDim+i]
+					}
+				}
+				s0, s1, s2, s3 := kernel.innerLoop(lhs, rhs, output, 0, 0, 0, blockDim)
+				for i, got := range []float32{s0, s1, s2, s3} {
+					requireClose(t, fmt.Sprintf("sum%d", i), got, want[i])
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"strings"
+
+	"github.com/gomlx/gomlx/backends/simplego/internal/cblas"
+	"github.com/gomlx/gomlx/pkg/core/shapes"
+	"github.com/gomlx/gopjrt/dtypes"
+	"github.com/pkg/errors"
+	"github.com/x448/float16"
+)
+
+// DotGeneralBLASMinWork is the minimum amount of work, measured in multiply-adds (M*N*K of each batch example),
+// of the matrix multiplications delegated to the BLAS library (OpenBLAS or MKL), when one is linked (see
+// BLASLibrary). Below it, the native kernels are faster, since they don't pay for the cgo call and the
+// synchronization of the BLAS threads.
+var DotGeneralBLASMinWork = 128 * 128 * 128
+
+// hasBLAS indicates whether a BLAS library is linked, see BLASLibrary.
+var hasBLAS = cblas.Library == cblas.OpenBLAS || cblas.Library == cblas.MKL
+
+// BLASLibrary returns the name of the BLAS library linked, "OpenBLAS" or "MKL", or "" if none.
+//
+// The BLAS libraries are only linked when building with cgo and the "openblas" or "mkl" build tags (e.g.:
+// go build -tags openblas), so the default build stays pure Go. The compiler and linker flags to find them can be
+// given with CGO_CFLAGS and CGO_LDFLAGS, and their number of threads with their usual environment variables
+// (OPENBLAS_NUM_THREADS or MKL_NUM_THREADS).
+func BLASLibrary() string {
+	if !hasBLAS {
+		return ""
+	}
+	return cblas.Library
+}
+
+// SetDotGeneralBLAS enables or disables delegating the float32 and float16 matrix multiplications
+// ([M, K] × [K, N] and [M, K] × [N, K], optionally batched) with at least DotGeneralBLASMinWork multiply-adds to
+// the cblas_sgemm of the BLAS library linked (see BLASLibrary). Smaller ones use the native kernels.
+//
+// It is enabled by default if a BLAS library is linked, and it can be disabled with the "dotgeneral_noblas"
+// configuration, see New. Enabling it returns an error if no BLAS library is linked.
+//
+// Float16 operands are converted to float32, multiplied and the result converted back to Float16.
+func (b *Backend) SetDotGeneralBLAS(enabled bool) error {
+	if enabled && !hasBLAS {
+		return errors.New("SetDotGeneralBLAS: no BLAS library linked, it requires building with cgo and the " +
+			"\"openblas\" or \"mkl\" build tags")
+	}
+	b.dotGeneralBLAS = enabled
+	return nil
+}
+
+// externalGEMMKernelName is the name of the kernel of the external library in profiles, see ProfileLabelKernel.
+var externalGEMMKernelName = strings.ToLower(cblas.Library)
+
+// useExternalGEMM returns whether a matrix multiplication [m, k] × [k, n] should use the cblas_sgemm of the
+// external library linked (Apple's Accelerate or BLAS), instead of the native kernels.
+func useExternalGEMM(backend *Backend, m, n, k int) bool {
+	return useAccelerate(backend, m, n, k) || (backend.dotGeneralBLAS && m*n*k >= DotGeneralBLASMinWork)
+}
+
+// gemmExternalFloat32 sets output = lhs·rhs (or lhs·rhsᵀ if rhsTransposed) for each of the batchSize examples,
+// with the cblas_sgemm of the external library: lhs is [batchSize, m, k], rhs is [batchSize, k, n] (or
+// [batchSize, n, k]) and output is [batchSize, m, n], all row-major.
+//
+// The external libraries parallelize each multiplication themselves, so the batch examples are executed
+// sequentially.
+func gemmExternalFloat32(batchSize, m, n, k int, lhs, rhs []float32, rhsTransposed bool, output []float32) {
+	ldb := n
+	if rhsTransposed {
+		ldb = k
+	}
+	for batchIdx := range batchSize {
+		cblas.Sgemm(rhsTransposed, m, n, k,
+			lhs[batchIdx*m*k:(batchIdx+1)*m*k], k,
+			rhs[batchIdx*k*n:(batchIdx+1)*k*n], ldb,
+			output[batchIdx*m*n:(batchIdx+1)*m*n], n)
+	}
+}
+
+// execDotGeneralExternalFloat16 executes a Float16 matrix multiplication with the cblas_sgemm of the external
+// library, converting the operands to float32 and the result back to Float16.
+func execDotGeneralExternalFloat16(backend *Backend, lhs, rhs *Buffer, params *dotGeneralNodeData, output *Buffer,
+	rhsTransposed bool) {
+	batchSize := params.batchSize
+	m, n, k := params.lhsCrossSize, params.rhsCrossSize, params.contractingSize
+
+	lhsFloat32 := backend.getBufferForShape(shapes.Make(dtypes.Float32, batchSize*m*k))
+	defer backend.putBuffer(lhsFloat32)
+	rhsFloat32 := backend.getBufferForShape(shapes.Make(dtypes.Float32, batchSize*k*n))
+	defer backend.putBuffer(rhsFloat32)
+	outputFloat32 := backend.getBufferForShape(shapes.Make(dtypes.Float32, batchSize*m*n))
+	defer backend.putBuffer(outputFloat32)
+
+	convertFloat16SliceToFloat32(lhs.flat.([]float16.Float16), lhsFloat32.flat.([]float32))
+	convertFloat16SliceToFloat32(rhs.flat.([]float16.Float16), rhsFloat32.flat.([]float32))
+	gemmExternalFloat32(batchSize, m, n, k, lhsFloat32.flat.([]float32), rhsFloat32.flat.([]float32),
+		rhsTransposed, outputFloat32.flat.([]float32))
+	convertFloat32SliceToFloat16(outputFloat32.flat.([]float32), output.flat.([]float16.Float16))
+}
@@ -0,0 +1,80 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/gomlx/gomlx/backends"
+	"github.com/gomlx/gomlx/pkg/core/graph"
+	"github.com/gomlx/gomlx/pkg/core/tensors"
+	"github.com/gomlx/gopjrt/dtypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDotGeneral_BLAS(t *testing.T) {
+	nativeBackend, err := New("dotgeneral_noblas")
+	require.NoError(t, err)
+	defer nativeBackend.Finalize()
+	if BLASLibrary() == "" {
+		require.False(t, backend.(*Backend).dotGeneralBLAS)
+		require.Error(t, backend.(*Backend).SetDotGeneralBLAS(true))
+		t.Skip("BLAS not available: it requires building with cgo and -tags openblas or -tags mkl")
+	}
+	blasBackend, err := New("")
+	require.NoError(t, err)
+	defer blasBackend.Finalize()
+	require.True(t, blasBackend.(*Backend).dotGeneralBLAS)
+	defer func(minWork int) { DotGeneralBLASMinWork = minWork }(DotGeneralBLASMinWork)
+	DotGeneralBLASMinWork = 1
+
+	t.Logf("BLAS library: %s", BLASLibrary())
+	requireDotGeneralMatches(t, nativeBackend, blasBackend)
+}
+
+// requireDotGeneralMatches checks that the float32 and float16 matrix multiplications of the tested backend, in the
+// layouts supported by the external libraries, match the ones of the reference backend.
+func requireDotGeneralMatches(t *testing.T, reference, tested backends.Backend) {
+	rng := rand.New(rand.NewSource(42))
+	const batchSize, m, n, k = 2, 33, 70, 45
+	lhsValues := make([]float32, batchSize*m*k)
+	for i := range lhsValues {
+		lhsValues[i] = rng.Float32()*2 - 1
+	}
+	rhsValues := make([]float32, batchSize*k*n)
+	for i := range rhsValues {
+		rhsValues[i] = rng.Float32()*2 - 1
+	}
+	for _, dtype := range []dtypes.DType{dtypes.Float32, dtypes.Float16} {
+		for _, rhsTransposed := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s/rhsTransposed=%v", dtype, rhsTransposed), func(t *testing.T) {
+				rhsDims, rhsContractingAxis := []int{batchSize, k, n}, 1
+				if rhsTransposed {
+					rhsDims, rhsContractingAxis = []int{batchSize, n, k}, 2
+				}
+				// dotGeneral returns the DotGeneral executed by the backend, converted to float32.
+				dotGeneral := func(backend backends.Backend) []float32 {
+					lhs := tensors.FromFlatDataAndDimensions(lhsValues, batchSize, m, k)
+					rhs := tensors.FromFlatDataAndDimensions(rhsValues, rhsDims...)
+					got := graph.MustExecOnce(backend, func(lhs, rhs *graph.Node) *graph.Node {
+						lhs, rhs = graph.ConvertDType(lhs, dtype), graph.ConvertDType(rhs, dtype)
+						output := graph.DotGeneral(lhs, []int{2}, []int{0}, rhs, []int{rhsContractingAxis}, []int{0})
+						return graph.ConvertDType(output, dtypes.Float32)
+					}, lhs, rhs)
+					require.NoError(t, got.Shape().Check(dtypes.Float32, batchSize, m, n))
+					return tensors.MustCopyFlatData[float32](got)
+				}
+				delta := 1e-4
+				if dtype == dtypes.Float16 {
+					// Both accumulate in float32, but the results are rounded to Float16.
+					delta = 1e-2
+				}
+				require.InDeltaSlice(t, dotGeneral(reference), dotGeneral(tested), delta)
+			})
+		}
+	}
+}
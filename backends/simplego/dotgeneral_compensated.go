@@ -0,0 +1,152 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"github.com/gomlx/gopjrt/dtypes"
+)
+
+// DotGeneralCompensatedMinK is the minimum size of the contraction (the product of the dimensions of the
+// contracting axes) for which DotGeneral uses compensated summation, when enabled with
+// Backend.SetDotGeneralCompensatedSummation.
+var DotGeneralCompensatedMinK = 4096
+
+// compensatedBlockK is the size of the blocks of the contraction summed by the float32 kernels in the compensated
+// summation: the (rounding) error of each block grows with compensatedBlockK, but not with the size of the
+// contraction.
+const compensatedBlockK = 512
+
+// SetDotGeneralCompensatedSummation enables or disables the compensated summation of the float32 DotGeneral with
+// long contractions (see DotGeneralCompensatedMinK). It can also be enabled with the "dotgeneral_compensated"
+// configuration, see New.
+//
+// The float32 kernels accumulate the products in float32, and their rounding error grows with the size of the
+// contraction K: with K in the tens of thousands, only 3 or 4 digits of the results may be correct. With the
+// compensated summation, the contraction is split in blocks of compensatedBlockK values, each summed by the usual
+// kernels, and the results of the blocks are accumulated with Kahan summation. So the error is bounded by the one
+// of a single block, independently of K, at a small cost in speed.
+//
+// It applies to Float32 DotGeneral, including the mixed-precision ones (see Builder.DotGeneral), but not to the
+// half-precision ones.
+func (b *Backend) SetDotGeneralCompensatedSummation(enabled bool) {
+	b.dotGeneralCompensated = enabled
+}
+
+// useCompensatedSummation returns whether the DotGeneral should use the compensated summation, see
+// Backend.SetDotGeneralCompensatedSummation.
+func useCompensatedSummation(backend *Backend, params *dotGeneralNodeData, outputDType dtypes.DType) bool {
+	return backend.dotGeneralCompensated && outputDType == dtypes.Float32 &&
+		params.contractingSize >= DotGeneralCompensatedMinK
+}
+
+// execDotGeneralCompensated executes a float32 DotGeneral with compensated summation, see
+// Backend.SetDotGeneralCompensatedSummation. The output must be zero-initialized.
+//
+//   - In the standard layout ([M, K] × [K, N]), the blocks of the contraction are multiplied by gemmFloat32 (or
+//     gemvFloat32 for single rows) into a scratch buffer, and then added to the output with Kahan summation.
+//   - Any other layout is normalized to [batchSize, crossSize, contractingSize] (if it is not already the case,
+//     as in [M, K] × [N, K]), and each output element is the Kahan sum of the dot products of the blocks.
+func execDotGeneralCompensated(backend *Backend, lhs, rhs *Buffer, params *dotGeneralNodeData, output *Buffer) {
+	batchSize := params.batchSize
+	lhsCrossSize := params.lhsCrossSize       // M
+	rhsCrossSize := params.rhsCrossSize       // N
+	contractingSize := params.contractingSize // K
+
+	lhsBatchStride := lhsCrossSize * contractingSize // M * K
+	rhsBatchStride := rhsCrossSize * contractingSize // N * K
+	outputBatchStride := lhsCrossSize * rhsCrossSize // M * N
+	outputFlat := output.flat.([]float32)
+
+	if isStandardMatmul(lhs.shape, rhs.shape,
+		params.lhsContractingAxes, params.rhsContractingAxes,
+		params.lhsBatchAxes, params.rhsBatchAxes) {
+		lhsFlat := lhs.flat.([]float32)
+		rhsFlat := rhs.flat.([]float32)
+		blockSizes := backend.gemmBlockSizes
+		parallelizeDotGeneral(backend, batchSize, lhsCrossSize, rhsCrossSize*contractingSize, func(batchIdx, rowStart, rowEnd int) {
+			for blockRowStart := rowStart; blockRowStart < rowEnd; blockRowStart += blockSizes.MC {
+				numRows := min(blockSizes.MC, rowEnd-blockRowStart)
+				lhsIdx := batchIdx*lhsBatchStride + blockRowStart*contractingSize
+				outputRows := outputFlat[batchIdx*outputBatchStride+blockRowStart*rhsCrossSize:][:numRows*rhsCrossSize]
+				partialBuf := getGemmPackedBuffer(len(outputRows))
+				compensationBuf := getGemmPackedBuffer(len(outputRows))
+				partial, compensation := *partialBuf, *compensationBuf
+				clear(compensation)
+				for k := 0; k < contractingSize; k += compensatedBlockK {
+					blockK := min(compensatedBlockK, contractingSize-k)
+					clear(partial)
+					rhsIdx := batchIdx*rhsBatchStride + k*rhsCrossSize
+					if numRows == 1 {
+						gemvFloat32(blockK, rhsCrossSize, lhsFlat, lhsIdx+k, rhsFlat, rhsIdx, rhsCrossSize, partial, 0)
+					} else {
+						gemmFloat32(blockSizes, numRows, rhsCrossSize, blockK,
+							lhsFlat, lhsIdx+k, contractingSize,
+							rhsFlat, rhsIdx, rhsCrossSize,
+							partial, 0, rhsCrossSize)
+					}
+					kahanAddFloat32(outputRows, compensation, partial)
+				}
+				gemmPackedPool.Put(partialBuf)
+				gemmPackedPool.Put(compensationBuf)
+			}
+		})
+		return
+	}
+
+	if !isTransposedRHSMatmul(lhs.shape, rhs.shape,
+		params.lhsContractingAxes, params.rhsContractingAxes,
+		params.lhsBatchAxes, params.rhsBatchAxes) {
+		normalizeFn := dotGeneralNormalizeShapeDTypeMap.Get(dtypes.Float32).(func(backend *Backend, source *Buffer, contractingAxes, batchAxes []int, batchSize, crossSize, contractingSize int) *Buffer)
+		if normalized := normalizeFn(backend, lhs, params.lhsContractingAxes, params.lhsBatchAxes,
+			batchSize, lhsCrossSize, contractingSize); normalized != nil {
+			lhs = normalized
+			defer backend.putBuffer(normalized)
+		}
+		if normalized := normalizeFn(backend, rhs, params.rhsContractingAxes, params.rhsBatchAxes,
+			batchSize, rhsCrossSize, contractingSize); normalized != nil {
+			rhs = normalized
+			defer backend.putBuffer(normalized)
+		}
+	}
+	lhsFlat := lhs.flat.([]float32)
+	rhsFlat := rhs.flat.([]float32)
+	parallelizeDotGeneral(backend, batchSize, lhsCrossSize, rhsCrossSize*contractingSize, func(batchIdx, rowStart, rowEnd int) {
+		for m := rowStart; m < rowEnd; m++ {
+			lhsRowIdx := batchIdx*lhsBatchStride + m*contractingSize
+			outputRow := outputFlat[batchIdx*outputBatchStride+m*rhsCrossSize:][:rhsCrossSize]
+			for n := range outputRow {
+				outputRow[n] = dotProductCompensatedFloat32(lhsFlat, rhsFlat,
+					lhsRowIdx, batchIdx*rhsBatchStride+n*contractingSize, contractingSize)
+			}
+		}
+	})
+}
+
+// kahanAddFloat32 adds values to sums, with the Kahan compensated summation: compensation holds the (negated)
+// low-order parts lost in the previous additions, and it is updated.
+func kahanAddFloat32(sums, compensation, values []float32) {
+	compensation = compensation[:len(sums)]
+	values = values[:len(sums)]
+	for i, sum := range sums {
+		y := values[i] - compensation[i]
+		t := sum + y
+		compensation[i] = (t - sum) - y
+		sums[i] = t
+	}
+}
+
+// dotProductCompensatedFloat32 returns the dot product of a[aIdx:aIdx+n] and b[bIdx:bIdx+n], as the Kahan sum of
+// the dot products of blocks of compensatedBlockK values.
+func dotProductCompensatedFloat32(a, b []float32, aIdx, bIdx, n int) float32 {
+	var sum, compensation float32
+	for k := 0; k < n; k += compensatedBlockK {
+		blockK := min(compensatedBlockK, n-k)
+		y := dotProductFloat32(a, b, aIdx+k, bIdx+k, blockK) - compensation
+		t := sum + y
+		compensation = (t - sum) - y
+		sum = t
+	}
+	return sum
+}
@@ -0,0 +1,80 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/gomlx/gomlx/pkg/core/graph"
+	"github.com/gomlx/gomlx/pkg/core/tensors"
+	"github.com/gomlx/gopjrt/dtypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDotGeneral_CompensatedSummation(t *testing.T) {
+	compensatedBackend, err := New("dotgeneral_compensated")
+	require.NoError(t, err)
+	defer compensatedBackend.Finalize()
+
+	// Positive values, so the sums grow, and the error with them.
+	rng := rand.New(rand.NewSource(42))
+	const m, n, k = 3, 5, 50_000
+	lhsValues := make([]float32, m*k)
+	for i := range lhsValues {
+		lhsValues[i] = rng.Float32()
+	}
+	rhsValues := make([]float32, k*n)
+	for i := range rhsValues {
+		rhsValues[i] = rng.Float32()
+	}
+
+	// layout 0 is the standard layout, 1 has the RHS transposed and 2 has the LHS transposed.
+	for layout := range 3 {
+		lhsDims, rhsDims := []int{m, k}, []int{k, n}
+		lhsIdx := func(i, p int) int { return i*k + p }
+		rhsIdx := func(p, j int) int { return p*n + j }
+		lhsContractingAxis, rhsContractingAxis := 1, 0
+		switch layout {
+		case 1:
+			rhsDims = []int{n, k}
+			rhsIdx = func(p, j int) int { return j*k + p }
+			rhsContractingAxis = 1
+		case 2:
+			lhsDims = []int{k, m}
+			lhsIdx = func(i, p int) int { return p*m + i }
+			lhsContractingAxis = 0
+		}
+		want := make([]float64, m*n)
+		for i := range m {
+			for j := range n {
+				for p := range k {
+					want[i*n+j] += float64(lhsValues[lhsIdx(i, p)]) * float64(rhsValues[rhsIdx(p, j)])
+				}
+			}
+		}
+		// maxRelativeError returns the largest relative error of the DotGeneral executed by the backend.
+		maxRelativeError := func(backend *Backend) float64 {
+			got := graph.MustExecOnce(backend, func(lhs, rhs *graph.Node) *graph.Node {
+				return graph.DotGeneral(lhs, []int{lhsContractingAxis}, nil, rhs, []int{rhsContractingAxis}, nil)
+			}, tensors.FromFlatDataAndDimensions(lhsValues, lhsDims...), tensors.FromFlatDataAndDimensions(rhsValues, rhsDims...))
+			require.NoError(t, got.Shape().Check(dtypes.Float32, m, n))
+			var maxError float64
+			for i, value := range tensors.MustCopyFlatData[float32](got) {
+				maxError = max(maxError, math.Abs(float64(value)-want[i])/want[i])
+			}
+			return maxError
+		}
+		t.Run(fmt.Sprintf("layout=%d", layout), func(t *testing.T) {
+			compensatedError := maxRelativeError(compensatedBackend.(*Backend))
+			defaultError := maxRelativeError(backend.(*Backend))
+			t.Logf("max relative error: default=%.3g, compensated=%.3g", defaultError, compensatedError)
+			require.Less(t, compensatedError, 1e-6)
+			require.LessOrEqual(t, compensatedError, defaultError)
+		})
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"math"
+
+	"github.com/gomlx/gopjrt/dtypes"
+)
+
+// The cost model estimates the execution time of the DotGeneral kernels from the dimensions of the problem, to
+// select the fastest one. The costs are relative to one multiply-add of the GEMM micro-kernel (see gemmFloat32),
+// for a single core: all native kernels parallelize over the rows (or columns) of the output in the same way.
+//
+// The relative costs were measured on x86-64 (AVX2), except for SME, and they are rough: they only need to get the
+// crossovers between the kernels approximately right.
+//
+// The external libraries (see useExternalGEMM) and Strassen (see useStrassen) are not part of the model: when
+// enabled, they are used above their own thresholds.
+const (
+	// costGEMMMulAdd is the cost of each multiply-add of the GEMM micro-kernel, including the padding of the tiles
+	// to multiples of gemmMR rows and gemmNR columns.
+	costGEMMMulAdd = 1.0
+
+	// costGEMMPack is the cost of packing each element of the operands of the GEMM.
+	costGEMMPack = 8.0
+
+	// costGEMVMulAdd is the cost of each multiply-add of the SIMD kernels of gemvFloat32, if the RHS fits in the
+	// cache (see costCacheBytes). Otherwise, it is memory-bound and costs costGEMVMulAddUncached.
+	costGEMVMulAdd         = 1.5
+	costGEMVMulAddUncached = 3.0
+
+	// costGEMVGoMulAdd is the cost of each multiply-add of the pure Go gemvBlockGo, used for the columns not in
+	// full blocks of gemvNB columns, or for all of them if there are no SIMD kernels.
+	costGEMVGoMulAdd = 8.0
+
+	// costScalarMulAdd is the cost of each multiply-add of the scalar loop of the fast path, with strided
+	// accesses to the RHS columns.
+	costScalarMulAdd = 18.0
+
+	// costSmallMulAdd is the cost of each multiply-add of execDotGeneralSmall.
+	costSmallMulAdd = 20.0
+
+	// costBlockedMulAdd is the cost of each multiply-add of the generic block kernel of execDotGeneralLarge,
+	// including the padding of the operands to multiples of the block dimension.
+	costBlockedMulAdd = 5.0
+
+	// costSMEMulAdd is the cost of each multiply-add of the SME outer products (see buildDotGeneralKernelSME), for
+	// the blocks of execDotGeneralLarge.
+	costSMEMulAdd = 0.125
+
+	// costBlockedCopy is the cost of copying each element into (or out of) blocks, in execDotGeneralLarge.
+	costBlockedCopy = 15.0
+
+	// costSMETranspose is the cost of transposing each element of the blocks (in the cache) for the SME kernel.
+	costSMETranspose = 2.0
+
+	// costBlockedFixed is the fixed cost of execDotGeneralLarge: allocation of the blocks and scheduling.
+	costBlockedFixed = 1000.0
+
+	// costCacheBytes is the size of the operands that are assumed to stay in the cache (about the L2 of a core).
+	costCacheBytes = 1 << 20
+)
+
+// matmulKernel is a kernel of the float32 matrix multiplications of the fast path, see selectMatmulKernel.
+type matmulKernel int
+
+const (
+	// matmulKernelGEMM is the cache-blocked gemmFloat32.
+	matmulKernelGEMM matmulKernel = iota
+
+	// matmulKernelGEMV is gemvFloat32, for each row of the LHS.
+	matmulKernelGEMV
+
+	// matmulKernelScalar is the scalar loop, with strided accesses to the RHS.
+	matmulKernelScalar
+
+	// matmulKernelBlocked is the normalized and blocked execDotGeneralLarge, only selected when it uses the SME
+	// kernels (see smeBlockedKernelAvailable).
+	matmulKernelBlocked
+)
+
+// String implements fmt.Stringer, with the names used in the profiles (see ProfileLabelKernel).
+func (k matmulKernel) String() string {
+	switch k {
+	case matmulKernelGEMM:
+		return "gemm"
+	case matmulKernelGEMV:
+		return "gemv"
+	case matmulKernelScalar:
+		return "scalar"
+	case matmulKernelBlocked:
+		return "large"
+	default:
+		return "unknown"
+	}
+}
+
+// smeBlockedKernelAvailable returns whether the blocks of execDotGeneralLarge are multiplied with the SME kernels
+// (see buildDotGeneralKernelSME) for the dtype.
+func smeBlockedKernelAvailable(dtype dtypes.DType) bool {
+	if !hasSME || dtype != dtypes.Float32 {
+		return false
+	}
+	blockDim := 1 << DotGeneralTargetBlockLog2Dim[dtype]
+	tileDim := 2 * smeVectorLanes
+	return tileDim > 0 && blockDim%tileDim == 0
+}
+
+// selectMatmulKernel returns the fastest kernel, according to the cost model, for a float32 matrix multiplication
+// of batchSize examples of [m, k] × [k, n] in the fast path. If sme is true, the blocked SME kernels are
+// considered as well.
+func selectMatmulKernel(batchSize, m, n, k int, sme bool) matmulKernel {
+	candidates := []matmulKernel{matmulKernelGEMM, matmulKernelGEMV, matmulKernelScalar}
+	if sme {
+		candidates = append(candidates, matmulKernelBlocked)
+	}
+	best, bestCost := matmulKernelGEMM, math.Inf(1)
+	for _, kernel := range candidates {
+		if cost := estimateMatmulCost(kernel, batchSize, m, n, k); cost < bestCost {
+			best, bestCost = kernel, cost
+		}
+	}
+	return best
+}
+
+// estimateMatmulCost estimates the cost of a float32 matrix multiplication of batchSize examples of [m, k] × [k, n]
+// with the kernel.
+func estimateMatmulCost(kernel matmulKernel, batchSize, m, n, k int) float64 {
+	mulAdds := float64(m) * float64(n) * float64(k)
+	var cost float64
+	switch kernel {
+	case matmulKernelGEMM:
+		padded := float64(roundUp(m, gemmMR)) * float64(roundUp(n, gemmNR)) * float64(k)
+		cost = padded*costGEMMMulAdd + float64(m*k+k*n)*costGEMMPack
+	case matmulKernelGEMV:
+		perMulAdd := costGEMVMulAdd
+		if k*n*dtypes.Float32.Size() > costCacheBytes {
+			perMulAdd = costGEMVMulAddUncached
+		}
+		simdColumns := 0
+		if hasAVX2 || hasAVX512 {
+			simdColumns = n / gemvNB * gemvNB
+		}
+		cost = float64(m) * float64(k) * (float64(simdColumns)*perMulAdd + float64(n-simdColumns)*costGEMVGoMulAdd)
+	case matmulKernelScalar:
+		cost = mulAdds * costScalarMulAdd
+	case matmulKernelBlocked:
+		return estimateBlockedCost(dtypes.Float32, batchSize, m, n, k, true)
+	}
+	return float64(batchSize) * cost
+}
+
+// estimateBlockedCost estimates the cost of execDotGeneralLarge for batchSize examples of [m, k] × [k, n], with the
+// SME kernels if sme is true.
+func estimateBlockedCost(dtype dtypes.DType, batchSize, m, n, k int, sme bool) float64 {
+	blockDim := 1 << DotGeneralTargetBlockLog2Dim[dtype]
+	paddedM, paddedN, paddedK := roundUp(m, blockDim), roundUp(n, blockDim), roundUp(k, blockDim)
+	perMulAdd := costBlockedMulAdd
+	if sme {
+		// Plus the transposition of both blocks for each block product.
+		perMulAdd = costSMEMulAdd + 2*costSMETranspose/float64(blockDim)
+	}
+	mulAdds := float64(paddedM) * float64(paddedN) * float64(paddedK)
+	copies := float64(paddedM*paddedK + paddedK*paddedN + paddedM*paddedN)
+	return float64(batchSize)*(mulAdds*perMulAdd+copies*costBlockedCopy) + costBlockedFixed
+}
+
+// selectDotGeneralProblemSize returns the fastest of execDotGeneralSmall and execDotGeneralLarge, according to the
+// cost model, for the DotGeneral with the normalized params.
+//
+// The blocked execDotGeneralLarge pads the operands to multiples of the block dimension, so it is slower for short
+// contractions or few rows or columns, even if the output is large.
+func selectDotGeneralProblemSize(dtype dtypes.DType, params *dotGeneralNodeData) dotGeneralProblemSizeType {
+	batchSize, m, n, k := params.batchSize, params.lhsCrossSize, params.rhsCrossSize, params.contractingSize
+	smallCost := float64(batchSize) * float64(m) * float64(n) * float64(k) * costSmallMulAdd
+	if estimateBlockedCost(dtype, batchSize, m, n, k, smeBlockedKernelAvailable(dtype)) < smallCost {
+		return largeProblemSize
+	}
+	return smallProblemSize
+}
@@ -0,0 +1,89 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gomlx/gomlx/pkg/core/graph"
+	"github.com/gomlx/gomlx/pkg/core/tensors"
+	"github.com/gomlx/gomlx/pkg/support/xslices"
+	"github.com/gomlx/gopjrt/dtypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectMatmulKernel(t *testing.T) {
+	for _, tc := range []struct {
+		batchSize, m, n, k int
+		sme                bool
+		want               matmulKernel
+	}{
+		{1, 1, 4096, 4096, false, matmulKernelGEMV},  // Decoder step.
+		{1, 3, 1024, 1024, false, matmulKernelGEMV},  // Few rows.
+		{8, 4, 256, 256, false, matmulKernelGEMV},    // Few rows, RHS in the cache.
+		{1, 64, 1024, 1024, false, matmulKernelGEMM}, // Many rows.
+		{1, 512, 4, 512, false, matmulKernelGEMM},    // Narrow output: padding the tiles is cheaper.
+		{1, 1024, 1024, 1024, true, matmulKernelBlocked},
+		{1, 16, 16, 16, true, matmulKernelGEMM}, // Too small for the blocking.
+		{1, 1, 4096, 4096, true, matmulKernelGEMV},
+	} {
+		if tc.want == matmulKernelGEMV && tc.m > 1 && !hasAVX2 {
+			// Without the SIMD GEMV kernels, only single rows use GEMV.
+			tc.want = matmulKernelGEMM
+		}
+		got := selectMatmulKernel(tc.batchSize, tc.m, tc.n, tc.k, tc.sme)
+		require.Equalf(t, tc.want, got, "%+v: got %s", tc, got)
+	}
+}
+
+func TestSelectDotGeneralProblemSize(t *testing.T) {
+	for _, tc := range []struct {
+		batchSize, m, n, k int
+		want               dotGeneralProblemSizeType
+	}{
+		{1, 8, 8, 8, smallProblemSize},
+		{1, 1024, 1024, 4, smallProblemSize}, // Short contraction: padding it to the block dimension dominates.
+		{1, 8, 512, 512, smallProblemSize},   // Few rows.
+		{1, 128, 128, 128, largeProblemSize},
+		{1, 64, 64, 4096, largeProblemSize}, // Long contraction.
+		{16, 256, 256, 64, largeProblemSize},
+	} {
+		params := &dotGeneralNodeData{batchSize: tc.batchSize, lhsCrossSize: tc.m, rhsCrossSize: tc.n,
+			contractingSize: tc.k}
+		got := selectDotGeneralProblemSize(dtypes.Float32, params)
+		if hasSME && tc.want == smallProblemSize {
+			// The SME kernels make the blocked algorithm cheaper.
+			continue
+		}
+		require.Equalf(t, tc.want, got, "%+v: got %s", tc, got)
+	}
+}
+
+func TestDotGeneral_FastPathGEMVRows(t *testing.T) {
+	for _, dims := range [][4]int{{2, 3, 4, 100}, {1, 2, 1000, 8}, {3, 5, 7, 130}} {
+		B, M, K, N := dims[0], dims[1], dims[2], dims[3]
+		t.Run(fmt.Sprintf("B=%d_M=%d_K=%d_N=%d", B, M, K, N), func(t *testing.T) {
+			lhsFlat := xslices.Iota(float32(0), B*M*K)
+			rhsFlat := xslices.Iota(float32(1), B*K*N)
+			got := graph.MustExecOnce(backend, func(lhs, rhs *graph.Node) *graph.Node {
+				return graph.Einsum("bmk,bkn->bmn", lhs, rhs)
+			}, tensors.FromFlatDataAndDimensions(lhsFlat, B, M, K), tensors.FromFlatDataAndDimensions(rhsFlat, B, K, N))
+			want := make([]float32, B*M*N)
+			for b := range B {
+				for m := range M {
+					for n := range N {
+						var sum float64
+						for k := range K {
+							sum += float64(lhsFlat[(b*M+m)*K+k]) * float64(rhsFlat[(b*K+k)*N+n])
+						}
+						want[(b*M+m)*N+n] = float32(sum)
+					}
+				}
+			}
+			require.True(t, got.InDelta(tensors.FromFlatDataAndDimensions(want, B, M, N), 1e-6*float64(want[len(want)-1])))
+		})
+	}
+}
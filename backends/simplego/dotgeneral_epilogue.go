@@ -0,0 +1,334 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"math"
+	"slices"
+
+	"github.com/gomlx/gomlx/backends"
+	"github.com/gomlx/gomlx/pkg/core/shapes"
+	"github.com/gomlx/gomlx/pkg/support/sets"
+	"github.com/gomlx/gopjrt/dtypes"
+)
+
+// dotGeneralActivation is an activation function applied by the epilogue of a fused DotGeneral.
+type dotGeneralActivation int
+
+const (
+	activationNone dotGeneralActivation = iota
+
+	// activationReLU is max(x, 0), as in activations.Relu.
+	activationReLU
+
+	// activationSiLU is x * sigmoid(x), as in activations.Swish.
+	activationSiLU
+
+	// activationGELU is the exact x * 0.5 * (1 + erf(x/√2)), as in activations.Gelu.
+	activationGELU
+)
+
+// dotGeneralEpilogue describes the operations fused into a DotGeneral (see Builder.fuseDotGeneralEpilogues), applied
+// to the output of the matrix multiplication: first a bias is added (if hasBias) and then the activation.
+//
+// A fused DotGeneral node has the shape of the last fused operation, and its inputs are the lhs, the rhs and, if
+// hasBias, the bias, with as many values as the last dimension of the output.
+type dotGeneralEpilogue struct {
+	hasBias    bool
+	activation dotGeneralActivation
+
+	// outputShape is the shape of the normalized DotGeneral output ([batchSize, lhsCrossSize, rhsCrossSize]).
+	outputShape shapes.Shape
+}
+
+// float32Epilogue is a dotGeneralEpilogue bound to the bias of one execution of a float32 DotGeneral. A nil
+// *float32Epilogue is a no-op.
+type float32Epilogue struct {
+	backend    *Backend
+	activation dotGeneralActivation
+
+	// bias has one value per column (the last dimension) of the output, or it is nil.
+	bias []float32
+}
+
+// newFloat32Epilogue returns the epilogue to apply to the output of the DotGeneral node, or nil if it has none.
+func newFloat32Epilogue(backend *Backend, params *dotGeneralNodeData, inputs []*Buffer) *float32Epilogue {
+	if params.epilogue == nil {
+		return nil
+	}
+	ep := &float32Epilogue{backend: backend, activation: params.epilogue.activation}
+	if params.epilogue.hasBias {
+		ep.bias = inputs[2].flat.([]float32)
+	}
+	return ep
+}
+
+// dotGeneralEpilogueChunkSize is the number of values the activations of the epilogue are computed at a time,
+// using a scratch buffer that stays in the L1 cache.
+const dotGeneralEpilogueChunkSize = 512
+
+// geluInvSqrt2 is the constant used by activations.Gelu: it is matched exactly by the fusion.
+const geluInvSqrt2 = float32(1 / math.Sqrt2)
+
+// apply the epilogue to values, a range of the row-major output that starts at column colStart: the values are
+// rows of len(bias) columns.
+//
+// It is called by the DotGeneral kernels on the parts of the output they have just computed, while they are still
+// in cache.
+func (ep *float32Epilogue) apply(values []float32, colStart int) {
+	if ep == nil {
+		return
+	}
+	if ep.bias == nil {
+		ep.applySegment(values, nil)
+		return
+	}
+	numCols := len(ep.bias)
+	col := colStart
+	for len(values) > 0 {
+		segmentLen := min(len(values), numCols-col)
+		ep.applySegment(values[:segmentLen], ep.bias[col:col+segmentLen])
+		values = values[segmentLen:]
+		col = 0
+	}
+}
+
+// applySegment applies the epilogue to values, with the corresponding bias (if not nil).
+func (ep *float32Epilogue) applySegment(values, bias []float32) {
+	if bias != nil {
+		for ii, b := range bias[:len(values)] {
+			values[ii] += b
+		}
+	}
+	switch ep.activation {
+	case activationReLU:
+		for ii, value := range values {
+			values[ii] = max(value, 0)
+		}
+	case activationSiLU:
+		var scratch [dotGeneralEpilogueChunkSize]float32
+		for start := 0; start < len(values); start += dotGeneralEpilogueChunkSize {
+			chunk := values[start:min(start+dotGeneralEpilogueChunkSize, len(values))]
+			sigmoid := scratch[:len(chunk)]
+			execLogisticFloat32(chunk, sigmoid)
+			for ii, s := range sigmoid {
+				chunk[ii] *= s
+			}
+		}
+	case activationGELU:
+		var scratch [dotGeneralEpilogueChunkSize]float32
+		for start := 0; start < len(values); start += dotGeneralEpilogueChunkSize {
+			chunk := values[start:min(start+dotGeneralEpilogueChunkSize, len(values))]
+			erf := scratch[:len(chunk)]
+			for ii, value := range chunk {
+				erf[ii] = value * geluInvSqrt2
+			}
+			execErfFloat32(ep.backend, erf, erf)
+			for ii, e := range erf {
+				chunk[ii] *= (e + 1) * 0.5
+			}
+		}
+	}
+}
+
+// fuseDotGeneralEpilogues finds the float32 DotGeneral nodes followed by the addition of a bias and/or an
+// activation (ReLU, SiLU or GELU), as created by layers.Dense followed by one of the activations, and fuses them
+// into the DotGeneral (see dotGeneralEpilogue).
+//
+// The last node of each fused pattern is converted in place to the fused DotGeneral, so the nodes that use it are
+// not affected, and the intermediary nodes are no longer used. A pattern is only fused if its intermediary nodes are
+// not used elsewhere.
+//
+// It is called by Builder.Compile, and it can be disabled with the "dotgeneral_nofusion" backend configuration.
+func (b *Builder) fuseDotGeneralEpilogues() {
+	consumers := make([][]*Node, len(b.nodes))
+	for _, node := range b.nodes {
+		for _, input := range node.inputs {
+			consumers[input.builderIdx] = append(consumers[input.builderIdx], node)
+		}
+	}
+	outputs := sets.MakeWith(b.outputs...)
+	fused := sets.Make[*Node]()
+
+	// Visit the nodes backwards, so the largest pattern (with the activation) is matched first.
+	for _, root := range slices.Backward(b.nodes) {
+		if fused.Has(root) || root.shape.DType != dtypes.Float32 || root.IsMultiOutputs() || root.isNodeSelectOutput {
+			continue
+		}
+		pattern := &dotGeneralPattern{}
+		if !pattern.match(root) {
+			continue
+		}
+		isFusable := true
+		for _, node := range pattern.intermediary {
+			if outputs.Has(node) {
+				isFusable = false
+				break
+			}
+			for _, consumer := range consumers[node.builderIdx] {
+				if consumer != root && !slices.Contains(pattern.intermediary, consumer) {
+					isFusable = false
+					break
+				}
+			}
+		}
+		if !isFusable {
+			continue
+		}
+
+		dotGeneral := pattern.dotGeneral
+		params := *dotGeneral.data.(*dotGeneralNodeData)
+		params.epilogue = &dotGeneralEpilogue{
+			hasBias:     pattern.bias != nil,
+			activation:  pattern.activation,
+			outputShape: dotGeneral.shape,
+		}
+		root.opType = backends.OpTypeDotGeneral
+		root.inputs = slices.Clone(dotGeneral.inputs)
+		if pattern.bias != nil {
+			root.inputs = append(root.inputs, pattern.bias)
+		}
+		root.data = &params
+		for _, node := range pattern.intermediary {
+			fused.Insert(node)
+		}
+	}
+}
+
+// dotGeneralPattern is a DotGeneral followed by an epilogue matched by fuseDotGeneralEpilogues.
+type dotGeneralPattern struct {
+	dotGeneral, bias *Node
+	activation       dotGeneralActivation
+
+	// intermediary nodes of the pattern, that are no longer used once it is fused.
+	intermediary []*Node
+}
+
+// match tries to match the pattern ending at root.
+func (p *dotGeneralPattern) match(root *Node) bool {
+	x := p.matchActivation(root)
+	if x != root {
+		p.intermediary = append(p.intermediary, x)
+	}
+	if x.opType == backends.OpTypeAdd && x.shape.Rank() > 0 {
+		numCols := x.shape.Dimensions[x.shape.Rank()-1]
+		for _, operands := range [][2]*Node{{x.inputs[0], x.inputs[1]}, {x.inputs[1], x.inputs[0]}} {
+			value, bias := operands[0], operands[1]
+			if value.shape.Equal(x.shape) && bias.shape.DType == dtypes.Float32 && bias.shape.Size() == numCols &&
+				bias.shape.Rank() > 0 && bias.shape.Dimensions[bias.shape.Rank()-1] == numCols {
+				p.bias = bias
+				x = value
+				p.intermediary = append(p.intermediary, x)
+				break
+			}
+		}
+	}
+	if p.bias == nil && p.activation == activationNone {
+		return false
+	}
+
+	// Reshapes don't change the layout of the values.
+	for x.opType == backends.OpTypeReshape {
+		x = x.inputs[0]
+		p.intermediary = append(p.intermediary, x)
+	}
+	if x.opType != backends.OpTypeDotGeneral || x.shape.DType != dtypes.Float32 ||
+		x.data.(*dotGeneralNodeData).epilogue != nil {
+		return false
+	}
+	// The bias is applied to each row of rhsCrossSize values of the DotGeneral output, which can differ from the
+	// last axis of the Add after a Reshape (e.g. [3, 5] -> [1, 15]), or with multiple RHS cross axes.
+	if p.bias != nil && p.bias.shape.Size() != x.data.(*dotGeneralNodeData).rhsCrossSize {
+		return false
+	}
+	p.dotGeneral = x
+	return true
+}
+
+// matchActivation matches the supported activations ending at root, and it returns the node they are applied to.
+// If none is matched, it returns root itself.
+func (p *dotGeneralPattern) matchActivation(root *Node) *Node {
+	switch root.opType {
+	case backends.OpTypeMax:
+		// ReLU: Max(x, 0).
+		if x, zero := commutativeOperands(root, isConstantFloat32(0)); zero != nil && x.shape.Equal(root.shape) {
+			p.activation = activationReLU
+			return x
+		}
+
+	case backends.OpTypeMul:
+		for _, operands := range [][2]*Node{{root.inputs[0], root.inputs[1]}, {root.inputs[1], root.inputs[0]}} {
+			x, other := operands[0], operands[1]
+			if !x.shape.Equal(root.shape) {
+				continue
+			}
+			// SiLU: Mul(x, Logistic(x)).
+			if other.opType == backends.OpTypeLogistic && other.inputs[0] == x {
+				p.activation = activationSiLU
+				p.intermediary = append(p.intermediary, other)
+				return x
+			}
+			// GELU: Mul(x, Mul(Add(Erf(Mul(x, 1/√2)), 1), 0.5)).
+			if other.opType != backends.OpTypeMul {
+				continue
+			}
+			sum, half := commutativeOperands(other, isConstantFloat32(0.5))
+			if half == nil || sum.opType != backends.OpTypeAdd {
+				continue
+			}
+			erf, one := commutativeOperands(sum, isConstantFloat32(1))
+			if one == nil || erf.opType != backends.OpTypeErf {
+				continue
+			}
+			scaled := erf.inputs[0]
+			if scaled.opType != backends.OpTypeMul {
+				continue
+			}
+			if scaledX, scale := commutativeOperands(scaled, isConstantFloat32(geluInvSqrt2)); scale != nil && scaledX == x {
+				p.activation = activationGELU
+				p.intermediary = append(p.intermediary, other, sum, erf, scaled)
+				return x
+			}
+		}
+	}
+	return root
+}
+
+// commutativeOperands returns the operands of the binary node, such that the second one satisfies the predicate.
+// If none does, the second operand returned is nil.
+func commutativeOperands(node *Node, predicate func(*Node) bool) (operand, other *Node) {
+	if predicate(node.inputs[1]) {
+		return node.inputs[0], node.inputs[1]
+	}
+	if predicate(node.inputs[0]) {
+		return node.inputs[1], node.inputs[0]
+	}
+	return nil, nil
+}
+
+// isConstantFloat32 returns a predicate of whether a node is a float32 constant with all values equal to value,
+// possibly broadcast or reshaped.
+func isConstantFloat32(value float32) func(*Node) bool {
+	var predicate func(node *Node) bool
+	predicate = func(node *Node) bool {
+		switch node.opType {
+		case backends.OpTypeBroadcast, backends.OpTypeBroadcastInDim, backends.OpTypeReshape:
+			return predicate(node.inputs[0])
+		case backends.OpTypeConstant:
+			flat, ok := node.data.(*Buffer).flat.([]float32)
+			if !ok {
+				return false
+			}
+			for _, v := range flat {
+				if v != value {
+					return false
+				}
+			}
+			return true
+		}
+		return false
+	}
+	return predicate
+}
@@ -0,0 +1,181 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/gomlx/gomlx/backends"
+	"github.com/gomlx/gomlx/pkg/core/graph"
+	"github.com/gomlx/gomlx/pkg/core/shapes"
+	"github.com/gomlx/gomlx/pkg/core/tensors"
+	"github.com/gomlx/gomlx/pkg/ml/layers/activations"
+	"github.com/gomlx/gopjrt/dtypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDotGeneralEpilogue(t *testing.T) {
+	noFusionBackend, err := New("dotgeneral_nofusion")
+	require.NoError(t, err)
+	defer noFusionBackend.Finalize()
+
+	activationFns := map[string]func(*graph.Node) *graph.Node{
+		"none":  func(x *graph.Node) *graph.Node { return x },
+		"relu":  activations.Relu,
+		"swish": activations.Swish,
+		"gelu":  activations.Gelu,
+	}
+	for _, dims := range [][3]int{{1, 64, 300}, {7, 16, 5}, {33, 40, 70}, {65, 128, 1000}} {
+		lhsRows, contractingSize, numCols := dims[0], dims[1], dims[2]
+		lhs := tensors.FromShape(shapes.Make(dtypes.Float32, lhsRows, contractingSize))
+		tensors.MutableFlatData(lhs, func(flat []float32) {
+			for ii := range flat {
+				flat[ii] = float32(math.Sin(float64(ii)))
+			}
+		})
+		rhs := tensors.FromShape(shapes.Make(dtypes.Float32, contractingSize, numCols))
+		tensors.MutableFlatData(rhs, func(flat []float32) {
+			for ii := range flat {
+				flat[ii] = float32(math.Cos(float64(ii))) / float32(contractingSize)
+			}
+		})
+		bias := tensors.FromShape(shapes.Make(dtypes.Float32, numCols))
+		tensors.MutableFlatData(bias, func(flat []float32) {
+			for ii := range flat {
+				flat[ii] = float32(ii%7) - 3
+			}
+		})
+		for name, activationFn := range activationFns {
+			for _, useBias := range []bool{true, false} {
+				t.Run(fmt.Sprintf("%dx%dx%d/%s/bias=%v", lhsRows, contractingSize, numCols, name, useBias), func(t *testing.T) {
+					// Same graph as a layers.Dense followed by the activation.
+					denseFn := func(lhs, rhs, bias *graph.Node) *graph.Node {
+						output := graph.Dot(lhs, rhs)
+						if useBias {
+							output = graph.Add(output, graph.Reshape(bias, 1, numCols))
+						}
+						return activationFn(output)
+					}
+					want := graph.MustExecOnce(noFusionBackend, denseFn, lhs.Value(), rhs.Value(), bias.Value())
+					got := graph.MustExecOnce(backend, denseFn, lhs.Value(), rhs.Value(), bias.Value())
+					requireSameTensorsFloat32(t, want, got, 1e-5)
+				})
+			}
+		}
+	}
+}
+
+func TestDotGeneralEpilogueBiasLayout(t *testing.T) {
+	noFusionBackend, err := New("dotgeneral_nofusion")
+	require.NoError(t, err)
+	defer noFusionBackend.Finalize()
+
+	iota := func(dims ...int) *tensors.Tensor {
+		values := tensors.FromShape(shapes.Make(dtypes.Float32, dims...))
+		tensors.MutableFlatData(values, func(flat []float32) {
+			for ii := range flat {
+				flat[ii] = float32(ii)
+			}
+		})
+		return values
+	}
+	testCases := []struct {
+		name           string
+		lhs, rhs, bias *tensors.Tensor
+		graphFn        func(lhs, rhs, bias *graph.Node) *graph.Node
+	}{
+		{
+			// The rows of 5 values of the DotGeneral are merged into a single row of 15 by the Reshape.
+			name: "reshaped_rows",
+			lhs:  iota(3, 4), rhs: iota(5, 4), bias: iota(1, 15),
+			graphFn: func(lhs, rhs, bias *graph.Node) *graph.Node {
+				dot := graph.DotGeneral(lhs, []int{1}, nil, rhs, []int{1}, nil)
+				return graph.Add(graph.Reshape(dot, 1, 15), bias)
+			},
+		},
+		{
+			// Rows of 15 values (2 RHS cross axes), with a bias of the last axis only.
+			name: "multiple_rhs_cross_axes",
+			lhs:  iota(3, 4), rhs: iota(4, 3, 5), bias: iota(5),
+			graphFn: func(lhs, rhs, bias *graph.Node) *graph.Node {
+				dot := graph.DotGeneral(lhs, []int{1}, nil, rhs, []int{0}, nil)
+				return graph.Add(dot, graph.Reshape(bias, 1, 1, 5))
+			},
+		},
+		{
+			// Reshapes that keep the rows can be fused.
+			name: "reshaped_batch",
+			lhs:  iota(2, 3, 4), rhs: iota(4, 5), bias: iota(5),
+			graphFn: func(lhs, rhs, bias *graph.Node) *graph.Node {
+				dot := graph.DotGeneral(lhs, []int{2}, nil, rhs, []int{0}, nil)
+				return graph.Add(graph.Reshape(dot, 6, 5), graph.Reshape(bias, 1, 5))
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			want := graph.MustExecOnce(noFusionBackend, tc.graphFn, tc.lhs.Value(), tc.rhs.Value(), tc.bias.Value())
+			got := graph.MustExecOnce(backend, tc.graphFn, tc.lhs.Value(), tc.rhs.Value(), tc.bias.Value())
+			requireSameTensorsFloat32(t, want, got, 1e-5)
+		})
+	}
+}
+
+func TestFuseDotGeneralEpilogues(t *testing.T) {
+	S := shapes.Make
+	F32 := dtypes.Float32
+	const numRows, contractingSize, numCols = 3, 4, 5
+
+	// buildDenseRelu builds Max(Add(Dot(lhs, rhs), bias), 0), and it returns the Add and the Max nodes.
+	buildDenseRelu := func(builder *Builder) (add, relu backends.Op) {
+		lhs, err := builder.Parameter("lhs", S(F32, numRows, contractingSize), nil)
+		require.NoError(t, err)
+		rhs, err := builder.Parameter("rhs", S(F32, contractingSize, numCols), nil)
+		require.NoError(t, err)
+		bias, err := builder.Parameter("bias", S(F32, numCols), nil)
+		require.NoError(t, err)
+		dot, err := builder.Dot(lhs, rhs)
+		require.NoError(t, err)
+		bias, err = builder.Reshape(bias, 1, numCols)
+		require.NoError(t, err)
+		add, err = builder.Add(dot, bias)
+		require.NoError(t, err)
+		zero, err := builder.Constant([]float32{0}, 1)
+		require.NoError(t, err)
+		zero, err = builder.BroadcastInDim(zero, S(F32, numRows, numCols), []int{1})
+		require.NoError(t, err)
+		relu, err = builder.Max(add, zero)
+		require.NoError(t, err)
+		return add, relu
+	}
+
+	// Fused: the Max node becomes a DotGeneral.
+	builder := backend.Builder("fused").(*Builder)
+	_, relu := buildDenseRelu(builder)
+	_, err := builder.Compile([]backends.Op{relu}, nil)
+	require.NoError(t, err)
+	node := relu.(*Node)
+	require.Equal(t, backends.OpTypeDotGeneral, node.opType)
+	require.Len(t, node.inputs, 3)
+	require.NoError(t, node.shape.Check(F32, numRows, numCols))
+	epilogue := node.data.(*dotGeneralNodeData).epilogue
+	require.NotNil(t, epilogue)
+	require.True(t, epilogue.hasBias)
+	require.Equal(t, activationReLU, epilogue.activation)
+
+	// The Add is also an output, so only it is fused, and the Max is left as is.
+	builder = backend.Builder("partially_fused").(*Builder)
+	add, relu := buildDenseRelu(builder)
+	_, err = builder.Compile([]backends.Op{add, relu}, nil)
+	require.NoError(t, err)
+	require.Equal(t, backends.OpTypeMax, relu.(*Node).opType)
+	node = add.(*Node)
+	require.Equal(t, backends.OpTypeDotGeneral, node.opType)
+	epilogue = node.data.(*dotGeneralNodeData).epilogue
+	require.True(t, epilogue.hasBias)
+	require.Equal(t, activationNone, epilogue.activation)
+}
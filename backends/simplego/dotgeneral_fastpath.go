@@ -1,6 +1,9 @@
 package simplego
 
 import (
+	"runtime"
+	"sync"
+
 	"github.com/gomlx/gopjrt/dtypes"
 	"github.com/gomlx/gomlx/pkg/core/shapes"
 )
@@ -11,7 +14,8 @@ import (
 // Standard patterns that can skip normalization:
 // 1. Matrix × Matrix: [M, K] × [K, N] → [M, N] (contracting on last axis of lhs, first of rhs)
 // 2. Matrix × Vector: [M, K] × [K] → [M] (contracting on last axis of lhs, only axis of rhs)
-// 3. Batched MatMul: [B, M, K] × [B, K, N] → [B, M, N] (batch on first axis)
+// 3. Vector × Matrix: [K] × [K, N] → [N] (contracting on only axis of lhs, first of rhs)
+// 4. Batched MatMul: [B, M, K] × [B, K, N] → [B, M, N] (batch on first axis)
 //
 // Returns true if we can use the fast path (no transpose needed).
 func isStandardMatmul(lhsShape, rhsShape shapes.Shape, lhsContractingAxes, rhsContractingAxes, lhsBatchAxes, rhsBatchAxes []int) bool {
@@ -38,6 +42,16 @@ func isStandardMatmul(lhsShape, rhsShape shapes.Shape, lhsContractingAxes, rhsCo
 		}
 	}
 
+	// Check for vector-matrix multiplication: [K] × [K, N]
+	if lhsRank == 1 && rhsRank == 2 &&
+		len(lhsContractingAxes) == 1 && len(rhsContractingAxes) == 1 &&
+		len(lhsBatchAxes) == 0 && len(rhsBatchAxes) == 0 {
+		// Contracting: lhs only axis (0) with rhs first axis (0)
+		if lhsContractingAxes[0] == 0 && rhsContractingAxes[0] == 0 {
+			return true
+		}
+	}
+
 	// Check for batched matrix multiplication: [B, M, K] × [B, K, N]
 	if lhsRank == 3 && rhsRank == 3 &&
 		len(lhsContractingAxes) == 1 && len(rhsContractingAxes) == 1 &&
@@ -123,17 +137,118 @@ func canUseFastPath(lhs, rhs *Buffer, params *dotGeneralNodeData) bool {
 	return true
 }
 
+// isTransposedRHSMatmul checks if the DotGeneral operation is a matrix multiplication with the RHS stored
+// transposed, with both operands contracting on their last axis:
+//
+// 1. Matrix × Matrixᵀ: [M, K] × [N, K] → [M, N]
+// 2. Batched: [B, M, K] × [B, N, K] → [B, M, N] (batch on first axis)
+// 3. Multi-batch: [B1, B2, M, K] × [B1, B2, N, K] → [B1, B2, M, N] (batch on first two axes)
+//
+// This is the layout of, e.g., the query × keyᵀ product in attention, and of weights stored as [out, in].
+// Both the LHS rows and the RHS rows are contiguous, which is ideal for the dot-product kernels.
+func isTransposedRHSMatmul(lhsShape, rhsShape shapes.Shape, lhsContractingAxes, rhsContractingAxes, lhsBatchAxes, rhsBatchAxes []int) bool {
+	rank := lhsShape.Rank()
+	if rank < 2 || rank > 4 || rhsShape.Rank() != rank {
+		return false
+	}
+	if len(lhsContractingAxes) != 1 || len(rhsContractingAxes) != 1 ||
+		lhsContractingAxes[0] != rank-1 || rhsContractingAxes[0] != rank-1 {
+		return false
+	}
+	if len(lhsBatchAxes) != rank-2 || len(rhsBatchAxes) != rank-2 {
+		return false
+	}
+	for ii := range rank - 2 {
+		if lhsBatchAxes[ii] != ii || rhsBatchAxes[ii] != ii {
+			return false
+		}
+	}
+	return true
+}
+
+// canUseTransposedRHSFastPath determines if we can use the fast path for a matrix multiplication with
+// the RHS transposed (see isTransposedRHSMatmul).
+func canUseTransposedRHSFastPath(lhs, rhs *Buffer, params *dotGeneralNodeData) bool {
+	return lhs.shape.DType == dtypes.Float32 &&
+		isTransposedRHSMatmul(lhs.shape, rhs.shape,
+			params.lhsContractingAxes, params.rhsContractingAxes,
+			params.lhsBatchAxes, params.rhsBatchAxes)
+}
+
 // execDotGeneralFastPath executes a standard matrix multiplication without normalization.
 // This is a significant optimization for the common case of A × B matrix multiplication.
 // Returns true if fast path was used, false if caller should use standard path.
-func execDotGeneralFastPath(backend *Backend, lhs, rhs *Buffer, params *dotGeneralNodeData, output *Buffer) bool {
-	if !canUseFastPath(lhs, rhs, params) {
-		return false
+//
+// The float32 paths apply the epilogue (if not nil) to the parts of the output as they are computed.
+func execDotGeneralFastPath(backend *Backend, lhs, rhs *Buffer, params *dotGeneralNodeData, output *Buffer, epilogue *float32Epilogue) bool {
+	if canUseFastPath(lhs, rhs, params) && execDotGeneralFastPathFloat32(backend, lhs, rhs, params, output, epilogue) {
+		return true
+	}
+	if canUseTransposedRHSFastPath(lhs, rhs, params) {
+		profileDotGeneralKernel(backend, output, "transposed_rhs")
+		execDotGeneralFastPathTransposedRHSFloat32(backend, lhs, rhs, params, output, epilogue)
+		return true
+	}
+	if ok, rhsTransposed := canUseFastPathFloat16(lhs, rhs, params); ok {
+		profileDotGeneralKernel(backend, output, "float16")
+		execDotGeneralFastPathFloat16(backend, lhs, rhs, params, output, rhsTransposed)
+		return true
+	}
+	if ok, rhsTransposed := canUseFastPathFloat64(lhs, rhs, params); ok {
+		profileDotGeneralKernel(backend, output, "float64")
+		execDotGeneralFastPathFloat64(backend, lhs, rhs, params, output, rhsTransposed)
+		return true
 	}
+	return false
+}
 
-	// Execute the optimized float32 path
-	execDotGeneralFastPathFloat32(backend, lhs, rhs, params, output)
-	return true
+// execDotGeneralFastPathTransposedRHSFloat32 is the fast path for float32 matrix multiplication with the RHS
+// transposed: [M, K] × [N, K] → [M, N], optionally with leading batch axes.
+//
+// Each output element is the dot product of a LHS row and a RHS row, both contiguous, so it uses the
+// Group4 dot-product kernels, that stream 4 RHS rows against the same LHS row. Large multiplications can use
+// an external library instead: Apple's Accelerate (see Backend.SetDotGeneralAccelerate) or BLAS (see
+// Backend.SetDotGeneralBLAS).
+func execDotGeneralFastPathTransposedRHSFloat32(backend *Backend, lhs, rhs *Buffer, params *dotGeneralNodeData, output *Buffer, epilogue *float32Epilogue) {
+	lhsFlat := lhs.flat.([]float32)
+	rhsFlat := rhs.flat.([]float32)
+	outputFlat := output.flat.([]float32)
+
+	lhsCrossSize := params.lhsCrossSize       // M
+	rhsCrossSize := params.rhsCrossSize       // N
+	contractingSize := params.contractingSize // K
+
+	lhsBatchStride := lhsCrossSize * contractingSize // M * K
+	rhsBatchStride := rhsCrossSize * contractingSize // N * K
+	outputBatchStride := lhsCrossSize * rhsCrossSize // M * N
+
+	if useExternalGEMM(backend, lhsCrossSize, rhsCrossSize, contractingSize) {
+		profileDotGeneralKernel(backend, output, externalGEMMKernelName)
+		gemmExternalFloat32(params.batchSize, lhsCrossSize, rhsCrossSize, contractingSize, lhsFlat, rhsFlat, true, outputFlat)
+		epilogue.apply(outputFlat, 0)
+		return
+	}
+	parallelizeDotGeneral(backend, params.batchSize, lhsCrossSize, rhsCrossSize*contractingSize, func(batchIdx, rowStart, rowEnd int) {
+		rhsBaseIdx := batchIdx * rhsBatchStride
+		for m := rowStart; m < rowEnd; m++ {
+			lhsRowIdx := batchIdx*lhsBatchStride + m*contractingSize
+			outputRowIdx := batchIdx*outputBatchStride + m*rhsCrossSize
+			n := 0
+			for ; n+3 < rhsCrossSize; n += 4 {
+				outputFlat[outputRowIdx+n] = 0
+				outputFlat[outputRowIdx+n+1] = 0
+				outputFlat[outputRowIdx+n+2] = 0
+				outputFlat[outputRowIdx+n+3] = 0
+				dotProductGroup4Float32(lhsFlat, rhsFlat, outputFlat,
+					lhsRowIdx, rhsBaseIdx+n*contractingSize, outputRowIdx+n, contractingSize)
+			}
+			for ; n < rhsCrossSize; n++ {
+				outputFlat[outputRowIdx+n] = dotProductFloat32(lhsFlat, rhsFlat,
+					lhsRowIdx, rhsBaseIdx+n*contractingSize, contractingSize)
+			}
+			epilogue.apply(outputFlat[outputRowIdx:outputRowIdx+rhsCrossSize], 0)
+		}
+	})
 }
 
 // execDotGeneralFastPathFloat32 is the fast path for float32 matrix multiplication.
@@ -150,7 +265,18 @@ func execDotGeneralFastPath(backend *Backend, lhs, rhs *Buffer, params *dotGener
 // Note: Column n in RHS has stride N between elements (not contiguous),
 // so we cannot use the Group4 NEON path which requires contiguous columns.
 // We use the standard scalar loop with explicit strided access.
-func execDotGeneralFastPathFloat32(backend *Backend, lhs, rhs *Buffer, params *dotGeneralNodeData, output *Buffer) {
+//
+// The output rows (of all batch examples) are split among the backend workers, see parallelizeDotGeneral.
+//
+// The kernel is selected by the cost model (see selectMatmulKernel): usually the cache-blocked gemmFloat32, or
+// gemvFloat32 for few rows (e.g. single-token decoder steps), with the columns split among the backend workers.
+// Huge multiplications can use strassenFloat32 instead, if enabled with Backend.SetDotGeneralStrassen, and large ones
+// can use an external library: Apple's Accelerate (see Backend.SetDotGeneralAccelerate) or BLAS (see
+// Backend.SetDotGeneralBLAS).
+//
+// It returns false, without executing anything, if the blocked SME kernels of execDotGeneralLarge are expected to
+// be faster.
+func execDotGeneralFastPathFloat32(backend *Backend, lhs, rhs *Buffer, params *dotGeneralNodeData, output *Buffer, epilogue *float32Epilogue) bool {
 	lhsFlat := lhs.flat.([]float32)
 	rhsFlat := rhs.flat.([]float32)
 	outputFlat := output.flat.([]float32)
@@ -167,12 +293,91 @@ func execDotGeneralFastPathFloat32(backend *Backend, lhs, rhs *Buffer, params *d
 	// For row-major RHS [K, N], the stride between elements in the same column is N
 	rhsColStride := rhsCrossSize // N
 
-	for batchIdx := 0; batchIdx < batchSize; batchIdx++ {
+	var packedRHS *PackedWeight
+	if batchSize == 1 {
+		packedRHS = backend.packedWeightCache.Get(rhs)
+		if packedRHS != nil && (packedRHS.K != contractingSize || packedRHS.N != rhsCrossSize) {
+			packedRHS = nil
+		}
+	}
+	if packedRHS == nil && useExternalGEMM(backend, lhsCrossSize, rhsCrossSize, contractingSize) {
+		profileDotGeneralKernel(backend, output, externalGEMMKernelName)
+		gemmExternalFloat32(batchSize, lhsCrossSize, rhsCrossSize, contractingSize, lhsFlat, rhsFlat, false, outputFlat)
+		epilogue.apply(outputFlat, 0)
+		return true
+	}
+	if packedRHS == nil && useStrassen(backend, lhsCrossSize, rhsCrossSize, contractingSize) {
+		profileDotGeneralKernel(backend, output, "strassen")
+		for batchIdx := range batchSize {
+			strassenFloat32(backend, strassenMaxDepth, lhsCrossSize, rhsCrossSize, contractingSize,
+				lhsFlat, batchIdx*lhsBatchStride, contractingSize,
+				rhsFlat, batchIdx*rhsBatchStride, rhsCrossSize,
+				outputFlat, batchIdx*outputBatchStride, rhsCrossSize)
+		}
+		epilogue.apply(outputFlat, 0)
+		return true
+	}
+	kernel := matmulKernelGEMM
+	if packedRHS == nil {
+		kernel = selectMatmulKernel(batchSize, lhsCrossSize, rhsCrossSize, contractingSize,
+			smeBlockedKernelAvailable(dtypes.Float32))
+	}
+	switch kernel {
+	case matmulKernelBlocked:
+		return false
+	case matmulKernelGEMV:
+		profileDotGeneralKernel(backend, output, kernel.String())
+		// Each row of the LHS is a vector × matrix product, with the columns split among the backend workers.
+		numColBlocks := (rhsCrossSize + gemvNB - 1) / gemvNB
+		parallelizeDotGeneral(backend, batchSize*lhsCrossSize, numColBlocks, gemvNB*contractingSize, func(row, blockStart, blockEnd int) {
+			batchIdx := row / lhsCrossSize
+			colStart := blockStart * gemvNB
+			colEnd := min(blockEnd*gemvNB, rhsCrossSize)
+			outputStart := row*rhsCrossSize + colStart
+			gemvFloat32(contractingSize, colEnd-colStart,
+				lhsFlat, row*contractingSize,
+				rhsFlat, batchIdx*rhsBatchStride+colStart, rhsCrossSize,
+				outputFlat, outputStart)
+			epilogue.apply(outputFlat[outputStart:outputStart+colEnd-colStart], colStart)
+		})
+		return true
+	case matmulKernelGEMM:
+		if packedRHS != nil {
+			profileDotGeneralKernel(backend, output, "packed_gemm")
+		} else {
+			profileDotGeneralKernel(backend, output, kernel.String())
+		}
+	default:
+		profileDotGeneralKernel(backend, output, kernel.String())
+	}
+	useGEMM := kernel == matmulKernelGEMM
+	blockSizes := backend.gemmBlockSizes
+	parallelizeDotGeneral(backend, batchSize, lhsCrossSize, rhsCrossSize*contractingSize, func(batchIdx, rowStart, rowEnd int) {
 		lhsBaseIdx := batchIdx * lhsBatchStride
 		rhsBaseIdx := batchIdx * rhsBatchStride
 		outputBaseIdx := batchIdx * outputBatchStride
 
-		for m := 0; m < lhsCrossSize; m++ {
+		// The epilogue is applied to the rows of the task once they are computed.
+		defer func() {
+			epilogue.apply(outputFlat[outputBaseIdx+rowStart*rhsCrossSize:outputBaseIdx+rowEnd*rhsCrossSize], 0)
+		}()
+
+		if packedRHS != nil {
+			gemmFloat32PackedB(rowEnd-rowStart,
+				lhsFlat, lhsBaseIdx+rowStart*contractingSize, contractingSize,
+				packedRHS,
+				outputFlat, outputBaseIdx+rowStart*rhsCrossSize, rhsCrossSize)
+			return
+		}
+		if useGEMM {
+			gemmFloat32(blockSizes, rowEnd-rowStart, rhsCrossSize, contractingSize,
+				lhsFlat, lhsBaseIdx+rowStart*contractingSize, contractingSize,
+				rhsFlat, rhsBaseIdx, rhsCrossSize,
+				outputFlat, outputBaseIdx+rowStart*rhsCrossSize, rhsCrossSize)
+			return
+		}
+
+		for m := rowStart; m < rowEnd; m++ {
 			lhsRowStart := lhsBaseIdx + m*contractingSize
 			outputRowStart := outputBaseIdx + m*rhsCrossSize
 
@@ -197,5 +402,67 @@ func execDotGeneralFastPathFloat32(backend *Backend, lhs, rhs *Buffer, params *d
 				outputFlat[outputRowStart+n] = sum
 			}
 		}
+	})
+	return true
+}
+
+// DotGeneralParallelMinWork is the default minimum amount of work, measured in multiply-adds, of a DotGeneral (or of
+// each of the parallel tasks it is split into) before it is parallelized: below that, the cost of
+// synchronizing the goroutines dominates. It can be changed per backend, see Backend.SetDotGeneralParallelMinWork.
+//
+// The maximum parallelism is the one of the backend, see Backend.SetMaxParallelism.
+var DotGeneralParallelMinWork = 64 * 1024
+
+// parallelizeDotGeneral splits the work of a DotGeneral with batchSize examples, each with numRows output rows
+// that cost rowWork multiply-adds each, into tasks of consecutive rows, executed by the backend workers.
+// It calls fn for each batch example and range of rows, and returns when all are done.
+//
+// Small problems (see Backend.SetDotGeneralParallelMinWork) are executed inline. Tasks for which there are no workers
+// available are also executed inline, so it never blocks waiting for workers.
+func parallelizeDotGeneral(backend *Backend, batchSize, numRows, rowWork int, fn func(batchIdx, rowStart, rowEnd int)) {
+	totalRows := batchSize * numRows
+	maxParallelism := backend.workers.MaxParallelism()
+	if backend.workers.IsUnlimited() {
+		maxParallelism = runtime.NumCPU()
+	}
+	numTasks := min(maxParallelism, totalRows, totalRows*rowWork/max(backend.dotGeneralParallelMinWork, 1))
+	if numTasks <= 1 {
+		for batchIdx := range batchSize {
+			fn(batchIdx, 0, numRows)
+		}
+		return
+	}
+
+	// runRows runs the rows [start, end) of the flattened batch and rows.
+	numNUMANodes := 0
+	if backend.numaPolicy == NUMALocal {
+		numNUMANodes = len(getNUMATopology())
+	}
+	runRows := func(start, end int) {
+		if numNUMANodes > 1 {
+			defer pinToNUMANode(numaNodeForRows(start, end, totalRows, numNUMANodes))()
+		}
+		for start < end {
+			batchIdx, rowStart := start/numRows, start%numRows
+			rowEnd := min(numRows, rowStart+end-start)
+			fn(batchIdx, rowStart, rowEnd)
+			start += rowEnd - rowStart
+		}
+	}
+	rowsPerTask := (totalRows + numTasks - 1) / numTasks
+	var wg sync.WaitGroup
+	for start := rowsPerTask; start < totalRows; start += rowsPerTask {
+		end := min(start+rowsPerTask, totalRows)
+		wg.Add(1)
+		task := func() {
+			runRows(start, end)
+			wg.Done()
+		}
+		if !backend.workers.StartIfAvailable(task) {
+			task()
+		}
 	}
+	// The first task is run by the current goroutine.
+	runRows(0, min(rowsPerTask, totalRows))
+	wg.Wait()
 }
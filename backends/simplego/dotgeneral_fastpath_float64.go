@@ -0,0 +1,160 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"github.com/gomlx/gopjrt/dtypes"
+)
+
+// Block sizes of the Float64 fast path in the standard layout: each task accumulates its output rows in blocks of
+// float64BlockN columns (2KB, kept in the L1 cache), over blocks of float64BlockK rows of the RHS (256KB, kept in
+// the L2 cache and shared by all the rows of the task).
+const (
+	float64BlockN = 256
+	float64BlockK = 128
+)
+
+// canUseFastPathFloat64 determines if we can use the Float64 fast path for this DotGeneral operation, and
+// whether the RHS is transposed (see isTransposedRHSMatmul) or in the standard layout (see isStandardMatmul).
+//
+// It requires the AVX2 or NEON kernels: in pure Go the normalized DotGeneral path is faster.
+func canUseFastPathFloat64(lhs, rhs *Buffer, params *dotGeneralNodeData) (ok, rhsTransposed bool) {
+	if lhs.shape.DType != dtypes.Float64 || !(hasAVX2 || hasNEON) {
+		return false, false
+	}
+	if isStandardMatmul(lhs.shape, rhs.shape,
+		params.lhsContractingAxes, params.rhsContractingAxes,
+		params.lhsBatchAxes, params.rhsBatchAxes) {
+		return true, false
+	}
+	if isTransposedRHSMatmul(lhs.shape, rhs.shape,
+		params.lhsContractingAxes, params.rhsContractingAxes,
+		params.lhsBatchAxes, params.rhsBatchAxes) {
+		return true, true
+	}
+	return false, false
+}
+
+// execDotGeneralFastPathFloat64 is the fast path for Float64 matrix multiplication, for both the standard
+// ([M, K] × [K, N]) and the transposed RHS ([M, K] × [N, K]) layouts. The output must be zero-initialized.
+//
+//   - In the standard layout, each output row is accumulated as a sum of the RHS rows scaled by the LHS values
+//     (axpyFloat64), in cache blocks (see float64BlockN and float64BlockK).
+//   - With the RHS transposed, each output element is the dot product of a LHS row and a RHS row, both
+//     contiguous (dotProductFloat64).
+//
+// Both use the AVX2 (4 values per vector) or NEON (2 values per vector) kernels. The output rows
+// (of all batch examples) are split among the backend workers, or the output columns for vector × matrix products
+// (M = 1).
+func execDotGeneralFastPathFloat64(backend *Backend, lhs, rhs *Buffer, params *dotGeneralNodeData, output *Buffer, rhsTransposed bool) {
+	lhsFlat := lhs.flat.([]float64)
+	rhsFlat := rhs.flat.([]float64)
+	outputFlat := output.flat.([]float64)
+
+	batchSize := params.batchSize
+	lhsCrossSize := params.lhsCrossSize       // M
+	rhsCrossSize := params.rhsCrossSize       // N
+	contractingSize := params.contractingSize // K
+
+	lhsBatchStride := lhsCrossSize * contractingSize // M * K
+	rhsBatchStride := rhsCrossSize * contractingSize // N * K
+	outputBatchStride := lhsCrossSize * rhsCrossSize // M * N
+
+	if rhsTransposed {
+		parallelizeDotGeneral(backend, batchSize, lhsCrossSize, rhsCrossSize*contractingSize, func(batchIdx, rowStart, rowEnd int) {
+			rhsBaseIdx := batchIdx * rhsBatchStride
+			for m := rowStart; m < rowEnd; m++ {
+				lhsRow := lhsFlat[batchIdx*lhsBatchStride+m*contractingSize:][:contractingSize]
+				outputRow := outputFlat[batchIdx*outputBatchStride+m*rhsCrossSize:][:rhsCrossSize]
+				for n := range outputRow {
+					outputRow[n] = dotProductFloat64(lhsRow, rhsFlat[rhsBaseIdx+n*contractingSize:][:contractingSize])
+				}
+			}
+		})
+		return
+	}
+
+	// matMulBlock accumulates the output rows [rowStart, rowEnd) and columns [colStart, colEnd) of the batch example.
+	matMulBlock := func(batchIdx, rowStart, rowEnd, colStart, colEnd int) {
+		lhsBaseIdx := batchIdx * lhsBatchStride
+		rhsBaseIdx := batchIdx * rhsBatchStride
+		outputBaseIdx := batchIdx * outputBatchStride
+		for blockColStart := colStart; blockColStart < colEnd; blockColStart += float64BlockN {
+			blockColEnd := min(blockColStart+float64BlockN, colEnd)
+			for blockKStart := 0; blockKStart < contractingSize; blockKStart += float64BlockK {
+				blockKEnd := min(blockKStart+float64BlockK, contractingSize)
+				for m := rowStart; m < rowEnd; m++ {
+					outputRow := outputFlat[outputBaseIdx+m*rhsCrossSize+blockColStart : outputBaseIdx+m*rhsCrossSize+blockColEnd]
+					lhsRow := lhsFlat[lhsBaseIdx+m*contractingSize:]
+					for k := blockKStart; k < blockKEnd; k++ {
+						rhsRowIdx := rhsBaseIdx + k*rhsCrossSize
+						axpyFloat64(lhsRow[k], rhsFlat[rhsRowIdx+blockColStart:rhsRowIdx+blockColEnd], outputRow)
+					}
+				}
+			}
+		}
+	}
+	if lhsCrossSize == 1 {
+		numColBlocks := (rhsCrossSize + float64BlockN - 1) / float64BlockN
+		parallelizeDotGeneral(backend, batchSize, numColBlocks, float64BlockN*contractingSize, func(batchIdx, blockStart, blockEnd int) {
+			matMulBlock(batchIdx, 0, 1, blockStart*float64BlockN, min(blockEnd*float64BlockN, rhsCrossSize))
+		})
+		return
+	}
+	parallelizeDotGeneral(backend, batchSize, lhsCrossSize, rhsCrossSize*contractingSize, func(batchIdx, rowStart, rowEnd int) {
+		matMulBlock(batchIdx, rowStart, rowEnd, 0, rhsCrossSize)
+	})
+}
+
+// axpyFloat64 computes y += alpha·x, where y has at least the length of x.
+func axpyFloat64(alpha float64, x, y []float64) {
+	n := len(x)
+	y = y[:n]
+	i := 0
+	if n >= 4 {
+		switch {
+		case hasAVX2:
+			i = axpyFloat64AVX2(alpha, x, y)
+		case hasNEON:
+			i = axpyFloat64NEON(alpha, x, y)
+		}
+	}
+	for ; i+3 < n; i += 4 {
+		y[i] += alpha * x[i]
+		y[i+1] += alpha * x[i+1]
+		y[i+2] += alpha * x[i+2]
+		y[i+3] += alpha * x[i+3]
+	}
+	for ; i < n; i++ {
+		y[i] += alpha * x[i]
+	}
+}
+
+// dotProductFloat64 returns the dot product of the slices a and b, where b has at least the length of a.
+func dotProductFloat64(a, b []float64) float64 {
+	n := len(a)
+	b = b[:n]
+	var sum float64
+	i := 0
+	if n >= 4 {
+		switch {
+		case hasAVX2:
+			sum, i = dotProductFloat64AVX2(a, b)
+		case hasNEON:
+			sum, i = dotProductFloat64NEON(a, b)
+		}
+	}
+	var sum0, sum1, sum2, sum3 float64
+	for ; i+3 < n; i += 4 {
+		sum0 += a[i] * b[i]
+		sum1 += a[i+1] * b[i+1]
+		sum2 += a[i+2] * b[i+2]
+		sum3 += a[i+3] * b[i+3]
+	}
+	for ; i < n; i++ {
+		sum0 += a[i] * b[i]
+	}
+	return sum + (sum0 + sum1) + (sum2 + sum3)
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/gomlx/gomlx/pkg/core/graph"
+	"github.com/gomlx/gomlx/pkg/core/shapes"
+	"github.com/gomlx/gomlx/pkg/core/tensors"
+	"github.com/gomlx/gopjrt/dtypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFloat64Kernels(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	for _, simd := range []string{SIMDAuto, SIMDOff} {
+		require.NoError(t, SetSIMD(simd))
+		for _, n := range []int{1, 3, 4, 7, 8, 15, 16, 17, 33, 100} {
+			x, y := make([]float64, n), make([]float64, n+1)
+			for i := range x {
+				x[i] = rng.Float64()*2 - 1
+				y[i] = rng.Float64()*2 - 1
+			}
+			y[n] = 7 // Must not be changed.
+			var wantDot float64
+			wantAxpy := make([]float64, n)
+			for i := range x {
+				wantDot += x[i] * y[i]
+				wantAxpy[i] = y[i] + 0.5*x[i]
+			}
+			require.InDeltaf(t, wantDot, dotProductFloat64(x, y), 1e-12, "dotProductFloat64 with %s, n=%d", simd, n)
+			axpyFloat64(0.5, x, y)
+			require.InDeltaSlicef(t, wantAxpy, y[:n], 1e-12, "axpyFloat64 with %s, n=%d", simd, n)
+			require.Equal(t, 7.0, y[n])
+		}
+	}
+	require.NoError(t, SetSIMD(SIMDAuto))
+}
+
+func TestDotGeneral_FastPathFloat64(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	randomTensor := func(dims ...int) *tensors.Tensor {
+		tensor := tensors.FromShape(shapes.Make(dtypes.Float64, dims...))
+		tensors.MustMutableFlatData(tensor, func(flat []float64) {
+			for i := range flat {
+				flat[i] = rng.Float64()*2 - 1
+			}
+		})
+		return tensor
+	}
+	for _, dims := range [][4]int{{1, 1, 1, 1}, {1, 1, 300, 70}, {3, 5, 7, 9}, {2, 33, 600, 150}} {
+		batchSize, m, n, k := dims[0], dims[1], dims[2], dims[3]
+		for _, rhsTransposed := range []bool{false, true} {
+			lhs := randomTensor(batchSize, m, k)
+			rhsDims := []int{batchSize, k, n}
+			if rhsTransposed {
+				rhsDims = []int{batchSize, n, k}
+			}
+			rhs := randomTensor(rhsDims...)
+			lhsFlat := tensors.MustCopyFlatData[float64](lhs)
+			rhsFlat := tensors.MustCopyFlatData[float64](rhs)
+			want := make([]float64, batchSize*m*n)
+			for b := range batchSize {
+				for i := range m {
+					for j := range n {
+						var sum float64
+						for p := range k {
+							rhsIdx := b*k*n + p*n + j
+							if rhsTransposed {
+								rhsIdx = b*n*k + j*k + p
+							}
+							sum += lhsFlat[b*m*k+i*k+p] * rhsFlat[rhsIdx]
+						}
+						want[b*m*n+i*n+j] = sum
+					}
+				}
+			}
+			for _, simd := range []string{SIMDAuto, SIMDOff} {
+				t.Run(fmt.Sprintf("%s/batch=%d,m=%d,n=%d,k=%d/transposed=%v", simd, batchSize, m, n, k, rhsTransposed), func(t *testing.T) {
+					require.NoError(t, SetSIMD(simd))
+					defer func() { require.NoError(t, SetSIMD(SIMDAuto)) }()
+					rhsContractingAxis := 1
+					if rhsTransposed {
+						rhsContractingAxis = 2
+					}
+					got := graph.MustExecOnce(backend, func(lhs, rhs *graph.Node) *graph.Node {
+						return graph.DotGeneral(lhs, []int{2}, []int{0}, rhs, []int{rhsContractingAxis}, []int{0})
+					}, lhs, rhs)
+					require.NoError(t, got.Shape().Check(dtypes.Float64, batchSize, m, n))
+					require.InDeltaSlice(t, want, tensors.MustCopyFlatData[float64](got), 1e-10)
+				})
+			}
+		}
+	}
+}
+
+func BenchmarkDotGeneral_FastPathFloat64(b *testing.B) {
+	const m, n, k = 64, 512, 512
+	lhs := tensors.FromShape(shapes.Make(dtypes.Float64, m, k))
+	rhs := tensors.FromShape(shapes.Make(dtypes.Float64, k, n))
+	exec := graph.MustNewExec(backend, func(lhs, rhs *graph.Node) *graph.Node {
+		return graph.Dot(lhs, rhs)
+	})
+	for _, simd := range []string{SIMDAuto, SIMDOff} {
+		b.Run(simd, func(b *testing.B) {
+			require.NoError(b, SetSIMD(simd))
+			defer func() { require.NoError(b, SetSIMD(SIMDAuto)) }()
+			for range b.N {
+				exec.MustExec(lhs, rhs)[0].FinalizeAll()
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"unsafe"
+
+	"github.com/gomlx/gomlx/pkg/core/shapes"
+	"github.com/gomlx/gopjrt/dtypes"
+	"github.com/x448/float16"
+)
+
+// canUseFastPathFloat16 determines if we can use the Float16 fast path for this DotGeneral operation, and
+// whether the RHS is transposed (see isTransposedRHSMatmul) or in the standard layout (see isStandardMatmul).
+func canUseFastPathFloat16(lhs, rhs *Buffer, params *dotGeneralNodeData) (ok, rhsTransposed bool) {
+	if lhs.shape.DType != dtypes.Float16 {
+		return false, false
+	}
+	if isStandardMatmul(lhs.shape, rhs.shape,
+		params.lhsContractingAxes, params.rhsContractingAxes,
+		params.lhsBatchAxes, params.rhsBatchAxes) {
+		return true, false
+	}
+	if isTransposedRHSMatmul(lhs.shape, rhs.shape,
+		params.lhsContractingAxes, params.rhsContractingAxes,
+		params.lhsBatchAxes, params.rhsBatchAxes) {
+		return true, true
+	}
+	return false, false
+}
+
+// execDotGeneralFastPathFloat16 is the fast path for Float16 matrix multiplication, for both the standard
+// ([M, K] × [K, N]) and the transposed RHS ([M, K] × [N, K]) layouts.
+//
+// Each output element is the dot product of a LHS row and a RHS row, accumulated in float32 -- with the
+// NEON FMLAL/FMLAL2 instructions when available (see hasFP16NEON) -- and converted to Float16 at the end.
+// In the standard layout the RHS is first transposed, so that the RHS rows are contiguous.
+//
+// Large multiplications can use an external library instead: Apple's Accelerate (see
+// Backend.SetDotGeneralAccelerate) or BLAS (see Backend.SetDotGeneralBLAS).
+func execDotGeneralFastPathFloat16(backend *Backend, lhs, rhs *Buffer, params *dotGeneralNodeData, output *Buffer, rhsTransposed bool) {
+	if useExternalGEMM(backend, params.lhsCrossSize, params.rhsCrossSize, params.contractingSize) {
+		profileDotGeneralKernel(backend, output, externalGEMMKernelName)
+		execDotGeneralExternalFloat16(backend, lhs, rhs, params, output, rhsTransposed)
+		return
+	}
+	lhsFlat := lhs.flat.([]float16.Float16)
+	rhsFlat := rhs.flat.([]float16.Float16)
+	outputFlat := output.flat.([]float16.Float16)
+
+	batchSize := params.batchSize
+	lhsCrossSize := params.lhsCrossSize       // M
+	rhsCrossSize := params.rhsCrossSize       // N
+	contractingSize := params.contractingSize // K
+
+	lhsBatchStride := lhsCrossSize * contractingSize // M * K
+	rhsBatchStride := rhsCrossSize * contractingSize // N * K
+	outputBatchStride := lhsCrossSize * rhsCrossSize // M * N
+
+	if !rhsTransposed {
+		transposed := backend.getBufferForShape(shapes.Make(dtypes.Float16, batchSize*rhsBatchStride))
+		defer backend.putBuffer(transposed)
+		transposedFlat := transposed.flat.([]float16.Float16)
+		for batchIdx := range batchSize {
+			src := rhsFlat[batchIdx*rhsBatchStride : (batchIdx+1)*rhsBatchStride]
+			dst := transposedFlat[batchIdx*rhsBatchStride : (batchIdx+1)*rhsBatchStride]
+			for k := range contractingSize {
+				for n, value := range src[k*rhsCrossSize : (k+1)*rhsCrossSize] {
+					dst[n*contractingSize+k] = value
+				}
+			}
+		}
+		rhsFlat = transposedFlat
+	}
+
+	// Accumulate in float32.
+	accumulator := backend.getBufferForShape(shapes.Make(dtypes.Float32, batchSize*outputBatchStride))
+	defer backend.putBuffer(accumulator)
+	accumulatorFlat := accumulator.flat.([]float32)
+
+	parallelizeDotGeneral(backend, batchSize, lhsCrossSize, rhsCrossSize*contractingSize, func(batchIdx, rowStart, rowEnd int) {
+		rhsBaseIdx := batchIdx * rhsBatchStride
+		for m := rowStart; m < rowEnd; m++ {
+			lhsRow := lhsFlat[batchIdx*lhsBatchStride+m*contractingSize:][:contractingSize]
+			accumulatorRowIdx := batchIdx*outputBatchStride + m*rhsCrossSize
+			n := 0
+			for ; n+3 < rhsCrossSize; n += 4 {
+				rhsIdx := rhsBaseIdx + n*contractingSize
+				accumulatorFlat[accumulatorRowIdx+n], accumulatorFlat[accumulatorRowIdx+n+1],
+					accumulatorFlat[accumulatorRowIdx+n+2], accumulatorFlat[accumulatorRowIdx+n+3] =
+					dotProductGroup4Float16(lhsRow, rhsFlat[rhsIdx:rhsIdx+4*contractingSize], contractingSize)
+			}
+			for ; n < rhsCrossSize; n++ {
+				rhsIdx := rhsBaseIdx + n*contractingSize
+				accumulatorFlat[accumulatorRowIdx+n] = dotProductFloat16(lhsRow, rhsFlat[rhsIdx:rhsIdx+contractingSize])
+			}
+		}
+	})
+	convertFloat32SliceToFloat16(accumulatorFlat, outputFlat)
+}
+
+// dotProductFloat16 returns the dot product of the Float16 slices a and b (with the same length), accumulated
+// in float32.
+func dotProductFloat16(a, b []float16.Float16) float32 {
+	n := len(a)
+	if hasFP16NEON && n >= 8 {
+		return dotProductFP16_neon_asm(unsafe.Pointer(&a[0]), unsafe.Pointer(&b[0]), int64(n))
+	}
+	b = b[:n]
+	var sum float32
+	for i, value := range a {
+		sum += value.Float32() * b[i].Float32()
+	}
+	return sum
+}
+
+// dotProductGroup4Float16 returns the 4 dot products of the Float16 slice a with the 4 consecutive vectors of
+// size n in b, accumulated in float32.
+func dotProductGroup4Float16(a, b []float16.Float16, n int) (sum0, sum1, sum2, sum3 float32) {
+	if hasFP16NEON && n >= 8 {
+		return dotProductFP16Group4_neon_asm(unsafe.Pointer(&a[0]), unsafe.Pointer(&b[0]), int64(n), int64(n))
+	}
+	a = a[:n]
+	b0, b1, b2, b3 := b[:n], b[n:2*n], b[2*n:3*n], b[3*n:4*n]
+	for i, value := range a {
+		v := value.Float32()
+		sum0 += v * b0[i].Float32()
+		sum1 += v * b1[i].Float32()
+		sum2 += v * b2[i].Float32()
+		sum3 += v * b3[i].Float32()
+	}
+	return
+}
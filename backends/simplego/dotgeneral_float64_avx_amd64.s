@@ -0,0 +1,106 @@
+//go:build !noasm && amd64
+
+// AVX2 (with FMA) kernels of the Float64 DotGeneral fast path: see execDotGeneralFastPathFloat64.
+// They process n values, a multiple of 4 (4 float64 per ymm register), leaving the remainder to Go.
+
+#include "textflag.h"
+
+// func axpyFloat64_avx2_asm(alpha float64, x, y unsafe.Pointer, n int64)
+// Computes y[0:n] += alpha * x[0:n].
+TEXT ·axpyFloat64_avx2_asm(SB), NOSPLIT, $0-32
+	VBROADCASTSD alpha+0(FP), Y0
+	MOVQ         x+8(FP), SI
+	MOVQ         y+16(FP), DI
+	MOVQ         n+24(FP), CX
+
+	CMPQ CX, $16
+	JL   axpy_loop4
+
+axpy_loop16:
+	// 16 values per iteration.
+	VMOVUPD     (DI), Y1
+	VMOVUPD     32(DI), Y2
+	VMOVUPD     64(DI), Y3
+	VMOVUPD     96(DI), Y4
+	VFMADD231PD (SI), Y0, Y1
+	VFMADD231PD 32(SI), Y0, Y2
+	VFMADD231PD 64(SI), Y0, Y3
+	VFMADD231PD 96(SI), Y0, Y4
+	VMOVUPD     Y1, (DI)
+	VMOVUPD     Y2, 32(DI)
+	VMOVUPD     Y3, 64(DI)
+	VMOVUPD     Y4, 96(DI)
+	ADDQ        $128, SI
+	ADDQ        $128, DI
+	SUBQ        $16, CX
+	CMPQ        CX, $16
+	JGE         axpy_loop16
+
+axpy_loop4:
+	CMPQ        CX, $4
+	JL          axpy_done
+	VMOVUPD     (DI), Y1
+	VFMADD231PD (SI), Y0, Y1
+	VMOVUPD     Y1, (DI)
+	ADDQ        $32, SI
+	ADDQ        $32, DI
+	SUBQ        $4, CX
+	JMP         axpy_loop4
+
+axpy_done:
+	VZEROUPPER
+	RET
+
+// func dotProductFloat64_avx2_asm(a, b unsafe.Pointer, n int64) float64
+// Returns the sum of a[0:n] * b[0:n].
+TEXT ·dotProductFloat64_avx2_asm(SB), NOSPLIT, $0-32
+	MOVQ a+0(FP), SI
+	MOVQ b+8(FP), DI
+	MOVQ n+16(FP), CX
+
+	// 4 accumulators.
+	VXORPD Y0, Y0, Y0
+	VXORPD Y1, Y1, Y1
+	VXORPD Y2, Y2, Y2
+	VXORPD Y3, Y3, Y3
+
+	CMPQ CX, $16
+	JL   dot_loop4
+
+dot_loop16:
+	// 16 values per iteration, 4 per accumulator.
+	VMOVUPD     (SI), Y4
+	VMOVUPD     32(SI), Y5
+	VMOVUPD     64(SI), Y6
+	VMOVUPD     96(SI), Y7
+	VFMADD231PD (DI), Y4, Y0
+	VFMADD231PD 32(DI), Y5, Y1
+	VFMADD231PD 64(DI), Y6, Y2
+	VFMADD231PD 96(DI), Y7, Y3
+	ADDQ        $128, SI
+	ADDQ        $128, DI
+	SUBQ        $16, CX
+	CMPQ        CX, $16
+	JGE         dot_loop16
+
+dot_loop4:
+	CMPQ        CX, $4
+	JL          dot_reduce
+	VMOVUPD     (SI), Y4
+	VFMADD231PD (DI), Y4, Y0
+	ADDQ        $32, SI
+	ADDQ        $32, DI
+	SUBQ        $4, CX
+	JMP         dot_loop4
+
+dot_reduce:
+	VADDPD       Y1, Y0, Y0
+	VADDPD       Y3, Y2, Y2
+	VADDPD       Y2, Y0, Y0
+	VEXTRACTF128 $1, Y0, X1
+	VADDPD       X1, X0, X0
+	VUNPCKHPD    X0, X0, X1
+	VADDSD       X1, X0, X0
+	VZEROUPPER
+	MOVSD        X0, ret+24(FP)
+	RET
@@ -0,0 +1,17 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build noasm || !amd64
+
+package simplego
+
+// axpyFloat64AVX2 stub for non-AMD64 platforms.
+func axpyFloat64AVX2(alpha float64, x, y []float64) int {
+	panic("AVX2 not available")
+}
+
+// dotProductFloat64AVX2 stub for non-AMD64 platforms.
+func dotProductFloat64AVX2(a, b []float64) (sum float64, n int) {
+	panic("AVX2 not available")
+}
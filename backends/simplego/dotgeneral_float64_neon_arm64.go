@@ -0,0 +1,48 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !noasm && arm64
+
+package simplego
+
+import (
+	"runtime"
+	"unsafe"
+)
+
+// axpyFloat64_neon_asm is implemented in dotgeneral_float64_neon_arm64.s.
+// It computes y[0:n] += alpha * x[0:n], for n a multiple of 4.
+//
+//go:noescape
+func axpyFloat64_neon_asm(alpha float64, x, y unsafe.Pointer, n int64)
+
+// dotProductFloat64_neon_asm is implemented in dotgeneral_float64_neon_arm64.s.
+// It returns the dot product of a[0:n] and b[0:n], for n a multiple of 4.
+//
+//go:noescape
+func dotProductFloat64_neon_asm(a, b unsafe.Pointer, n int64) float64
+
+// axpyFloat64NEON computes y[0:n] += alpha * x[0:n] with NEON, where n is the largest multiple of 4 of len(x)
+// (it must be at least 4). It returns n.
+func axpyFloat64NEON(alpha float64, x, y []float64) int {
+	n := len(x) &^ 3
+	_ = x[n-1]
+	_ = y[n-1]
+	axpyFloat64_neon_asm(alpha, unsafe.Pointer(&x[0]), unsafe.Pointer(&y[0]), int64(n))
+	runtime.KeepAlive(x)
+	runtime.KeepAlive(y)
+	return n
+}
+
+// dotProductFloat64NEON returns the dot product of a[0:n] and b[0:n] with NEON, where n is the largest multiple
+// of 4 of len(a) (it must be at least 4), and n.
+func dotProductFloat64NEON(a, b []float64) (sum float64, n int) {
+	n = len(a) &^ 3
+	_ = a[n-1]
+	_ = b[n-1]
+	sum = dotProductFloat64_neon_asm(unsafe.Pointer(&a[0]), unsafe.Pointer(&b[0]), int64(n))
+	runtime.KeepAlive(a)
+	runtime.KeepAlive(b)
+	return sum, n
+}
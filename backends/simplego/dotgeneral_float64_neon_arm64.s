@@ -0,0 +1,83 @@
+//go:build !noasm && arm64
+
+// NEON kernels of the Float64 DotGeneral fast path: see execDotGeneralFastPathFloat64.
+// They process n values, a multiple of 4 (2 float64 per NEON register), leaving the remainder to Go.
+
+#include "textflag.h"
+
+// func axpyFloat64_neon_asm(alpha float64, x, y unsafe.Pointer, n int64)
+// Computes y[0:n] += alpha * x[0:n].
+TEXT ·axpyFloat64_neon_asm(SB), NOSPLIT, $0-32
+	FMOVD alpha+0(FP), F0
+	MOVD  x+8(FP), R0
+	MOVD  y+16(FP), R1
+	MOVD  n+24(FP), R2
+	WORD  $0x4e080400 // dup v0.2d, v0.d[0]
+
+axpy_loop8:
+	CMP  $8, R2
+	BLT  axpy_loop4
+	WORD $0x4cdf2c04 // ld1 {v4.2d, v5.2d, v6.2d, v7.2d}, [x0], #64
+	WORD $0x4c402c30 // ld1 {v16.2d, v17.2d, v18.2d, v19.2d}, [x1]
+	WORD $0x4e60cc90 // fmla v16.2d, v4.2d, v0.2d
+	WORD $0x4e60ccb1 // fmla v17.2d, v5.2d, v0.2d
+	WORD $0x4e60ccd2 // fmla v18.2d, v6.2d, v0.2d
+	WORD $0x4e60ccf3 // fmla v19.2d, v7.2d, v0.2d
+	WORD $0x4c9f2c30 // st1 {v16.2d, v17.2d, v18.2d, v19.2d}, [x1], #64
+	SUB  $8, R2
+	B    axpy_loop8
+
+axpy_loop4:
+	CBZ  R2, axpy_done
+	WORD $0x4cdfac04 // ld1 {v4.2d, v5.2d}, [x0], #32
+	WORD $0x4c40ac30 // ld1 {v16.2d, v17.2d}, [x1]
+	WORD $0x4e60cc90 // fmla v16.2d, v4.2d, v0.2d
+	WORD $0x4e60ccb1 // fmla v17.2d, v5.2d, v0.2d
+	WORD $0x4c9fac30 // st1 {v16.2d, v17.2d}, [x1], #32
+	SUB  $4, R2
+	B    axpy_loop4
+
+axpy_done:
+	RET
+
+// func dotProductFloat64_neon_asm(a, b unsafe.Pointer, n int64) float64
+// Returns the sum of a[0:n] * b[0:n].
+TEXT ·dotProductFloat64_neon_asm(SB), NOSPLIT, $0-32
+	MOVD a+0(FP), R0
+	MOVD b+8(FP), R1
+	MOVD n+16(FP), R2
+
+	// 4 accumulators.
+	WORD $0x6f00e400 // movi v0.2d, #0
+	WORD $0x6f00e401 // movi v1.2d, #0
+	WORD $0x6f00e402 // movi v2.2d, #0
+	WORD $0x6f00e403 // movi v3.2d, #0
+
+dot_loop8:
+	CMP  $8, R2
+	BLT  dot_loop4
+	WORD $0x4cdf2c04 // ld1 {v4.2d, v5.2d, v6.2d, v7.2d}, [x0], #64
+	WORD $0x4cdf2c30 // ld1 {v16.2d, v17.2d, v18.2d, v19.2d}, [x1], #64
+	WORD $0x4e70cc80 // fmla v0.2d, v4.2d, v16.2d
+	WORD $0x4e71cca1 // fmla v1.2d, v5.2d, v17.2d
+	WORD $0x4e72ccc2 // fmla v2.2d, v6.2d, v18.2d
+	WORD $0x4e73cce3 // fmla v3.2d, v7.2d, v19.2d
+	SUB  $8, R2
+	B    dot_loop8
+
+dot_loop4:
+	CBZ  R2, dot_reduce
+	WORD $0x4cdfac04 // ld1 {v4.2d, v5.2d}, [x0], #32
+	WORD $0x4cdfac30 // ld1 {v16.2d, v17.2d}, [x1], #32
+	WORD $0x4e70cc80 // fmla v0.2d, v4.2d, v16.2d
+	WORD $0x4e71cca1 // fmla v1.2d, v5.2d, v17.2d
+	SUB  $4, R2
+	B    dot_loop4
+
+dot_reduce:
+	WORD  $0x4e61d400 // fadd v0.2d, v0.2d, v1.2d
+	WORD  $0x4e63d442 // fadd v2.2d, v2.2d, v3.2d
+	WORD  $0x4e62d400 // fadd v0.2d, v0.2d, v2.2d
+	WORD  $0x7e70d800 // faddp d0, v0.2d
+	FMOVD F0, ret+24(FP)
+	RET
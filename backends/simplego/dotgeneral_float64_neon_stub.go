@@ -0,0 +1,17 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build noasm || !arm64
+
+package simplego
+
+// axpyFloat64NEON stub for non-ARM64 platforms.
+func axpyFloat64NEON(alpha float64, x, y []float64) int {
+	panic("NEON not available")
+}
+
+// dotProductFloat64NEON stub for non-ARM64 platforms.
+func dotProductFloat64NEON(a, b []float64) (sum float64, n int) {
+	panic("NEON not available")
+}
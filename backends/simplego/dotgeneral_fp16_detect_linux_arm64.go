@@ -4,23 +4,15 @@ package simplego
 
 // detectFP16NEON checks if FP16 NEON instructions (FMLAL/FMLAL2) are available.
 // These require ARMv8.2-A with FEAT_FHM (half-precision FP multiply-add).
+//
+// Notice FEAT_FHM is reported as "asimdfhm" by the kernel: "fphp" and "asimdhp" only indicate half-precision
+// arithmetic (FEAT_FP16), and don't imply FMLAL/FMLAL2 support.
 func detectFP16NEON() bool {
-	// NOTE: Even when /proc/cpuinfo reports "fphp" or "asimdhp", Apple Silicon
-	// running in Docker does NOT actually support FMLAL/FMLAL2 instructions.
-	// They cause SIGILL. Apple's ARM implementation doesn't include these.
-	// Disable FP16 NEON for safety until we can verify on actual ARM Linux hardware
-	// (e.g., AWS Graviton, Ampere Altra, or other server ARM64 chips).
-	// TODO: Re-enable with proper hardware detection on real ARM64 Linux servers.
-	return false
+	return cpuFeaturesARM64.HasFHM
 }
 
-// detectBF16NEON checks if BF16 NEON instructions (BFMLALB/BFMLALT) are available.
+// detectBF16NEON checks if BF16 NEON instructions (BFDOT, BFMLALB/BFMLALT) are available.
 // These require ARMv8.6-A with FEAT_BF16.
 func detectBF16NEON() bool {
-	// NOTE: Even when /proc/cpuinfo reports "bf16", Apple Silicon running in Docker
-	// does NOT actually support BFMLALB/BFMLALT instructions - they cause SIGILL.
-	// This appears to be a hardware limitation of Apple M-series chips.
-	// Disable BF16 NEON for safety until we can verify on actual ARM Linux hardware.
-	// TODO: Re-enable with proper hardware detection on real ARM64 Linux servers.
-	return false
+	return cpuFeaturesARM64.HasBF16
 }
@@ -30,6 +30,8 @@ var hasFP16NEON = detectFP16NEON()
 // BFMLALB/BFMLALT require ARMv8.6-A (FEAT_BF16).
 var hasBF16NEON = detectBF16NEON()
 
+var _ = registerSIMD(SIMDFP16, &hasFP16NEON, &hasBF16NEON)
+
 // execNormalizedDotGeneralFloat16ToFloat32 is a specialized implementation for FP16×FP16→FP32
 // using native FMLAL instructions when available.
 func execNormalizedDotGeneralFloat16ToFloat32(lhs, rhs, output *Buffer, params *dotGeneralNodeData, batchStartIdx, batchEndIdx int) {
@@ -0,0 +1,17 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build noasm || (!amd64 && !arm64)
+
+package simplego
+
+// dotProductInt8 returns the int32 dot product of the int8 slices a and b, which must have the same length.
+func dotProductInt8(a, b []int8) int32 {
+	return dotProductInt8Go(a, b)
+}
+
+// dotProductUint8 returns the int32 dot product of the uint8 slices a and b, which must have the same length.
+func dotProductUint8(a, b []uint8) int32 {
+	return dotProductUint8Go(a, b)
+}
@@ -12,7 +12,8 @@ import (
 )
 
 // execNormalizedDotGeneralInt8ToInt32 is a fallback implementation for int8×int8→int32
-// matrix multiplication on non-ARM64 platforms. Uses scalar operations.
+// matrix multiplication on non-ARM64 platforms. It uses the VNNI kernels on AMD64, if available,
+// and scalar operations otherwise (see dotProductInt8).
 func execNormalizedDotGeneralInt8ToInt32(lhs, rhs, output *Buffer, params *dotGeneralNodeData, batchStartIdx, batchEndIdx int) {
 	lhsFlat := lhs.flat.([]int8)
 	rhsFlat := rhs.flat.([]int8)
@@ -37,15 +38,8 @@ func execNormalizedDotGeneralInt8ToInt32(lhs, rhs, output *Buffer, params *dotGe
 
 			for idxRhsCross := 0; idxRhsCross < rhsCrossSize; idxRhsCross++ {
 				rhsColStartIdx := rhsBaseIdx + idxRhsCross*contractingSize
-				var sum int32
-
-				// Scalar implementation
-				for idxContracting := 0; idxContracting < contractingSize; idxContracting++ {
-					lhsVal := int32(lhsFlat[lhsRowStartIdx+idxContracting])
-					rhsVal := int32(rhsFlat[rhsColStartIdx+idxContracting])
-					sum += lhsVal * rhsVal
-				}
-
+				sum := dotProductInt8(lhsFlat[lhsRowStartIdx:lhsRowStartIdx+contractingSize],
+					rhsFlat[rhsColStartIdx:rhsColStartIdx+contractingSize])
 				outputFlat[outputRowStartIdx+idxRhsCross] += sum
 			}
 		}
@@ -54,6 +48,7 @@ func execNormalizedDotGeneralInt8ToInt32(lhs, rhs, output *Buffer, params *dotGe
 
 // execNormalizedDotGeneralUint8ToInt32 is a fallback implementation for uint8×uint8→int32
 // Also handles mixed int8/uint8 cases by treating everything as unsigned
+// It uses the VNNI kernels on AMD64, if available, and scalar operations otherwise (see dotProductUint8).
 func execNormalizedDotGeneralUint8ToInt32(lhs, rhs, output *Buffer, params *dotGeneralNodeData, batchStartIdx, batchEndIdx int) {
 	// Handle both uint8 and int8 inputs by converting to uint8 view
 	var lhsFlat, rhsFlat []uint8
@@ -98,15 +93,8 @@ func execNormalizedDotGeneralUint8ToInt32(lhs, rhs, output *Buffer, params *dotG
 
 			for idxRhsCross := 0; idxRhsCross < rhsCrossSize; idxRhsCross++ {
 				rhsColStartIdx := rhsBaseIdx + idxRhsCross*contractingSize
-				var sum int32
-
-				// Scalar implementation
-				for idxContracting := 0; idxContracting < contractingSize; idxContracting++ {
-					lhsVal := int32(lhsFlat[lhsRowStartIdx+idxContracting])
-					rhsVal := int32(rhsFlat[rhsColStartIdx+idxContracting])
-					sum += lhsVal * rhsVal
-				}
-
+				sum := dotProductUint8(lhsFlat[lhsRowStartIdx:lhsRowStartIdx+contractingSize],
+					rhsFlat[rhsColStartIdx:rhsColStartIdx+contractingSize])
 				outputFlat[outputRowStartIdx+idxRhsCross] += sum
 			}
 		}
@@ -0,0 +1,86 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !noasm && amd64
+
+package simplego
+
+import (
+	"runtime"
+	"unsafe"
+)
+
+// hasAVX512VNNI and hasAVXVNNI indicate whether the int8 dot-product instructions (VPDPBUSD) are available,
+// with 512 bits (AVX-512 VNNI) or with 256 bits (AVX-VNNI, e.g. in CPUs without AVX-512).
+//
+// The kernels are selected in order of preference: AVX-512 VNNI → AVX-VNNI → scalar. They can be disabled
+// with SetSIMD.
+var (
+	hasAVX512VNNI = cpuFeaturesAMD64.HasAVX512F && cpuFeaturesAMD64.HasAVX512VNNI
+	hasAVXVNNI    = cpuFeaturesAMD64.HasAVX2 && cpuFeaturesAMD64.HasAVXVNNI
+)
+
+var _ = registerSIMD(SIMDVNNI, &hasAVX512VNNI, &hasAVXVNNI)
+
+// dotProductInt8_avx512vnni_asm is implemented in dotgeneral_int8_vnni_amd64.s.
+// It computes the dot product of the first n/64*64 int8 values of a and b.
+//
+//go:noescape
+func dotProductInt8_avx512vnni_asm(a, b unsafe.Pointer, n int64) int32
+
+// dotProductUint8_avx512vnni_asm is implemented in dotgeneral_int8_vnni_amd64.s.
+// It computes the dot product of the first n/64*64 uint8 values of a and b.
+//
+//go:noescape
+func dotProductUint8_avx512vnni_asm(a, b unsafe.Pointer, n int64) int32
+
+// dotProductInt8_avxvnni_asm is implemented in dotgeneral_int8_vnni_amd64.s.
+// It computes the dot product of the first n/32*32 int8 values of a and b.
+//
+//go:noescape
+func dotProductInt8_avxvnni_asm(a, b unsafe.Pointer, n int64) int32
+
+// dotProductUint8_avxvnni_asm is implemented in dotgeneral_int8_vnni_amd64.s.
+// It computes the dot product of the first n/32*32 uint8 values of a and b.
+//
+//go:noescape
+func dotProductUint8_avxvnni_asm(a, b unsafe.Pointer, n int64) int32
+
+// dotProductInt8 returns the int32 dot product of the int8 slices a and b, which must have the same length.
+func dotProductInt8(a, b []int8) int32 {
+	n := len(a)
+	b = b[:n]
+	var sum int32
+	var done int
+	switch {
+	case hasAVX512VNNI && n >= 64:
+		sum = dotProductInt8_avx512vnni_asm(unsafe.Pointer(&a[0]), unsafe.Pointer(&b[0]), int64(n))
+		done = n &^ 63
+	case hasAVXVNNI && n >= 32:
+		sum = dotProductInt8_avxvnni_asm(unsafe.Pointer(&a[0]), unsafe.Pointer(&b[0]), int64(n))
+		done = n &^ 31
+	}
+	runtime.KeepAlive(a)
+	runtime.KeepAlive(b)
+	return sum + dotProductInt8Go(a[done:], b[done:])
+}
+
+// dotProductUint8 returns the int32 dot product of the uint8 slices a and b, which must have the same length.
+func dotProductUint8(a, b []uint8) int32 {
+	n := len(a)
+	b = b[:n]
+	var sum int32
+	var done int
+	switch {
+	case hasAVX512VNNI && n >= 64:
+		sum = dotProductUint8_avx512vnni_asm(unsafe.Pointer(&a[0]), unsafe.Pointer(&b[0]), int64(n))
+		done = n &^ 63
+	case hasAVXVNNI && n >= 32:
+		sum = dotProductUint8_avxvnni_asm(unsafe.Pointer(&a[0]), unsafe.Pointer(&b[0]), int64(n))
+		done = n &^ 31
+	}
+	runtime.KeepAlive(a)
+	runtime.KeepAlive(b)
+	return sum + dotProductUint8Go(a[done:], b[done:])
+}
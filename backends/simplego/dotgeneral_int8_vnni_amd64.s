@@ -0,0 +1,187 @@
+//go:build !noasm && amd64
+
+// Int8 and uint8 dot products for AMD64, using VPDPBUSD (AVX-512 VNNI and AVX-VNNI).
+//
+// VPDPBUSD multiplies unsigned bytes by signed bytes, and accumulates groups of 4 products into int32 lanes.
+// Both signed and unsigned dot products are mapped to it by flipping the sign bit of one operand, which
+// adds (or subtracts) 128 to each of its values, and correcting the result with the sum of the other operand:
+//
+//	int8:  Σ a·b = Σ (a+128)·b - 128·Σ b,  with (a+128) = a XOR 0x80 as uint8.
+//	uint8: Σ a·b = Σ a·(b-128) + 128·Σ a,  with (b-128) = b XOR 0x80 as int8.
+//
+// The sums Σ b and Σ a are also calculated with VPDPBUSD, multiplying by a vector of ones.
+//
+// The kernels only process full vectors (64 bytes for AVX-512, 32 bytes for AVX-VNNI): the remaining
+// values are handled by the Go wrappers.
+
+#include "textflag.h"
+
+// The Go assembler only encodes VPDPBUSD with EVEX (AVX-512 VNNI), so the VEX encoded versions (AVX-VNNI)
+// are given as bytes: VEX.256.66.0F38.W0 50 /r.
+
+// Y0 += VPDPBUSD(u8 Y4, s8 Y5)
+#define VPDPBUSD_Y5_Y4_Y0 BYTE $0xC4; BYTE $0xE2; BYTE $0x5D; BYTE $0x50; BYTE $0xC5
+
+// Y1 += VPDPBUSD(u8 Y6, s8 Y5)
+#define VPDPBUSD_Y5_Y6_Y1 BYTE $0xC4; BYTE $0xE2; BYTE $0x4D; BYTE $0x50; BYTE $0xCD
+
+// Y1 += VPDPBUSD(u8 Y4, s8 Y6)
+#define VPDPBUSD_Y6_Y4_Y1 BYTE $0xC4; BYTE $0xE2; BYTE $0x5D; BYTE $0x50; BYTE $0xCE
+
+// REDUCE_Y0 sums the 8 int32 lanes of Y0 into AX. It uses X1 as scratch.
+#define REDUCE_Y0 \
+	VEXTRACTI128 $1, Y0, X1;    \
+	VPADDD       X1, X0, X0;    \
+	VPSHUFD      $0x4E, X0, X1; \
+	VPADDD       X1, X0, X0;    \
+	VPSHUFD      $0xB1, X0, X1; \
+	VPADDD       X1, X0, X0;    \
+	VMOVD        X0, AX
+
+// func dotProductInt8_avx512vnni_asm(a, b unsafe.Pointer, n int64) int32
+TEXT ·dotProductInt8_avx512vnni_asm(SB), NOSPLIT, $0-28
+	MOVQ a+0(FP), SI
+	MOVQ b+8(FP), DI
+	MOVQ n+16(FP), CX
+
+	MOVL         $0x80808080, AX
+	VPBROADCASTD AX, Z14
+	MOVL         $0x01010101, AX
+	VPBROADCASTD AX, Z15
+	VPXORD       Z0, Z0, Z0
+	VPXORD       Z1, Z1, Z1
+
+	SHRQ $6, CX
+	JZ   int8_avx512_reduce
+
+int8_avx512_loop:
+	VMOVDQU32 (SI), Z4
+	VMOVDQU32 (DI), Z5
+	VPXORD    Z14, Z4, Z4
+	VPDPBUSD  Z5, Z4, Z0
+	VPDPBUSD  Z5, Z15, Z1
+	ADDQ      $64, SI
+	ADDQ      $64, DI
+	DECQ      CX
+	JNZ       int8_avx512_loop
+
+int8_avx512_reduce:
+	VPSLLD        $7, Z1, Z1
+	VPSUBD        Z1, Z0, Z0
+	VEXTRACTI64X4 $1, Z0, Y1
+	VPADDD        Y1, Y0, Y0
+	REDUCE_Y0
+	MOVL          AX, ret+24(FP)
+	VZEROUPPER
+	RET
+
+// func dotProductUint8_avx512vnni_asm(a, b unsafe.Pointer, n int64) int32
+TEXT ·dotProductUint8_avx512vnni_asm(SB), NOSPLIT, $0-28
+	MOVQ a+0(FP), SI
+	MOVQ b+8(FP), DI
+	MOVQ n+16(FP), CX
+
+	MOVL         $0x80808080, AX
+	VPBROADCASTD AX, Z14
+	MOVL         $0x01010101, AX
+	VPBROADCASTD AX, Z15
+	VPXORD       Z0, Z0, Z0
+	VPXORD       Z1, Z1, Z1
+
+	SHRQ $6, CX
+	JZ   uint8_avx512_reduce
+
+uint8_avx512_loop:
+	VMOVDQU32 (SI), Z4
+	VMOVDQU32 (DI), Z5
+	VPXORD    Z14, Z5, Z5
+	VPDPBUSD  Z5, Z4, Z0
+	VPDPBUSD  Z15, Z4, Z1
+	ADDQ      $64, SI
+	ADDQ      $64, DI
+	DECQ      CX
+	JNZ       uint8_avx512_loop
+
+uint8_avx512_reduce:
+	VPSLLD        $7, Z1, Z1
+	VPADDD        Z1, Z0, Z0
+	VEXTRACTI64X4 $1, Z0, Y1
+	VPADDD        Y1, Y0, Y0
+	REDUCE_Y0
+	MOVL          AX, ret+24(FP)
+	VZEROUPPER
+	RET
+
+// func dotProductInt8_avxvnni_asm(a, b unsafe.Pointer, n int64) int32
+TEXT ·dotProductInt8_avxvnni_asm(SB), NOSPLIT, $0-28
+	MOVQ a+0(FP), SI
+	MOVQ b+8(FP), DI
+	MOVQ n+16(FP), CX
+
+	MOVL         $0x80808080, AX
+	VMOVD        AX, X7
+	VPBROADCASTD X7, Y7
+	MOVL         $0x01010101, AX
+	VMOVD        AX, X6
+	VPBROADCASTD X6, Y6
+	VPXOR        Y0, Y0, Y0
+	VPXOR        Y1, Y1, Y1
+
+	SHRQ $5, CX
+	JZ   int8_avx_reduce
+
+int8_avx_loop:
+	VMOVDQU (SI), Y4
+	VMOVDQU (DI), Y5
+	VPXOR   Y7, Y4, Y4
+	VPDPBUSD_Y5_Y4_Y0
+	VPDPBUSD_Y5_Y6_Y1
+	ADDQ    $32, SI
+	ADDQ    $32, DI
+	DECQ    CX
+	JNZ     int8_avx_loop
+
+int8_avx_reduce:
+	VPSLLD $7, Y1, Y1
+	VPSUBD Y1, Y0, Y0
+	REDUCE_Y0
+	MOVL   AX, ret+24(FP)
+	VZEROUPPER
+	RET
+
+// func dotProductUint8_avxvnni_asm(a, b unsafe.Pointer, n int64) int32
+TEXT ·dotProductUint8_avxvnni_asm(SB), NOSPLIT, $0-28
+	MOVQ a+0(FP), SI
+	MOVQ b+8(FP), DI
+	MOVQ n+16(FP), CX
+
+	MOVL         $0x80808080, AX
+	VMOVD        AX, X7
+	VPBROADCASTD X7, Y7
+	MOVL         $0x01010101, AX
+	VMOVD        AX, X6
+	VPBROADCASTD X6, Y6
+	VPXOR        Y0, Y0, Y0
+	VPXOR        Y1, Y1, Y1
+
+	SHRQ $5, CX
+	JZ   uint8_avx_reduce
+
+uint8_avx_loop:
+	VMOVDQU (SI), Y4
+	VMOVDQU (DI), Y5
+	VPXOR   Y7, Y5, Y5
+	VPDPBUSD_Y5_Y4_Y0
+	VPDPBUSD_Y6_Y4_Y1
+	ADDQ    $32, SI
+	ADDQ    $32, DI
+	DECQ    CX
+	JNZ     uint8_avx_loop
+
+uint8_avx_reduce:
+	VPSLLD $7, Y1, Y1
+	VPADDD Y1, Y0, Y0
+	REDUCE_Y0
+	MOVL   AX, ret+24(FP)
+	VZEROUPPER
+	RET
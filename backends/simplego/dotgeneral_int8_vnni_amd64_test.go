@@ -0,0 +1,57 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !noasm && amd64
+
+package simplego
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDotProductInt8VNNI(t *testing.T) {
+	defer func(avx512, avx bool) { hasAVX512VNNI, hasAVXVNNI = avx512, avx }(hasAVX512VNNI, hasAVXVNNI)
+	kernels := []struct {
+		name           string
+		avx512, avx256 bool
+	}{
+		{"avx512vnni", true, false},
+		{"avxvnni", false, true},
+	}
+	rng := rand.New(rand.NewSource(42))
+	for _, kernel := range kernels {
+		if (kernel.avx512 && !cpuFeaturesAMD64.HasAVX512VNNI) || (kernel.avx256 && !cpuFeaturesAMD64.HasAVXVNNI) {
+			fmt.Printf("\tskipping %s, not supported by the CPU\n", kernel.name)
+			continue
+		}
+		hasAVX512VNNI, hasAVXVNNI = kernel.avx512, kernel.avx256
+		for _, n := range []int{0, 1, 31, 32, 63, 64, 65, 200, 1000} {
+			t.Run(fmt.Sprintf("%s/n=%d", kernel.name, n), func(t *testing.T) {
+				a, b := make([]int8, n), make([]int8, n)
+				for i := range n {
+					a[i], b[i] = int8(rng.Intn(256)-128), int8(rng.Intn(256)-128)
+				}
+				require.Equal(t, dotProductInt8Go(a, b), dotProductInt8(a, b))
+
+				ua, ub := make([]uint8, n), make([]uint8, n)
+				for i := range n {
+					ua[i], ub[i] = uint8(rng.Intn(256)), uint8(rng.Intn(256))
+				}
+				require.Equal(t, dotProductUint8Go(ua, ub), dotProductUint8(ua, ub))
+
+				// Extreme values.
+				for i := range n {
+					a[i], b[i] = -128, -128
+					ua[i], ub[i] = 255, 255
+				}
+				require.Equal(t, int32(n*128*128), dotProductInt8(a, b))
+				require.Equal(t, int32(n*255*255), dotProductUint8(ua, ub))
+			})
+		}
+	}
+}
@@ -578,6 +578,14 @@ type kernelFuncType func(lhsBlockIdx, rhsBlockIdx, outputBlockIdx int)
 // buildDotGeneralKernel returns a kernel function that does a DotGeneral (matrix multiplication) of the lhs/rhs block
 // to the corresponding output buffer block, given the indices of the square blocks.
 func buildDotGeneralKernel[T PODNumericConstraints](lhs, rhs, output *Buffer, blockDim int) kernelFuncType {
+	// SME (e.g. Apple M4) computes whole tiles of the block with outer products, and it is used for float32 if
+	// the block dimension is a multiple of its tile dimension.
+	if hasSME {
+		if kernel := buildDotGeneralKernelSME(lhs, rhs, output, blockDim); kernel != nil {
+			return kernel
+		}
+	}
+
 	lhsFlat := lhs.flat.([]T)
 	rhsFlat := rhs.flat.([]T)
 	outputFlat := output.flat.([]T)
@@ -594,29 +602,36 @@ func buildDotGeneralKernel[T PODNumericConstraints](lhs, rhs, output *Buffer, bl
 				lhsIdx := baseLhsIdx
 				var sum0, sum1, sum2, sum3 T
 
-				// SIMD acceleration for float32 on ARM64 using NEON Group4.
+				// SIMD acceleration for float32 using NEON Group4 on ARM64, or AVX-512/AVX2 Group4 on AMD64.
 				// For other types or platforms, fall back to pure Go.
 				//
 				// Threshold of blockDim >= 16 enables NEON for most practical matrix sizes.
 				// At blockDim=32 (default for float32), NEON processes 8 vector iterations per row.
 				// Even at blockDim=16, we get 4 vector iterations which provides meaningful speedup.
-				if lhsFloat32, ok := any(lhsFlat).([]float32); ok {
-					if hasNEON && blockDim >= 16 {
-						// NEON Group4 path - fastest for all ARM64 including Apple M4
-						rhsFloat32 := any(rhsFlat).([]float32)
-						outputFloat32 := any(outputFlat).([]float32)
-
-						s0, s1, s2, s3 := dotProductInnerLoopNEON(
-							lhsFloat32, rhsFloat32, outputFloat32,
-							lhsIdx, rhsIdx, outputIdx, blockDim)
-
-						sum0 = T(s0)
-						sum1 = T(s1)
-						sum2 = T(s2)
-						sum3 = T(s3)
-						rhsIdx += blockDim // Compensate for skipping scalar loop
-						goto done
+				// AVX-512 processes 16 floats per vector and AVX2 8, so the same threshold applies.
+				// SVE is only enabled for vectors of 256 bits or more, where it is faster than NEON.
+				if lhsFloat32, ok := any(lhsFlat).([]float32); ok && blockDim >= 16 && (hasNEON || hasSVE || hasAVX512 || hasAVX2) {
+					innerLoop := dotProductInnerLoopNEON // NEON Group4 path - fastest for all ARM64 including Apple M4
+					if hasAVX512 {
+						innerLoop = dotProductInnerLoopAVX512
+					} else if hasAVX2 {
+						innerLoop = dotProductInnerLoopAVX2
+					} else if hasSVE {
+						innerLoop = dotProductInnerLoopSVE
 					}
+					rhsFloat32 := any(rhsFlat).([]float32)
+					outputFloat32 := any(outputFlat).([]float32)
+
+					s0, s1, s2, s3 := innerLoop(
+						lhsFloat32, rhsFloat32, outputFloat32,
+						lhsIdx, rhsIdx, outputIdx, blockDim)
+
+					sum0 = T(s0)
+					sum1 = T(s1)
+					sum2 = T(s2)
+					sum3 = T(s3)
+					rhsIdx += blockDim // Compensate for skipping scalar loop
+					goto done
 				}
 
 				// Pure Go implementation fallback
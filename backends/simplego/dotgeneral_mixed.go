@@ -0,0 +1,264 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"github.com/gomlx/gopjrt/dtypes"
+	"github.com/gomlx/gopjrt/dtypes/bfloat16"
+	"github.com/x448/float16"
+)
+
+// mixedGemvBlockSize is the number of values of the RHS converted to float32 at a time by the mixed-precision
+// vector × matrix products: the converted block (32KB) stays in the L1/L2 cache.
+const mixedGemvBlockSize = 8 * 1024
+
+// halfFloat are the half-precision dtypes accepted by the mixed-precision DotGeneral.
+type halfFloat interface {
+	float16.Float16 | bfloat16.BFloat16
+}
+
+// isMixedPrecisionDotGeneral returns whether DotGeneral accepts operands of the different dtypes lhsDType and
+// rhsDType: any combination of Float32, Float16 and BFloat16. They are multiplied (and accumulated) in float32,
+// and the output is Float32.
+//
+// This allows keeping the weights of a model in half-precision (half the memory, and half the memory bandwidth to
+// read them) while the activations and the results are kept in float32.
+func isMixedPrecisionDotGeneral(lhsDType, rhsDType dtypes.DType) bool {
+	isFloat := func(dtype dtypes.DType) bool {
+		return dtype == dtypes.Float32 || dtype == dtypes.Float16 || dtype == dtypes.BFloat16
+	}
+	return lhsDType != rhsDType && isFloat(lhsDType) && isFloat(rhsDType)
+}
+
+// canUseMixedFastPath determines if we can use the mixed-precision fast path for this DotGeneral operation: a
+// float32 LHS and a Float16 or BFloat16 RHS (usually the weights), with the RHS in the standard layout (see
+// isStandardMatmul) or transposed (see isTransposedRHSMatmul).
+func canUseMixedFastPath(lhs, rhs *Buffer, params *dotGeneralNodeData) (ok, rhsTransposed bool) {
+	if lhs.shape.DType != dtypes.Float32 || (rhs.shape.DType != dtypes.Float16 && rhs.shape.DType != dtypes.BFloat16) {
+		return false, false
+	}
+	if isStandardMatmul(lhs.shape, rhs.shape,
+		params.lhsContractingAxes, params.rhsContractingAxes,
+		params.lhsBatchAxes, params.rhsBatchAxes) {
+		return true, false
+	}
+	if isTransposedRHSMatmul(lhs.shape, rhs.shape,
+		params.lhsContractingAxes, params.rhsContractingAxes,
+		params.lhsBatchAxes, params.rhsBatchAxes) {
+		return true, true
+	}
+	return false, false
+}
+
+// execDotGeneralMixedFastPath is the fast path for the multiplication of a float32 LHS by a half-precision RHS,
+// see canUseMixedFastPath. The output must be zero-initialized.
+//
+// The RHS is converted to float32 in blocks that stay in cache, as they are used by the float32 kernels, so a
+// float32 copy of the RHS is never materialized:
+//
+//   - With the RHS in the standard layout, the blocks of the RHS are converted while they are packed for
+//     gemmFloat32 (see gemmHalfB), or, for fewer than gemmMR rows, in blocks of rows for gemvFloat32 (see gemvHalfB).
+//   - With the RHS transposed, 4 rows of the RHS are converted at a time, and multiplied by all the LHS rows with
+//     the Group4 dot-product kernels.
+func execDotGeneralMixedFastPath(backend *Backend, lhs, rhs *Buffer, params *dotGeneralNodeData, output *Buffer,
+	epilogue *float32Epilogue, rhsTransposed bool) {
+	switch rhsFlat := rhs.flat.(type) {
+	case []float16.Float16:
+		execDotGeneralMixedFastPathHalf(backend, lhs.flat.([]float32), rhsFlat, params, output.flat.([]float32),
+			epilogue, rhsTransposed)
+	case []bfloat16.BFloat16:
+		execDotGeneralMixedFastPathHalf(backend, lhs.flat.([]float32), rhsFlat, params, output.flat.([]float32),
+			epilogue, rhsTransposed)
+	}
+}
+
+// execDotGeneralMixedFastPathHalf implements execDotGeneralMixedFastPath for the RHS dtype T.
+func execDotGeneralMixedFastPathHalf[T halfFloat](backend *Backend, lhsFlat []float32, rhsFlat []T,
+	params *dotGeneralNodeData, outputFlat []float32, epilogue *float32Epilogue, rhsTransposed bool) {
+	batchSize := params.batchSize
+	lhsCrossSize := params.lhsCrossSize       // M
+	rhsCrossSize := params.rhsCrossSize       // N
+	contractingSize := params.contractingSize // K
+
+	lhsBatchStride := lhsCrossSize * contractingSize // M * K
+	rhsBatchStride := rhsCrossSize * contractingSize // N * K
+	outputBatchStride := lhsCrossSize * rhsCrossSize // M * N
+
+	if rhsTransposed {
+		numGroups := (rhsCrossSize + 3) / 4
+		parallelizeDotGeneral(backend, batchSize, numGroups, 4*contractingSize*lhsCrossSize, func(batchIdx, groupStart, groupEnd int) {
+			rhsRowsBuf := getGemmPackedBuffer(4 * contractingSize)
+			defer gemmPackedPool.Put(rhsRowsBuf)
+			rhsRows := *rhsRowsBuf
+			for group := groupStart; group < groupEnd; group++ {
+				n := 4 * group
+				numRows := min(4, rhsCrossSize-n)
+				convertHalfToFloat32(rhsFlat[batchIdx*rhsBatchStride+n*contractingSize:][:numRows*contractingSize], rhsRows)
+				for m := range lhsCrossSize {
+					lhsRowIdx := batchIdx*lhsBatchStride + m*contractingSize
+					outputIdx := batchIdx*outputBatchStride + m*rhsCrossSize + n
+					if numRows == 4 {
+						dotProductGroup4Float32(lhsFlat, rhsRows, outputFlat, lhsRowIdx, 0, outputIdx, contractingSize)
+						continue
+					}
+					for row := range numRows {
+						outputFlat[outputIdx+row] = dotProductFloat32(lhsFlat, rhsRows, lhsRowIdx, row*contractingSize, contractingSize)
+					}
+				}
+			}
+		})
+		epilogue.apply(outputFlat, 0)
+		return
+	}
+
+	if lhsCrossSize < gemmMR {
+		// Few rows: each row is a vector × matrix product, with the columns split among the backend workers.
+		numColBlocks := (rhsCrossSize + gemvNB - 1) / gemvNB
+		parallelizeDotGeneral(backend, batchSize*lhsCrossSize, numColBlocks, gemvNB*contractingSize, func(row, blockStart, blockEnd int) {
+			batchIdx, m := row/lhsCrossSize, row%lhsCrossSize
+			lhsRowIdx := batchIdx*lhsBatchStride + m*contractingSize
+			outputRowIdx := batchIdx*outputBatchStride + m*rhsCrossSize
+			colStart := blockStart * gemvNB
+			colEnd := min(blockEnd*gemvNB, rhsCrossSize)
+			gemvHalfB(contractingSize, colEnd-colStart,
+				lhsFlat, lhsRowIdx,
+				rhsFlat, batchIdx*rhsBatchStride+colStart, rhsCrossSize,
+				outputFlat, outputRowIdx+colStart)
+			epilogue.apply(outputFlat[outputRowIdx+colStart:outputRowIdx+colEnd], colStart)
+		})
+		return
+	}
+	blockSizes := backend.gemmBlockSizes
+	parallelizeDotGeneral(backend, batchSize, lhsCrossSize, rhsCrossSize*contractingSize, func(batchIdx, rowStart, rowEnd int) {
+		outputBaseIdx := batchIdx * outputBatchStride
+		gemmHalfB(blockSizes, rowEnd-rowStart, rhsCrossSize, contractingSize,
+			lhsFlat, batchIdx*lhsBatchStride+rowStart*contractingSize, contractingSize,
+			rhsFlat, batchIdx*rhsBatchStride, rhsCrossSize,
+			outputFlat, outputBaseIdx+rowStart*rhsCrossSize, rhsCrossSize)
+		epilogue.apply(outputFlat[outputBaseIdx+rowStart*rhsCrossSize:outputBaseIdx+rowEnd*rhsCrossSize], 0)
+	})
+}
+
+// gemmHalfB computes C += A·B, like gemmFloat32, where B is a half-precision [k, n] matrix: each block of B is
+// converted to float32 while it is packed (see gemmPackBHalf).
+func gemmHalfB[T halfFloat](sizes GEMMBlockSizes, m, n, k int,
+	a []float32, aIdx, lda int,
+	b []T, bIdx, ldb int,
+	c []float32, cIdx, ldc int) {
+	if m == 0 || n == 0 || k == 0 {
+		return
+	}
+	mc, kc, nc := min(sizes.MC, m), min(sizes.KC, k), min(sizes.NC, n)
+	aPackedBuf := getGemmPackedBuffer(roundUp(mc, gemmMR) * kc)
+	bPackedBuf := getGemmPackedBuffer(roundUp(nc, gemmNR) * kc)
+	bRowBuf := getGemmPackedBuffer(nc)
+	defer gemmPackedPool.Put(aPackedBuf)
+	defer gemmPackedPool.Put(bPackedBuf)
+	defer gemmPackedPool.Put(bRowBuf)
+	aPacked, bPacked, bRow := *aPackedBuf, *bPackedBuf, *bRowBuf
+
+	for jc := 0; jc < n; jc += sizes.NC {
+		ncBlock := min(sizes.NC, n-jc)
+		for pc := 0; pc < k; pc += sizes.KC {
+			kcBlock := min(sizes.KC, k-pc)
+			gemmPackBHalf(kcBlock, ncBlock, b, bIdx+pc*ldb+jc, ldb, bRow, bPacked)
+			for ic := 0; ic < m; ic += sizes.MC {
+				mcBlock := min(sizes.MC, m-ic)
+				gemmPackA(mcBlock, kcBlock, a, aIdx+ic*lda+pc, lda, aPacked)
+				gemmMacroKernel(mcBlock, ncBlock, kcBlock, aPacked, bPacked, c, cIdx+ic*ldc+jc, ldc)
+			}
+		}
+	}
+}
+
+// gemmPackBHalf packs the half-precision block B[kc, nc] like gemmPackB, converting the values to float32.
+// Each row of the block is converted at once into bRow (with at least nc values), and then copied to the slivers.
+func gemmPackBHalf[T halfFloat](kc, nc int, b []T, bIdx, ldb int, bRow, packed []float32) {
+	bRow = bRow[:nc]
+	for p := range kc {
+		convertHalfToFloat32(b[bIdx+p*ldb:bIdx+p*ldb+nc], bRow)
+		packedIdx := p * gemmNR
+		for jr := 0; jr < nc; jr += gemmNR {
+			nr := min(gemmNR, nc-jr)
+			dst := packed[packedIdx : packedIdx+gemmNR]
+			copy(dst, bRow[jr:jr+nr])
+			clear(dst[nr:])
+			packedIdx += kc * gemmNR
+		}
+	}
+}
+
+// gemvHalfB computes c += a·B, like gemvFloat32, where B is a half-precision [k, n] matrix: blocks of B with up to
+// mixedGemvBlockSize values (and at least one row) are converted to float32 at a time.
+func gemvHalfB[T halfFloat](k, n int,
+	a []float32, aIdx int,
+	b []T, bIdx, ldb int,
+	c []float32, cIdx int) {
+	blockRows := max(1, mixedGemvBlockSize/n)
+	blockBuf := getGemmPackedBuffer(blockRows * n)
+	defer gemmPackedPool.Put(blockBuf)
+	block := *blockBuf
+	for p0 := 0; p0 < k; p0 += blockRows {
+		kb := min(blockRows, k-p0)
+		for p := range kb {
+			rowIdx := bIdx + (p0+p)*ldb
+			convertHalfToFloat32(b[rowIdx:rowIdx+n], block[p*n:])
+		}
+		gemvFloat32(kb, n, a, aIdx+p0, block, 0, n, c, cIdx)
+	}
+}
+
+// convertHalfToFloat32 converts the half-precision values of src to float32 into dst, that must have at least the
+// length of src.
+//
+// It uses F16C (Float16) and AVX2 (BFloat16) on AMD64, and NEON on ARM64, if available.
+func convertHalfToFloat32[T halfFloat](src []T, dst []float32) {
+	n := len(src)
+	dst = dst[:n]
+	i := 0
+	switch src := any(src).(type) {
+	case []float16.Float16:
+		if !hasAVX2 || !hasF16C || n < 8 {
+			// With NEON on ARM64, see convertFP16ToFP32_neon_asm.
+			convertFloat16SliceToFloat32(src, dst)
+			return
+		}
+		i = convertFloat16ToFloat32F16C(src, dst)
+		for ; i < n; i++ {
+			dst[i] = src[i].Float32()
+		}
+	case []bfloat16.BFloat16:
+		if n >= 8 {
+			switch {
+			case hasAVX2:
+				i = convertBFloat16ToFloat32AVX2(src, dst)
+			case hasNEON:
+				i = convertBFloat16ToFloat32NEON(src, dst)
+			}
+		}
+		for ; i < n; i++ {
+			dst[i] = src[i].Float32()
+		}
+	}
+}
+
+// convertMixedOperandToFloat32 returns a float32 copy of the half-precision operand of a mixed-precision
+// DotGeneral, taken from the backend buffer pool, or nil if the operand is already float32.
+func convertMixedOperandToFloat32(backend *Backend, operand *Buffer) *Buffer {
+	var converted *Buffer
+	switch flat := operand.flat.(type) {
+	case []float16.Float16:
+		converted = backend.getBuffer(dtypes.Float32, len(flat))
+		convertHalfToFloat32(flat, converted.flat.([]float32))
+	case []bfloat16.BFloat16:
+		converted = backend.getBuffer(dtypes.Float32, len(flat))
+		convertHalfToFloat32(flat, converted.flat.([]float32))
+	default:
+		return nil
+	}
+	converted.shape = operand.shape.Clone()
+	converted.shape.DType = dtypes.Float32
+	return converted
+}
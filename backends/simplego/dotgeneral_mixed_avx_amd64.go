@@ -0,0 +1,55 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !noasm && amd64
+
+package simplego
+
+import (
+	"runtime"
+	"unsafe"
+
+	"github.com/gomlx/gopjrt/dtypes/bfloat16"
+	"github.com/x448/float16"
+)
+
+// hasF16C indicates whether the CPU supports the F16C conversions between Float16 and float32.
+// They are only used along with the AVX2 kernels, so they are disabled with them by SetSIMD.
+var hasF16C = cpuFeaturesAMD64.HasF16C
+
+// convertFloat16ToFloat32_f16c_asm is implemented in dotgeneral_mixed_avx_amd64.s.
+// It converts src[0:n] to dst[0:n], for n a multiple of 8.
+//
+//go:noescape
+func convertFloat16ToFloat32_f16c_asm(src, dst unsafe.Pointer, n int64)
+
+// convertBFloat16ToFloat32_avx2_asm is implemented in dotgeneral_mixed_avx_amd64.s.
+// It converts src[0:n] to dst[0:n], for n a multiple of 8.
+//
+//go:noescape
+func convertBFloat16ToFloat32_avx2_asm(src, dst unsafe.Pointer, n int64)
+
+// convertFloat16ToFloat32F16C converts src[0:n] to dst[0:n] with F16C, where n is the largest multiple of 8 of
+// len(src) (it must be at least 8). It returns n.
+func convertFloat16ToFloat32F16C(src []float16.Float16, dst []float32) int {
+	n := len(src) &^ 7
+	_ = src[n-1]
+	_ = dst[n-1]
+	convertFloat16ToFloat32_f16c_asm(unsafe.Pointer(&src[0]), unsafe.Pointer(&dst[0]), int64(n))
+	runtime.KeepAlive(src)
+	runtime.KeepAlive(dst)
+	return n
+}
+
+// convertBFloat16ToFloat32AVX2 converts src[0:n] to dst[0:n] with AVX2, where n is the largest multiple of 8 of
+// len(src) (it must be at least 8). It returns n.
+func convertBFloat16ToFloat32AVX2(src []bfloat16.BFloat16, dst []float32) int {
+	n := len(src) &^ 7
+	_ = src[n-1]
+	_ = dst[n-1]
+	convertBFloat16ToFloat32_avx2_asm(unsafe.Pointer(&src[0]), unsafe.Pointer(&dst[0]), int64(n))
+	runtime.KeepAlive(src)
+	runtime.KeepAlive(dst)
+	return n
+}
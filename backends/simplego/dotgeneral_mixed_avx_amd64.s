@@ -0,0 +1,92 @@
+//go:build !noasm && amd64
+
+// AVX2 kernels of the mixed-precision DotGeneral: they convert the Float16 (with F16C) and BFloat16 operands to
+// float32, to be multiplied by the float32 kernels, see execDotGeneralMixedFastPath.
+// They process n values, a multiple of 8 (8 float32 per ymm register), leaving the remainder to Go.
+
+#include "textflag.h"
+
+// func convertFloat16ToFloat32_f16c_asm(src, dst unsafe.Pointer, n int64)
+TEXT ·convertFloat16ToFloat32_f16c_asm(SB), NOSPLIT, $0-24
+	MOVQ src+0(FP), SI
+	MOVQ dst+8(FP), DI
+	MOVQ n+16(FP), CX
+
+	CMPQ CX, $32
+	JL   f16_loop8
+
+f16_loop32:
+	// 32 values per iteration.
+	VCVTPH2PS (SI), Y0
+	VCVTPH2PS 16(SI), Y1
+	VCVTPH2PS 32(SI), Y2
+	VCVTPH2PS 48(SI), Y3
+	VMOVUPS   Y0, (DI)
+	VMOVUPS   Y1, 32(DI)
+	VMOVUPS   Y2, 64(DI)
+	VMOVUPS   Y3, 96(DI)
+	ADDQ      $64, SI
+	ADDQ      $128, DI
+	SUBQ      $32, CX
+	CMPQ      CX, $32
+	JGE       f16_loop32
+
+f16_loop8:
+	CMPQ      CX, $8
+	JL        f16_done
+	VCVTPH2PS (SI), Y0
+	VMOVUPS   Y0, (DI)
+	ADDQ      $16, SI
+	ADDQ      $32, DI
+	SUBQ      $8, CX
+	JMP       f16_loop8
+
+f16_done:
+	VZEROUPPER
+	RET
+
+// func convertBFloat16ToFloat32_avx2_asm(src, dst unsafe.Pointer, n int64)
+// A BFloat16 is the upper half of the float32 with the same value: each value is zero-extended to 32 bits and
+// shifted left by 16 bits.
+TEXT ·convertBFloat16ToFloat32_avx2_asm(SB), NOSPLIT, $0-24
+	MOVQ src+0(FP), SI
+	MOVQ dst+8(FP), DI
+	MOVQ n+16(FP), CX
+
+	CMPQ CX, $32
+	JL   bf16_loop8
+
+bf16_loop32:
+	// 32 values per iteration.
+	VPMOVZXWD (SI), Y0
+	VPMOVZXWD 16(SI), Y1
+	VPMOVZXWD 32(SI), Y2
+	VPMOVZXWD 48(SI), Y3
+	VPSLLD    $16, Y0, Y0
+	VPSLLD    $16, Y1, Y1
+	VPSLLD    $16, Y2, Y2
+	VPSLLD    $16, Y3, Y3
+	VMOVUPS   Y0, (DI)
+	VMOVUPS   Y1, 32(DI)
+	VMOVUPS   Y2, 64(DI)
+	VMOVUPS   Y3, 96(DI)
+	ADDQ      $64, SI
+	ADDQ      $128, DI
+	SUBQ      $32, CX
+	CMPQ      CX, $32
+	JGE       bf16_loop32
+
+bf16_loop8:
+	CMPQ      CX, $8
+	JL        bf16_done
+	VPMOVZXWD (SI), Y0
+	VPSLLD    $16, Y0, Y0
+	VMOVUPS   Y0, (DI)
+	ADDQ      $16, SI
+	ADDQ      $32, DI
+	SUBQ      $8, CX
+	JMP       bf16_loop8
+
+bf16_done:
+	VZEROUPPER
+	RET
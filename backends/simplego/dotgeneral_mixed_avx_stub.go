@@ -0,0 +1,25 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build noasm || !amd64
+
+package simplego
+
+import (
+	"github.com/gomlx/gopjrt/dtypes/bfloat16"
+	"github.com/x448/float16"
+)
+
+// hasF16C indicates whether the F16C conversions are available.
+var hasF16C = false
+
+// convertFloat16ToFloat32F16C stub for non-AMD64 platforms.
+func convertFloat16ToFloat32F16C(src []float16.Float16, dst []float32) int {
+	panic("F16C not available")
+}
+
+// convertBFloat16ToFloat32AVX2 stub for non-AMD64 platforms.
+func convertBFloat16ToFloat32AVX2(src []bfloat16.BFloat16, dst []float32) int {
+	panic("AVX2 not available")
+}
@@ -0,0 +1,32 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !noasm && arm64
+
+package simplego
+
+import (
+	"runtime"
+	"unsafe"
+
+	"github.com/gomlx/gopjrt/dtypes/bfloat16"
+)
+
+// convertBFloat16ToFloat32_neon_asm is implemented in dotgeneral_mixed_neon_arm64.s.
+// It converts src[0:n] to dst[0:n], for n a multiple of 8.
+//
+//go:noescape
+func convertBFloat16ToFloat32_neon_asm(src, dst unsafe.Pointer, n int64)
+
+// convertBFloat16ToFloat32NEON converts src[0:n] to dst[0:n] with NEON, where n is the largest multiple of 8 of
+// len(src) (it must be at least 8). It returns n.
+func convertBFloat16ToFloat32NEON(src []bfloat16.BFloat16, dst []float32) int {
+	n := len(src) &^ 7
+	_ = src[n-1]
+	_ = dst[n-1]
+	convertBFloat16ToFloat32_neon_asm(unsafe.Pointer(&src[0]), unsafe.Pointer(&dst[0]), int64(n))
+	runtime.KeepAlive(src)
+	runtime.KeepAlive(dst)
+	return n
+}
@@ -0,0 +1,34 @@
+//go:build !noasm && arm64
+
+// NEON kernel of the mixed-precision DotGeneral: it converts the BFloat16 operands to float32, to be multiplied by
+// the float32 kernels, see execDotGeneralMixedFastPath. The Float16 operands are converted by
+// convertFP16ToFP32_neon_asm.
+//
+// A BFloat16 is the upper half of the float32 with the same value, so the conversion is a widening shift left by
+// 16 bits: SHLL (lower 4 values) and SHLL2 (upper 4 values).
+//
+// Encodings: ld1 {v0.8h}, [x0], #16 = 0x4cdf7400, st1 {vt.4s}, [x1], #16 = 0x4c9f7820 | Rt,
+// shll vd.4s, vn.4h, #16 = 0x2e613800 | Rn<<5 | Rd, shll2 vd.4s, vn.8h, #16 = 0x6e613800 | Rn<<5 | Rd.
+
+#include "textflag.h"
+
+// func convertBFloat16ToFloat32_neon_asm(src, dst unsafe.Pointer, n int64)
+TEXT ·convertBFloat16ToFloat32_neon_asm(SB), NOSPLIT, $0-24
+	MOVD src+0(FP), R0
+	MOVD dst+8(FP), R1
+	MOVD n+16(FP), R2
+	LSR  $3, R2, R3 // R3 = n / 8
+
+	CBZ R3, bf16_done
+
+bf16_loop8:
+	WORD $0x4cdf7400 // ld1 {v0.8h}, [x0], #16
+	WORD $0x2e613801 // shll v1.4s, v0.4h, #16
+	WORD $0x6e613802 // shll2 v2.4s, v0.8h, #16
+	WORD $0x4c9f7821 // st1 {v1.4s}, [x1], #16
+	WORD $0x4c9f7822 // st1 {v2.4s}, [x1], #16
+	SUBS $1, R3, R3
+	BNE  bf16_loop8
+
+bf16_done:
+	RET
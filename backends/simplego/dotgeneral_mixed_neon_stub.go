@@ -0,0 +1,14 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build noasm || !arm64
+
+package simplego
+
+import "github.com/gomlx/gopjrt/dtypes/bfloat16"
+
+// convertBFloat16ToFloat32NEON stub for non-ARM64 platforms.
+func convertBFloat16ToFloat32NEON(src []bfloat16.BFloat16, dst []float32) int {
+	panic("NEON not available")
+}
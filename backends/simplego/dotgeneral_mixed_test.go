@@ -0,0 +1,201 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/gomlx/gomlx/pkg/core/graph"
+	"github.com/gomlx/gomlx/pkg/core/shapes"
+	"github.com/gomlx/gomlx/pkg/core/tensors"
+	"github.com/gomlx/gopjrt/dtypes"
+	"github.com/gomlx/gopjrt/dtypes/bfloat16"
+	"github.com/stretchr/testify/require"
+	"github.com/x448/float16"
+)
+
+func TestConvertHalfToFloat32(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	for _, simd := range []string{SIMDAuto, SIMDOff} {
+		require.NoError(t, SetSIMD(simd))
+		for _, n := range []int{1, 7, 8, 9, 31, 32, 33, 100} {
+			f16 := make([]float16.Float16, n)
+			bf16 := make([]bfloat16.BFloat16, n)
+			for i := range n {
+				f16[i] = float16.Fromfloat32(rng.Float32()*200 - 100)
+				bf16[i] = bfloat16.FromFloat32(rng.Float32()*200 - 100)
+			}
+			got := make([]float32, n+1)
+			got[n] = 7 // Must not be changed.
+			convertHalfToFloat32(f16, got)
+			for i := range n {
+				require.Equalf(t, f16[i].Float32(), got[i], "Float16 with %s, n=%d, i=%d", simd, n, i)
+			}
+			convertHalfToFloat32(bf16, got)
+			for i := range n {
+				require.Equalf(t, bf16[i].Float32(), got[i], "BFloat16 with %s, n=%d, i=%d", simd, n, i)
+			}
+			require.Equal(t, float32(7), got[n])
+		}
+	}
+	require.NoError(t, SetSIMD(SIMDAuto))
+}
+
+func TestDotGeneral_MixedPrecision(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	// randomTensor returns a tensor with random values, and the values converted to float32.
+	randomTensor := func(dtype dtypes.DType, dims ...int) (*tensors.Tensor, []float32) {
+		tensor := tensors.FromShape(shapes.Make(dtype, dims...))
+		values := make([]float32, tensor.Shape().Size())
+		for i := range values {
+			values[i] = rng.Float32()*2 - 1
+		}
+		switch dtype {
+		case dtypes.Float32:
+			tensors.MustMutableFlatData(tensor, func(flat []float32) { copy(flat, values) })
+		case dtypes.Float16:
+			tensors.MustMutableFlatData(tensor, func(flat []float16.Float16) {
+				for i, v := range values {
+					flat[i] = float16.Fromfloat32(v)
+					values[i] = flat[i].Float32()
+				}
+			})
+		case dtypes.BFloat16:
+			tensors.MustMutableFlatData(tensor, func(flat []bfloat16.BFloat16) {
+				for i, v := range values {
+					flat[i] = bfloat16.FromFloat32(v)
+					values[i] = flat[i].Float32()
+				}
+			})
+		}
+		return tensor, values
+	}
+
+	dtypePairs := [][2]dtypes.DType{
+		{dtypes.Float32, dtypes.Float16},
+		{dtypes.Float32, dtypes.BFloat16},
+		{dtypes.Float16, dtypes.Float32},
+		{dtypes.BFloat16, dtypes.Float16},
+	}
+	for _, dtypePair := range dtypePairs {
+		for _, dims := range [][4]int{{1, 1, 1, 1}, {1, 1, 300, 200}, {2, 3, 70, 9}, {1, 8, 33, 17}, {2, 37, 150, 300}} {
+			batchSize, m, n, k := dims[0], dims[1], dims[2], dims[3]
+			// layout 0 is the standard layout, 1 has the RHS transposed and 2 has the LHS transposed.
+			for layout := range 3 {
+				lhsDims := []int{batchSize, m, k}
+				rhsDims := []int{batchSize, k, n}
+				lhsContractingAxis, rhsContractingAxis := 2, 1
+				switch layout {
+				case 1:
+					rhsDims = []int{batchSize, n, k}
+					rhsContractingAxis = 2
+				case 2:
+					lhsDims = []int{batchSize, k, m}
+					lhsContractingAxis = 1
+				}
+				lhs, lhsValues := randomTensor(dtypePair[0], lhsDims...)
+				rhs, rhsValues := randomTensor(dtypePair[1], rhsDims...)
+				want := make([]float32, batchSize*m*n)
+				for b := range batchSize {
+					for i := range m {
+						for j := range n {
+							var sum float64
+							for p := range k {
+								lhsIdx := b*m*k + i*k + p
+								if layout == 2 {
+									lhsIdx = b*k*m + p*m + i
+								}
+								rhsIdx := b*k*n + p*n + j
+								if layout == 1 {
+									rhsIdx = b*n*k + j*k + p
+								}
+								sum += float64(lhsValues[lhsIdx]) * float64(rhsValues[rhsIdx])
+							}
+							want[b*m*n+i*n+j] = float32(sum)
+						}
+					}
+				}
+				for _, simd := range []string{SIMDAuto, SIMDOff} {
+					name := fmt.Sprintf("%s×%s/%s/batch=%d,m=%d,n=%d,k=%d/layout=%d",
+						dtypePair[0], dtypePair[1], simd, batchSize, m, n, k, layout)
+					t.Run(name, func(t *testing.T) {
+						require.NoError(t, SetSIMD(simd))
+						defer func() { require.NoError(t, SetSIMD(SIMDAuto)) }()
+						got := graph.MustExecOnce(backend, func(lhs, rhs *graph.Node) *graph.Node {
+							return graph.DotGeneral(lhs, []int{lhsContractingAxis}, []int{0}, rhs, []int{rhsContractingAxis}, []int{0})
+						}, lhs, rhs)
+						require.NoError(t, got.Shape().Check(dtypes.Float32, batchSize, m, n))
+						require.InDeltaSlice(t, want, tensors.MustCopyFlatData[float32](got), 1e-4)
+					})
+				}
+			}
+		}
+	}
+
+	// Operands of other dtypes must still match.
+	builder := backend.Builder("mixed")
+	lhsOp, err := builder.Parameter("lhs", shapes.Make(dtypes.Float32, 2, 3), nil)
+	require.NoError(t, err)
+	rhsOp, err := builder.Parameter("rhs", shapes.Make(dtypes.Float64, 3, 4), nil)
+	require.NoError(t, err)
+	_, err = builder.DotGeneral(lhsOp, []int{1}, nil, rhsOp, []int{0}, nil)
+	require.Error(t, err)
+}
+
+func TestDotGeneral_MixedPrecisionEpilogue(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	for _, m := range []int{1, 8} {
+		const n, k = 100, 50
+		lhsValues := make([]float32, m*k)
+		for i := range lhsValues {
+			lhsValues[i] = rng.Float32()*2 - 1
+		}
+		rhsValues := make([]float16.Float16, k*n)
+		for i := range rhsValues {
+			rhsValues[i] = float16.Fromfloat32(rng.Float32()*2 - 1)
+		}
+		biasValues := make([]float32, n)
+		for i := range biasValues {
+			biasValues[i] = rng.Float32()*2 - 1
+		}
+		want := make([]float32, m*n)
+		for i := range m {
+			for j := range n {
+				var sum float32
+				for p := range k {
+					sum += lhsValues[i*k+p] * rhsValues[p*n+j].Float32()
+				}
+				want[i*n+j] = max(sum+biasValues[j], 0)
+			}
+		}
+		lhs := tensors.FromFlatDataAndDimensions(lhsValues, m, k)
+		rhs := tensors.FromFlatDataAndDimensions(rhsValues, k, n)
+		bias := tensors.FromFlatDataAndDimensions(biasValues, n)
+		got := graph.MustExecOnce(backend, func(lhs, rhs, bias *graph.Node) *graph.Node {
+			return graph.Max(graph.Add(graph.Dot(lhs, rhs), graph.Reshape(bias, 1, n)), graph.ScalarZero(lhs.Graph(), dtypes.Float32))
+		}, lhs, rhs, bias)
+		require.InDeltaSlicef(t, want, tensors.MustCopyFlatData[float32](got), 1e-4, "m=%d", m)
+	}
+}
+
+func BenchmarkDotGeneral_MixedPrecision(b *testing.B) {
+	const k, n = 1024, 1024
+	for _, rhsDType := range []dtypes.DType{dtypes.Float32, dtypes.Float16, dtypes.BFloat16} {
+		for _, m := range []int{1, 64} {
+			lhs := tensors.FromShape(shapes.Make(dtypes.Float32, m, k))
+			rhs := tensors.FromShape(shapes.Make(rhsDType, k, n))
+			exec := graph.MustNewExec(backend, func(lhs, rhs *graph.Node) *graph.Node {
+				return graph.Dot(lhs, rhs)
+			})
+			b.Run(fmt.Sprintf("rhs=%s/m=%d", rhsDType, m), func(b *testing.B) {
+				for range b.N {
+					exec.MustExec(lhs, rhs)[0].FinalizeAll()
+				}
+			})
+		}
+	}
+}
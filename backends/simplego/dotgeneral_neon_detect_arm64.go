@@ -7,5 +7,7 @@
 package simplego
 
 // hasNEON indicates whether NEON SIMD optimizations are available.
-// NEON is always available on ARM64 processors.
-const hasNEON = true
+// NEON is always available on ARM64 processors, but it can be disabled with SetSIMD.
+var hasNEON = true
+
+var _ = registerSIMD(SIMDNEON, &hasNEON)
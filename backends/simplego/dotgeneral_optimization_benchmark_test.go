@@ -73,7 +73,7 @@ func BenchmarkDotGeneralMatMul(b *testing.B) {
 
 			for i := 0; i < b.N; i++ {
 				output.Zeros()
-				execDotGeneralFastPathFloat32(backend, lhs, rhs, params, output)
+				execDotGeneralFastPathFloat32(backend, lhs, rhs, params, output, nil)
 			}
 
 			// Report GFLOPS
@@ -125,7 +125,7 @@ func BenchmarkDotGeneralFastPathVsNormalized(b *testing.B) {
 		b.ResetTimer()
 		for i := 0; i < b.N; i++ {
 			output.Zeros()
-			execDotGeneralFastPathFloat32(backend, lhs, rhs, params, output)
+			execDotGeneralFastPathFloat32(backend, lhs, rhs, params, output, nil)
 		}
 	})
 
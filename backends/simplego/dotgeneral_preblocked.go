@@ -248,6 +248,7 @@ func TryExecDotGeneralWithPreBlockedWeights(backend *Backend, lhs, rhs *Buffer,
 	}
 
 	// Execute using pre-blocked RHS
+	profileDotGeneralKernel(backend, output, "preblocked")
 	err := execDotGeneralWithPreBlockedRHS(backend, lhs, pbw, params, output)
 	return err == nil
 }
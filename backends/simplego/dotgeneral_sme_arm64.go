@@ -0,0 +1,119 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !noasm && arm64
+
+package simplego
+
+import (
+	"runtime"
+	"sync"
+	"unsafe"
+
+	"github.com/gomlx/gomlx/internal/exceptions"
+)
+
+// smeVectorLanes is the number of float32 values in an SME streaming vector, or 0 if SME is not supported.
+// The SME kernels compute output tiles of (2*smeVectorLanes)×(2*smeVectorLanes) values.
+var smeVectorLanes = detectSMEVectorLanes()
+
+// hasSME indicates whether the SME (Scalable Matrix Extension) kernels are used, e.g. on Apple M4.
+// They can be disabled with SetSIMD.
+var hasSME = smeVectorLanes > 0
+
+var _ = registerSIMD(SIMDSME, &hasSME)
+
+// detectSMEVectorLanes returns the number of float32 values in an SME streaming vector, or 0 if SME is not supported.
+func detectSMEVectorLanes() int {
+	if !detectSME() {
+		return 0
+	}
+	return int(smeVectorLanes_asm())
+}
+
+// smeVectorLanes_asm is implemented in dotgeneral_sme_arm64.s.
+// It returns the number of float32 values in an SME streaming vector, and it can only be called if SME is supported.
+//
+//go:noescape
+func smeVectorLanes_asm() int64
+
+// dotGeneralBlock_sme_asm is implemented in dotgeneral_sme_arm64.s.
+// It computes C += A·B for a rows×cols block of C using FMOPA outer products, where A and B are laid out with the
+// contracting axis first. See dotGeneralBlockSME.
+//
+//go:noescape
+func dotGeneralBlock_sme_asm(kc int64, a unsafe.Pointer, lda int64, b unsafe.Pointer, ldb int64, c unsafe.Pointer,
+	ldc, rows, cols int64)
+
+// dotGeneralBlockSME computes C += A·B for a rows×cols block of C:
+//
+//	c[i*ldc + j] += sum_k a[k*lda + i] * b[k*ldb + j],   for i in [0, rows), j in [0, cols) and k in [0, kc).
+//
+// The rows and cols must be multiples of the SME output tile dimension, 2*smeVectorLanes. The whole block is computed
+// within one streaming mode region, which is expensive to enter and exit.
+func dotGeneralBlockSME(kc int, a []float32, lda int, b []float32, ldb int, c []float32, ldc int, rows, cols int) {
+	tileDim := 2 * smeVectorLanes
+	if rows%tileDim != 0 || cols%tileDim != 0 {
+		exceptions.Panicf("dotGeneralBlockSME requires rows (%d) and cols (%d) to be multiples of %d",
+			rows, cols, tileDim)
+	}
+	if rows == 0 || cols == 0 {
+		return
+	}
+	if kc > 0 {
+		_ = a[(kc-1)*lda+rows-1]
+		_ = b[(kc-1)*ldb+cols-1]
+	}
+	_ = c[(rows-1)*ldc+cols-1]
+	dotGeneralBlock_sme_asm(int64(kc), unsafe.Pointer(&a[0]), int64(lda), unsafe.Pointer(&b[0]), int64(ldb),
+		unsafe.Pointer(&c[0]), int64(ldc), int64(rows), int64(cols))
+	runtime.KeepAlive(a)
+	runtime.KeepAlive(b)
+	runtime.KeepAlive(c)
+}
+
+// buildDotGeneralKernelSME returns the SME version of the float32 kernel returned by buildDotGeneralKernel, or nil if
+// the SME kernel can't be used: if the dtype is not float32, or if blockDim is not a multiple of the SME output tile.
+//
+// Each lhs and rhs block is transposed (to have the contracting axis first) into a scratch buffer, so that the columns
+// of the blocks can be loaded as contiguous vectors for the FMOPA outer products.
+func buildDotGeneralKernelSME(lhs, rhs, output *Buffer, blockDim int) kernelFuncType {
+	lhsFlat, ok := lhs.flat.([]float32)
+	tileDim := 2 * smeVectorLanes
+	if !ok || tileDim == 0 || blockDim%tileDim != 0 {
+		return nil
+	}
+	rhsFlat := rhs.flat.([]float32)
+	outputFlat := output.flat.([]float32)
+	blockSize := blockDim * blockDim
+	scratchPool := sync.Pool{
+		New: func() any {
+			scratch := make([]float32, 2*blockSize)
+			return &scratch
+		},
+	}
+
+	return func(lhsBlockIdx, rhsBlockIdx, outputBlockIdx int) {
+		scratch := scratchPool.Get().(*[]float32)
+		lhsT := (*scratch)[:blockSize]
+		rhsT := (*scratch)[blockSize:]
+		transposeBlockFloat32(lhsFlat[lhsBlockIdx*blockSize:(lhsBlockIdx+1)*blockSize], lhsT, blockDim)
+		transposeBlockFloat32(rhsFlat[rhsBlockIdx*blockSize:(rhsBlockIdx+1)*blockSize], rhsT, blockDim)
+		outputBlock := outputFlat[outputBlockIdx*blockSize : (outputBlockIdx+1)*blockSize]
+		dotGeneralBlockSME(blockDim, lhsT, blockDim, rhsT, blockDim, outputBlock, blockDim, blockDim, blockDim)
+		scratchPool.Put(scratch)
+	}
+}
+
+// transposeBlockFloat32 transposes the square block src of dimension blockDim into dst.
+func transposeBlockFloat32(src, dst []float32, blockDim int) {
+	_ = src[blockDim*blockDim-1]
+	_ = dst[blockDim*blockDim-1]
+	for row := range blockDim {
+		srcRow := src[row*blockDim : (row+1)*blockDim]
+		for col, value := range srcRow {
+			dst[col*blockDim+row] = value
+		}
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !noasm && arm64
+
+#include "textflag.h"
+
+// SME (Scalable Matrix Extension) float32 kernels.
+//
+// The Go assembler doesn't support SME/SVE instructions, so they are encoded with WORD directives (the encodings
+// were generated with llvm-mc -mattr=+sme).
+//
+// The kernels use the four 32-bit ZA tiles (za0.s to za3.s), each holding L×L float32 values, where L is the
+// number of float32 lanes of the streaming vector (see smeVectorLanes_asm). Together they hold a (2L)×(2L) tile of
+// the output:
+//
+//	za0: rows [0, L),  cols [0, L)     za1: rows [0, L),  cols [L, 2L)
+//	za2: rows [L, 2L), cols [0, L)     za3: rows [L, 2L), cols [L, 2L)
+//
+// Each step of the contracting axis is 4 FMOPA (outer products) of 2 vectors of A and 2 vectors of B.
+
+// func smeVectorLanes_asm() int64
+//
+// Returns the number of float32 values in an SME streaming vector (the streaming vector length in bytes / 4).
+// It can only be called if SME is supported.
+TEXT ·smeVectorLanes_asm(SB), NOSPLIT, $0-8
+	WORD $0xd503437f // smstart sm
+	WORD $0x04a0e3e0 // cntw x0
+	WORD $0xd503427f // smstop sm
+	MOVD R0, ret+0(FP)
+	RET
+
+// func dotGeneralBlock_sme_asm(kc int64, a unsafe.Pointer, lda int64, b unsafe.Pointer, ldb int64, c unsafe.Pointer, ldc, rows, cols int64)
+//
+// Computes C += A·B for a rows×cols block of C, where rows and cols are multiples of 2L, and A and B are given
+// "transposed", with the contracting axis first:
+//
+//	C[i, j] += sum_k A[k*lda + i] * B[k*ldb + j],   for i in [0, rows), j in [0, cols) and k in [0, kc).
+//
+// C is row-major with a row stride of ldc. All strides are in elements (float32).
+//
+// The whole block is processed inside one streaming mode region (smstart/smstop), since entering and exiting the
+// streaming mode is expensive: the block is iterated over in (2L)×(2L) tiles, each accumulated in the ZA tiles.
+TEXT ·dotGeneralBlock_sme_asm(SB), NOSPLIT, $0-72
+	MOVD kc+0(FP), R0
+	MOVD a+8(FP), R19
+	MOVD lda+16(FP), R2
+	MOVD b+24(FP), R3
+	MOVD ldb+32(FP), R4
+	MOVD c+40(FP), R20
+	MOVD ldc+48(FP), R6
+	MOVD rows+56(FP), R15
+	MOVD cols+64(FP), R16
+
+	// Strides in bytes.
+	LSL $2, R2
+	LSL $2, R4
+	LSL $2, R6
+	MOVD R3, R25 // R25: saves the start of B.
+
+	WORD $0xd503477f // smstart: enter streaming mode and enable ZA.
+	WORD $0x2598e3e0 // ptrue p0.s
+	WORD $0x04a0e3e7 // cntw x7: L, the number of float32 lanes.
+
+	MUL R7, R6, R9    // R9: offset in bytes from row i to row L+i of C.
+	LSL $1, R7, R24   // R24: 2L, the tile dimension.
+	LSL $3, R7, R10   // R10: 2L*4, the tile dimension in bytes.
+	MUL R24, R6, R17  // R17: offset in bytes from a row of tiles of C to the next.
+	MOVD $0, R13      // R13: row of the current tile.
+
+sme_block_row_loop:
+	MOVD R25, R21 // R21: start of the columns of B for the current tile.
+	MOVD R20, R22 // R22: start of the current tile of C.
+	MOVD $0, R14  // R14: column of the current tile.
+
+sme_block_col_loop:
+	// Load the current values of the tile of C into the ZA tiles, one row (horizontal slice) at a time.
+	MOVD R22, R5
+	MOVW $0, R12
+
+sme_tile_load:
+	ADD  R9, R5, R8
+	WORD $0xe09f00a0 // ld1w {za0h.s[w12, 0]}, p0/z, [x5]
+	WORD $0xe08700a4 // ld1w {za1h.s[w12, 0]}, p0/z, [x5, x7, lsl #2]
+	WORD $0xe09f0108 // ld1w {za2h.s[w12, 0]}, p0/z, [x8]
+	WORD $0xe087010c // ld1w {za3h.s[w12, 0]}, p0/z, [x8, x7, lsl #2]
+	ADD  R6, R5
+	ADD  $1, R12
+	CMP  R7, R12
+	BLT  sme_tile_load
+
+	MOVD R19, R1
+	MOVD R21, R3
+	MOVD R0, R23
+	CBZ  R23, sme_tile_store_start
+
+sme_tile_loop:
+	WORD $0xa540a020 // ld1w {z0.s}, p0/z, [x1]
+	WORD $0xa5474021 // ld1w {z1.s}, p0/z, [x1, x7, lsl #2]
+	WORD $0xa540a062 // ld1w {z2.s}, p0/z, [x3]
+	WORD $0xa5474063 // ld1w {z3.s}, p0/z, [x3, x7, lsl #2]
+	WORD $0x80820000 // fmopa za0.s, p0/m, p0/m, z0.s, z2.s
+	WORD $0x80830001 // fmopa za1.s, p0/m, p0/m, z0.s, z3.s
+	WORD $0x80820022 // fmopa za2.s, p0/m, p0/m, z1.s, z2.s
+	WORD $0x80830023 // fmopa za3.s, p0/m, p0/m, z1.s, z3.s
+	ADD  R2, R1
+	ADD  R4, R3
+	SUBS $1, R23
+	BNE  sme_tile_loop
+
+sme_tile_store_start:
+	// Store the ZA tiles back to the tile of C.
+	MOVD R22, R5
+	MOVW $0, R12
+
+sme_tile_store:
+	ADD  R9, R5, R8
+	WORD $0xe0bf00a0 // st1w {za0h.s[w12, 0]}, p0, [x5]
+	WORD $0xe0a700a4 // st1w {za1h.s[w12, 0]}, p0, [x5, x7, lsl #2]
+	WORD $0xe0bf0108 // st1w {za2h.s[w12, 0]}, p0, [x8]
+	WORD $0xe0a7010c // st1w {za3h.s[w12, 0]}, p0, [x8, x7, lsl #2]
+	ADD  R6, R5
+	ADD  $1, R12
+	CMP  R7, R12
+	BLT  sme_tile_store
+
+	// Next tile in the row.
+	ADD R10, R21
+	ADD R10, R22
+	ADD R24, R14
+	CMP R16, R14
+	BLT sme_block_col_loop
+
+	// Next row of tiles.
+	ADD R10, R19
+	ADD R17, R20
+	ADD R24, R13
+	CMP R15, R13
+	BLT sme_block_row_loop
+
+	WORD $0xd503467f // smstop: exit streaming mode and disable ZA.
+	RET
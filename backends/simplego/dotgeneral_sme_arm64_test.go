@@ -0,0 +1,92 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !noasm && arm64
+
+package simplego
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/gomlx/gopjrt/dtypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransposeBlockFloat32(t *testing.T) {
+	const blockDim = 4
+	src := make([]float32, blockDim*blockDim)
+	for ii := range src {
+		src[ii] = float32(ii)
+	}
+	dst := make([]float32, len(src))
+	transposeBlockFloat32(src, dst, blockDim)
+	for row := range blockDim {
+		for col := range blockDim {
+			require.Equal(t, src[row*blockDim+col], dst[col*blockDim+row])
+		}
+	}
+}
+
+func TestDotGeneralKernelSME(t *testing.T) {
+	if !hasSME {
+		t.Skip("SME not supported")
+	}
+	t.Logf("SME streaming vector with %d float32 lanes", smeVectorLanes)
+	blockDim := 1 << DotGeneralTargetBlockLog2Dim[dtypes.Float32]
+	blockSize := blockDim * blockDim
+	newBuffer := func(numBlocks int, random bool) *Buffer {
+		flat := make([]float32, numBlocks*blockSize)
+		if random {
+			for ii := range flat {
+				flat[ii] = rand.Float32()*2 - 1
+			}
+		}
+		return &Buffer{flat: flat}
+	}
+	lhs, rhs := newBuffer(2, true), newBuffer(3, true)
+	got, want := newBuffer(2, true), newBuffer(2, false)
+	copy(want.flat.([]float32), got.flat.([]float32))
+
+	smeKernel := buildDotGeneralKernelSME(lhs, rhs, got, blockDim)
+	require.NotNil(t, smeKernel)
+	hasSME = false
+	goKernel := buildDotGeneralKernel[float32](lhs, rhs, want, blockDim)
+	hasSME = true
+	smeKernel(1, 2, 1)
+	goKernel(1, 2, 1)
+	for ii, value := range want.flat.([]float32) {
+		require.InDelta(t, value, got.flat.([]float32)[ii], 1e-3, "index %d", ii)
+	}
+}
+
+func TestDotGeneralBlockSME(t *testing.T) {
+	if !hasSME {
+		t.Skip("SME not supported")
+	}
+	tileDim := 2 * smeVectorLanes
+	kc, rows, cols := 7, 2*tileDim, 3*tileDim
+	lda, ldb, ldc := rows+1, cols+2, cols+3
+	a := make([]float32, kc*lda)
+	b := make([]float32, kc*ldb)
+	c := make([]float32, rows*ldc)
+	for _, values := range [][]float32{a, b, c} {
+		for ii := range values {
+			values[ii] = rand.Float32()*2 - 1
+		}
+	}
+	want := make([]float32, len(c))
+	copy(want, c)
+	for i := range rows {
+		for j := range cols {
+			for k := range kc {
+				want[i*ldc+j] += a[k*lda+i] * b[k*ldb+j]
+			}
+		}
+	}
+	dotGeneralBlockSME(kc, a, lda, b, ldb, c, ldc, rows, cols)
+	for ii := range want {
+		require.InDelta(t, want[ii], c[ii], 1e-4, "index %d", ii)
+	}
+}
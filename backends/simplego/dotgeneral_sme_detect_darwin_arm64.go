@@ -0,0 +1,18 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build darwin && arm64
+
+package simplego
+
+import (
+	"syscall"
+)
+
+// detectSME checks if the SME (Scalable Matrix Extension) instructions are available.
+// Apple Silicon M4 and later support SME.
+func detectSME() bool {
+	val, err := syscall.Sysctl("hw.optional.arm.FEAT_SME")
+	return err == nil && len(val) > 0 && val[0] != 0
+}
@@ -0,0 +1,12 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !arm64 || (arm64 && !darwin && !linux)
+
+package simplego
+
+// detectSME returns false on non-ARM64 platforms and unsupported ARM64 OSes.
+func detectSME() bool {
+	return false
+}
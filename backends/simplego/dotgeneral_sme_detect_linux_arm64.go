@@ -0,0 +1,15 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux && arm64
+
+package simplego
+
+// detectSME checks if the SME (Scalable Matrix Extension) instructions are available.
+//
+// The Linux kernel saves and restores the streaming mode and the ZA storage across context switches and signal
+// handlers, so the SME kernels can be safely used by goroutines.
+func detectSME() bool {
+	return cpuFeaturesARM64.HasSME
+}
@@ -0,0 +1,18 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build noasm || !arm64
+
+package simplego
+
+// hasSME indicates whether the SME kernels are used: never on this platform.
+const hasSME = false
+
+// buildDotGeneralKernelSME stub for platforms without SME.
+func buildDotGeneralKernelSME(lhs, rhs, output *Buffer, blockDim int) kernelFuncType {
+	panic("SME not available")
+}
+
+// smeVectorLanes is the number of float32 values in an SME streaming vector: 0, since SME is not available.
+const smeVectorLanes = 0
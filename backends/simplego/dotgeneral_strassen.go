@@ -0,0 +1,180 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+// DotGeneralStrassenMinSize is the crossover size of the Strassen matrix multiplication, when enabled with
+// Backend.SetDotGeneralStrassen: a (recursive) level of Strassen is used while all the dimensions (M, N and K)
+// of the (sub-)matrices are at least this size, up to strassenMaxDepth levels. Below it, the blocked GEMM is
+// faster, because it uses the cache better and it doesn't pay for the matrix additions.
+var DotGeneralStrassenMinSize = 2048
+
+// strassenMaxDepth is the maximum number of recursive levels of Strassen: each level saves 1/8 of the
+// multiplications, but it makes the rounding errors larger.
+const strassenMaxDepth = 2
+
+// SetDotGeneralStrassen enables or disables the Strassen algorithm for the float32 matrix multiplications
+// [M, K] × [K, N] where M, N and K are all at least DotGeneralStrassenMinSize. It can also be enabled with the
+// "dotgeneral_strassen" configuration, see New.
+//
+// Strassen computes the product of 2×2 block matrices with 7 multiplications of the blocks (instead of 8), at the
+// cost of 18 block additions. Applied recursively (up to 2 levels) above the blocked GEMM, it saves up to 23% of
+// the multiplications, which pays off for big offline workloads.
+//
+// Accuracy: differently from the regular GEMM, whose error bound is relative to each output element, the error
+// bound of Strassen is relative to the norm of the whole matrices. Outputs much smaller than the norm of the
+// inputs (e.g.: with cancellations, or with rows/columns of very different scales) can have large relative
+// errors. Each level also makes the error bound larger by a constant factor. Only enable it if this trade-off is
+// acceptable for the workload.
+func (b *Backend) SetDotGeneralStrassen(enabled bool) {
+	b.dotGeneralStrassen = enabled
+}
+
+// useStrassen returns whether the [m, k] × [k, n] float32 matrix multiplication should use strassenFloat32.
+func useStrassen(backend *Backend, m, n, k int) bool {
+	return backend.dotGeneralStrassen && min(m, n, k) >= DotGeneralStrassenMinSize
+}
+
+// strassenFloat32 computes C += A·B, where A is a [m, k] matrix, B is a [k, n] matrix and C is a [m, n] matrix, all
+// row-major, given as in gemmFloat32, using up to depth levels of Strassen above the GEMM.
+//
+// Odd dimensions are handled by peeling: Strassen is used on the largest even-sized sub-matrices, and the
+// remaining row/column (and the remaining products of the contraction) are added with the GEMM.
+func strassenFloat32(backend *Backend, depth, m, n, k int,
+	a []float32, aIdx, lda int,
+	b []float32, bIdx, ldb int,
+	c []float32, cIdx, ldc int) {
+	if depth == 0 || min(m, n, k) < DotGeneralStrassenMinSize {
+		gemmParallelFloat32(backend, m, n, k, a, aIdx, lda, b, bIdx, ldb, c, cIdx, ldc)
+		return
+	}
+	mEven, nEven, kEven := m&^1, n&^1, k&^1
+	if kEven < k {
+		// Last column of A times last row of B.
+		gemmParallelFloat32(backend, mEven, nEven, 1, a, aIdx+kEven, lda, b, bIdx+kEven*ldb, ldb, c, cIdx, ldc)
+	}
+	if nEven < n {
+		// Last column of C.
+		gemmParallelFloat32(backend, mEven, 1, k, a, aIdx, lda, b, bIdx+nEven, ldb, c, cIdx+nEven, ldc)
+	}
+	if mEven < m {
+		// Last row of C.
+		gemmParallelFloat32(backend, 1, n, k, a, aIdx+mEven*lda, lda, b, bIdx, ldb, c, cIdx+mEven*ldc, ldc)
+	}
+
+	mh, nh, kh := mEven/2, nEven/2, kEven/2
+	a11, a12, a21, a22 := aIdx, aIdx+kh, aIdx+mh*lda, aIdx+mh*lda+kh
+	b11, b12, b21, b22 := bIdx, bIdx+nh, bIdx+kh*ldb, bIdx+kh*ldb+nh
+	c11, c12, c21, c22 := cIdx, cIdx+nh, cIdx+mh*ldc, cIdx+mh*ldc+nh
+
+	// Temporaries, with the quadrants dimensions as their leading dimensions.
+	tmpABuf := getGemmPackedBuffer(mh * kh)
+	tmpBBuf := getGemmPackedBuffer(kh * nh)
+	productBuf := getGemmPackedBuffer(mh * nh)
+	defer gemmPackedPool.Put(tmpABuf)
+	defer gemmPackedPool.Put(tmpBBuf)
+	defer gemmPackedPool.Put(productBuf)
+	tmpA, tmpB, product := *tmpABuf, *tmpBBuf, *productBuf
+
+	// multiply sets product = x·y, where x is [mh, kh] and y is [kh, nh].
+	multiply := func(x []float32, xIdx, ldx int, y []float32, yIdx, ldy int) {
+		clear(product)
+		strassenFloat32(backend, depth-1, mh, nh, kh, x, xIdx, ldx, y, yIdx, ldy, product, 0, nh)
+	}
+	// accumulate adds sign·product to the quadrant of C starting at quadrantIdx.
+	accumulate := func(quadrantIdx int, sign float32) {
+		matAddFloat32(mh, nh, c, quadrantIdx, ldc, c, quadrantIdx, ldc, sign, product, 0, nh)
+	}
+
+	// M1 = (A11 + A22)·(B11 + B22): C11 += M1, C22 += M1.
+	matAddFloat32(mh, kh, tmpA, 0, kh, a, a11, lda, 1, a, a22, lda)
+	matAddFloat32(kh, nh, tmpB, 0, nh, b, b11, ldb, 1, b, b22, ldb)
+	multiply(tmpA, 0, kh, tmpB, 0, nh)
+	accumulate(c11, 1)
+	accumulate(c22, 1)
+
+	// M2 = (A21 + A22)·B11: C21 += M2, C22 -= M2.
+	matAddFloat32(mh, kh, tmpA, 0, kh, a, a21, lda, 1, a, a22, lda)
+	multiply(tmpA, 0, kh, b, b11, ldb)
+	accumulate(c21, 1)
+	accumulate(c22, -1)
+
+	// M3 = A11·(B12 - B22): C12 += M3, C22 += M3.
+	matAddFloat32(kh, nh, tmpB, 0, nh, b, b12, ldb, -1, b, b22, ldb)
+	multiply(a, a11, lda, tmpB, 0, nh)
+	accumulate(c12, 1)
+	accumulate(c22, 1)
+
+	// M4 = A22·(B21 - B11): C11 += M4, C21 += M4.
+	matAddFloat32(kh, nh, tmpB, 0, nh, b, b21, ldb, -1, b, b11, ldb)
+	multiply(a, a22, lda, tmpB, 0, nh)
+	accumulate(c11, 1)
+	accumulate(c21, 1)
+
+	// M5 = (A11 + A12)·B22: C11 -= M5, C12 += M5.
+	matAddFloat32(mh, kh, tmpA, 0, kh, a, a11, lda, 1, a, a12, lda)
+	multiply(tmpA, 0, kh, b, b22, ldb)
+	accumulate(c11, -1)
+	accumulate(c12, 1)
+
+	// M6 = (A21 - A11)·(B11 + B12): C22 += M6.
+	matAddFloat32(mh, kh, tmpA, 0, kh, a, a21, lda, -1, a, a11, lda)
+	matAddFloat32(kh, nh, tmpB, 0, nh, b, b11, ldb, 1, b, b12, ldb)
+	multiply(tmpA, 0, kh, tmpB, 0, nh)
+	accumulate(c22, 1)
+
+	// M7 = (A12 - A22)·(B21 + B22): C11 += M7.
+	matAddFloat32(mh, kh, tmpA, 0, kh, a, a12, lda, -1, a, a22, lda)
+	matAddFloat32(kh, nh, tmpB, 0, nh, b, b21, ldb, 1, b, b22, ldb)
+	multiply(tmpA, 0, kh, tmpB, 0, nh)
+	accumulate(c11, 1)
+}
+
+// matAddFloat32 sets Z = X + sign·Y, for [rows, cols] row-major matrices given by their flat slice, the index of
+// their first element and their leading dimension. sign is 1 or -1. Z can be the same as X.
+func matAddFloat32(rows, cols int,
+	z []float32, zIdx, ldz int,
+	x []float32, xIdx, ldx int,
+	sign float32,
+	y []float32, yIdx, ldy int) {
+	for row := range rows {
+		addScaledFloat32(z[zIdx+row*ldz:zIdx+row*ldz+cols], x[xIdx+row*ldx:], sign, y[yIdx+row*ldy:])
+	}
+}
+
+// addScaledFloat32 computes z = x + scale·y, where x and y have at least the length of z.
+func addScaledFloat32(z, x []float32, scale float32, y []float32) {
+	n := len(z)
+	x = x[:n]
+	y = y[:n]
+	i := 0
+	if n >= 8 {
+		switch {
+		case hasAVX2:
+			i = addScaledFloat32AVX2(z, x, scale, y)
+		case hasNEON:
+			i = addScaledFloat32NEON(z, x, scale, y)
+		}
+	}
+	for ; i < n; i++ {
+		z[i] = x[i] + scale*y[i]
+	}
+}
+
+// gemmParallelFloat32 computes C += A·B like gemmFloat32, with the rows split among the backend workers.
+func gemmParallelFloat32(backend *Backend, m, n, k int,
+	a []float32, aIdx, lda int,
+	b []float32, bIdx, ldb int,
+	c []float32, cIdx, ldc int) {
+	if m == 0 || n == 0 || k == 0 {
+		return
+	}
+	blockSizes := backend.gemmBlockSizes
+	parallelizeDotGeneral(backend, 1, m, n*k, func(_, rowStart, rowEnd int) {
+		gemmFloat32(blockSizes, rowEnd-rowStart, n, k,
+			a, aIdx+rowStart*lda, lda,
+			b, bIdx, ldb,
+			c, cIdx+rowStart*ldc, ldc)
+	})
+}
@@ -0,0 +1,32 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !noasm && amd64
+
+package simplego
+
+import (
+	"runtime"
+	"unsafe"
+)
+
+// addScaledFloat32_avx2_asm is implemented in dotgeneral_strassen_avx_amd64.s.
+// It computes z[0:n] = x[0:n] + scale * y[0:n], for n a multiple of 8.
+//
+//go:noescape
+func addScaledFloat32_avx2_asm(scale float32, x, y, z unsafe.Pointer, n int64)
+
+// addScaledFloat32AVX2 computes z[0:n] = x[0:n] + scale * y[0:n] with AVX2, where n is the largest multiple of 8 of
+// len(z) (it must be at least 8). It returns n.
+func addScaledFloat32AVX2(z, x []float32, scale float32, y []float32) int {
+	n := len(z) &^ 7
+	_ = x[n-1]
+	_ = y[n-1]
+	_ = z[n-1]
+	addScaledFloat32_avx2_asm(scale, unsafe.Pointer(&x[0]), unsafe.Pointer(&y[0]), unsafe.Pointer(&z[0]), int64(n))
+	runtime.KeepAlive(x)
+	runtime.KeepAlive(y)
+	runtime.KeepAlive(z)
+	return n
+}
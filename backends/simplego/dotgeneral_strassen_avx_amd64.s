@@ -0,0 +1,56 @@
+//go:build !noasm && amd64
+
+// AVX2 (with FMA) kernel of the matrix additions of Strassen: see matAddFloat32.
+// It processes n values, a multiple of 8 (8 float32 per ymm register), leaving the remainder to Go.
+
+#include "textflag.h"
+
+// func addScaledFloat32_avx2_asm(scale float32, x, y, z unsafe.Pointer, n int64)
+// Computes z[0:n] = x[0:n] + scale * y[0:n]. With scale = ±1 the fused multiply-add rounds as the addition
+// (subtraction).
+TEXT ·addScaledFloat32_avx2_asm(SB), NOSPLIT, $0-40
+	VBROADCASTSS scale+0(FP), Y0
+	MOVQ         x+8(FP), SI
+	MOVQ         y+16(FP), DX
+	MOVQ         z+24(FP), DI
+	MOVQ         n+32(FP), CX
+
+	CMPQ CX, $32
+	JL   add_loop8
+
+add_loop32:
+	// 32 values per iteration.
+	VMOVUPS     (SI), Y1
+	VMOVUPS     32(SI), Y2
+	VMOVUPS     64(SI), Y3
+	VMOVUPS     96(SI), Y4
+	VFMADD231PS (DX), Y0, Y1
+	VFMADD231PS 32(DX), Y0, Y2
+	VFMADD231PS 64(DX), Y0, Y3
+	VFMADD231PS 96(DX), Y0, Y4
+	VMOVUPS     Y1, (DI)
+	VMOVUPS     Y2, 32(DI)
+	VMOVUPS     Y3, 64(DI)
+	VMOVUPS     Y4, 96(DI)
+	ADDQ        $128, SI
+	ADDQ        $128, DX
+	ADDQ        $128, DI
+	SUBQ        $32, CX
+	CMPQ        CX, $32
+	JGE         add_loop32
+
+add_loop8:
+	CMPQ        CX, $8
+	JL          add_done
+	VMOVUPS     (SI), Y1
+	VFMADD231PS (DX), Y0, Y1
+	VMOVUPS     Y1, (DI)
+	ADDQ        $32, SI
+	ADDQ        $32, DX
+	ADDQ        $32, DI
+	SUBQ        $8, CX
+	JMP         add_loop8
+
+add_done:
+	VZEROUPPER
+	RET
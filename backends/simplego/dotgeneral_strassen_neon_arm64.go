@@ -0,0 +1,32 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !noasm && arm64
+
+package simplego
+
+import (
+	"runtime"
+	"unsafe"
+)
+
+// addScaledFloat32_neon_asm is implemented in dotgeneral_strassen_neon_arm64.s.
+// It computes z[0:n] = x[0:n] + scale * y[0:n], for n a multiple of 4.
+//
+//go:noescape
+func addScaledFloat32_neon_asm(scale float32, x, y, z unsafe.Pointer, n int64)
+
+// addScaledFloat32NEON computes z[0:n] = x[0:n] + scale * y[0:n] with NEON, where n is the largest multiple of 4 of
+// len(z) (it must be at least 4). It returns n.
+func addScaledFloat32NEON(z, x []float32, scale float32, y []float32) int {
+	n := len(z) &^ 3
+	_ = x[n-1]
+	_ = y[n-1]
+	_ = z[n-1]
+	addScaledFloat32_neon_asm(scale, unsafe.Pointer(&x[0]), unsafe.Pointer(&y[0]), unsafe.Pointer(&z[0]), int64(n))
+	runtime.KeepAlive(x)
+	runtime.KeepAlive(y)
+	runtime.KeepAlive(z)
+	return n
+}
@@ -0,0 +1,50 @@
+//go:build !noasm && arm64
+
+// NEON kernel of the matrix additions of Strassen: see matAddFloat32.
+// It processes n values, a multiple of 4 (4 float32 per register), leaving the remainder to Go.
+//
+// Encodings: fmla vd.4s, vn.4s, vm.4s = 0x4e20cc00 | Rm<<16 | Rn<<5 | Rd, dup vd.4s, vn.s[0] = 0x4e040400 |
+// Rn<<5 | Rd, ld1/st1 of 4 registers post-indexed = 0x4cdf2800/0x4c9f2800 | Rn<<5 | Rt, and of 1 register =
+// 0x4cdf7800/0x4c9f7800 | Rn<<5 | Rt.
+
+#include "textflag.h"
+
+// func addScaledFloat32_neon_asm(scale float32, x, y, z unsafe.Pointer, n int64)
+// Computes z[0:n] = x[0:n] + scale * y[0:n]. With scale = ±1 the fused multiply-add rounds as the addition
+// (subtraction).
+TEXT ·addScaledFloat32_neon_asm(SB), NOSPLIT, $0-40
+	FMOVS scale+0(FP), F0
+	MOVD  x+8(FP), R0
+	MOVD  y+16(FP), R1
+	MOVD  z+24(FP), R2
+	MOVD  n+32(FP), R3
+	WORD  $0x4e040400 // dup v0.4s, v0.s[0]
+
+	CMP $16, R3
+	BLT add_loop4
+
+add_loop16:
+	// 16 values per iteration.
+	WORD $0x4cdf2801 // ld1 {v1.4s-v4.4s}, [x0], #64
+	WORD $0x4cdf2825 // ld1 {v5.4s-v8.4s}, [x1], #64
+	WORD $0x4e20cca1 // fmla v1.4s, v5.4s, v0.4s
+	WORD $0x4e20ccc2 // fmla v2.4s, v6.4s, v0.4s
+	WORD $0x4e20cce3 // fmla v3.4s, v7.4s, v0.4s
+	WORD $0x4e20cd04 // fmla v4.4s, v8.4s, v0.4s
+	WORD $0x4c9f2841 // st1 {v1.4s-v4.4s}, [x2], #64
+	SUB  $16, R3, R3
+	CMP  $16, R3
+	BGE  add_loop16
+
+add_loop4:
+	CMP  $4, R3
+	BLT  add_done
+	WORD $0x4cdf7801 // ld1 {v1.4s}, [x0], #16
+	WORD $0x4cdf7825 // ld1 {v5.4s}, [x1], #16
+	WORD $0x4e20cca1 // fmla v1.4s, v5.4s, v0.4s
+	WORD $0x4c9f7841 // st1 {v1.4s}, [x2], #16
+	SUB  $4, R3, R3
+	B    add_loop4
+
+add_done:
+	RET
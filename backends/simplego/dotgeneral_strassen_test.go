@@ -0,0 +1,114 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/gomlx/gomlx/pkg/core/graph"
+	"github.com/gomlx/gomlx/pkg/core/shapes"
+	"github.com/gomlx/gomlx/pkg/core/tensors"
+	"github.com/gomlx/gopjrt/dtypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStrassenFloat32(t *testing.T) {
+	defer func(minSize int) { DotGeneralStrassenMinSize = minSize }(DotGeneralStrassenMinSize)
+	DotGeneralStrassenMinSize = 8
+	rng := rand.New(rand.NewSource(42))
+	b := backend.(*Backend)
+	for _, simd := range []string{SIMDAuto, SIMDOff} {
+		require.NoError(t, SetSIMD(simd))
+		for _, dims := range [][3]int{{16, 16, 16}, {33, 40, 35}, {64, 17, 50}, {7, 64, 64}} {
+			m, n, k := dims[0], dims[1], dims[2]
+			for depth := range strassenMaxDepth + 1 {
+				// The matrices are embedded in larger ones, to test the leading dimensions.
+				lda, ldb, ldc := k+3, n+1, n+2
+				a := make([]float32, m*lda)
+				bMat := make([]float32, k*ldb)
+				c := make([]float32, m*ldc)
+				for i := range a {
+					a[i] = rng.Float32()*2 - 1
+				}
+				for i := range bMat {
+					bMat[i] = rng.Float32()*2 - 1
+				}
+				for i := range c {
+					c[i] = rng.Float32()*2 - 1
+				}
+				want := make([]float32, len(c))
+				copy(want, c)
+				for i := range m {
+					for j := range n {
+						var sum float32
+						for p := range k {
+							sum += a[i*lda+p] * bMat[p*ldb+j]
+						}
+						want[i*ldc+j] += sum
+					}
+				}
+				strassenFloat32(b, depth, m, n, k, a, 0, lda, bMat, 0, ldb, c, 0, ldc)
+				require.InDeltaSlicef(t, want, c, 1e-4, "%s: m=%d, n=%d, k=%d, depth=%d", simd, m, n, k, depth)
+			}
+		}
+	}
+	require.NoError(t, SetSIMD(SIMDAuto))
+}
+
+func TestDotGeneral_Strassen(t *testing.T) {
+	defer func(minSize int) { DotGeneralStrassenMinSize = minSize }(DotGeneralStrassenMinSize)
+	DotGeneralStrassenMinSize = 32
+	strassenBackend, err := New("dotgeneral_strassen")
+	require.NoError(t, err)
+	defer strassenBackend.Finalize()
+
+	const batchSize, m, n, k = 2, 130, 67, 101
+	rng := rand.New(rand.NewSource(42))
+	lhsValues := make([]float32, batchSize*m*k)
+	rhsValues := make([]float32, batchSize*k*n)
+	for i := range lhsValues {
+		lhsValues[i] = rng.Float32()*2 - 1
+	}
+	for i := range rhsValues {
+		rhsValues[i] = rng.Float32()*2 - 1
+	}
+	dotGeneral := func(lhs, rhs *graph.Node) *graph.Node {
+		return graph.DotGeneral(lhs, []int{2}, []int{0}, rhs, []int{1}, []int{0})
+	}
+	want := graph.MustExecOnce(backend, dotGeneral,
+		tensors.FromFlatDataAndDimensions(lhsValues, batchSize, m, k),
+		tensors.FromFlatDataAndDimensions(rhsValues, batchSize, k, n))
+	got := graph.MustExecOnce(strassenBackend, dotGeneral,
+		tensors.FromFlatDataAndDimensions(lhsValues, batchSize, m, k),
+		tensors.FromFlatDataAndDimensions(rhsValues, batchSize, k, n))
+	require.NoError(t, got.Shape().Check(dtypes.Float32, batchSize, m, n))
+	require.InDeltaSlice(t, tensors.MustCopyFlatData[float32](want), tensors.MustCopyFlatData[float32](got), 1e-4)
+}
+
+func BenchmarkDotGeneral_Strassen(b *testing.B) {
+	strassenBackend, err := New("dotgeneral_strassen")
+	require.NoError(b, err)
+	defer strassenBackend.Finalize()
+	for _, size := range []int{2048, 4096} {
+		for _, useStrassen := range []bool{false, true} {
+			execBackend := backend
+			if useStrassen {
+				execBackend = strassenBackend
+			}
+			lhs := tensors.FromShape(shapes.Make(dtypes.Float32, size, size))
+			rhs := tensors.FromShape(shapes.Make(dtypes.Float32, size, size))
+			exec := graph.MustNewExec(execBackend, func(lhs, rhs *graph.Node) *graph.Node {
+				return graph.Dot(lhs, rhs)
+			})
+			b.Run(fmt.Sprintf("size=%d/strassen=%v", size, useStrassen), func(b *testing.B) {
+				for range b.N {
+					exec.MustExec(lhs, rhs)[0].FinalizeAll()
+				}
+			})
+		}
+	}
+}
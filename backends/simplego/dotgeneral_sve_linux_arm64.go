@@ -0,0 +1,101 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !noasm && linux && arm64
+
+package simplego
+
+import (
+	"runtime"
+	"unsafe"
+)
+
+// sveVectorBits is the SVE vector length in bits, or 0 if SVE is not supported.
+var sveVectorBits = detectSVEVectorBits()
+
+// hasSVE indicates whether the SVE float32 kernels are used.
+//
+// They are only enabled for SVE vectors of at least 256 bits (e.g. Graviton3, Neoverse V1): with 128 bits vectors
+// (e.g. Graviton4, Neoverse N2/V2) the NEON kernels are just as fast. When enabled, they are preferred over NEON.
+// They can be disabled with SetSIMD.
+var hasSVE = sveVectorBits >= 256
+
+var _ = registerSIMD(SIMDSVE, &hasSVE)
+
+// detectSVEVectorBits returns the SVE vector length in bits, or 0 if SVE is not supported.
+func detectSVEVectorBits() int {
+	if !cpuFeaturesARM64.HasSVE {
+		return 0
+	}
+	return 8 * int(sveVectorBytes_asm())
+}
+
+// sveVectorBytes_asm is implemented in dotgeneral_sve_linux_arm64.s.
+// It returns the SVE vector length in bytes, and it can only be called if SVE is supported.
+//
+//go:noescape
+func sveVectorBytes_asm() int64
+
+// dotProduct_sve_asm is implemented in dotgeneral_sve_linux_arm64.s.
+// It computes a single dot product of n float32 values using SVE.
+//
+//go:noescape
+func dotProduct_sve_asm(a, b unsafe.Pointer, n int64) float32
+
+// dotProductGroup4_sve_asm is implemented in dotgeneral_sve_linux_arm64.s.
+// It computes 4 dot products simultaneously sharing the same LHS vector.
+// b_stride is the stride in elements (float32) between the start of each RHS vector.
+//
+//go:noescape
+func dotProductGroup4_sve_asm(a, b unsafe.Pointer, b_stride, n int64) (r0, r1, r2, r3 float32)
+
+// gemmMicroKernel4x16_sve_asm is implemented in dotgeneral_sve_linux_arm64.s.
+// It computes the 4x16 tile = A·B from the packed slivers of A and B, see gemmMicroKernelFloat32.
+//
+//go:noescape
+func gemmMicroKernel4x16_sve_asm(kc int64, a, b, tile unsafe.Pointer)
+
+// dotProduct_sve computes dot product using SVE and keeps the source slices alive.
+func dotProduct_sve(aSlice, bSlice []float32, aIdx, bIdx int, n int64) float32 {
+	_ = aSlice[aIdx+int(n)-1]
+	_ = bSlice[bIdx+int(n)-1]
+	result := dotProduct_sve_asm(unsafe.Pointer(&aSlice[aIdx]), unsafe.Pointer(&bSlice[bIdx]), n)
+	runtime.KeepAlive(aSlice)
+	runtime.KeepAlive(bSlice)
+	return result
+}
+
+// dotProductInnerLoopSVE is the SVE version of dotProductInnerLoopNEON: it adds the 4 dot products of the LHS
+// vector with the 4 consecutive RHS vectors (of size blockDim) to the current output values.
+func dotProductInnerLoopSVE(lhsFlat, rhsFlat, outputFlat []float32,
+	lhsIdx, rhsIdx, outputIdx, blockDim int) (sum0, sum1, sum2, sum3 float32) {
+	_ = lhsFlat[lhsIdx+blockDim-1]
+	_ = rhsFlat[rhsIdx+4*blockDim-1]
+	_ = outputFlat[outputIdx+3]
+	r0, r1, r2, r3 := dotProductGroup4_sve_asm(
+		unsafe.Pointer(&lhsFlat[lhsIdx]),
+		unsafe.Pointer(&rhsFlat[rhsIdx]),
+		int64(blockDim), // stride in elements
+		int64(blockDim)) // length n
+	runtime.KeepAlive(lhsFlat)
+	runtime.KeepAlive(rhsFlat)
+	sum0 = outputFlat[outputIdx] + r0
+	sum1 = outputFlat[outputIdx+1] + r1
+	sum2 = outputFlat[outputIdx+2] + r2
+	sum3 = outputFlat[outputIdx+3] + r3
+	return
+}
+
+// gemmMicroKernelSVE is the SVE version of gemmMicroKernelFloat32.
+func gemmMicroKernelSVE(kc int, a, b []float32, tile *[gemmMR * gemmNR]float32) {
+	if kc == 0 {
+		clear(tile[:])
+		return
+	}
+	_ = a[kc*gemmMR-1]
+	_ = b[kc*gemmNR-1]
+	gemmMicroKernel4x16_sve_asm(int64(kc), unsafe.Pointer(&a[0]), unsafe.Pointer(&b[0]), unsafe.Pointer(tile))
+	runtime.KeepAlive(a)
+	runtime.KeepAlive(b)
+}
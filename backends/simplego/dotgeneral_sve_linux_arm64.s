@@ -0,0 +1,169 @@
+//go:build !noasm && linux && arm64
+
+// SVE (Scalable Vector Extension) float32 kernels for ARM64 servers (e.g. Graviton3, Neoverse V1).
+//
+// The Go assembler doesn't support SVE, so the SVE instructions are given as WORDs, with the
+// instruction they encode in the comment.
+//
+// The dot products are vector-length agnostic: they loop with WHILELO predicates, so they work with any
+// SVE vector length. The GEMM micro-kernel uses only the first 8 lanes of each vector (PTRUE VL8), so
+// it requires vectors of at least 256 bits.
+
+#include "textflag.h"
+
+// func sveVectorBytes_asm() int64
+TEXT ·sveVectorBytes_asm(SB), NOSPLIT, $0-8
+	WORD $0x0420e3e0 // cntb x0
+	MOVD R0, ret+0(FP)
+	RET
+
+// func dotProduct_sve_asm(a, b unsafe.Pointer, n int64) float32
+// The main loop processes 2 vectors per iteration, with separate accumulators (z0 and z1) to hide the
+// FMLA latency. The tail is processed one (partial) vector at a time, with a WHILELO predicate.
+TEXT ·dotProduct_sve_asm(SB), NOSPLIT, $0-28
+	MOVD a+0(FP), R0
+	MOVD b+8(FP), R1
+	MOVD n+16(FP), R2
+	MOVD $0, R3          // R3 = index of the next element
+
+	WORD $0x25b8c000     // mov z0.s, #0
+	WORD $0x25b8c001     // mov z1.s, #0
+	WORD $0x2598e3e1     // ptrue p1.s
+	WORD $0x04a0e3e4     // cntw x4: number of float32 per vector
+	LSL  $1, R4, R5      // R5 = 2 vectors
+
+sve_loop2:
+	ADD R5, R3, R6
+	CMP R2, R6
+	BGT sve_tail
+	WORD $0xa5434402     // ld1w {z2.s}, p1/z, [x0, x3, lsl #2]
+	WORD $0xa5434423     // ld1w {z3.s}, p1/z, [x1, x3, lsl #2]
+	WORD $0x65a30440     // fmla z0.s, p1/m, z2.s, z3.s
+	ADD  R4, R3, R3
+	WORD $0xa5434404     // ld1w {z4.s}, p1/z, [x0, x3, lsl #2]
+	WORD $0xa5434425     // ld1w {z5.s}, p1/z, [x1, x3, lsl #2]
+	WORD $0x65a50481     // fmla z1.s, p1/m, z4.s, z5.s
+	ADD  R4, R3, R3
+	B    sve_loop2
+
+sve_tail:
+	WORD $0x25a21c60     // whilelo p0.s, x3, x2
+	BEQ  sve_reduce      // No active lanes.
+
+sve_tail_loop:
+	WORD $0xa5434002     // ld1w {z2.s}, p0/z, [x0, x3, lsl #2]
+	WORD $0xa5434023     // ld1w {z3.s}, p0/z, [x1, x3, lsl #2]
+	WORD $0x65a30040     // fmla z0.s, p0/m, z2.s, z3.s
+	ADD  R4, R3, R3
+	WORD $0x25a21c60     // whilelo p0.s, x3, x2
+	BMI  sve_tail_loop   // First lane active.
+
+sve_reduce:
+	WORD  $0x65810000    // fadd z0.s, z0.s, z1.s
+	WORD  $0x65802400    // faddv s0, p1, z0.s
+	FMOVS F0, ret+24(FP)
+	RET
+
+// func dotProductGroup4_sve_asm(a, b unsafe.Pointer, b_stride, n int64) (r0, r1, r2, r3 float32)
+// Calculates 4 dot products sharing the same LHS (a), with one accumulator each (z0-z3).
+TEXT ·dotProductGroup4_sve_asm(SB), NOSPLIT, $0-48
+	MOVD a+0(FP), R0
+	MOVD b+8(FP), R1
+	MOVD b_stride+16(FP), R2
+	MOVD n+24(FP), R3
+
+	LSL  $2, R2, R2      // stride in bytes
+	ADD  R2, R1, R4      // R4 = b1
+	ADD  R2, R4, R5      // R5 = b2
+	ADD  R2, R5, R6      // R6 = b3
+	MOVD $0, R7          // R7 = index of the next element
+
+	WORD $0x25b8c000     // mov z0.s, #0
+	WORD $0x25b8c001     // mov z1.s, #0
+	WORD $0x25b8c002     // mov z2.s, #0
+	WORD $0x25b8c003     // mov z3.s, #0
+
+	WORD $0x25a31ce0     // whilelo p0.s, x7, x3
+	BEQ  g4sve_reduce    // No active lanes.
+
+g4sve_loop:
+	WORD $0xa5474004     // ld1w {z4.s}, p0/z, [x0, x7, lsl #2]
+	WORD $0xa5474025     // ld1w {z5.s}, p0/z, [x1, x7, lsl #2]
+	WORD $0xa5474086     // ld1w {z6.s}, p0/z, [x4, x7, lsl #2]
+	WORD $0xa54740a7     // ld1w {z7.s}, p0/z, [x5, x7, lsl #2]
+	WORD $0xa54740d0     // ld1w {z16.s}, p0/z, [x6, x7, lsl #2]
+	WORD $0x65a50080     // fmla z0.s, p0/m, z4.s, z5.s
+	WORD $0x65a60081     // fmla z1.s, p0/m, z4.s, z6.s
+	WORD $0x65a70082     // fmla z2.s, p0/m, z4.s, z7.s
+	WORD $0x65b00083     // fmla z3.s, p0/m, z4.s, z16.s
+	WORD $0x04b0e3e7     // incw x7
+	WORD $0x25a31ce0     // whilelo p0.s, x7, x3
+	BMI  g4sve_loop      // First lane active.
+
+g4sve_reduce:
+	WORD  $0x2598e3e1    // ptrue p1.s
+	WORD  $0x65802400    // faddv s0, p1, z0.s
+	WORD  $0x65802421    // faddv s1, p1, z1.s
+	WORD  $0x65802442    // faddv s2, p1, z2.s
+	WORD  $0x65802463    // faddv s3, p1, z3.s
+	FMOVS F0, r0+32(FP)
+	FMOVS F1, r1+36(FP)
+	FMOVS F2, r2+40(FP)
+	FMOVS F3, r3+44(FP)
+	RET
+
+// func gemmMicroKernel4x16_sve_asm(kc int64, a, b, tile unsafe.Pointer)
+// It computes the 4x16 tile = A·B from the packed slivers of A and B, see gemmMicroKernelFloat32.
+// Each row of the tile is held in 2 vectors of 8 lanes (z0-z7), so it requires vectors of >= 256 bits.
+TEXT ·gemmMicroKernel4x16_sve_asm(SB), NOSPLIT, $0-32
+	MOVD kc+0(FP), R0
+	MOVD a+8(FP), R1
+	MOVD b+16(FP), R2
+	MOVD tile+24(FP), R3
+	MOVD $8, R5          // Offset (in float32) of the second half of the rows.
+
+	WORD $0x2598e100     // ptrue p0.s, vl8
+	WORD $0x25b8c000     // mov z0.s, #0
+	WORD $0x25b8c001     // mov z1.s, #0
+	WORD $0x25b8c002     // mov z2.s, #0
+	WORD $0x25b8c003     // mov z3.s, #0
+	WORD $0x25b8c004     // mov z4.s, #0
+	WORD $0x25b8c005     // mov z5.s, #0
+	WORD $0x25b8c006     // mov z6.s, #0
+	WORD $0x25b8c007     // mov z7.s, #0
+
+	CBZ R0, gemmsve_store
+
+gemmsve_loop:
+	WORD $0xa540a048     // ld1w {z8.s}, p0/z, [x2]
+	WORD $0xa5454049     // ld1w {z9.s}, p0/z, [x2, x5, lsl #2]
+	WORD $0x8540c02a     // ld1rw {z10.s}, p0/z, [x1]
+	WORD $0x8541c02b     // ld1rw {z11.s}, p0/z, [x1, #4]
+	WORD $0x8542c02c     // ld1rw {z12.s}, p0/z, [x1, #8]
+	WORD $0x8543c02d     // ld1rw {z13.s}, p0/z, [x1, #12]
+	WORD $0x65aa0100     // fmla z0.s, p0/m, z8.s, z10.s
+	WORD $0x65aa0121     // fmla z1.s, p0/m, z9.s, z10.s
+	WORD $0x65ab0102     // fmla z2.s, p0/m, z8.s, z11.s
+	WORD $0x65ab0123     // fmla z3.s, p0/m, z9.s, z11.s
+	WORD $0x65ac0104     // fmla z4.s, p0/m, z8.s, z12.s
+	WORD $0x65ac0125     // fmla z5.s, p0/m, z9.s, z12.s
+	WORD $0x65ad0106     // fmla z6.s, p0/m, z8.s, z13.s
+	WORD $0x65ad0127     // fmla z7.s, p0/m, z9.s, z13.s
+	ADD  $16, R1
+	ADD  $64, R2
+	SUBS $1, R0
+	BNE  gemmsve_loop
+
+gemmsve_store:
+	WORD $0xe540e060     // st1w {z0.s}, p0, [x3]
+	WORD $0xe5454061     // st1w {z1.s}, p0, [x3, x5, lsl #2]
+	ADD  $64, R3
+	WORD $0xe540e062     // st1w {z2.s}, p0, [x3]
+	WORD $0xe5454063     // st1w {z3.s}, p0, [x3, x5, lsl #2]
+	ADD  $64, R3
+	WORD $0xe540e064     // st1w {z4.s}, p0, [x3]
+	WORD $0xe5454065     // st1w {z5.s}, p0, [x3, x5, lsl #2]
+	ADD  $64, R3
+	WORD $0xe540e066     // st1w {z6.s}, p0, [x3]
+	WORD $0xe5454067     // st1w {z7.s}, p0, [x3, x5, lsl #2]
+	RET
@@ -0,0 +1,61 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !noasm && linux && arm64
+
+package simplego
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestSVEKernels compares the SVE kernels with the pure Go versions.
+func TestSVEKernels(t *testing.T) {
+	t.Logf("SVE=%v, SVE vector length=%d bits", cpuFeaturesARM64.HasSVE, sveVectorBits)
+	if !hasSVE {
+		t.Skip("SVE with vectors of >= 256 bits not available on this system")
+	}
+	rng := rand.New(rand.NewSource(42))
+	randomSlice := func(n int) []float32 {
+		s := make([]float32, n)
+		for i := range s {
+			s[i] = rng.Float32()*2 - 1
+		}
+		return s
+	}
+
+	for _, size := range []int{1, 7, 8, 15, 16, 17, 31, 32, 33, 64, 65, 100, 1000} {
+		t.Run(fmt.Sprintf("size_%d", size), func(t *testing.T) {
+			// Use offsets to test unaligned access.
+			a, b := randomSlice(size+1), randomSlice(4*size+3)
+			var want float32
+			for i := range size {
+				want += a[1+i] * b[3+i]
+			}
+			require.InDelta(t, want, dotProduct_sve(a, b, 1, 3, int64(size)), 1e-4)
+
+			output := []float32{1, 2, 3, 4}
+			s0, s1, s2, s3 := dotProductInnerLoopSVE(a, b, output, 1, 3, 0, size)
+			for ii, got := range []float32{s0, s1, s2, s3} {
+				var want float32
+				for i := range size {
+					want += a[1+i] * b[3+ii*size+i]
+				}
+				require.InDeltaf(t, output[ii]+want, got, 1e-4, "group4 result #%d", ii)
+			}
+		})
+	}
+
+	for _, kc := range []int{0, 1, 5, 64} {
+		a, b := randomSlice(kc*gemmMR), randomSlice(kc*gemmNR)
+		var got, want [gemmMR * gemmNR]float32
+		gemmMicroKernelSVE(kc, a, b, &got)
+		gemmMicroKernelGo(kc, a, b, &want)
+		require.InDeltaSlice(t, want[:], got[:], 1e-4, "gemm micro-kernel with kc=%d", kc)
+	}
+}
@@ -0,0 +1,26 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build noasm || !linux || !arm64
+
+package simplego
+
+// hasSVE indicates whether the SVE float32 kernels are used: never on this platform.
+const hasSVE = false
+
+// dotProduct_sve stub for platforms without SVE.
+func dotProduct_sve(aSlice, bSlice []float32, aIdx, bIdx int, n int64) float32 {
+	panic("SVE not available")
+}
+
+// dotProductInnerLoopSVE stub for platforms without SVE.
+func dotProductInnerLoopSVE(lhsFlat, rhsFlat, outputFlat []float32,
+	lhsIdx, rhsIdx, outputIdx, blockDim int) (sum0, sum1, sum2, sum3 float32) {
+	panic("SVE not available")
+}
+
+// gemmMicroKernelSVE stub for platforms without SVE.
+func gemmMicroKernelSVE(kc int, a, b []float32, tile *[gemmMR * gemmNR]float32) {
+	panic("SVE not available")
+}
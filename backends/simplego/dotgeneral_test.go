@@ -3,6 +3,7 @@ package simplego
 import (
 	"fmt"
 	"math"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -11,6 +12,7 @@ import (
 	"github.com/gomlx/gopjrt/dtypes/bfloat16"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/x448/float16"
 
 	"github.com/gomlx/gomlx/pkg/core/graph"
 	"github.com/gomlx/gomlx/pkg/core/shapes"
@@ -612,7 +614,7 @@ func TestDotGeneral_NonSquareMatrices(t *testing.T) {
 
 	// Execute via fast path (if applicable) or standard path
 	if canUseFastPath(lhs, rhs, params) {
-		execDotGeneralFastPathFloat32(be, lhs, rhs, params, output)
+		execDotGeneralFastPathFloat32(be, lhs, rhs, params, output, nil)
 	} else {
 		// Use standard path
 		execDotGeneralLarge(be, lhs, rhs, params, output)
@@ -690,7 +692,7 @@ func TestDotGeneral_NonSquareLarger(t *testing.T) {
 
 	// Execute via fast path (if applicable) or standard path
 	if canUseFastPath(lhs, rhs, params) {
-		execDotGeneralFastPathFloat32(be, lhs, rhs, params, output)
+		execDotGeneralFastPathFloat32(be, lhs, rhs, params, output, nil)
 	} else {
 		execDotGeneralLarge(be, lhs, rhs, params, output)
 	}
@@ -702,3 +704,208 @@ func TestDotGeneral_NonSquareLarger(t *testing.T) {
 			"Mismatch at index %d: expected %f, got %f", i, expected[i], outputFlat[i])
 	}
 }
+
+func TestParallelizeDotGeneral(t *testing.T) {
+	be, err := New("parallelism=4")
+	require.NoError(t, err)
+	defer be.Finalize()
+	goBackend := be.(*Backend)
+
+	for _, tc := range []struct{ batchSize, numRows, rowWork int }{
+		{1, 1, 1},                             // Inline.
+		{3, 7, DotGeneralParallelMinWork},     // Tasks crossing batch examples.
+		{1, 1000, DotGeneralParallelMinWork},  // Limited by parallelism.
+		{5, 3, DotGeneralParallelMinWork / 2}, // Limited by the work.
+	} {
+		var mu sync.Mutex
+		counts := make([]int, tc.batchSize*tc.numRows)
+		parallelizeDotGeneral(goBackend, tc.batchSize, tc.numRows, tc.rowWork, func(batchIdx, rowStart, rowEnd int) {
+			assert.True(t, rowStart < rowEnd && rowEnd <= tc.numRows)
+			mu.Lock()
+			defer mu.Unlock()
+			for row := rowStart; row < rowEnd; row++ {
+				counts[batchIdx*tc.numRows+row]++
+			}
+		})
+		for idx, count := range counts {
+			require.Equalf(t, 1, count, "%+v: row %d executed %d times", tc, idx, count)
+		}
+	}
+}
+
+func TestDotGeneral_FastPathParallel(t *testing.T) {
+	be, err := New("parallelism=4")
+	require.NoError(t, err)
+	defer be.Finalize()
+
+	B, M, K, N := 3, 50, 64, 70
+	lhsFlat := xslices.Iota(float32(0), B*M*K)
+	rhsFlat := xslices.Iota(float32(0), B*K*N)
+	got := graph.MustExecOnce(be, func(lhs, rhs *graph.Node) *graph.Node {
+		return graph.Einsum("bmk,bkn->bmn", lhs, rhs)
+	}, tensors.FromFlatDataAndDimensions(lhsFlat, B, M, K), tensors.FromFlatDataAndDimensions(rhsFlat, B, K, N))
+	want := make([]float32, B*M*N)
+	for b := range B {
+		for m := range M {
+			for n := range N {
+				var sum float64
+				for k := range K {
+					sum += float64(lhsFlat[(b*M+m)*K+k]) * float64(rhsFlat[(b*K+k)*N+n])
+				}
+				want[(b*M+m)*N+n] = float32(sum)
+			}
+		}
+	}
+	require.True(t, got.InDelta(tensors.FromFlatDataAndDimensions(want, B, M, N), 1e-6*float64(want[len(want)-1])))
+}
+
+func TestDotGeneral_FastPathTransposedRHS(t *testing.T) {
+	lhsShape := shapes.Make(dtypes.Float32, 2, 3, 5)
+	rhsShape := shapes.Make(dtypes.Float32, 2, 7, 5)
+	require.True(t, isTransposedRHSMatmul(lhsShape, rhsShape, []int{2}, []int{2}, []int{0}, []int{0}))
+	require.False(t, isTransposedRHSMatmul(lhsShape, rhsShape, []int{2}, []int{1}, []int{0}, []int{0}))
+	require.False(t, isTransposedRHSMatmul(lhsShape, rhsShape, []int{2}, []int{2}, nil, nil))
+
+	be, err := New("parallelism=4")
+	require.NoError(t, err)
+	defer be.Finalize()
+	defer func() { require.NoError(t, SetSIMD()) }()
+
+	for _, simd := range []string{SIMDAuto, SIMDOff} {
+		require.NoError(t, SetSIMD(simd))
+		for _, dims := range [][4]int{{1, 3, 5, 7}, {3, 50, 64, 70}, {2, 9, 37, 13}} {
+			B, M, K, N := dims[0], dims[1], dims[2], dims[3]
+			t.Run(fmt.Sprintf("simd=%s/B=%d,M=%d,K=%d,N=%d", simd, B, M, K, N), func(t *testing.T) {
+				lhsFlat := xslices.Iota(float32(0), B*M*K)
+				rhsFlat := xslices.Iota(float32(1), B*N*K)
+				got := graph.MustExecOnce(be, func(lhs, rhs *graph.Node) *graph.Node {
+					return graph.Einsum("bmk,bnk->bmn", lhs, rhs)
+				}, tensors.FromFlatDataAndDimensions(lhsFlat, B, M, K), tensors.FromFlatDataAndDimensions(rhsFlat, B, N, K))
+				want := make([]float32, B*M*N)
+				for b := range B {
+					for m := range M {
+						for n := range N {
+							var sum float64
+							for k := range K {
+								sum += float64(lhsFlat[(b*M+m)*K+k]) * float64(rhsFlat[(b*N+n)*K+k])
+							}
+							want[(b*M+m)*N+n] = float32(sum)
+						}
+					}
+				}
+				require.True(t, got.InDelta(tensors.FromFlatDataAndDimensions(want, B, M, N), 1e-6*float64(want[len(want)-1])))
+			})
+		}
+	}
+}
+
+func TestDotGeneral_FastPathGEMV(t *testing.T) {
+	require.True(t, isStandardMatmul(shapes.Make(dtypes.Float32, 5), shapes.Make(dtypes.Float32, 5, 7),
+		[]int{0}, []int{0}, nil, nil))
+
+	be, err := New("parallelism=4")
+	require.NoError(t, err)
+	defer be.Finalize()
+	defer func() { require.NoError(t, SetSIMD()) }()
+
+	for _, simd := range []string{SIMDAuto, SIMDOff} {
+		require.NoError(t, SetSIMD(simd))
+		for _, dims := range [][3]int{{0, 37, 130}, {0, 1024, 1000}, {3, 64, 200}} {
+			B, K, N := dims[0], dims[1], dims[2]
+			t.Run(fmt.Sprintf("simd=%s/B=%d,K=%d,N=%d", simd, B, K, N), func(t *testing.T) {
+				// B == 0 means no batch axis: [K] × [K, N]; otherwise [B, 1, K] × [B, K, N].
+				numBatches := max(B, 1)
+				lhsFlat := xslices.Iota(float32(0), numBatches*K)
+				rhsFlat := make([]float32, numBatches*K*N)
+				for i := range rhsFlat {
+					rhsFlat[i] = float32(i%7) - 3
+				}
+				var lhs, rhs *tensors.Tensor
+				if B == 0 {
+					lhs = tensors.FromFlatDataAndDimensions(lhsFlat, K)
+					rhs = tensors.FromFlatDataAndDimensions(rhsFlat, K, N)
+				} else {
+					lhs = tensors.FromFlatDataAndDimensions(lhsFlat, B, 1, K)
+					rhs = tensors.FromFlatDataAndDimensions(rhsFlat, B, K, N)
+				}
+				got := graph.MustExecOnce(be, func(lhs, rhs *graph.Node) *graph.Node {
+					if B == 0 {
+						return graph.DotGeneral(lhs, []int{0}, nil, rhs, []int{0}, nil)
+					}
+					return graph.Einsum("bmk,bkn->bmn", lhs, rhs)
+				}, lhs, rhs)
+				want := make([]float32, numBatches*N)
+				for b := range numBatches {
+					for n := range N {
+						var sum float64
+						for k := range K {
+							sum += float64(lhsFlat[b*K+k]) * float64(rhsFlat[(b*K+k)*N+n])
+						}
+						want[b*N+n] = float32(sum)
+					}
+				}
+				require.Equal(t, len(want), got.Shape().Size())
+				gotFlat := tensors.MustCopyFlatData[float32](got)
+				for i := range want {
+					require.InDeltaf(t, want[i], gotFlat[i], 1e-6*math.Abs(float64(want[i]))+1e-3, "mismatch at %d", i)
+				}
+			})
+		}
+	}
+}
+
+func TestDotGeneral_FastPathFloat16(t *testing.T) {
+	be, err := New("parallelism=4")
+	require.NoError(t, err)
+	defer be.Finalize()
+
+	for _, rhsTransposed := range []bool{false, true} {
+		for _, dims := range [][4]int{{0, 3, 5, 7}, {0, 1, 37, 9}, {2, 9, 37, 13}, {3, 50, 64, 70}} {
+			B, M, K, N := dims[0], dims[1], dims[2], dims[3]
+			t.Run(fmt.Sprintf("transposed=%v/B=%d,M=%d,K=%d,N=%d", rhsTransposed, B, M, K, N), func(t *testing.T) {
+				// B == 0 means no batch axis.
+				numBatches := max(B, 1)
+				lhsFlat := make([]float16.Float16, numBatches*M*K)
+				for i := range lhsFlat {
+					lhsFlat[i] = float16.Fromfloat32(float32(i%7-3) * 0.25)
+				}
+				rhsFlat := make([]float16.Float16, numBatches*K*N)
+				for i := range rhsFlat {
+					rhsFlat[i] = float16.Fromfloat32(float32(i%5-2) * 0.5)
+				}
+				lhsDims, rhsDims := []int{M, K}, []int{K, N}
+				equation := "mk,kn->mn"
+				if rhsTransposed {
+					rhsDims = []int{N, K}
+					equation = "mk,nk->mn"
+				}
+				if B > 0 {
+					lhsDims = append([]int{B}, lhsDims...)
+					rhsDims = append([]int{B}, rhsDims...)
+					equation = "b" + strings.ReplaceAll(strings.ReplaceAll(equation, ",", ",b"), "->", "->b")
+				}
+				got := graph.MustExecOnce(be, func(lhs, rhs *graph.Node) *graph.Node {
+					return graph.Einsum(equation, lhs, rhs)
+				}, tensors.FromFlatDataAndDimensions(lhsFlat, lhsDims...), tensors.FromFlatDataAndDimensions(rhsFlat, rhsDims...))
+				require.Equal(t, dtypes.Float16, got.DType())
+				gotFlat := tensors.MustCopyFlatData[float16.Float16](got)
+				for b := range numBatches {
+					for m := range M {
+						for n := range N {
+							var want float64
+							for k := range K {
+								rhsIdx := (b*K+k)*N + n
+								if rhsTransposed {
+									rhsIdx = (b*N+n)*K + k
+								}
+								want += float64(lhsFlat[(b*M+m)*K+k].Float32()) * float64(rhsFlat[rhsIdx].Float32())
+							}
+							require.InDeltaf(t, want, gotFlat[(b*M+m)*N+n].Float32(), 1e-3*math.Abs(want)+1e-3,
+								"mismatch at b=%d, m=%d, n=%d", b, m, n)
+						}
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+// dotProductFloat32 returns the dot product of a[aIdx:aIdx+n] and b[bIdx:bIdx+n], using the fastest SIMD kernel
+// enabled: AVX-512 → AVX2 → SVE → NEON → pure Go.
+func dotProductFloat32(a, b []float32, aIdx, bIdx, n int) float32 {
+	switch {
+	case hasAVX512 && n >= 16:
+		return dotProduct_avx512(a, b, aIdx, bIdx, int64(n))
+	case hasAVX2 && n >= 8:
+		return dotProduct_avx2(a, b, aIdx, bIdx, int64(n))
+	case hasSVE && n >= 16:
+		return dotProduct_sve(a, b, aIdx, bIdx, int64(n))
+	case hasNEON && n >= 16:
+		return dotProduct_neon(a, b, aIdx, bIdx, int64(n))
+	}
+	a = a[aIdx : aIdx+n]
+	b = b[bIdx : bIdx+n]
+	var sum0, sum1, sum2, sum3 float32
+	i := 0
+	for ; i+3 < n; i += 4 {
+		sum0 += a[i] * b[i]
+		sum1 += a[i+1] * b[i+1]
+		sum2 += a[i+2] * b[i+2]
+		sum3 += a[i+3] * b[i+3]
+	}
+	for ; i < n; i++ {
+		sum0 += a[i] * b[i]
+	}
+	return sum0 + sum1 + sum2 + sum3
+}
+
+// dotProductGroup4Float32 computes the 4 dot products of lhs[lhsIdx:lhsIdx+n] with the 4 consecutive vectors of
+// size n of rhs starting at rhsIdx, and adds them to output[outputIdx:outputIdx+4].
+//
+// It uses the fastest SIMD kernel enabled: AVX-512 → AVX2 → SVE → NEON → pure Go.
+func dotProductGroup4Float32(lhs, rhs, output []float32, lhsIdx, rhsIdx, outputIdx, n int) {
+	var sum0, sum1, sum2, sum3 float32
+	switch {
+	case hasAVX512 && n >= 16:
+		sum0, sum1, sum2, sum3 = dotProductInnerLoopAVX512(lhs, rhs, output, lhsIdx, rhsIdx, outputIdx, n)
+	case hasAVX2 && n >= 8:
+		sum0, sum1, sum2, sum3 = dotProductInnerLoopAVX2(lhs, rhs, output, lhsIdx, rhsIdx, outputIdx, n)
+	case hasSVE && n >= 16:
+		sum0, sum1, sum2, sum3 = dotProductInnerLoopSVE(lhs, rhs, output, lhsIdx, rhsIdx, outputIdx, n)
+	case hasNEON && n >= 16:
+		sum0, sum1, sum2, sum3 = dotProductInnerLoopNEON(lhs, rhs, output, lhsIdx, rhsIdx, outputIdx, n)
+	default:
+		a := lhs[lhsIdx : lhsIdx+n]
+		b0 := rhs[rhsIdx : rhsIdx+n]
+		b1 := rhs[rhsIdx+n : rhsIdx+2*n]
+		b2 := rhs[rhsIdx+2*n : rhsIdx+3*n]
+		b3 := rhs[rhsIdx+3*n : rhsIdx+4*n]
+		sum0, sum1, sum2, sum3 = output[outputIdx], output[outputIdx+1], output[outputIdx+2], output[outputIdx+3]
+		for i, v := range a {
+			sum0 += v * b0[i]
+			sum1 += v * b1[i]
+			sum2 += v * b2[i]
+			sum3 += v * b3[i]
+		}
+	}
+	output[outputIdx], output[outputIdx+1], output[outputIdx+2], output[outputIdx+3] = sum0, sum1, sum2, sum3
+}
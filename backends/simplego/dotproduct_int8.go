@@ -0,0 +1,25 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+// dotProductInt8Go is the pure Go version of the int8 dot product, accumulated in int32.
+func dotProductInt8Go(a, b []int8) int32 {
+	b = b[:len(a)]
+	var sum int32
+	for i, v := range a {
+		sum += int32(v) * int32(b[i])
+	}
+	return sum
+}
+
+// dotProductUint8Go is the pure Go version of the uint8 dot product, accumulated in int32.
+func dotProductUint8Go(a, b []uint8) int32 {
+	b = b[:len(a)]
+	var sum int32
+	for i, v := range a {
+		sum += int32(v) * int32(b[i])
+	}
+	return sum
+}
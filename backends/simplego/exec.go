@@ -4,6 +4,7 @@ import (
 	"sync"
 
 	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
 
 	"github.com/gomlx/gomlx/backends"
 	"github.com/gomlx/gomlx/pkg/core/shapes"
@@ -39,6 +40,9 @@ type Executable struct {
 	// dependents maps each node to the list of nodes that depend on it -- only count nodes that are used
 	// by this executable.
 	dependents [][]int
+
+	// plan of the buffers of the intermediate results, for sequential executions.
+	plan *bufferPlan
 }
 
 // executionBuffers holds the intermediate results during the execution of the graph.
@@ -155,6 +159,11 @@ func newExecutable(builder *Builder) *Executable {
 		e.countNodeUsesAndDependants(output)
 	}
 
+	e.plan = newBufferPlan(builder, numNodesToProcess, e.numUses)
+	if klog.V(1).Enabled() {
+		klog.Infof("SimpleGo: computation %q: estimated peak memory of the intermediate results %d bytes (%d bytes without reuse)",
+			builder.name, e.plan.peakBytes, e.plan.peakBytesNoReuse)
+	}
 	return e
 }
 
@@ -393,15 +402,29 @@ func (e *Executable) executeNode(node *Node, execBuf *executionBuffers) error {
 		inputsOwned = make([]bool, len(node.inputs))
 	}
 
+	if execBuf.opsExecutionType == opsExecutionParallel {
+		execBuf.mu.Lock()
+	}
 	for ii, input := range node.inputs {
 		inputNodeIdx := input.builderIdx
 		inputBuffers[ii] = execBuf.results[inputNodeIdx]
 		if inputBuffers[ii] == nil || !inputBuffers[ii].shape.Ok() {
+			if execBuf.opsExecutionType == opsExecutionParallel {
+				execBuf.mu.Unlock()
+			}
 			return errors.Errorf("SimpleGo execute: input #%d of node #%d is not calculated yet (!?) -- "+
 				"this is a bug, it should never have happened", ii, nodeIdx)
 		}
-		// Only "own" the input if this is the last use of it.
-		inputsOwned[ii] = execBuf.owned[inputNodeIdx] && e.numUses[inputNodeIdx]-execBuf.numUsed[inputNodeIdx] == 1
+		// Only "own" the input if this is the last use of it: in sequential executions it is known from the plan,
+		// in parallel executions it depends on the order the other users of the input were executed.
+		if execBuf.opsExecutionType == opsExecutionParallel {
+			inputsOwned[ii] = execBuf.owned[inputNodeIdx] && e.numUses[inputNodeIdx]-execBuf.numUsed[inputNodeIdx] == 1
+		} else {
+			inputsOwned[ii] = execBuf.owned[inputNodeIdx] && e.plan.isLastUse(node, ii)
+		}
+	}
+	if execBuf.opsExecutionType == opsExecutionParallel {
+		execBuf.mu.Unlock()
 	}
 
 	if node.IsMultiOutputs() {
@@ -413,7 +436,11 @@ func (e *Executable) executeNode(node *Node, execBuf *executionBuffers) error {
 				node.opType,
 			)
 		}
-		outputs, err := multiNodeExecutor(e.backend, node, inputBuffers, inputsOwned)
+		var outputs []*Buffer
+		var err error
+		e.backend.executeWithProfiling(node, func() {
+			outputs, err = multiNodeExecutor(e.backend, node, inputBuffers, inputsOwned)
+		})
 		if err != nil {
 			return errors.WithMessagef(err, "while executing %q", node.opType)
 		}
@@ -436,7 +463,9 @@ func (e *Executable) executeNode(node *Node, execBuf *executionBuffers) error {
 			return errors.Errorf("Execute: node executor for op type %s not implemented!?", node.opType)
 		}
 		var err error
-		execBuf.results[nodeIdx], err = nodeExecutor(e.backend, node, inputBuffers, inputsOwned)
+		e.backend.executeWithProfiling(node, func() {
+			execBuf.results[nodeIdx], err = nodeExecutor(e.backend, node, inputBuffers, inputsOwned)
+		})
 		if err != nil {
 			return errors.WithMessagef(err, "while executing %q", node.opType)
 		}
@@ -0,0 +1,182 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"github.com/gomlx/gomlx/backends"
+)
+
+// bufferPlan is the plan of the buffers of the intermediate results of an Executable, based on their liveness in
+// the sequential order of the nodes. It is computed once, when the Executable is created.
+//
+// The buffer of an intermediate result is released (back to the backend pool, to be reused by the following
+// nodes) right after its last use, or taken over by the node of its last use, if this node executes in place
+// (see inPlaceOperands): e.g. in "Exp(Add(x, y))" the Exp overwrites the output of the Add.
+type bufferPlan struct {
+	// lastUse is the index of the last node (in sequential order) that uses the result of each node, or -1 if it
+	// is not used by any node, or if it is an output of the computation (its buffer is returned).
+	lastUse []int
+
+	// inPlace is the index of the operand whose buffer each node takes over for its output, if it is an
+	// intermediate result at its last use, or -1 if none.
+	//
+	// Parameters are only taken over if donated, which is only known at execution time.
+	inPlace []int
+
+	// peakBytes is the estimated peak memory of the intermediate results (excluding parameters and constants)
+	// during a sequential execution with the plan, and peakBytesNoReuse is the memory if no buffer was reused.
+	peakBytes, peakBytesNoReuse int
+}
+
+// inPlaceOperands lists, for each op type, the operands whose buffers its executor may take over for the output,
+// in order of preference, if they are owned (see nodeExecutor) and have the same size and element size as the
+// output.
+var inPlaceOperands [backends.OpTypeLast][]int
+
+func init() {
+	for _, opType := range []backends.OpType{
+		// Unary ops, see unaryOperandAndOutput.
+		backends.OpTypeNeg, backends.OpTypeAbs, backends.OpTypeSign, backends.OpTypeLogicalNot,
+		backends.OpTypeBitwiseNot, backends.OpTypeBitCount, backends.OpTypeClz, backends.OpTypeExp,
+		backends.OpTypeExpm1, backends.OpTypeLog, backends.OpTypeLog1p, backends.OpTypeCeil, backends.OpTypeFloor,
+		backends.OpTypeRound, backends.OpTypeRsqrt, backends.OpTypeSqrt, backends.OpTypeCos, backends.OpTypeSin,
+		backends.OpTypeLogistic, backends.OpTypeTanh, backends.OpTypeErf,
+
+		// Other ops that reuse their first operand.
+		backends.OpTypeIdentity, backends.OpTypeReshape, backends.OpTypeConvertDType, backends.OpTypeScatterMax,
+		backends.OpTypeScatterMin, backends.OpTypeScatterSum,
+	} {
+		inPlaceOperands[opType] = []int{0}
+	}
+
+	// Binary ops, see binaryOperandsAndOutput.
+	for _, opType := range []backends.OpType{
+		backends.OpTypeAdd, backends.OpTypeMul, backends.OpTypeSub, backends.OpTypeDiv, backends.OpTypeRem,
+		backends.OpTypePow, backends.OpTypeMax, backends.OpTypeMin, backends.OpTypeBitwiseAnd,
+		backends.OpTypeBitwiseOr, backends.OpTypeBitwiseXor, backends.OpTypeLogicalAnd, backends.OpTypeLogicalOr,
+		backends.OpTypeLogicalXor,
+	} {
+		inPlaceOperands[opType] = []int{1, 0}
+	}
+
+	// Where(condition, onTrue, onFalse), see execWhere.
+	inPlaceOperands[backends.OpTypeWhere] = []int{1, 2}
+}
+
+// newBufferPlan creates the plan of the buffers for the first numNodes nodes of the builder, given the number of
+// uses of each node (see Executable.numUses).
+func newBufferPlan(builder *Builder, numNodes int, numUses []int) *bufferPlan {
+	plan := &bufferPlan{
+		lastUse: make([]int, numNodes),
+		inPlace: make([]int, numNodes),
+	}
+	for nodeIdx := range numNodes {
+		plan.lastUse[nodeIdx] = -1
+		plan.inPlace[nodeIdx] = -1
+	}
+	for nodeIdx := range numNodes {
+		if numUses[nodeIdx] == 0 {
+			continue
+		}
+		for _, input := range builder.nodes[nodeIdx].inputs {
+			plan.lastUse[input.builderIdx] = nodeIdx
+		}
+	}
+
+	for _, output := range builder.outputs {
+		plan.lastUse[output.builderIdx] = -1
+	}
+	// isIntermediate returns whether the result of the node is owned by the executor, in every execution.
+	isIntermediate := func(node *Node) bool {
+		return node.opType != backends.OpTypeConstant && node.opType != backends.OpTypeParameter
+	}
+
+	var liveBytes, allBytes int
+	for nodeIdx := range numNodes {
+		node := builder.nodes[nodeIdx]
+		if numUses[nodeIdx] == 0 || !isIntermediate(node) {
+			continue
+		}
+		if !node.IsMultiOutputs() {
+			plan.inPlace[nodeIdx] = plan.selectInPlaceOperand(node, isIntermediate)
+
+			// The output is allocated, unless it takes over the buffer of an intermediate result.
+			outputBytes := int(node.shape.Memory())
+			allBytes += outputBytes
+			if operandIdx := plan.inPlace[nodeIdx]; operandIdx < 0 || !isIntermediate(node.inputs[operandIdx]) {
+				liveBytes += outputBytes
+			}
+			plan.peakBytes = max(plan.peakBytes, liveBytes)
+		}
+
+		// The intermediate results at their last use are released.
+		for inputIdx, input := range node.inputs {
+			if inputIdx != firstInputUse(node, input) || inputIdx == plan.inPlace[nodeIdx] ||
+				!isIntermediate(input) || input.IsMultiOutputs() || plan.lastUse[input.builderIdx] != nodeIdx {
+				continue
+			}
+			liveBytes -= int(input.shape.Memory())
+		}
+	}
+	plan.peakBytesNoReuse = allBytes
+	return plan
+}
+
+// selectInPlaceOperand returns the operand whose buffer the node can take over, preferring intermediate results
+// to parameters (which are only owned if donated), or -1 if none.
+func (plan *bufferPlan) selectInPlaceOperand(node *Node, isIntermediate func(*Node) bool) int {
+	selected := -1
+	for _, operandIdx := range inPlaceOperands[node.opType] {
+		if operandIdx >= len(node.inputs) {
+			continue
+		}
+		operand := node.inputs[operandIdx]
+		if operand.opType == backends.OpTypeConstant || !plan.isLastUse(node, operandIdx) ||
+			!canTakeOverBuffer(node, operand) {
+			continue
+		}
+		if isIntermediate(operand) {
+			return operandIdx
+		}
+		if selected < 0 {
+			selected = operandIdx
+		}
+	}
+	return selected
+}
+
+// isLastUse returns whether the node is the last one using its input #inputIdx, and it is its only use by the
+// node: so its buffer, if owned by the executor, can be reused by the node.
+func (plan *bufferPlan) isLastUse(node *Node, inputIdx int) bool {
+	input := node.inputs[inputIdx]
+	return plan.lastUse[input.builderIdx] == node.builderIdx && countInputUses(node, input) == 1
+}
+
+// canTakeOverBuffer returns whether the output of the node can be stored in the buffer of the operand: it must
+// have the same number of elements, of the same size.
+func canTakeOverBuffer(node, operand *Node) bool {
+	return operand.shape.Size() == node.shape.Size() && operand.shape.DType.Size() == node.shape.DType.Size()
+}
+
+// countInputUses returns how many times input is used by the node.
+func countInputUses(node, input *Node) int {
+	var count int
+	for _, nodeInput := range node.inputs {
+		if nodeInput == input {
+			count++
+		}
+	}
+	return count
+}
+
+// firstInputUse returns the index of the first use of input by the node.
+func firstInputUse(node, input *Node) int {
+	for inputIdx, nodeInput := range node.inputs {
+		if nodeInput == input {
+			return inputIdx
+		}
+	}
+	return -1
+}
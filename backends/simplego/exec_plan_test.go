@@ -0,0 +1,75 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gomlx/gopjrt/dtypes"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gomlx/gomlx/backends"
+	"github.com/gomlx/gomlx/pkg/core/shapes"
+)
+
+func TestBufferPlan(t *testing.T) {
+	const size = 1024
+	builder := backend.Builder("TestBufferPlan")
+	x, err := builder.Parameter("x", shapes.Make(dtypes.Float32, size), nil)
+	require.NoError(t, err)
+	sum, err := builder.Add(x, x)
+	require.NoError(t, err)
+	exp, err := builder.Exp(sum)
+	require.NoError(t, err)
+	mul, err := builder.Mul(x, exp)
+	require.NoError(t, err)
+	converted, err := builder.ConvertDType(mul, dtypes.Int32)
+	require.NoError(t, err)
+	exec, err := builder.Compile([]backends.Op{converted}, nil)
+	require.NoError(t, err)
+	defer exec.Finalize()
+
+	plan := exec.(*Executable).plan
+	nodeIdx := func(op backends.Op) int { return op.(*Node).builderIdx }
+	require.Equal(t, nodeIdx(mul), plan.lastUse[nodeIdx(x)])
+	require.Equal(t, -1, plan.lastUse[nodeIdx(converted)])
+	require.Equal(t, -1, plan.inPlace[nodeIdx(sum)]) // x is used twice.
+	require.Equal(t, 0, plan.inPlace[nodeIdx(exp)])
+	require.Equal(t, 1, plan.inPlace[nodeIdx(mul)]) // The intermediate result is preferred to the parameter.
+	require.Equal(t, 0, plan.inPlace[nodeIdx(converted)])
+
+	// The intermediate results share a single buffer.
+	require.Equal(t, 4*size, plan.peakBytes)
+	require.Equal(t, 4*4*size, plan.peakBytesNoReuse)
+
+	for _, config := range []string{"ops_sequential", "ops_parallel"} {
+		be, err := New(config)
+		require.NoError(t, err)
+		builder := be.Builder("TestBufferPlan")
+		x, err := builder.Parameter("x", shapes.Make(dtypes.Float32, size), nil)
+		require.NoError(t, err)
+		sum, _ := builder.Add(x, x)
+		exp, _ := builder.Exp(sum)
+		mul, _ := builder.Mul(x, exp)
+		converted, _ := builder.ConvertDType(mul, dtypes.Int32)
+		exec, err := builder.Compile([]backends.Op{converted}, nil)
+		require.NoError(t, err)
+		input := make([]float32, size)
+		for i := range input {
+			input[i] = float32(i) / size
+		}
+		inputBuf, err := be.BufferFromFlatData(0, input, shapes.Make(dtypes.Float32, size))
+		require.NoError(t, err)
+		outputs, err := exec.Execute([]backends.Buffer{inputBuf}, nil, 0)
+		require.NoError(t, err)
+		output := make([]int32, size)
+		require.NoError(t, be.BufferToFlatData(outputs[0], output))
+		for i, value := range input {
+			require.Equalf(t, int32(float32(math.Exp(float64(2*value)))*value), output[i], "%s: index %d", config, i)
+		}
+		be.Finalize()
+	}
+}
@@ -1192,10 +1192,19 @@ func execConcatenate(backend *Backend, node *Node, inputs []*Buffer, inputsOwned
 
 func execConvertDType(backend *Backend, node *Node, inputs []*Buffer, inputsOwned []bool) (*Buffer, error) {
 	operand := inputs[0]
-	_ = inputsOwned // We don't reuse the inputs.
+	convertFn := convertDTypePairMap.Get(operand.shape.DType, node.shape.DType).(convertFnType)
+	if inputsOwned[0] && operand.shape.DType.Size() == node.shape.DType.Size() {
+		// Convert in place: each element is converted to one of the same size, at the same position, so the
+		// operand buffer is reinterpreted as the output.
+		inputs[0] = nil
+		view := &Buffer{shape: operand.shape, flat: operand.flat, valid: true}
+		operand.flat = reinterpretFlat(operand.flat, node.shape.DType)
+		operand.shape = node.shape
+		convertFn(view, operand)
+		return operand, nil
+	}
 	output := backend.getBuffer(node.shape.DType, operand.shape.Size())
 	output.shape = node.shape
-	convertFn := convertDTypePairMap.Get(operand.shape.DType, output.shape.DType).(convertFnType)
 	convertFn(operand, output)
 	return output, nil
 }
@@ -364,7 +364,7 @@ func execExp(backend *Backend, node *Node, inputs []*Buffer, inputsOwned []bool)
 	input, output := unaryOperandAndOutput(backend, inputs, inputsOwned)
 	switch input.shape.DType {
 	case dtypes.Float32:
-		execExpGeneric[float32](input.flat.([]float32), output.flat.([]float32))
+		execExpFloat32(input.flat.([]float32), output.flat.([]float32))
 	case dtypes.Float64:
 		execExpGeneric[float64](input.flat.([]float64), output.flat.([]float64))
 	case dtypes.BFloat16:
@@ -381,6 +381,15 @@ func execExpGeneric[T float32 | float64](inputs, outputs []T) {
 	}
 }
 
+// execExpFloat32 uses the AVX2 kernel if available, and the Go version for the remaining values.
+func execExpFloat32(inputs, outputs []float32) {
+	n := 0
+	if hasAVX2 {
+		n = expFloat32AVX2(inputs, outputs)
+	}
+	execExpGeneric(inputs[n:], outputs[n:])
+}
+
 func execExpBF16(inputs, outputs []bfloat16.BFloat16) {
 	for ii, input := range inputs {
 		outputs[ii] = bfloat16.FromFloat32(float32(math.Exp(float64(input.Float32()))))
@@ -680,7 +689,7 @@ func execLogistic(backend *Backend, node *Node, inputs []*Buffer, inputsOwned []
 	input, output := unaryOperandAndOutput(backend, inputs, inputsOwned)
 	switch input.shape.DType {
 	case dtypes.Float32:
-		execLogisticGeneric[float32](input.flat.([]float32), output.flat.([]float32))
+		execLogisticFloat32(input.flat.([]float32), output.flat.([]float32))
 	case dtypes.Float64:
 		execLogisticGeneric[float64](input.flat.([]float64), output.flat.([]float64))
 	case dtypes.BFloat16:
@@ -702,6 +711,15 @@ func execLogisticGeneric[T float32 | float64](inputs, outputs []T) {
 	}
 }
 
+// execLogisticFloat32 uses the AVX2 kernel if available, and the Go version for the remaining values.
+func execLogisticFloat32(inputs, outputs []float32) {
+	n := 0
+	if hasAVX2 {
+		n = sigmoidFloat32AVX2(inputs, outputs)
+	}
+	execLogisticGeneric(inputs[n:], outputs[n:])
+}
+
 func execLogisticBF16(inputs, outputs []bfloat16.BFloat16) {
 	for ii, input := range inputs {
 		input64 := float64(input.Float32())
@@ -721,7 +739,7 @@ func execTanh(backend *Backend, node *Node, inputs []*Buffer, inputsOwned []bool
 	input, output := unaryOperandAndOutput(backend, inputs, inputsOwned)
 	switch input.shape.DType {
 	case dtypes.Float32:
-		execTanhGeneric[float32](input.flat.([]float32), output.flat.([]float32))
+		execTanhFloat32(input.flat.([]float32), output.flat.([]float32))
 	case dtypes.Float64:
 		execTanhGeneric[float64](input.flat.([]float64), output.flat.([]float64))
 	case dtypes.BFloat16:
@@ -738,6 +756,15 @@ func execTanhGeneric[T float32 | float64](inputs, outputs []T) {
 	}
 }
 
+// execTanhFloat32 uses the AVX2 kernel if available, and the Go version for the remaining values.
+func execTanhFloat32(inputs, outputs []float32) {
+	n := 0
+	if hasAVX2 {
+		n = tanhFloat32AVX2(inputs, outputs)
+	}
+	execTanhGeneric(inputs[n:], outputs[n:])
+}
+
 func execTanhBF16(inputs, outputs []bfloat16.BFloat16) {
 	for ii, input := range inputs {
 		outputs[ii] = bfloat16.FromFloat32(float32(math.Tanh(float64(input.Float32()))))
@@ -781,7 +808,7 @@ func execErf(backend *Backend, node *Node, inputs []*Buffer, inputsOwned []bool)
 	input, output := unaryOperandAndOutput(backend, inputs, inputsOwned)
 	switch input.shape.DType {
 	case dtypes.Float32:
-		execErfGeneric[float32](backend, input.flat.([]float32), output.flat.([]float32))
+		execErfFloat32(backend, input.flat.([]float32), output.flat.([]float32))
 	case dtypes.Float64:
 		execErfGeneric[float64](backend, input.flat.([]float64), output.flat.([]float64))
 	case dtypes.BFloat16:
@@ -792,15 +819,14 @@ func execErf(backend *Backend, node *Node, inputs []*Buffer, inputsOwned []bool)
 	return output, nil
 }
 
-const unaryMinParallelizeChunk = 4096
-
 func execErfGeneric[T float32 | float64](backend *Backend, inputs, outputs []T) {
 	lenInputs := len(inputs)
-	if backend.workers.IsEnabled() && lenInputs > unaryMinParallelizeChunk {
+	chunkSize := backend.unaryParallelMinSize
+	if backend.workers.IsEnabled() && lenInputs > chunkSize {
 		// Parallelize operation into chunks.
 		var wg sync.WaitGroup
-		for ii := 0; ii < lenInputs; ii += unaryMinParallelizeChunk {
-			iiEnd := min(ii+unaryMinParallelizeChunk, lenInputs)
+		for ii := 0; ii < lenInputs; ii += chunkSize {
+			iiEnd := min(ii+chunkSize, lenInputs)
 			wg.Add(1)
 			backend.workers.WaitToStart(func() {
 				for jj := ii; jj < iiEnd; jj++ {
@@ -819,6 +845,18 @@ func execErfGeneric[T float32 | float64](backend *Backend, inputs, outputs []T)
 	}
 }
 
+// execErfFloat32 uses the AVX2 kernel if available, and the Go version for the remaining values.
+func execErfFloat32(backend *Backend, inputs, outputs []float32) {
+	if !hasAVX2 {
+		execErfGeneric(backend, inputs, outputs)
+		return
+	}
+	n := erfFloat32AVX2(inputs, outputs)
+	for ii := n; ii < len(inputs); ii++ {
+		outputs[ii] = float32(math.Erf(float64(inputs[ii])))
+	}
+}
+
 func execErfBF16(inputs, outputs []bfloat16.BFloat16) {
 	for ii, input := range inputs {
 		outputs[ii] = bfloat16.FromFloat32(float32(math.Erf(float64(input.Float32()))))
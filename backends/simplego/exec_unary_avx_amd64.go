@@ -0,0 +1,67 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !noasm && amd64
+
+package simplego
+
+import (
+	"runtime"
+	"unsafe"
+)
+
+// The AVX2 float32 transcendental functions are implemented in exec_unary_avx_amd64.s. They process n values
+// (a multiple of 8) from x, and store the results in y, which can be the same as x.
+//
+// Their accuracy, measured against the float64 versions in the math package:
+//
+//   - exp: relative error < 1.5e-7 (~2 ULPs), with the correct +Inf, 0, denormal and NaN results.
+//   - sigmoid: relative error < 2e-7, but the denormal results (x < -87.3) are flushed to 0.
+//   - tanh: relative error < 2e-7.
+//   - erf: relative error < 3e-7 and absolute error < 2e-7.
+
+//go:noescape
+func expFloat32_avx2_asm(x, y unsafe.Pointer, n int64)
+
+//go:noescape
+func sigmoidFloat32_avx2_asm(x, y unsafe.Pointer, n int64)
+
+//go:noescape
+func tanhFloat32_avx2_asm(x, y unsafe.Pointer, n int64)
+
+//go:noescape
+func erfFloat32_avx2_asm(x, y unsafe.Pointer, n int64)
+
+// unaryFloat32AVX2 applies the AVX2 kernel to the first values of x, a multiple of 8, and stores the results in y.
+// It returns the number of values processed, the remaining ones must be processed by the caller.
+func unaryFloat32AVX2(kernel func(x, y unsafe.Pointer, n int64), x, y []float32) int {
+	n := min(len(x), len(y)) &^ 7
+	if n == 0 {
+		return 0
+	}
+	kernel(unsafe.Pointer(&x[0]), unsafe.Pointer(&y[0]), int64(n))
+	runtime.KeepAlive(x)
+	runtime.KeepAlive(y)
+	return n
+}
+
+// expFloat32AVX2 computes y = exp(x) for the first values of x, see unaryFloat32AVX2.
+func expFloat32AVX2(x, y []float32) int {
+	return unaryFloat32AVX2(expFloat32_avx2_asm, x, y)
+}
+
+// sigmoidFloat32AVX2 computes y = 1/(1+exp(-x)) for the first values of x, see unaryFloat32AVX2.
+func sigmoidFloat32AVX2(x, y []float32) int {
+	return unaryFloat32AVX2(sigmoidFloat32_avx2_asm, x, y)
+}
+
+// tanhFloat32AVX2 computes y = tanh(x) for the first values of x, see unaryFloat32AVX2.
+func tanhFloat32AVX2(x, y []float32) int {
+	return unaryFloat32AVX2(tanhFloat32_avx2_asm, x, y)
+}
+
+// erfFloat32AVX2 computes y = erf(x) for the first values of x, see unaryFloat32AVX2.
+func erfFloat32AVX2(x, y []float32) int {
+	return unaryFloat32AVX2(erfFloat32_avx2_asm, x, y)
+}
@@ -0,0 +1,272 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !noasm && amd64
+
+#include "textflag.h"
+
+// AVX2 (with FMA) float32 transcendental functions: exp, sigmoid (logistic), tanh and erf.
+//
+// They all process n values, where n must be a multiple of 8, reading from x and writing to y (which may be the
+// same as x). See exec_unary_avx_amd64.go for the accuracy of each function.
+
+// Constants, see the offsets used below.
+DATA unaryConsts<>+0(SB)/4, $0x3fb8aa3b   // log2(e)
+DATA unaryConsts<>+4(SB)/4, $0x3f318000   // ln(2), high part: 0.693359375
+DATA unaryConsts<>+8(SB)/4, $0xb95e8083   // ln(2), low part: -2.12194440e-4
+DATA unaryConsts<>+12(SB)/4, $0x3f800000  // 1.0
+DATA unaryConsts<>+16(SB)/4, $0x42b20000  // 89.0: exp(x) overflows to +Inf above it.
+DATA unaryConsts<>+20(SB)/4, $0xc2d00000  // -104.0: exp(x) underflows to 0 below it.
+DATA unaryConsts<>+24(SB)/4, $0x39506967  // exp polynomial: 1.9875691500e-4
+DATA unaryConsts<>+28(SB)/4, $0x3ab743ce  // exp polynomial: 1.3981999507e-3
+DATA unaryConsts<>+32(SB)/4, $0x3c088908  // exp polynomial: 8.3334519073e-3
+DATA unaryConsts<>+36(SB)/4, $0x3d2aa9c1  // exp polynomial: 4.1665795894e-2
+DATA unaryConsts<>+40(SB)/4, $0x3e2aaaaa  // exp polynomial: 1.6666665459e-1
+DATA unaryConsts<>+44(SB)/4, $0x3f000000  // exp polynomial: 5.0000001201e-1
+DATA unaryConsts<>+48(SB)/4, $0x7fffffff  // Mask to clear the sign bit.
+DATA unaryConsts<>+52(SB)/4, $0x40000000  // 2.0
+DATA unaryConsts<>+56(SB)/4, $0x3f200000  // 0.625: tanh uses its polynomial below it.
+DATA unaryConsts<>+60(SB)/4, $0xbbbaf0ea  // tanh polynomial: -5.70498872745e-3
+DATA unaryConsts<>+64(SB)/4, $0x3ca9134e  // tanh polynomial: 2.06390887954e-2
+DATA unaryConsts<>+68(SB)/4, $0xbd5c1e2d  // tanh polynomial: -5.37397155531e-2
+DATA unaryConsts<>+72(SB)/4, $0x3e088393  // tanh polynomial: 1.33314422036e-1
+DATA unaryConsts<>+76(SB)/4, $0xbeaaaa99  // tanh polynomial: -3.33332819422e-1
+DATA unaryConsts<>+80(SB)/4, $0x3ea7ba05  // erf, Abramowitz & Stegun 7.1.26: p = 0.3275911
+DATA unaryConsts<>+84(SB)/4, $0x3f87dc22  // erf, Abramowitz & Stegun 7.1.26: a5 = 1.061405429
+DATA unaryConsts<>+88(SB)/4, $0xbfba00e3  // erf, Abramowitz & Stegun 7.1.26: a4 = -1.453152027
+DATA unaryConsts<>+92(SB)/4, $0x3fb5f0e3  // erf, Abramowitz & Stegun 7.1.26: a3 = 1.421413741
+DATA unaryConsts<>+96(SB)/4, $0xbe91a98e  // erf, Abramowitz & Stegun 7.1.26: a2 = -0.284496736
+DATA unaryConsts<>+100(SB)/4, $0x3e827906 // erf, Abramowitz & Stegun 7.1.26: a1 = 0.254829592
+DATA unaryConsts<>+104(SB)/4, $0x38a4b519 // erf polynomial for |x| < 1: 7.853861353153693e-5
+DATA unaryConsts<>+108(SB)/4, $0xba51fb80 // erf polynomial for |x| < 1: -8.010193625184903e-4
+DATA unaryConsts<>+112(SB)/4, $0x3baa02d9 // erf polynomial for |x| < 1: 5.188327685732524e-3
+DATA unaryConsts<>+116(SB)/4, $0xbcdbfc87 // erf polynomial for |x| < 1: -2.685381193529856e-2
+DATA unaryConsts<>+120(SB)/4, $0x3de7167c // erf polynomial for |x| < 1: 1.128358514861418e-1
+DATA unaryConsts<>+124(SB)/4, $0xbec0939f // erf polynomial for |x| < 1: -3.761262582423300e-1
+DATA unaryConsts<>+128(SB)/4, $0x3f906eba // erf polynomial for |x| < 1: 1.128379165726710
+GLOBL unaryConsts<>(SB), RODATA|NOPTR, $132
+
+// LOAD_EXP_CONSTS loads the constants used by EXP_Y0: Y10 to Y15.
+#define LOAD_EXP_CONSTS \
+	VBROADCASTSS unaryConsts<>+0(SB), Y15  ; \
+	VBROADCASTSS unaryConsts<>+4(SB), Y14  ; \
+	VBROADCASTSS unaryConsts<>+8(SB), Y13  ; \
+	VBROADCASTSS unaryConsts<>+12(SB), Y12 ; \
+	VBROADCASTSS unaryConsts<>+16(SB), Y11 ; \
+	VBROADCASTSS unaryConsts<>+20(SB), Y10
+
+// EXP_Y0 computes Y3 = exp(Y0). It clobbers Y0, Y1, Y2 and Y4.
+//
+// It uses the Cephes expf algorithm: exp(x) = 2^n * exp(r), with n = round(x * log2(e)) and r = x - n*ln(2), where
+// exp(r) is approximated by a polynomial. The scaling by 2^n is done in two steps, 2^(n/2) * 2^(n-n/2), so that
+// the results close to overflow and the denormal results are correct.
+//
+// The clamping of x keeps NaNs: VMAXPS and VMINPS return their second source operand (x) if any operand is NaN.
+#define EXP_Y0 \
+	VMAXPS       Y0, Y10, Y0                   ; \
+	VMINPS       Y0, Y11, Y0                   ; \
+	VMULPS       Y15, Y0, Y1                   ; \
+	VROUNDPS     $0, Y1, Y1                    ; \
+	VFNMADD231PS Y14, Y1, Y0                   ; \
+	VFNMADD231PS Y13, Y1, Y0                   ; \
+	VMULPS       Y0, Y0, Y2                    ; \
+	VBROADCASTSS unaryConsts<>+24(SB), Y3      ; \
+	VBROADCASTSS unaryConsts<>+28(SB), Y4      ; \
+	VFMADD213PS  Y4, Y0, Y3                    ; \
+	VBROADCASTSS unaryConsts<>+32(SB), Y4      ; \
+	VFMADD213PS  Y4, Y0, Y3                    ; \
+	VBROADCASTSS unaryConsts<>+36(SB), Y4      ; \
+	VFMADD213PS  Y4, Y0, Y3                    ; \
+	VBROADCASTSS unaryConsts<>+40(SB), Y4      ; \
+	VFMADD213PS  Y4, Y0, Y3                    ; \
+	VBROADCASTSS unaryConsts<>+44(SB), Y4      ; \
+	VFMADD213PS  Y4, Y0, Y3                    ; \
+	VFMADD213PS  Y0, Y2, Y3                    ; \
+	VADDPS       Y12, Y3, Y3                   ; \
+	VCVTPS2DQ    Y1, Y1                        ; \
+	VPSRAD       $1, Y1, Y2                    ; \
+	VPSUBD       Y2, Y1, Y1                    ; \
+	VPSLLD       $23, Y2, Y2                   ; \
+	VPADDD       Y12, Y2, Y2                   ; \
+	VPSLLD       $23, Y1, Y1                   ; \
+	VPADDD       Y12, Y1, Y1                   ; \
+	VMULPS       Y2, Y3, Y3                    ; \
+	VMULPS       Y1, Y3, Y3
+
+// func expFloat32_avx2_asm(x, y unsafe.Pointer, n int64)
+TEXT ·expFloat32_avx2_asm(SB), NOSPLIT, $0-24
+	MOVQ x+0(FP), SI
+	MOVQ y+8(FP), DI
+	MOVQ n+16(FP), CX
+	SHRQ $3, CX
+	JZ   exp_done
+	LOAD_EXP_CONSTS
+
+exp_loop:
+	VMOVUPS (SI), Y0
+	EXP_Y0
+	VMOVUPS Y3, (DI)
+	ADDQ    $32, SI
+	ADDQ    $32, DI
+	DECQ    CX
+	JNZ     exp_loop
+
+exp_done:
+	VZEROUPPER
+	RET
+
+// func sigmoidFloat32_avx2_asm(x, y unsafe.Pointer, n int64)
+//
+// sigmoid(x) = 1 / (1 + exp(-x)).
+TEXT ·sigmoidFloat32_avx2_asm(SB), NOSPLIT, $0-24
+	MOVQ x+0(FP), SI
+	MOVQ y+8(FP), DI
+	MOVQ n+16(FP), CX
+	SHRQ $3, CX
+	JZ   sigmoid_done
+	LOAD_EXP_CONSTS
+	VXORPS Y9, Y9, Y9 // 0.0
+
+sigmoid_loop:
+	VMOVUPS (SI), Y0
+	VSUBPS  Y0, Y9, Y0
+	EXP_Y0
+	VADDPS  Y12, Y3, Y3
+	VDIVPS  Y3, Y12, Y3
+	VMOVUPS Y3, (DI)
+	ADDQ    $32, SI
+	ADDQ    $32, DI
+	DECQ    CX
+	JNZ     sigmoid_loop
+
+sigmoid_done:
+	VZEROUPPER
+	RET
+
+// func tanhFloat32_avx2_asm(x, y unsafe.Pointer, n int64)
+//
+// For |x| < 0.625, tanh(x) is approximated by the Cephes tanhf polynomial, x + x^3 * P(x^2).
+// Otherwise tanh(|x|) = 1 - 2 / (exp(2|x|) + 1), and the sign of x is copied to the result.
+TEXT ·tanhFloat32_avx2_asm(SB), NOSPLIT, $0-24
+	MOVQ x+0(FP), SI
+	MOVQ y+8(FP), DI
+	MOVQ n+16(FP), CX
+	SHRQ $3, CX
+	JZ   tanh_done
+	LOAD_EXP_CONSTS
+	VBROADCASTSS unaryConsts<>+48(SB), Y9 // Abs mask.
+	VBROADCASTSS unaryConsts<>+52(SB), Y8 // 2.0
+
+tanh_loop:
+	VMOVUPS (SI), Y5
+	VANDPS  Y9, Y5, Y6 // Y6 = |x|
+	VANDNPS Y5, Y9, Y7 // Y7 = sign of x
+
+	// Large |x|: Y3 = 1 - 2 / (exp(2|x|) + 1)
+	VADDPS Y6, Y6, Y0
+	EXP_Y0
+	VADDPS Y12, Y3, Y3
+	VDIVPS Y3, Y8, Y3
+	VSUBPS Y3, Y12, Y3
+
+	// Small |x|: Y1 = |x| + |x|^3 * P(x^2)
+	VMULPS       Y6, Y6, Y2
+	VBROADCASTSS unaryConsts<>+60(SB), Y1
+	VBROADCASTSS unaryConsts<>+64(SB), Y4
+	VFMADD213PS  Y4, Y2, Y1
+	VBROADCASTSS unaryConsts<>+68(SB), Y4
+	VFMADD213PS  Y4, Y2, Y1
+	VBROADCASTSS unaryConsts<>+72(SB), Y4
+	VFMADD213PS  Y4, Y2, Y1
+	VBROADCASTSS unaryConsts<>+76(SB), Y4
+	VFMADD213PS  Y4, Y2, Y1
+	VMULPS       Y2, Y1, Y1
+	VFMADD213PS  Y6, Y6, Y1
+
+	// Select the small |x| approximation where |x| < 0.625, and restore the sign.
+	VBROADCASTSS unaryConsts<>+56(SB), Y4
+	VCMPPS       $1, Y4, Y6, Y0 // Y0 = |x| < 0.625
+	VBLENDVPS    Y0, Y1, Y3, Y3
+	VORPS        Y7, Y3, Y3
+	VMOVUPS      Y3, (DI)
+	ADDQ         $32, SI
+	ADDQ         $32, DI
+	DECQ         CX
+	JNZ          tanh_loop
+
+tanh_done:
+	VZEROUPPER
+	RET
+
+// func erfFloat32_avx2_asm(x, y unsafe.Pointer, n int64)
+//
+// For |x| < 1, erf(x) is approximated by the Cephes erff polynomial, x * P(x^2).
+// Otherwise erf(|x|) = 1 - (a1*t + a2*t^2 + a3*t^3 + a4*t^4 + a5*t^5) * exp(-x^2), with t = 1 / (1 + p*|x|)
+// (Abramowitz & Stegun 7.1.26), and the sign of x is copied to the result.
+TEXT ·erfFloat32_avx2_asm(SB), NOSPLIT, $0-24
+	MOVQ x+0(FP), SI
+	MOVQ y+8(FP), DI
+	MOVQ n+16(FP), CX
+	SHRQ $3, CX
+	JZ   erf_done
+	LOAD_EXP_CONSTS
+	VBROADCASTSS unaryConsts<>+48(SB), Y9 // Abs mask.
+
+erf_loop:
+	VMOVUPS (SI), Y5
+	VANDPS  Y9, Y5, Y6 // Y6 = |x|
+	VANDNPS Y5, Y9, Y7 // Y7 = sign of x
+
+	// Large |x|: Y3 = 1 - t * A(t) * exp(-x^2), with Y8 = t = 1 / (1 + p*|x|).
+	VBROADCASTSS unaryConsts<>+80(SB), Y4
+	VMULPS       Y4, Y6, Y1
+	VADDPS       Y12, Y1, Y1
+	VDIVPS       Y1, Y12, Y8
+	VMULPS       Y6, Y6, Y0
+	VXORPS       Y1, Y1, Y1
+	VSUBPS       Y0, Y1, Y0
+	EXP_Y0
+	VBROADCASTSS unaryConsts<>+84(SB), Y1
+	VBROADCASTSS unaryConsts<>+88(SB), Y4
+	VFMADD213PS  Y4, Y8, Y1
+	VBROADCASTSS unaryConsts<>+92(SB), Y4
+	VFMADD213PS  Y4, Y8, Y1
+	VBROADCASTSS unaryConsts<>+96(SB), Y4
+	VFMADD213PS  Y4, Y8, Y1
+	VBROADCASTSS unaryConsts<>+100(SB), Y4
+	VFMADD213PS  Y4, Y8, Y1
+	VMULPS       Y8, Y1, Y1
+	VMULPS       Y3, Y1, Y1
+	VSUBPS       Y1, Y12, Y3
+
+	// Small |x|: Y1 = |x| * P(x^2)
+	VMULPS       Y6, Y6, Y2
+	VBROADCASTSS unaryConsts<>+104(SB), Y1
+	VBROADCASTSS unaryConsts<>+108(SB), Y4
+	VFMADD213PS  Y4, Y2, Y1
+	VBROADCASTSS unaryConsts<>+112(SB), Y4
+	VFMADD213PS  Y4, Y2, Y1
+	VBROADCASTSS unaryConsts<>+116(SB), Y4
+	VFMADD213PS  Y4, Y2, Y1
+	VBROADCASTSS unaryConsts<>+120(SB), Y4
+	VFMADD213PS  Y4, Y2, Y1
+	VBROADCASTSS unaryConsts<>+124(SB), Y4
+	VFMADD213PS  Y4, Y2, Y1
+	VBROADCASTSS unaryConsts<>+128(SB), Y4
+	VFMADD213PS  Y4, Y2, Y1
+	VMULPS       Y6, Y1, Y1
+
+	// Select the small |x| approximation where |x| < 1, and restore the sign.
+	VCMPPS    $1, Y12, Y6, Y0 // Y0 = |x| < 1
+	VBLENDVPS Y0, Y1, Y3, Y3
+	VORPS     Y7, Y3, Y3
+	VMOVUPS   Y3, (DI)
+	ADDQ      $32, SI
+	ADDQ      $32, DI
+	DECQ      CX
+	JNZ       erf_loop
+
+erf_done:
+	VZEROUPPER
+	RET
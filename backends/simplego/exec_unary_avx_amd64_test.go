@@ -0,0 +1,93 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !noasm && amd64
+
+package simplego
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnaryFloat32AVX2(t *testing.T) {
+	if !hasAVX2 {
+		t.Skip("AVX2 not supported")
+	}
+	var inputs []float32
+	for x := float32(-110); x < 110; x += 0.00137 {
+		inputs = append(inputs, x)
+	}
+	for x := float32(-2); x < 2; x += 0.0000123 {
+		inputs = append(inputs, x)
+	}
+	inputs = append(inputs, 0, float32(math.Copysign(0, -1)), 1e-30, -1e-30, 88.72, 88.73, -103.5, -104.5,
+		float32(math.Inf(1)), float32(math.Inf(-1)), float32(math.NaN()))
+	for len(inputs)%8 != 0 {
+		inputs = append(inputs, 0.5)
+	}
+
+	testCases := []struct {
+		name           string
+		simd           func(x, y []float32) int
+		want           func(float64) float64
+		maxRel, maxAbs float64
+	}{
+		{"exp", expFloat32AVX2, math.Exp, 1.5e-7, math.Inf(1)},
+		{"sigmoid", sigmoidFloat32AVX2, func(x float64) float64 { return 1 / (1 + math.Exp(-x)) }, 2e-7, math.Inf(1)},
+		{"tanh", tanhFloat32AVX2, math.Tanh, 2e-7, math.Inf(1)},
+		{"erf", erfFloat32AVX2, math.Erf, 3e-7, 2e-7},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			outputs := make([]float32, len(inputs))
+			require.Equal(t, len(inputs), tc.simd(inputs, outputs))
+			for ii, x := range inputs {
+				want, got := tc.want(float64(x)), float64(outputs[ii])
+				var ok bool
+				switch {
+				case math.IsNaN(want):
+					ok = math.IsNaN(got)
+				case math.Abs(want) < 0x1p-126 || math.IsInf(float64(float32(want)), 0):
+					// Denormal, zero or infinite results: denormals may be flushed to zero.
+					ok = float32(want) == float32(got) || math.Abs(want-got) < 0x1p-126
+				default:
+					ok = math.Signbit(want) == math.Signbit(got) &&
+						math.Abs(got-want)/math.Abs(want) <= tc.maxRel && math.Abs(got-want) <= tc.maxAbs
+				}
+				if !ok {
+					t.Fatalf("%s(%g)=%g, wanted %g", tc.name, x, got, want)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkUnaryFloat32(b *testing.B) {
+	inputs := make([]float32, 4096)
+	for ii := range inputs {
+		inputs[ii] = float32(ii)/512 - 4
+	}
+	outputs := make([]float32, len(inputs))
+	for _, simd := range []string{SIMDAuto, SIMDOff} {
+		if err := SetSIMD(simd); err != nil {
+			b.Fatal(err)
+		}
+		for name, fn := range map[string]func(inputs, outputs []float32){
+			"exp":     execExpFloat32,
+			"sigmoid": execLogisticFloat32,
+			"tanh":    execTanhFloat32,
+		} {
+			b.Run(fmt.Sprintf("%s/%s", name, simd), func(b *testing.B) {
+				for range b.N {
+					fn(inputs, outputs)
+				}
+			})
+		}
+	}
+	_ = SetSIMD(SIMDAuto)
+}
@@ -0,0 +1,27 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build noasm || !amd64
+
+package simplego
+
+// expFloat32AVX2 stub for non-AMD64 platforms.
+func expFloat32AVX2(x, y []float32) int {
+	panic("AVX2 not available")
+}
+
+// sigmoidFloat32AVX2 stub for non-AMD64 platforms.
+func sigmoidFloat32AVX2(x, y []float32) int {
+	panic("AVX2 not available")
+}
+
+// tanhFloat32AVX2 stub for non-AMD64 platforms.
+func tanhFloat32AVX2(x, y []float32) int {
+	panic("AVX2 not available")
+}
+
+// erfFloat32AVX2 stub for non-AMD64 platforms.
+func erfFloat32AVX2(x, y []float32) int {
+	panic("AVX2 not available")
+}
@@ -0,0 +1,231 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"math"
+	"sync"
+
+	"github.com/gomlx/gopjrt/dtypes"
+	"github.com/pkg/errors"
+
+	"github.com/gomlx/gomlx/backends"
+	"github.com/gomlx/gomlx/pkg/core/shapes"
+)
+
+// FP8Format is an 8-bit floating point format, used to store weights and caches (e.g. the KV cache of a decoder)
+// with a quarter of the memory of float32.
+//
+// The dtypes package doesn't have Go types for the FP8 dtypes, so the backend stores the FP8 values as Uint8
+// buffers (or []uint8) holding their encoding: see Backend.BufferToFP8, Backend.BufferFromFP8 and
+// Backend.MatMulDequantizeFP8.
+type FP8Format int
+
+const (
+	// FP8E4M3 is the E4M3FN format (dtypes.F8E4M3FN): 1 sign bit, 4 exponent bits (bias 7) and 3 mantissa bits.
+	// It has no infinities, and its largest value is 448. It is usually used for weights and activations.
+	FP8E4M3 FP8Format = iota
+
+	// FP8E5M2 is the E5M2 format (dtypes.F8E5M2): 1 sign bit, 5 exponent bits (bias 15) and 2 mantissa bits, like
+	// a float16 with a truncated mantissa. Its largest finite value is 57344. It is usually used for gradients.
+	FP8E5M2
+)
+
+// String implements fmt.Stringer.
+func (f FP8Format) String() string {
+	switch f {
+	case FP8E4M3:
+		return "E4M3"
+	case FP8E5M2:
+		return "E5M2"
+	default:
+		return "unknown"
+	}
+}
+
+// DType returns the corresponding dtype, used by backends that support FP8 natively.
+func (f FP8Format) DType() dtypes.DType {
+	if f == FP8E5M2 {
+		return dtypes.F8E5M2
+	}
+	return dtypes.F8E4M3FN
+}
+
+// MaxValue returns the largest finite value of the format.
+func (f FP8Format) MaxValue() float32 {
+	if f == FP8E5M2 {
+		return 57344
+	}
+	return 448
+}
+
+// layout returns the number of mantissa bits, the exponent bias and the largest finite encoding (without the
+// sign) of the format.
+func (f FP8Format) layout() (mantissaBits, bias int, maxCode uint8) {
+	if f == FP8E5M2 {
+		return 2, 15, 0x7B
+	}
+	return 3, 7, 0x7E
+}
+
+// validate returns an error if the format is not known.
+func (f FP8Format) validate() error {
+	if f != FP8E4M3 && f != FP8E5M2 {
+		return errors.Errorf("invalid FP8 format %d", f)
+	}
+	return nil
+}
+
+// encodeFP8 returns the FP8 encoding of the value, rounded to the nearest (ties to even).
+//
+// Finite values beyond the range of the format saturate to its largest value (the "satfinite" conversion of the
+// OCP FP8 specification), as usually done when quantizing. Infinities are kept in FP8E5M2, and saturate in
+// FP8E4M3, and NaNs are kept.
+func encodeFP8(format FP8Format, value float32) uint8 {
+	mantissaBits, bias, maxCode := format.layout()
+	bits := math.Float32bits(value)
+	sign := uint8(bits>>24) & 0x80
+	abs := math.Float32frombits(bits &^ (1 << 31))
+	switch {
+	case abs != abs: // NaN
+		return sign | 0x7F
+	case math.IsInf(float64(abs), 1):
+		if format == FP8E5M2 {
+			return sign | 0x7C
+		}
+		return sign | maxCode
+	case abs == 0:
+		return sign
+	}
+
+	// Quantum (distance between consecutive values) around the value: subnormals share the quantum of the
+	// smallest normal exponent.
+	exponent := max(int(bits>>23&0xFF)-127, 1-bias)
+	q := int(math.RoundToEven(math.Ldexp(float64(abs), mantissaBits-exponent)))
+	if q == 0 {
+		return sign
+	}
+	var code int
+	if exponent == 1-bias && q < 1<<mantissaBits {
+		// Subnormal.
+		code = q
+	} else {
+		if q == 1<<(mantissaBits+1) {
+			// Rounded up to the next exponent.
+			exponent++
+			q = 1 << mantissaBits
+		}
+		code = (exponent+bias)<<mantissaBits | (q - 1<<mantissaBits)
+	}
+	if code > int(maxCode) {
+		code = int(maxCode)
+	}
+	return sign | uint8(code)
+}
+
+// fp8DecodeTables holds the float32 value of each of the 256 encodings of the formats.
+var fp8DecodeTables = sync.OnceValue(func() *[2][256]float32 {
+	tables := &[2][256]float32{}
+	for _, format := range []FP8Format{FP8E4M3, FP8E5M2} {
+		mantissaBits, bias, _ := format.layout()
+		for code := range 256 {
+			exponentCode := code & 0x7F >> mantissaBits
+			mantissa := code & (1<<mantissaBits - 1)
+			var value float64
+			switch {
+			case format == FP8E4M3 && code&0x7F == 0x7F, format == FP8E5M2 && exponentCode == 0x1F && mantissa != 0:
+				value = math.NaN()
+			case format == FP8E5M2 && exponentCode == 0x1F:
+				value = math.Inf(1)
+			case exponentCode == 0:
+				value = math.Ldexp(float64(mantissa), 1-bias-mantissaBits)
+			default:
+				value = math.Ldexp(float64(mantissa|1<<mantissaBits), exponentCode-bias-mantissaBits)
+			}
+			if code&0x80 != 0 {
+				value = -value
+			}
+			tables[format][code] = float32(value)
+		}
+	}
+	return tables
+})
+
+// fp8DecodeTable returns the float32 value of each of the 256 encodings of the format.
+func fp8DecodeTable(format FP8Format) *[256]float32 {
+	return &fp8DecodeTables()[format]
+}
+
+// EncodeFP8 converts the float32 values to their FP8 encoding in dst, which must have the same length, rounding to
+// the nearest value (ties to even) and saturating values beyond the range of the format, see FP8Format.
+func EncodeFP8(format FP8Format, values []float32, dst []uint8) error {
+	if err := format.validate(); err != nil {
+		return err
+	}
+	if len(dst) != len(values) {
+		return errors.Errorf("EncodeFP8: expected %d values in dst, got %d", len(values), len(dst))
+	}
+	for i, value := range values {
+		dst[i] = encodeFP8(format, value)
+	}
+	return nil
+}
+
+// DecodeFP8 converts the FP8 encoded values to float32 in dst, which must have the same length.
+func DecodeFP8(format FP8Format, values []uint8, dst []float32) error {
+	if err := format.validate(); err != nil {
+		return err
+	}
+	if len(dst) != len(values) {
+		return errors.Errorf("DecodeFP8: expected %d values in dst, got %d", len(values), len(dst))
+	}
+	table := fp8DecodeTable(format)
+	for i, code := range values {
+		dst[i] = table[code]
+	}
+	return nil
+}
+
+// BufferToFP8 converts a Float32 buffer to a new Uint8 buffer of the same dimensions, holding the FP8 encoding of
+// the values (see EncodeFP8). The source buffer is not modified.
+func (b *Backend) BufferToFP8(buffer backends.Buffer, format FP8Format) (backends.Buffer, error) {
+	buf, ok := buffer.(*Buffer)
+	if !ok {
+		return nil, errors.Errorf("buffer is not a %q backend buffer", BackendName)
+	}
+	if buf.shape.DType != dtypes.Float32 {
+		return nil, errors.Errorf("BufferToFP8: expected a Float32 buffer, got %s", buf.shape)
+	}
+	if b.isFinalized {
+		return nil, errors.Errorf("backend is already finalized")
+	}
+	output := b.NewBuffer(shapes.Make(dtypes.Uint8, buf.shape.Dimensions...))
+	if err := EncodeFP8(format, buf.flat.([]float32), output.flat.([]uint8)); err != nil {
+		b.putBuffer(output)
+		return nil, err
+	}
+	return output, nil
+}
+
+// BufferFromFP8 converts a Uint8 buffer holding FP8 encoded values (see BufferToFP8) to a new Float32 buffer of the
+// same dimensions. The source buffer is not modified.
+func (b *Backend) BufferFromFP8(buffer backends.Buffer, format FP8Format) (backends.Buffer, error) {
+	buf, ok := buffer.(*Buffer)
+	if !ok {
+		return nil, errors.Errorf("buffer is not a %q backend buffer", BackendName)
+	}
+	if buf.shape.DType != dtypes.Uint8 {
+		return nil, errors.Errorf("BufferFromFP8: expected a Uint8 buffer with the FP8 encoding, got %s", buf.shape)
+	}
+	if b.isFinalized {
+		return nil, errors.Errorf("backend is already finalized")
+	}
+	output := b.NewBuffer(shapes.Make(dtypes.Float32, buf.shape.Dimensions...))
+	if err := DecodeFP8(format, buf.flat.([]uint8), output.flat.([]float32)); err != nil {
+		b.putBuffer(output)
+		return nil, err
+	}
+	return output, nil
+}
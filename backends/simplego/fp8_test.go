@@ -0,0 +1,91 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gomlx/gopjrt/dtypes"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gomlx/gomlx/pkg/core/shapes"
+)
+
+func TestEncodeFP8(t *testing.T) {
+	inf := float32(math.Inf(1))
+	for _, tc := range []struct {
+		format FP8Format
+		value  float32
+		want   uint8
+	}{
+		{FP8E4M3, 1, 0x38},
+		{FP8E4M3, -2, 0xC0},
+		{FP8E4M3, 448, 0x7E},
+		{FP8E4M3, 1000, 0x7E}, // Saturates.
+		{FP8E4M3, -inf, 0xFE},
+		{FP8E4M3, 1.0625, 0x38},          // Tie: rounds to the even mantissa.
+		{FP8E4M3, 1.1875, 0x3A},          // Tie: rounds to the even mantissa.
+		{FP8E4M3, 1.0 / 512, 0x01},       // Smallest subnormal.
+		{FP8E4M3, 1.0 / 2048, 0x00},      // Rounds to zero.
+		{FP8E4M3, 0.015625 - 1e-6, 0x08}, // Rounds up to the smallest normal.
+		{FP8E5M2, 1, 0x3C},
+		{FP8E5M2, 57344, 0x7B},
+		{FP8E5M2, 1e6, 0x7B}, // Saturates.
+		{FP8E5M2, inf, 0x7C},
+		{FP8E5M2, -0.5, 0xB8},
+	} {
+		require.Equalf(t, tc.want, encodeFP8(tc.format, tc.value), "%s(%g)", tc.format, tc.value)
+	}
+	require.Equal(t, uint8(0x7F), encodeFP8(FP8E4M3, float32(math.NaN()))&0x7F)
+	require.True(t, math.IsNaN(float64(fp8DecodeTable(FP8E5M2)[encodeFP8(FP8E5M2, float32(math.NaN()))])))
+
+	// All the encodings round trip.
+	for _, format := range []FP8Format{FP8E4M3, FP8E5M2} {
+		_, _, maxCode := format.layout()
+		require.Equal(t, format.MaxValue(), fp8DecodeTable(format)[maxCode])
+		for code := range 256 {
+			value := fp8DecodeTable(format)[code]
+			if value != value {
+				continue
+			}
+			require.Equalf(t, uint8(code), encodeFP8(format, value), "%s: code 0x%02x (%g)", format, code, value)
+		}
+	}
+	require.Error(t, EncodeFP8(FP8Format(3), []float32{1}, []uint8{0}))
+	require.Error(t, DecodeFP8(FP8E4M3, []uint8{1}, nil))
+}
+
+func TestBufferFP8(t *testing.T) {
+	be, ok := backend.(*Backend)
+	if !ok {
+		t.Skip("Skipping test because backend is not a SimpleGo Backend")
+	}
+	values := []float32{0, 1, -1.5, 3.25, 100, -448}
+	buffer, err := be.BufferFromFlatData(0, values, shapes.Make(dtypes.Float32, 2, 3))
+	require.NoError(t, err)
+	for _, format := range []FP8Format{FP8E4M3, FP8E5M2} {
+		encoded, err := be.BufferToFP8(buffer, format)
+		require.NoError(t, err)
+		shape, err := be.BufferShape(encoded)
+		require.NoError(t, err)
+		require.True(t, shape.Equal(shapes.Make(dtypes.Uint8, 2, 3)))
+		decoded, err := be.BufferFromFP8(encoded, format)
+		require.NoError(t, err)
+		got := make([]float32, len(values))
+		require.NoError(t, be.BufferToFlatData(decoded, got))
+		for i, value := range values {
+			// The relative error is at most half a unit in the last place of the mantissa.
+			require.InDeltaf(t, value, got[i], math.Abs(float64(value))/8, "%s: index %d", format, i)
+		}
+		_, err = be.BufferToFP8(encoded, format)
+		require.Error(t, err)
+		_, err = be.BufferFromFP8(decoded, format)
+		require.Error(t, err)
+		require.NoError(t, be.BufferFinalize(encoded))
+		require.NoError(t, be.BufferFinalize(decoded))
+	}
+	require.NoError(t, be.BufferFinalize(buffer))
+}
@@ -0,0 +1,211 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"sync"
+)
+
+// This file implements a cache-blocked GEMM (GEneral Matrix Multiplication) for float32, in the style of
+// Goto's algorithm (see "Anatomy of High-Performance Matrix Multiplication", Goto & van de Geijn, 2008):
+//
+//   - The output C[M, N] is split in column blocks of NC columns, the contracting axis in blocks of KC
+//     and the rows in blocks of MC rows (see GEMMBlockSizes).
+//   - For each (K, N) block, a panel of B[kc, nc] is packed into contiguous slivers of gemmNR columns.
+//   - For each (M, K) block, a panel of A[mc, kc] is packed into contiguous slivers of gemmMR rows.
+//   - A register-level micro-kernel multiplies a sliver of A by a sliver of B, producing a
+//     gemmMR x gemmNR tile of C.
+//
+// The block sizes are chosen so that a B sliver fits in L1, the A panel in L2 and the B panel in L3.
+
+const (
+	// gemmMR is the number of rows of the micro-kernel tile.
+	gemmMR = 4
+
+	// gemmNR is the number of columns of the micro-kernel tile.
+	gemmNR = 16
+)
+
+// defaultGEMMBlockSizes are the block sizes used by a new Backend, see Backend.SetGEMMBlockSizes.
+var defaultGEMMBlockSizes = GEMMBlockSizes{MC: 128, KC: 256, NC: 2048}
+
+// gemmMicroKernelFloat32 computes the tile[gemmMR][gemmNR] = A·B, where "a" is a packed sliver of A with kc
+// columns of gemmMR values, and "b" is a packed sliver of B with kc rows of gemmNR values.
+//
+// It uses the fastest version enabled: AVX-512 → AVX2 → SVE → pure Go (gemmMicroKernelGo).
+func gemmMicroKernelFloat32(kc int, a, b []float32, tile *[gemmMR * gemmNR]float32) {
+	switch {
+	case hasAVX512:
+		gemmMicroKernelAVX512(kc, a, b, tile)
+	case hasAVX2:
+		gemmMicroKernelAVX2(kc, a, b, tile)
+	case hasSVE:
+		gemmMicroKernelSVE(kc, a, b, tile)
+	default:
+		gemmMicroKernelGo(kc, a, b, tile)
+	}
+}
+
+// gemmPackedPool holds buffers used to pack panels of A and B.
+var gemmPackedPool = sync.Pool{
+	New: func() any {
+		buf := make([]float32, 0)
+		return &buf
+	},
+}
+
+// getGemmPackedBuffer returns a buffer with at least size elements, from the gemmPackedPool.
+func getGemmPackedBuffer(size int) *[]float32 {
+	buf := gemmPackedPool.Get().(*[]float32)
+	if cap(*buf) < size {
+		*buf = makeAligned[float32](size, size)
+	}
+	*buf = (*buf)[:size]
+	return buf
+}
+
+// gemmFloat32 computes C += A·B, where A is a [m, k] matrix, B is a [k, n] matrix and C is a [m, n] matrix, all
+// row-major. Each matrix is given by its flat slice, the index of its first element and its leading dimension
+// (the stride between rows). The blocks are given by sizes.
+func gemmFloat32(sizes GEMMBlockSizes, m, n, k int,
+	a []float32, aIdx, lda int,
+	b []float32, bIdx, ldb int,
+	c []float32, cIdx, ldc int) {
+	if m == 0 || n == 0 || k == 0 {
+		return
+	}
+	mc, kc, nc := min(sizes.MC, m), min(sizes.KC, k), min(sizes.NC, n)
+	aPackedBuf := getGemmPackedBuffer(roundUp(mc, gemmMR) * kc)
+	bPackedBuf := getGemmPackedBuffer(roundUp(nc, gemmNR) * kc)
+	defer gemmPackedPool.Put(aPackedBuf)
+	defer gemmPackedPool.Put(bPackedBuf)
+	aPacked, bPacked := *aPackedBuf, *bPackedBuf
+
+	for jc := 0; jc < n; jc += sizes.NC {
+		ncBlock := min(sizes.NC, n-jc)
+		for pc := 0; pc < k; pc += sizes.KC {
+			kcBlock := min(sizes.KC, k-pc)
+			gemmPackB(kcBlock, ncBlock, b, bIdx+pc*ldb+jc, ldb, bPacked)
+			for ic := 0; ic < m; ic += sizes.MC {
+				mcBlock := min(sizes.MC, m-ic)
+				gemmPackA(mcBlock, kcBlock, a, aIdx+ic*lda+pc, lda, aPacked)
+				gemmMacroKernel(mcBlock, ncBlock, kcBlock, aPacked, bPacked, c, cIdx+ic*ldc+jc, ldc)
+			}
+		}
+	}
+}
+
+// roundUp rounds x up to a multiple of multiple.
+func roundUp(x, multiple int) int {
+	return (x + multiple - 1) / multiple * multiple
+}
+
+// gemmPackA packs the block A[mc, kc] (starting at aIdx, with leading dimension lda) into slivers of gemmMR rows:
+// for each sliver, the gemmMR values of each column are contiguous. The last sliver is padded with zeros.
+func gemmPackA(mc, kc int, a []float32, aIdx, lda int, packed []float32) {
+	packedIdx := 0
+	for ir := 0; ir < mc; ir += gemmMR {
+		mr := min(gemmMR, mc-ir)
+		if mr == gemmMR {
+			row0 := a[aIdx+ir*lda : aIdx+ir*lda+kc]
+			row1 := a[aIdx+(ir+1)*lda : aIdx+(ir+1)*lda+kc]
+			row2 := a[aIdx+(ir+2)*lda : aIdx+(ir+2)*lda+kc]
+			row3 := a[aIdx+(ir+3)*lda : aIdx+(ir+3)*lda+kc]
+			dst := packed[packedIdx : packedIdx+kc*gemmMR]
+			for p := range kc {
+				dst[p*gemmMR] = row0[p]
+				dst[p*gemmMR+1] = row1[p]
+				dst[p*gemmMR+2] = row2[p]
+				dst[p*gemmMR+3] = row3[p]
+			}
+		} else {
+			for p := range kc {
+				for i := range gemmMR {
+					if i < mr {
+						packed[packedIdx+p*gemmMR+i] = a[aIdx+(ir+i)*lda+p]
+					} else {
+						packed[packedIdx+p*gemmMR+i] = 0
+					}
+				}
+			}
+		}
+		packedIdx += kc * gemmMR
+	}
+}
+
+// gemmPackB packs the block B[kc, nc] (starting at bIdx, with leading dimension ldb) into slivers of gemmNR
+// columns: for each sliver, the gemmNR values of each row are contiguous. The last sliver is padded with zeros.
+func gemmPackB(kc, nc int, b []float32, bIdx, ldb int, packed []float32) {
+	packedIdx := 0
+	for jr := 0; jr < nc; jr += gemmNR {
+		nr := min(gemmNR, nc-jr)
+		for p := range kc {
+			dst := packed[packedIdx+p*gemmNR : packedIdx+(p+1)*gemmNR]
+			src := b[bIdx+p*ldb+jr : bIdx+p*ldb+jr+nr]
+			copy(dst, src)
+			clear(dst[nr:])
+		}
+		packedIdx += kc * gemmNR
+	}
+}
+
+// gemmMacroKernel multiplies the packed panels of A[mc, kc] and B[kc, nc], and adds the result to C[mc, nc]
+// (starting at cIdx, with leading dimension ldc).
+func gemmMacroKernel(mc, nc, kc int, aPacked, bPacked []float32, c []float32, cIdx, ldc int) {
+	var tile [gemmMR * gemmNR]float32
+	for jr := 0; jr < nc; jr += gemmNR {
+		nr := min(gemmNR, nc-jr)
+		bSliver := bPacked[jr*kc : (jr+gemmNR)*kc]
+		for ir := 0; ir < mc; ir += gemmMR {
+			mr := min(gemmMR, mc-ir)
+			aSliver := aPacked[ir*kc : (ir+gemmMR)*kc]
+			gemmMicroKernelFloat32(kc, aSliver, bSliver, &tile)
+			for i := range mr {
+				cRow := c[cIdx+(ir+i)*ldc+jr : cIdx+(ir+i)*ldc+jr+nr]
+				tileRow := tile[i*gemmNR : i*gemmNR+nr]
+				for j, v := range tileRow {
+					cRow[j] += v
+				}
+			}
+		}
+	}
+}
+
+// gemmMicroKernelGo is the pure Go version of gemmMicroKernelFloat32, used when assembly is not available
+// (e.g. with the noasm build tag).
+//
+// It computes the tile as 8 sub-tiles of 2 x 4, each accumulated in 8 local variables. With the 4 values of B and
+// the value of A loaded for each step, they fit in the 15 XMM registers available to Go on amd64: larger sub-tiles
+// spill the accumulators to the stack. With GOAMD64=v3 (or on arm64), the compiler fuses the multiply-adds.
+// The slivers are accessed through array pointers, so there is only one bounds check per step.
+func gemmMicroKernelGo(kc int, a, b []float32, tile *[gemmMR * gemmNR]float32) {
+	a = a[:kc*gemmMR]
+	b = b[:kc*gemmNR]
+	for i0 := 0; i0 < gemmMR; i0 += 2 {
+		for j0 := 0; j0 < gemmNR; j0 += 4 {
+			var c00, c01, c02, c03 float32
+			var c10, c11, c12, c13 float32
+			for p := range kc {
+				bp := (*[4]float32)(b[p*gemmNR+j0:])
+				ap := (*[2]float32)(a[p*gemmMR+i0:])
+				b0, b1, b2, b3 := bp[0], bp[1], bp[2], bp[3]
+				a0 := ap[0]
+				c00 += a0 * b0
+				c01 += a0 * b1
+				c02 += a0 * b2
+				c03 += a0 * b3
+				a1 := ap[1]
+				c10 += a1 * b0
+				c11 += a1 * b1
+				c12 += a1 * b2
+				c13 += a1 * b3
+			}
+			row0 := (*[4]float32)(tile[i0*gemmNR+j0:])
+			row1 := (*[4]float32)(tile[(i0+1)*gemmNR+j0:])
+			row0[0], row0[1], row0[2], row0[3] = c00, c01, c02, c03
+			row1[0], row1[1], row1[2], row1[3] = c10, c11, c12, c13
+		}
+	}
+}
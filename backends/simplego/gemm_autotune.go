@@ -0,0 +1,142 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+// GEMMBlockSizes are the block sizes used by the cache-blocked GEMM (see gemm.go) of the DotGeneral fast path.
+type GEMMBlockSizes struct {
+	// MC is the number of rows of the LHS packed at a time. It must be a multiple of 4.
+	MC int
+
+	// KC is the size of the blocks of the contracting axis.
+	KC int
+
+	// NC is the number of columns of the RHS packed at a time. It must be a multiple of 16.
+	NC int
+}
+
+// GEMMBlockSizes returns the block sizes used by the GEMM of the DotGeneral fast path of the backend.
+func (b *Backend) GEMMBlockSizes() GEMMBlockSizes {
+	return b.gemmBlockSizes
+}
+
+// SetGEMMBlockSizes sets the block sizes used by the GEMM of the DotGeneral fast path of the backend.
+// The defaults work well on most machines, but see also Backend.AutotuneGEMM.
+//
+// Weights already packed with Backend.PackWeight keep the block sizes used when they were packed.
+//
+// It must not be called while computations are executing.
+func (b *Backend) SetGEMMBlockSizes(sizes GEMMBlockSizes) error {
+	if err := sizes.check(); err != nil {
+		return err
+	}
+	b.gemmBlockSizes = sizes
+	return nil
+}
+
+// check returns an error if the block sizes are invalid.
+func (sizes GEMMBlockSizes) check() error {
+	if sizes.MC <= 0 || sizes.KC <= 0 || sizes.NC <= 0 {
+		return errors.Errorf("invalid GEMM block sizes %+v, they must all be positive", sizes)
+	}
+	if sizes.MC%gemmMR != 0 || sizes.NC%gemmNR != 0 {
+		return errors.Errorf("invalid GEMM block sizes %+v, MC must be a multiple of %d and NC a multiple of %d",
+			sizes, gemmMR, gemmNR)
+	}
+	return nil
+}
+
+// Candidates benchmarked by AutotuneGEMM for each block size.
+var (
+	gemmAutotuneKCs = []int{128, 256, 384, 512}
+	gemmAutotuneMCs = []int{64, 128, 192, 256}
+	gemmAutotuneNCs = []int{512, 1024, 2048, 4096}
+)
+
+// AutotuneGEMM benchmarks a few candidate block sizes for the GEMM of the DotGeneral fast path on the running machine,
+// and sets the fastest ones in the backend (see Backend.SetGEMMBlockSizes). It returns the block sizes selected.
+//
+// The benchmark takes from a fraction of a second to a few seconds, and it is only run once per process: later
+// calls (also from other backends) reuse the block sizes selected the first time.
+// It can also be triggered with the "gemm_autotune" backend configuration option.
+//
+// The best block sizes depend on the SIMD kernels used, so if using SetSIMD, call it before.
+//
+// It must not be called while computations are executing.
+func (b *Backend) AutotuneGEMM() GEMMBlockSizes {
+	b.gemmBlockSizes = gemmAutotuneOnce()
+	return b.gemmBlockSizes
+}
+
+// gemmAutotuneOnce runs the GEMM autotuning only once.
+var gemmAutotuneOnce = sync.OnceValue(autotuneGEMM)
+
+// autotuneGEMM implements Backend.AutotuneGEMM.
+//
+// Each block size is tuned in turn, keeping the others fixed (a coordinate search): KC (the packed slivers should
+// fit L1), then MC (the packed LHS panel should fit L2) and finally NC (the packed RHS panel should fit L3).
+// It starts from the default block sizes, and it doesn't change any state.
+func autotuneGEMM() GEMMBlockSizes {
+	start := time.Now()
+	best := defaultGEMMBlockSizes
+
+	// The problem must be large enough to span a few blocks of each axis. It is shrunk if it is too slow,
+	// e.g. if no SIMD kernel is available.
+	const maxRunTime = 20 * time.Millisecond
+	m, k, n := 256, 512, 2048
+	a := make([]float32, m*k)
+	b := make([]float32, k*n)
+	c := make([]float32, m*n)
+	for ii := range a {
+		a[ii] = float32(ii%7) * 0.1
+	}
+	for ii := range b {
+		b[ii] = float32(ii%5) * 0.1
+	}
+	for n > gemmNR && benchmarkGEMMFloat32(best, m, n, k, a, b, c, 1) > maxRunTime {
+		n /= 2
+	}
+
+	bestTime := benchmarkGEMMFloat32(best, m, n, k, a, b, c, 3)
+	tune := func(candidates []int, blockSize *int) {
+		bestValue := *blockSize
+		for _, candidate := range candidates {
+			if candidate == bestValue {
+				continue
+			}
+			*blockSize = candidate
+			if elapsed := benchmarkGEMMFloat32(best, m, n, k, a, b, c, 3); elapsed < bestTime {
+				bestTime, bestValue = elapsed, candidate
+			}
+		}
+		*blockSize = bestValue
+	}
+	tune(gemmAutotuneKCs, &best.KC)
+	tune(gemmAutotuneMCs, &best.MC)
+	tune(gemmAutotuneNCs, &best.NC)
+	klog.V(1).Infof("SimpleGo backend: GEMM autotuned block sizes %+v in %s", best, time.Since(start))
+	return best
+}
+
+// benchmarkGEMMFloat32 returns the fastest of repeats runs of gemmFloat32 with the given block sizes.
+func benchmarkGEMMFloat32(sizes GEMMBlockSizes, m, n, k int, a, b, c []float32, repeats int) time.Duration {
+	var fastest time.Duration
+	for ii := range repeats {
+		start := time.Now()
+		gemmFloat32(sizes, m, n, k, a, 0, k, b, 0, n, c, 0, n)
+		elapsed := time.Since(start)
+		if ii == 0 || elapsed < fastest {
+			fastest = elapsed
+		}
+	}
+	return fastest
+}
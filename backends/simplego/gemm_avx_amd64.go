@@ -0,0 +1,50 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !noasm && amd64
+
+package simplego
+
+import (
+	"runtime"
+	"unsafe"
+)
+
+// gemmMicroKernel4x16_avx512_asm is implemented in gemm_avx_amd64.s.
+// It computes the 4x16 tile = A·B from the packed slivers of A and B, see gemmMicroKernelFloat32.
+//
+//go:noescape
+func gemmMicroKernel4x16_avx512_asm(kc int64, a, b, tile unsafe.Pointer)
+
+// gemmMicroKernel4x16_avx2_asm is implemented in gemm_avx_amd64.s.
+// Same as gemmMicroKernel4x16_avx512_asm, using AVX2 and FMA.
+//
+//go:noescape
+func gemmMicroKernel4x16_avx2_asm(kc int64, a, b, tile unsafe.Pointer)
+
+// gemmMicroKernelAVX512 is the AVX-512 version of gemmMicroKernelFloat32.
+func gemmMicroKernelAVX512(kc int, a, b []float32, tile *[gemmMR * gemmNR]float32) {
+	if kc == 0 {
+		clear(tile[:])
+		return
+	}
+	_ = a[kc*gemmMR-1]
+	_ = b[kc*gemmNR-1]
+	gemmMicroKernel4x16_avx512_asm(int64(kc), unsafe.Pointer(&a[0]), unsafe.Pointer(&b[0]), unsafe.Pointer(tile))
+	runtime.KeepAlive(a)
+	runtime.KeepAlive(b)
+}
+
+// gemmMicroKernelAVX2 is the AVX2 version of gemmMicroKernelFloat32.
+func gemmMicroKernelAVX2(kc int, a, b []float32, tile *[gemmMR * gemmNR]float32) {
+	if kc == 0 {
+		clear(tile[:])
+		return
+	}
+	_ = a[kc*gemmMR-1]
+	_ = b[kc*gemmNR-1]
+	gemmMicroKernel4x16_avx2_asm(int64(kc), unsafe.Pointer(&a[0]), unsafe.Pointer(&b[0]), unsafe.Pointer(tile))
+	runtime.KeepAlive(a)
+	runtime.KeepAlive(b)
+}
@@ -0,0 +1,129 @@
+//go:build !noasm && amd64
+
+// GEMM micro-kernels for AMD64: they compute a 4x16 float32 tile = A·B from packed slivers of
+// A (kc columns of 4 values) and B (kc rows of 16 values).
+
+#include "textflag.h"
+
+// func gemmMicroKernel4x16_avx512_asm(kc int64, a, b, tile unsafe.Pointer)
+// Each row of the tile is held in one zmm register. The contracting axis is unrolled by 2, with
+// separate accumulators (Z0-Z3 and Z4-Z7) to hide the FMA latency.
+TEXT ·gemmMicroKernel4x16_avx512_asm(SB), NOSPLIT, $0-32
+	MOVQ kc+0(FP), CX
+	MOVQ a+8(FP), SI
+	MOVQ b+16(FP), DI
+	MOVQ tile+24(FP), DX
+
+	VPXORD Z0, Z0, Z0
+	VPXORD Z1, Z1, Z1
+	VPXORD Z2, Z2, Z2
+	VPXORD Z3, Z3, Z3
+	VPXORD Z4, Z4, Z4
+	VPXORD Z5, Z5, Z5
+	VPXORD Z6, Z6, Z6
+	VPXORD Z7, Z7, Z7
+
+	CMPQ CX, $2
+	JL   avx512_tail
+
+avx512_loop2:
+	VMOVUPS      (DI), Z8
+	VMOVUPS      64(DI), Z9
+	VBROADCASTSS (SI), Z10
+	VFMADD231PS  Z8, Z10, Z0
+	VBROADCASTSS 4(SI), Z11
+	VFMADD231PS  Z8, Z11, Z1
+	VBROADCASTSS 8(SI), Z12
+	VFMADD231PS  Z8, Z12, Z2
+	VBROADCASTSS 12(SI), Z13
+	VFMADD231PS  Z8, Z13, Z3
+	VBROADCASTSS 16(SI), Z10
+	VFMADD231PS  Z9, Z10, Z4
+	VBROADCASTSS 20(SI), Z11
+	VFMADD231PS  Z9, Z11, Z5
+	VBROADCASTSS 24(SI), Z12
+	VFMADD231PS  Z9, Z12, Z6
+	VBROADCASTSS 28(SI), Z13
+	VFMADD231PS  Z9, Z13, Z7
+	ADDQ         $32, SI
+	ADDQ         $128, DI
+	SUBQ         $2, CX
+	CMPQ         CX, $2
+	JGE          avx512_loop2
+
+avx512_tail:
+	TESTQ        CX, CX
+	JZ           avx512_store
+	VMOVUPS      (DI), Z8
+	VBROADCASTSS (SI), Z10
+	VFMADD231PS  Z8, Z10, Z0
+	VBROADCASTSS 4(SI), Z11
+	VFMADD231PS  Z8, Z11, Z1
+	VBROADCASTSS 8(SI), Z12
+	VFMADD231PS  Z8, Z12, Z2
+	VBROADCASTSS 12(SI), Z13
+	VFMADD231PS  Z8, Z13, Z3
+
+avx512_store:
+	VADDPS  Z4, Z0, Z0
+	VADDPS  Z5, Z1, Z1
+	VADDPS  Z6, Z2, Z2
+	VADDPS  Z7, Z3, Z3
+	VMOVUPS Z0, (DX)
+	VMOVUPS Z1, 64(DX)
+	VMOVUPS Z2, 128(DX)
+	VMOVUPS Z3, 192(DX)
+	VZEROUPPER
+	RET
+
+// func gemmMicroKernel4x16_avx2_asm(kc int64, a, b, tile unsafe.Pointer)
+// Each row of the tile is held in two ymm registers: 8 independent accumulators (Y0-Y7).
+TEXT ·gemmMicroKernel4x16_avx2_asm(SB), NOSPLIT, $0-32
+	MOVQ kc+0(FP), CX
+	MOVQ a+8(FP), SI
+	MOVQ b+16(FP), DI
+	MOVQ tile+24(FP), DX
+
+	VXORPS Y0, Y0, Y0
+	VXORPS Y1, Y1, Y1
+	VXORPS Y2, Y2, Y2
+	VXORPS Y3, Y3, Y3
+	VXORPS Y4, Y4, Y4
+	VXORPS Y5, Y5, Y5
+	VXORPS Y6, Y6, Y6
+	VXORPS Y7, Y7, Y7
+
+	TESTQ CX, CX
+	JZ    avx2_store
+
+avx2_loop:
+	VMOVUPS      (DI), Y8
+	VMOVUPS      32(DI), Y9
+	VBROADCASTSS (SI), Y10
+	VFMADD231PS  Y8, Y10, Y0
+	VFMADD231PS  Y9, Y10, Y1
+	VBROADCASTSS 4(SI), Y11
+	VFMADD231PS  Y8, Y11, Y2
+	VFMADD231PS  Y9, Y11, Y3
+	VBROADCASTSS 8(SI), Y12
+	VFMADD231PS  Y8, Y12, Y4
+	VFMADD231PS  Y9, Y12, Y5
+	VBROADCASTSS 12(SI), Y13
+	VFMADD231PS  Y8, Y13, Y6
+	VFMADD231PS  Y9, Y13, Y7
+	ADDQ         $16, SI
+	ADDQ         $64, DI
+	DECQ         CX
+	JNZ          avx2_loop
+
+avx2_store:
+	VMOVUPS Y0, (DX)
+	VMOVUPS Y1, 32(DX)
+	VMOVUPS Y2, 64(DX)
+	VMOVUPS Y3, 96(DX)
+	VMOVUPS Y4, 128(DX)
+	VMOVUPS Y5, 160(DX)
+	VMOVUPS Y6, 192(DX)
+	VMOVUPS Y7, 224(DX)
+	VZEROUPPER
+	RET
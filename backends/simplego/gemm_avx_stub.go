@@ -0,0 +1,17 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build noasm || !amd64
+
+package simplego
+
+// gemmMicroKernelAVX512 stub for non-AMD64 platforms.
+func gemmMicroKernelAVX512(kc int, a, b []float32, tile *[gemmMR * gemmNR]float32) {
+	panic("AVX-512 not available")
+}
+
+// gemmMicroKernelAVX2 stub for non-AMD64 platforms.
+func gemmMicroKernelAVX2(kc int, a, b []float32, tile *[gemmMR * gemmNR]float32) {
+	panic("AVX2 not available")
+}
@@ -0,0 +1,85 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"github.com/pkg/errors"
+)
+
+// StridedMatrix is one operand of a batch of matrix multiplications (see Backend.GEMMStridedBatchedFloat32):
+// the row-major matrix of the batch example i starts at Flat[Offset + i*BatchStride], with LeadingDim values
+// between the starts of consecutive rows.
+//
+// A BatchStride of 0 uses the same matrix for all batch examples, e.g. for weights shared by all heads.
+type StridedMatrix struct {
+	Flat                            []float32
+	Offset, LeadingDim, BatchStride int
+}
+
+// GEMMStridedBatchedFloat32 computes C[i] += A[i]·B[i] for each batch example i in [0, batchSize), where A[i] is
+// a [m, k] matrix, B[i] a [k, n] matrix and C[i] a [m, n] matrix, all given as StridedMatrix.
+//
+// This is the case of multi-head attention, where there are many small matrix multiplications with the same
+// shapes: the batch examples (and the rows of each one) are split among the backend workers, and each matrix
+// multiplication uses the GEMM kernels (or the GEMV kernels for few rows, see selectMatmulKernel).
+//
+// The C matrices of the batch must not overlap.
+func (b *Backend) GEMMStridedBatchedFloat32(batchSize, m, n, k int, aMat, bMat, cMat StridedMatrix) error {
+	if batchSize < 0 || m < 0 || n < 0 || k < 0 {
+		return errors.Errorf("GEMMStridedBatchedFloat32: invalid negative dimensions batchSize=%d, m=%d, n=%d, k=%d",
+			batchSize, m, n, k)
+	}
+	if batchSize == 0 || m == 0 || n == 0 {
+		return nil
+	}
+	for _, operand := range []struct {
+		name       string
+		mat        StridedMatrix
+		rows, cols int
+	}{{"A", aMat, m, k}, {"B", bMat, k, n}, {"C", cMat, m, n}} {
+		if err := operand.mat.check(batchSize, operand.rows, operand.cols); err != nil {
+			return errors.WithMessagef(err, "GEMMStridedBatchedFloat32: invalid operand %s", operand.name)
+		}
+	}
+
+	if estimateMatmulCost(matmulKernelGEMV, batchSize, m, n, k) < estimateMatmulCost(matmulKernelGEMM, batchSize, m, n, k) {
+		// Few rows (e.g. decoder steps): each row is a vector × matrix product, with the columns split instead.
+		numColBlocks := (n + gemvNB - 1) / gemvNB
+		parallelizeDotGeneral(b, batchSize*m, numColBlocks, gemvNB*k, func(batchRow, blockStart, blockEnd int) {
+			batchIdx, row := batchRow/m, batchRow%m
+			colStart := blockStart * gemvNB
+			colEnd := min(blockEnd*gemvNB, n)
+			gemvFloat32(k, colEnd-colStart,
+				aMat.Flat, aMat.Offset+batchIdx*aMat.BatchStride+row*aMat.LeadingDim,
+				bMat.Flat, bMat.Offset+batchIdx*bMat.BatchStride+colStart, bMat.LeadingDim,
+				cMat.Flat, cMat.Offset+batchIdx*cMat.BatchStride+row*cMat.LeadingDim+colStart)
+		})
+		return nil
+	}
+	blockSizes := b.gemmBlockSizes
+	parallelizeDotGeneral(b, batchSize, m, n*k, func(batchIdx, rowStart, rowEnd int) {
+		gemmFloat32(blockSizes, rowEnd-rowStart, n, k,
+			aMat.Flat, aMat.Offset+batchIdx*aMat.BatchStride+rowStart*aMat.LeadingDim, aMat.LeadingDim,
+			bMat.Flat, bMat.Offset+batchIdx*bMat.BatchStride, bMat.LeadingDim,
+			cMat.Flat, cMat.Offset+batchIdx*cMat.BatchStride+rowStart*cMat.LeadingDim, cMat.LeadingDim)
+	})
+	return nil
+}
+
+// check that the batch of [rows, cols] matrices fits in the flat values.
+func (s StridedMatrix) check(batchSize, rows, cols int) error {
+	if s.Offset < 0 || s.BatchStride < 0 || s.LeadingDim < cols {
+		return errors.Errorf("invalid Offset=%d, BatchStride=%d or LeadingDim=%d (must be >= %d columns)",
+			s.Offset, s.BatchStride, s.LeadingDim, cols)
+	}
+	if rows == 0 || cols == 0 {
+		return nil
+	}
+	if last := s.Offset + (batchSize-1)*s.BatchStride + (rows-1)*s.LeadingDim + cols; last > len(s.Flat) {
+		return errors.Errorf("%d batch examples of [%d, %d] matrices need %d values, but only %d were given",
+			batchSize, rows, cols, last, len(s.Flat))
+	}
+	return nil
+}
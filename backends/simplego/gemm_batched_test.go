@@ -0,0 +1,112 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGEMMStridedBatchedFloat32(t *testing.T) {
+	be, ok := backend.(*Backend)
+	if !ok {
+		t.Skip("Skipping test because backend is not a SimpleGo Backend")
+	}
+	rng := rand.New(rand.NewSource(42))
+	for _, dims := range [][4]int{{1, 1, 1, 1}, {12, 1, 100, 64}, {12, 7, 16, 64}, {3, 70, 33, 20}} {
+		batchSize, m, n, k := dims[0], dims[1], dims[2], dims[3]
+		for _, sharedB := range []bool{false, true} {
+			t.Run(fmt.Sprintf("batch=%d,m=%d,n=%d,k=%d/sharedB=%v", batchSize, m, n, k, sharedB), func(t *testing.T) {
+				// A is given with a padded leading dimension and an offset, C is interleaved with the padding.
+				const offset, padding = 5, 3
+				aMat := StridedMatrix{Offset: offset, LeadingDim: k + padding, BatchStride: m * (k + padding)}
+				aMat.Flat = make([]float32, offset+batchSize*aMat.BatchStride)
+				for i := range aMat.Flat {
+					aMat.Flat[i] = rng.Float32()*2 - 1
+				}
+				bMat := StridedMatrix{LeadingDim: n, BatchStride: k * n}
+				if sharedB {
+					bMat.BatchStride = 0
+				}
+				bMat.Flat = make([]float32, k*n+(batchSize-1)*bMat.BatchStride)
+				for i := range bMat.Flat {
+					bMat.Flat[i] = rng.Float32()*2 - 1
+				}
+				cMat := StridedMatrix{LeadingDim: n + padding, BatchStride: m * (n + padding)}
+				cMat.Flat = make([]float32, batchSize*cMat.BatchStride)
+				for i := range cMat.Flat {
+					cMat.Flat[i] = 1
+				}
+				require.NoError(t, be.GEMMStridedBatchedFloat32(batchSize, m, n, k, aMat, bMat, cMat))
+
+				for batchIdx := range batchSize {
+					a := make([]float32, 0, m*k)
+					for i := range m {
+						start := offset + batchIdx*aMat.BatchStride + i*aMat.LeadingDim
+						a = append(a, aMat.Flat[start:start+k]...)
+					}
+					b := bMat.Flat[batchIdx*bMat.BatchStride : batchIdx*bMat.BatchStride+k*n]
+					want := naiveMatMulFloat32(m, n, k, a, b)
+					for i := range m {
+						for j := range cMat.LeadingDim {
+							got := cMat.Flat[batchIdx*cMat.BatchStride+i*cMat.LeadingDim+j]
+							if j >= n {
+								require.Equalf(t, float32(1), got, "padding at (%d, %d, %d) was changed", batchIdx, i, j)
+								continue
+							}
+							require.InDeltaf(t, want[i*n+j]+1, got, 1e-4, "mismatch at (%d, %d, %d)", batchIdx, i, j)
+						}
+					}
+				}
+			})
+		}
+	}
+
+	// Invalid operands.
+	valid := func() (aMat, bMat, cMat StridedMatrix) {
+		return StridedMatrix{Flat: make([]float32, 2*6), LeadingDim: 3, BatchStride: 6},
+			StridedMatrix{Flat: make([]float32, 3*4), LeadingDim: 4},
+			StridedMatrix{Flat: make([]float32, 2*8), LeadingDim: 4, BatchStride: 8}
+	}
+	aMat, bMat, cMat := valid()
+	require.NoError(t, be.GEMMStridedBatchedFloat32(2, 2, 4, 3, aMat, bMat, cMat))
+	require.Error(t, be.GEMMStridedBatchedFloat32(3, 2, 4, 3, aMat, bMat, cMat))
+	require.Error(t, be.GEMMStridedBatchedFloat32(-1, 2, 4, 3, aMat, bMat, cMat))
+	aMat.LeadingDim = 2
+	require.Error(t, be.GEMMStridedBatchedFloat32(2, 2, 4, 3, aMat, bMat, cMat))
+	aMat, bMat, cMat = valid()
+	cMat.Offset = 1
+	require.Error(t, be.GEMMStridedBatchedFloat32(2, 2, 4, 3, aMat, bMat, cMat))
+}
+
+func BenchmarkGEMMStridedBatchedFloat32(b *testing.B) {
+	be, ok := backend.(*Backend)
+	if !ok {
+		b.Skip("Skipping benchmark because backend is not a SimpleGo Backend")
+	}
+	// Attention scores of 12 heads: [seqLen, headDim] × [headDim, seqLen].
+	const numHeads, seqLen, headDim = 12, 128, 64
+	aMat := StridedMatrix{Flat: make([]float32, numHeads*seqLen*headDim), LeadingDim: headDim, BatchStride: seqLen * headDim}
+	bMat := StridedMatrix{Flat: make([]float32, numHeads*headDim*seqLen), LeadingDim: seqLen, BatchStride: headDim * seqLen}
+	cMat := StridedMatrix{Flat: make([]float32, numHeads*seqLen*seqLen), LeadingDim: seqLen, BatchStride: seqLen * seqLen}
+	b.Run("batched", func(b *testing.B) {
+		for range b.N {
+			require.NoError(b, be.GEMMStridedBatchedFloat32(numHeads, seqLen, seqLen, headDim, aMat, bMat, cMat))
+		}
+	})
+	b.Run("sequential", func(b *testing.B) {
+		for range b.N {
+			for head := range numHeads {
+				gemmFloat32(be.GEMMBlockSizes(), seqLen, seqLen, headDim,
+					aMat.Flat, head*aMat.BatchStride, aMat.LeadingDim,
+					bMat.Flat, head*bMat.BatchStride, bMat.LeadingDim,
+					cMat.Flat, head*cMat.BatchStride, cMat.LeadingDim)
+			}
+		}
+	})
+}
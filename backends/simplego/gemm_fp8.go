@@ -0,0 +1,178 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"github.com/pkg/errors"
+)
+
+// QuantizedWeightFP8 is an FP8 weight matrix [K, N], used as the RHS of a matrix multiplication
+// [M, K] × [K, N] → [M, N], scaled per output channel: a weight w[k, n] is represented by the FP8 value q, with
+// `w ≈ q * Scales[n]`.
+//
+// See Backend.MatMulDequantizeFP8 and QuantizeWeightFP8.
+type QuantizedWeightFP8 struct {
+	// K and N are the dimensions of the weight matrix.
+	K, N int
+
+	// Format of the FP8 values.
+	Format FP8Format
+
+	// Values holds the K×N FP8 encoded weights, row-major, see EncodeFP8.
+	Values []uint8
+
+	// Scales of the weights, either with one value for the whole matrix, or with N values, one per output channel.
+	Scales []float32
+}
+
+// QuantizeWeightFP8 quantizes the row-major float32 weights [k, n] to FP8, with one scale per output channel that
+// maps the largest absolute value of the channel to the largest value of the format.
+func QuantizeWeightFP8(values []float32, k, n int, format FP8Format) (*QuantizedWeightFP8, error) {
+	if len(values) != k*n {
+		return nil, errors.Errorf("QuantizeWeightFP8: expected %d×%d values, got %d", k, n, len(values))
+	}
+	if err := format.validate(); err != nil {
+		return nil, err
+	}
+	w := &QuantizedWeightFP8{K: k, N: n, Format: format, Values: make([]uint8, k*n), Scales: make([]float32, n)}
+	for col := range n {
+		var maxAbs float32
+		for row := range k {
+			value := values[row*n+col]
+			maxAbs = max(maxAbs, value, -value)
+		}
+		scale := maxAbs / format.MaxValue()
+		if scale == 0 {
+			scale = 1
+		}
+		w.Scales[col] = scale
+		for row := range k {
+			w.Values[row*n+col] = encodeFP8(format, values[row*n+col]/scale)
+		}
+	}
+	return w, nil
+}
+
+// MatMulDequantizeFP8 computes output[M, N] = lhs[M, K] × rhs, for float32 activations and FP8 weights.
+//
+// As MatMulDequantizeInt8, the weights are dequantized on read: each FP8 value is decoded with a lookup table,
+// either while packing each panel of the weights for the GEMM (see gemmPackBDequantizeFP8), or, with a few rows
+// (e.g. single-token decoder steps), while streaming over the weights, applying the scales to the accumulators.
+//
+// The rows (or the columns, for few rows) are split among the backend workers.
+func (b *Backend) MatMulDequantizeFP8(lhs []float32, m int, rhs *QuantizedWeightFP8, output []float32) error {
+	k, n := rhs.K, rhs.N
+	if err := rhs.Format.validate(); err != nil {
+		return errors.WithMessage(err, "MatMulDequantizeFP8")
+	}
+	if len(lhs) != m*k || len(output) != m*n || len(rhs.Values) != k*n {
+		return errors.Errorf("MatMulDequantizeFP8: expected %d×%d lhs, %d×%d rhs and %d×%d output values, "+
+			"got %d, %d and %d", m, k, k, n, m, n, len(lhs), len(rhs.Values), len(output))
+	}
+	if len(rhs.Scales) != 1 && len(rhs.Scales) != n {
+		return errors.Errorf("MatMulDequantizeFP8: expected 1 or %d Scales, got %d", n, len(rhs.Scales))
+	}
+	scales := rhs.Scales
+	if len(scales) == 1 {
+		scales = make([]float32, n)
+		for j := range scales {
+			scales[j] = rhs.Scales[0]
+		}
+	}
+	table := fp8DecodeTable(rhs.Format)
+	clear(output)
+
+	if m < gemmMR {
+		// Few rows: the packing of the weights would cost as much as the multiplication itself.
+		numColBlocks := (n + gemvNB - 1) / gemvNB
+		parallelizeDotGeneral(b, m, numColBlocks, gemvNB*k, func(row, blockStart, blockEnd int) {
+			colStart := blockStart * gemvNB
+			colEnd := min(blockEnd*gemvNB, n)
+			for j := colStart; j < colEnd; j += gemvNB {
+				width := min(gemvNB, colEnd-j)
+				gemvDequantizeFP8Block(k, lhs[row*k:(row+1)*k], rhs.Values[j:], n, table,
+					scales[j:j+width], output[row*n+j:row*n+j+width])
+			}
+		})
+		return nil
+	}
+	blockSizes := b.gemmBlockSizes
+	parallelizeDotGeneral(b, 1, m, n*k, func(_, rowStart, rowEnd int) {
+		gemmDequantizeFP8(blockSizes, rowEnd-rowStart, n, k,
+			lhs, rowStart*k, k,
+			rhs.Values, n, table, scales,
+			output, rowStart*n, n)
+	})
+	return nil
+}
+
+// gemmDequantizeFP8 computes C += A·dequantize(B), like gemmFloat32, where B is a [k, n] FP8 matrix (with leading
+// dimension ldb) whose column j is dequantized as `table[q] * scales[j]`.
+func gemmDequantizeFP8(sizes GEMMBlockSizes, m, n, k int,
+	a []float32, aIdx, lda int,
+	b []uint8, ldb int, table *[256]float32, scales []float32,
+	c []float32, cIdx, ldc int) {
+	if m == 0 || n == 0 || k == 0 {
+		return
+	}
+	mc, kc, nc := min(sizes.MC, m), min(sizes.KC, k), min(sizes.NC, n)
+	aPackedBuf := getGemmPackedBuffer(roundUp(mc, gemmMR) * kc)
+	bPackedBuf := getGemmPackedBuffer(roundUp(nc, gemmNR) * kc)
+	defer gemmPackedPool.Put(aPackedBuf)
+	defer gemmPackedPool.Put(bPackedBuf)
+	aPacked, bPacked := *aPackedBuf, *bPackedBuf
+
+	for jc := 0; jc < n; jc += sizes.NC {
+		ncBlock := min(sizes.NC, n-jc)
+		for pc := 0; pc < k; pc += sizes.KC {
+			kcBlock := min(sizes.KC, k-pc)
+			gemmPackBDequantizeFP8(kcBlock, ncBlock, b, pc*ldb+jc, ldb, table, scales[jc:jc+ncBlock], bPacked)
+			for ic := 0; ic < m; ic += sizes.MC {
+				mcBlock := min(sizes.MC, m-ic)
+				gemmPackA(mcBlock, kcBlock, a, aIdx+ic*lda+pc, lda, aPacked)
+				gemmMacroKernel(mcBlock, ncBlock, kcBlock, aPacked, bPacked, c, cIdx+ic*ldc+jc, ldc)
+			}
+		}
+	}
+}
+
+// gemmPackBDequantizeFP8 packs the FP8 block B[kc, nc] like gemmPackB, dequantizing the values of each column j
+// as `table[q] * scales[j]`.
+func gemmPackBDequantizeFP8(kc, nc int, b []uint8, bIdx, ldb int, table *[256]float32, scales []float32,
+	packed []float32) {
+	packedIdx := 0
+	for jr := 0; jr < nc; jr += gemmNR {
+		nr := min(gemmNR, nc-jr)
+		sliverScales := scales[jr : jr+nr]
+		for p := range kc {
+			dst := packed[packedIdx+p*gemmNR : packedIdx+(p+1)*gemmNR]
+			src := b[bIdx+p*ldb+jr : bIdx+p*ldb+jr+nr]
+			for j, q := range src {
+				dst[j] = table[q] * sliverScales[j]
+			}
+			clear(dst[nr:])
+		}
+		packedIdx += kc * gemmNR
+	}
+}
+
+// gemvDequantizeFP8Block computes c += a·dequantize(B[:, 0:len(c)]), for len(c) <= gemvNB, where the column j of
+// the FP8 matrix B (with leading dimension ldb) is dequantized as `table[q] * scales[j]`.
+//
+// The products with the decoded values are accumulated first, and the scales are applied at the end.
+func gemvDequantizeFP8Block(k int, a []float32, b []uint8, ldb int, table *[256]float32, scales, c []float32) {
+	width := len(c)
+	var acc [gemvNB]float32
+	sums := acc[:width]
+	for p, aValue := range a[:k] {
+		row := b[p*ldb : p*ldb+width]
+		for j, q := range row {
+			sums[j] += aValue * table[q]
+		}
+	}
+	for j, sum := range sums {
+		c[j] += sum * scales[j]
+	}
+}
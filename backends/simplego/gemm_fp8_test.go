@@ -0,0 +1,74 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatMulDequantizeFP8(t *testing.T) {
+	be, ok := backend.(*Backend)
+	if !ok {
+		t.Skip("Skipping test because backend is not a SimpleGo Backend")
+	}
+	// Use small block sizes, to exercise multiple blocks in each axis.
+	defer func(sizes GEMMBlockSizes) { require.NoError(t, be.SetGEMMBlockSizes(sizes)) }(be.GEMMBlockSizes())
+	require.NoError(t, be.SetGEMMBlockSizes(GEMMBlockSizes{MC: 8, KC: 16, NC: 32}))
+
+	rng := rand.New(rand.NewSource(42))
+	for _, format := range []FP8Format{FP8E4M3, FP8E5M2} {
+		for _, dims := range [][3]int{{1, 1, 1}, {1, 100, 70}, {3, 17, 5}, {4, 16, 16}, {33, 65, 100}} {
+			m, n, k := dims[0], dims[1], dims[2]
+			t.Run(fmt.Sprintf("%s/m=%d,n=%d,k=%d", format, m, n, k), func(t *testing.T) {
+				lhs := make([]float32, m*k)
+				for i := range lhs {
+					lhs[i] = rng.Float32()*2 - 1
+				}
+				rhsValues := make([]float32, k*n)
+				for i := range rhsValues {
+					rhsValues[i] = (rng.Float32()*2 - 0.5) * float32(1+i%n)
+				}
+				rhs, err := QuantizeWeightFP8(rhsValues, k, n, format)
+				require.NoError(t, err)
+				dequantized := make([]float32, k*n)
+				require.NoError(t, DecodeFP8(format, rhs.Values, dequantized))
+				for i := range dequantized {
+					dequantized[i] *= rhs.Scales[i%n]
+				}
+				want := naiveMatMulFloat32(m, n, k, lhs, dequantized)
+
+				output := make([]float32, m*n)
+				for i := range output {
+					output[i] = 1 // It must be overwritten.
+				}
+				require.NoError(t, be.MatMulDequantizeFP8(lhs, m, rhs, output))
+				for i, value := range want {
+					require.InDeltaf(t, value, output[i], 1e-3*float64(1+i%n), "mismatch at flat index %d", i)
+				}
+			})
+		}
+	}
+
+	// A single scale for the whole matrix.
+	rhs := &QuantizedWeightFP8{K: 2, N: 3, Format: FP8E4M3, Values: []uint8{0x38, 0x40, 0x00, 0x38, 0xB8, 0x44},
+		Scales: []float32{0.5}}
+	output := make([]float32, 3)
+	require.NoError(t, be.MatMulDequantizeFP8([]float32{1, 2}, 1, rhs, output))
+	require.Equal(t, []float32{1.5, 0, 3}, output)
+
+	// Invalid parameters.
+	rhs.Scales = []float32{1, 2}
+	require.Error(t, be.MatMulDequantizeFP8([]float32{1, 2}, 1, rhs, output))
+	rhs.Scales = []float32{1}
+	require.Error(t, be.MatMulDequantizeFP8([]float32{1, 2, 3}, 1, rhs, output))
+	rhs.Format = FP8Format(5)
+	require.Error(t, be.MatMulDequantizeFP8([]float32{1, 2}, 1, rhs, output))
+	_, err := QuantizeWeightFP8(make([]float32, 5), 2, 3, FP8E4M3)
+	require.Error(t, err)
+}
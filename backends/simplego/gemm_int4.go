@@ -0,0 +1,287 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// Int4SymmetricZeroPoint is the zero-point of the 4-bit weights quantized symmetrically, see QuantizedWeightInt4.
+const Int4SymmetricZeroPoint = 8
+
+// QuantizedWeightInt4 is a 4-bit weight matrix [K, N], used as the RHS of a matrix multiplication
+// [M, K] × [K, N] → [M, N], quantized group-wise (as in the Q4 formats of llama.cpp): the rows are split in groups
+// of GroupSize consecutive rows (along the contracting axis), with one scale and zero-point per group and output
+// channel. A weight w[k, n] is represented by an unsigned 4-bit q (from 0 to 15), with
+// `w ≈ (q - ZeroPoints[g*N+n]) * Scales[g*N+n]`, where g = k/GroupSize is the group of the row.
+//
+// See Backend.MatMulDequantizeInt4 and QuantizeWeightInt4.
+type QuantizedWeightInt4 struct {
+	// K and N are the dimensions of the weight matrix.
+	K, N int
+
+	// GroupSize is the number of rows in each group of the quantization.
+	GroupSize int
+
+	// Values holds the K×N 4-bit values, row-major, packed two per byte: the column 2j in the low nibble and the
+	// column 2j+1 in the high nibble of the byte j of each row. Each row takes (N+1)/2 bytes.
+	Values []uint8
+
+	// Scales holds one scale per group and output channel: they are shaped [NumGroups(), N].
+	Scales []float32
+
+	// ZeroPoints are shaped like the Scales, with values from 0 to 15. They can be left empty for symmetric
+	// quantization, in which case the zero-point is Int4SymmetricZeroPoint.
+	ZeroPoints []uint8
+}
+
+// NumGroups returns the number of groups of rows of the quantization, ceil(K/GroupSize).
+func (w *QuantizedWeightInt4) NumGroups() int {
+	return (w.K + w.GroupSize - 1) / w.GroupSize
+}
+
+// RowBytes returns the number of bytes of each row of the Values, (N+1)/2.
+func (w *QuantizedWeightInt4) RowBytes() int {
+	return (w.N + 1) / 2
+}
+
+// QuantizeWeightInt4 quantizes the row-major float32 weights [k, n] to 4 bits, with one scale (and zero-point, if
+// not symmetric) per group of groupSize rows and output channel, see QuantizedWeightInt4.
+//
+// Symmetric quantization maps the largest absolute value of the group to ±7 (and the zero-point is
+// Int4SymmetricZeroPoint), while asymmetric quantization maps the range of the values to [0, 15].
+func QuantizeWeightInt4(values []float32, k, n, groupSize int, symmetric bool) (*QuantizedWeightInt4, error) {
+	if len(values) != k*n {
+		return nil, errors.Errorf("QuantizeWeightInt4: expected %d×%d values, got %d", k, n, len(values))
+	}
+	if groupSize <= 0 {
+		return nil, errors.Errorf("QuantizeWeightInt4: invalid groupSize %d", groupSize)
+	}
+	w := &QuantizedWeightInt4{K: k, N: n, GroupSize: groupSize}
+	numGroups, rowBytes := w.NumGroups(), w.RowBytes()
+	w.Values = make([]uint8, k*rowBytes)
+	w.Scales = make([]float32, numGroups*n)
+	if !symmetric {
+		w.ZeroPoints = make([]uint8, numGroups*n)
+	}
+	for g := range numGroups {
+		rowStart, rowEnd := g*groupSize, min((g+1)*groupSize, k)
+		for col := range n {
+			minValue, maxValue := float32(0), float32(0)
+			for row := rowStart; row < rowEnd; row++ {
+				minValue = min(minValue, values[row*n+col])
+				maxValue = max(maxValue, values[row*n+col])
+			}
+			var scale float32
+			zeroPoint := float32(Int4SymmetricZeroPoint)
+			if symmetric {
+				scale = max(-minValue, maxValue) / 7
+			} else {
+				scale = (maxValue - minValue) / 15
+				if scale > 0 {
+					zeroPoint = float32(math.Round(float64(-minValue / scale)))
+				}
+				w.ZeroPoints[g*n+col] = uint8(zeroPoint)
+			}
+			if scale == 0 {
+				scale = 1
+			}
+			w.Scales[g*n+col] = scale
+			for row := rowStart; row < rowEnd; row++ {
+				q := math.Round(float64(values[row*n+col]/scale + zeroPoint))
+				w.Values[row*rowBytes+col/2] |= uint8(min(max(q, 0), 15)) << (4 * (col % 2))
+			}
+		}
+	}
+	return w, nil
+}
+
+// MatMulDequantizeInt4 computes output[M, N] = lhs[M, K] × rhs, for float32 activations and weight-only 4-bit
+// quantized weights, as used to run Q4 quantized models.
+//
+// As MatMulDequantizeInt8, it operates directly on the packed 4-bit weights: the nibbles are expanded to float32
+// in registers, either while packing each panel of the weights for the GEMM (see gemmPackBDequantizeInt4), or,
+// with a few rows (e.g. single-token decoder steps), while streaming over the weights, applying the scales of each
+// group to the accumulators. So no int8 or float32 copy of the weights is materialized, and only an eighth of the
+// memory of the float32 weights is read.
+//
+// The rows (or the columns, for few rows) are split among the backend workers.
+func (b *Backend) MatMulDequantizeInt4(lhs []float32, m int, rhs *QuantizedWeightInt4, output []float32) error {
+	k, n := rhs.K, rhs.N
+	if rhs.GroupSize <= 0 {
+		return errors.Errorf("MatMulDequantizeInt4: invalid GroupSize %d", rhs.GroupSize)
+	}
+	if len(lhs) != m*k || len(output) != m*n || len(rhs.Values) != k*rhs.RowBytes() {
+		return errors.Errorf("MatMulDequantizeInt4: expected %d×%d lhs, %d×%d rhs (%d bytes) and %d×%d output "+
+			"values, got %d, %d bytes and %d", m, k, k, n, k*rhs.RowBytes(), m, n, len(lhs), len(rhs.Values),
+			len(output))
+	}
+	scales, offsets, err := rhs.groupParams()
+	if err != nil {
+		return err
+	}
+	clear(output)
+
+	ldb := rhs.RowBytes()
+	if m < gemmMR {
+		// Few rows: the packing of the weights would cost as much as the multiplication itself.
+		numColBlocks := (n + gemvNB - 1) / gemvNB
+		parallelizeDotGeneral(b, m, numColBlocks, gemvNB*k, func(row, blockStart, blockEnd int) {
+			colStart := blockStart * gemvNB
+			colEnd := min(blockEnd*gemvNB, n)
+			for j := colStart; j < colEnd; j += gemvNB {
+				width := min(gemvNB, colEnd-j)
+				gemvDequantizeInt4Block(k, rhs.GroupSize, lhs[row*k:(row+1)*k], rhs.Values[j/2:], ldb,
+					scales[j:], offsets[j:], n, output[row*n+j:row*n+j+width])
+			}
+		})
+		return nil
+	}
+	blockSizes := b.gemmBlockSizes
+	parallelizeDotGeneral(b, 1, m, n*k, func(_, rowStart, rowEnd int) {
+		gemmDequantizeInt4(blockSizes, rowEnd-rowStart, n, k,
+			lhs, rowStart*k, k,
+			rhs.Values, ldb, rhs.GroupSize, scales, offsets,
+			output, rowStart*n, n)
+	})
+	return nil
+}
+
+// groupParams returns the scale and the offset (-zeroPoint * scale) of each group and output channel, shaped
+// [NumGroups(), N], such that a weight q of the group g and column n is dequantized as
+// `q * scales[g*N+n] + offsets[g*N+n]`.
+func (w *QuantizedWeightInt4) groupParams() (scales, offsets []float32, err error) {
+	size := w.NumGroups() * w.N
+	if len(w.Scales) != size {
+		return nil, nil, errors.Errorf("MatMulDequantizeInt4: expected %d×%d Scales, got %d",
+			w.NumGroups(), w.N, len(w.Scales))
+	}
+	if len(w.ZeroPoints) != 0 && len(w.ZeroPoints) != size {
+		return nil, nil, errors.Errorf("MatMulDequantizeInt4: expected 0 or %d×%d ZeroPoints, got %d",
+			w.NumGroups(), w.N, len(w.ZeroPoints))
+	}
+	offsets = make([]float32, size)
+	for idx, scale := range w.Scales {
+		zeroPoint := float32(Int4SymmetricZeroPoint)
+		if len(w.ZeroPoints) != 0 {
+			zeroPoint = float32(w.ZeroPoints[idx])
+		}
+		offsets[idx] = -zeroPoint * scale
+	}
+	return w.Scales, offsets, nil
+}
+
+// int4At returns the 4-bit value of the column col of a row of packed 4-bit values.
+func int4At(row []uint8, col int) uint8 {
+	return (row[col/2] >> (4 * (col % 2))) & 0xF
+}
+
+// gemmDequantizeInt4 computes C += A·dequantize(B), like gemmFloat32, where B is a [k, n] matrix of packed 4-bit
+// values (with ldb bytes per row), whose values of the group g (of groupSize rows) and column j are dequantized as
+// `q * scales[g*n+j] + offsets[g*n+j]`.
+func gemmDequantizeInt4(sizes GEMMBlockSizes, m, n, k int,
+	a []float32, aIdx, lda int,
+	b []uint8, ldb, groupSize int, scales, offsets []float32,
+	c []float32, cIdx, ldc int) {
+	if m == 0 || n == 0 || k == 0 {
+		return
+	}
+	mc, kc, nc := min(sizes.MC, m), min(sizes.KC, k), min(sizes.NC, n)
+	aPackedBuf := getGemmPackedBuffer(roundUp(mc, gemmMR) * kc)
+	bPackedBuf := getGemmPackedBuffer(roundUp(nc, gemmNR) * kc)
+	defer gemmPackedPool.Put(aPackedBuf)
+	defer gemmPackedPool.Put(bPackedBuf)
+	aPacked, bPacked := *aPackedBuf, *bPackedBuf
+
+	for jc := 0; jc < n; jc += sizes.NC {
+		ncBlock := min(sizes.NC, n-jc)
+		for pc := 0; pc < k; pc += sizes.KC {
+			kcBlock := min(sizes.KC, k-pc)
+			gemmPackBDequantizeInt4(kcBlock, ncBlock, b, pc, jc, ldb, n, groupSize, scales, offsets, bPacked)
+			for ic := 0; ic < m; ic += sizes.MC {
+				mcBlock := min(sizes.MC, m-ic)
+				gemmPackA(mcBlock, kcBlock, a, aIdx+ic*lda+pc, lda, aPacked)
+				gemmMacroKernel(mcBlock, ncBlock, kcBlock, aPacked, bPacked, c, cIdx+ic*ldc+jc, ldc)
+			}
+		}
+	}
+}
+
+// gemmPackBDequantizeInt4 packs the block B[pc:pc+kc, jc:jc+nc] of the packed 4-bit matrix B (with ldb bytes per
+// row and n columns) like gemmPackB, dequantizing the values of each group of groupSize rows, see
+// gemmDequantizeInt4. Full slivers are packed with AVX2, if available.
+//
+// The block size NC (a multiple of gemmNR) and gemmNR are even, so jc and the first column of each sliver are even,
+// and start at a byte boundary.
+func gemmPackBDequantizeInt4(kc, nc int, b []uint8, pc, jc, ldb, n, groupSize int, scales, offsets []float32,
+	packed []float32) {
+	packedIdx := 0
+	for jr := 0; jr < nc; jr += gemmNR {
+		nr := min(gemmNR, nc-jr)
+		col := jc + jr
+		// Rows are packed in segments within the same group, which share the scales and offsets.
+		for p := 0; p < kc; {
+			row := pc + p
+			group := row / groupSize
+			segmentLen := min(kc-p, (group+1)*groupSize-row)
+			sliverScales := scales[group*n+col : group*n+col+nr]
+			sliverOffsets := offsets[group*n+col : group*n+col+nr]
+			dst := packed[packedIdx+p*gemmNR : packedIdx+(p+segmentLen)*gemmNR]
+			if hasAVX2 && nr == gemmNR {
+				gemmPackBDequantizeInt4SliverAVX2(segmentLen, b[row*ldb+col/2:], ldb, sliverScales, sliverOffsets, dst)
+			} else {
+				for segmentRow := range segmentLen {
+					src := b[(row+segmentRow)*ldb:]
+					dstRow := dst[segmentRow*gemmNR : (segmentRow+1)*gemmNR]
+					for j := range nr {
+						dstRow[j] = float32(int4At(src, col+j))*sliverScales[j] + sliverOffsets[j]
+					}
+					clear(dstRow[nr:])
+				}
+			}
+			p += segmentLen
+		}
+		packedIdx += kc * gemmNR
+	}
+}
+
+// gemvDequantizeInt4Block computes c += a·dequantize(B[:, 0:len(c)]), for len(c) <= gemvNB, where B is a matrix of
+// packed 4-bit values (with ldb bytes per row) starting at a byte boundary, whose values of the group g (of
+// groupSize rows) and column j are dequantized as `q * scales[g*ldScales+j] + offsets[g*ldScales+j]`.
+//
+// The products with the 4-bit values of each group are accumulated first (with AVX2 if available), and its scales
+// and offsets are applied at the end of the group:
+// Σ_p a[p]·(q[p, j]·scale + offset) = scale·Σ_p a[p]·q[p, j] + offset·Σ_p a[p].
+func gemvDequantizeInt4Block(k, groupSize int, a []float32, b []uint8, ldb int, scales, offsets []float32,
+	ldScales int, c []float32) {
+	width := len(c)
+	var acc [gemvNB]float32
+	sums := acc[:width]
+	for rowStart := 0; rowStart < k; rowStart += groupSize {
+		rowEnd := min(rowStart+groupSize, k)
+		clear(sums)
+		var sumA float32
+		for _, aValue := range a[rowStart:rowEnd] {
+			sumA += aValue
+		}
+		if hasAVX2 && width == gemvNB {
+			gemvDequantizeInt4AVX2(rowEnd-rowStart, a[rowStart:], b[rowStart*ldb:], ldb, sums)
+		} else {
+			for p := rowStart; p < rowEnd; p++ {
+				aValue, row := a[p], b[p*ldb:]
+				for j := range sums {
+					sums[j] += aValue * float32(int4At(row, j))
+				}
+			}
+		}
+		groupScales := scales[rowStart/groupSize*ldScales:]
+		groupOffsets := offsets[rowStart/groupSize*ldScales:]
+		for j, sum := range sums {
+			c[j] += sum*groupScales[j] + sumA*groupOffsets[j]
+		}
+	}
+}
@@ -0,0 +1,134 @@
+//go:build !noasm && amd64
+
+// Kernels for the matrix multiplication with 4-bit quantized weights (see gemm_int4.go): each 4 bytes of packed
+// values (8 columns) are broadcast to the 8 int32 lanes of a ymm register (VPBROADCASTD), shifted right by 4×lane
+// bits (VPSRLVD) and masked (VPAND), which leaves the 4-bit value of each column in its lane, in the order of the
+// columns, without shuffles. They are then converted to float32 (VCVTDQ2PS).
+
+#include "textflag.h"
+
+// int4Consts holds the shifts of each lane (0, 4, ..., 28), followed by the 4-bit mask (0x0F) for the 8 lanes.
+DATA int4Consts<>+0(SB)/4, $0
+DATA int4Consts<>+4(SB)/4, $4
+DATA int4Consts<>+8(SB)/4, $8
+DATA int4Consts<>+12(SB)/4, $12
+DATA int4Consts<>+16(SB)/4, $16
+DATA int4Consts<>+20(SB)/4, $20
+DATA int4Consts<>+24(SB)/4, $24
+DATA int4Consts<>+28(SB)/4, $28
+DATA int4Consts<>+32(SB)/4, $0x0F
+DATA int4Consts<>+36(SB)/4, $0x0F
+DATA int4Consts<>+40(SB)/4, $0x0F
+DATA int4Consts<>+44(SB)/4, $0x0F
+DATA int4Consts<>+48(SB)/4, $0x0F
+DATA int4Consts<>+52(SB)/4, $0x0F
+DATA int4Consts<>+56(SB)/4, $0x0F
+DATA int4Consts<>+60(SB)/4, $0x0F
+GLOBL int4Consts<>(SB), RODATA|NOPTR, $64
+
+// INT4_TO_FLOAT32_X8 expands the 8 4-bit values of the 4 bytes at addr to float32 in y, given the shifts and the
+// mask of int4Consts.
+#define INT4_TO_FLOAT32_X8(addr, shifts, mask, y) \
+	VPBROADCASTD addr, y          \
+	VPSRLVD      shifts, y, y     \
+	VPAND        mask, y, y       \
+	VCVTDQ2PS    y, y
+
+// func gemvDequantizeInt4_64_avx2_asm(k int64, a, b unsafe.Pointer, ldb int64, sums unsafe.Pointer)
+// It computes sums[0:64] += a[0:k]·B[0:k, 0:64], where B holds packed 4-bit values (32 bytes for the 64 columns
+// of each row) with ldb bytes per row. The 64 sums are held in 8 ymm registers (Y0-Y7).
+TEXT ·gemvDequantizeInt4_64_avx2_asm(SB), NOSPLIT, $0-40
+	MOVQ k+0(FP), CX
+	MOVQ a+8(FP), SI
+	MOVQ b+16(FP), DI
+	MOVQ ldb+24(FP), R8
+	MOVQ sums+32(FP), DX
+
+	VMOVUPS (DX), Y0
+	VMOVUPS 32(DX), Y1
+	VMOVUPS 64(DX), Y2
+	VMOVUPS 96(DX), Y3
+	VMOVUPS 128(DX), Y4
+	VMOVUPS 160(DX), Y5
+	VMOVUPS 192(DX), Y6
+	VMOVUPS 224(DX), Y7
+
+	VMOVDQU int4Consts<>+0(SB), Y9
+	VMOVDQU int4Consts<>+32(SB), Y10
+
+	TESTQ CX, CX
+	JZ    gemv_store
+
+gemv_loop:
+	VBROADCASTSS (SI), Y8
+	INT4_TO_FLOAT32_X8((DI), Y9, Y10, Y11)
+	INT4_TO_FLOAT32_X8(4(DI), Y9, Y10, Y12)
+	INT4_TO_FLOAT32_X8(8(DI), Y9, Y10, Y13)
+	INT4_TO_FLOAT32_X8(12(DI), Y9, Y10, Y14)
+	VFMADD231PS  Y11, Y8, Y0
+	VFMADD231PS  Y12, Y8, Y1
+	VFMADD231PS  Y13, Y8, Y2
+	VFMADD231PS  Y14, Y8, Y3
+	INT4_TO_FLOAT32_X8(16(DI), Y9, Y10, Y11)
+	INT4_TO_FLOAT32_X8(20(DI), Y9, Y10, Y12)
+	INT4_TO_FLOAT32_X8(24(DI), Y9, Y10, Y13)
+	INT4_TO_FLOAT32_X8(28(DI), Y9, Y10, Y14)
+	VFMADD231PS  Y11, Y8, Y4
+	VFMADD231PS  Y12, Y8, Y5
+	VFMADD231PS  Y13, Y8, Y6
+	VFMADD231PS  Y14, Y8, Y7
+	ADDQ         R8, DI
+	ADDQ         $4, SI
+	DECQ         CX
+	JNZ          gemv_loop
+
+gemv_store:
+	VMOVUPS Y0, (DX)
+	VMOVUPS Y1, 32(DX)
+	VMOVUPS Y2, 64(DX)
+	VMOVUPS Y3, 96(DX)
+	VMOVUPS Y4, 128(DX)
+	VMOVUPS Y5, 160(DX)
+	VMOVUPS Y6, 192(DX)
+	VMOVUPS Y7, 224(DX)
+	VZEROUPPER
+	RET
+
+// func gemmPackBDequantizeInt4Sliver16_avx2_asm(kc int64, b unsafe.Pointer, ldb int64, scales, offsets, packed unsafe.Pointer)
+// It packs a sliver of 16 columns of the packed 4-bit B[0:kc, 0:16] (8 bytes per row, with ldb bytes per row),
+// dequantizing the column j as `q * scales[j] + offsets[j]`. The scales and offsets are held in registers
+// (Y12-Y15).
+TEXT ·gemmPackBDequantizeInt4Sliver16_avx2_asm(SB), NOSPLIT, $0-48
+	MOVQ kc+0(FP), CX
+	MOVQ b+8(FP), SI
+	MOVQ ldb+16(FP), R8
+	MOVQ scales+24(FP), AX
+	MOVQ offsets+32(FP), BX
+	MOVQ packed+40(FP), DI
+
+	VMOVUPS (AX), Y12
+	VMOVUPS 32(AX), Y13
+	VMOVUPS (BX), Y14
+	VMOVUPS 32(BX), Y15
+
+	VMOVDQU int4Consts<>+0(SB), Y9
+	VMOVDQU int4Consts<>+32(SB), Y10
+
+	TESTQ CX, CX
+	JZ    pack_done
+
+pack_loop:
+	INT4_TO_FLOAT32_X8((SI), Y9, Y10, Y0)
+	INT4_TO_FLOAT32_X8(4(SI), Y9, Y10, Y1)
+	VFMADD213PS Y14, Y12, Y0
+	VFMADD213PS Y15, Y13, Y1
+	VMOVUPS     Y0, (DI)
+	VMOVUPS     Y1, 32(DI)
+	ADDQ        R8, SI
+	ADDQ        $64, DI
+	DECQ        CX
+	JNZ         pack_loop
+
+pack_done:
+	VZEROUPPER
+	RET
@@ -0,0 +1,149 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// dequantizeInt4 returns the float32 values of the 4-bit weights.
+func dequantizeInt4(w *QuantizedWeightInt4) []float32 {
+	scales, offsets, err := w.groupParams()
+	if err != nil {
+		panic(err)
+	}
+	values := make([]float32, w.K*w.N)
+	for row := range w.K {
+		for col := range w.N {
+			idx := row/w.GroupSize*w.N + col
+			values[row*w.N+col] = float32(int4At(w.Values[row*w.RowBytes():], col))*scales[idx] + offsets[idx]
+		}
+	}
+	return values
+}
+
+func TestQuantizeWeightInt4(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	const k, n, groupSize = 37, 9, 8
+	values := make([]float32, k*n)
+	for i := range values {
+		values[i] = rng.Float32()*4 - 1
+	}
+	for _, symmetric := range []bool{true, false} {
+		w, err := QuantizeWeightInt4(values, k, n, groupSize, symmetric)
+		require.NoError(t, err)
+		require.Equal(t, 5, w.NumGroups())
+		require.Equal(t, 5, w.RowBytes())
+		require.Len(t, w.Values, k*5)
+		require.Len(t, w.Scales, 5*n)
+		dequantized := dequantizeInt4(w)
+		for i, value := range values {
+			// The error is at most half a quantization step.
+			scale := w.Scales[i/n/groupSize*n+i%n]
+			require.InDeltaf(t, value, dequantized[i], float64(scale)/2+1e-6, "symmetric=%v, index %d", symmetric, i)
+		}
+	}
+	_, err := QuantizeWeightInt4(values, k, n, 0, true)
+	require.Error(t, err)
+	_, err = QuantizeWeightInt4(values[1:], k, n, groupSize, true)
+	require.Error(t, err)
+}
+
+func TestMatMulDequantizeInt4(t *testing.T) {
+	be, ok := backend.(*Backend)
+	if !ok {
+		t.Skip("Skipping test because backend is not a SimpleGo Backend")
+	}
+	// Use small block sizes, to exercise multiple blocks in each axis, and groups crossing the blocks.
+	defer func(sizes GEMMBlockSizes) { require.NoError(t, be.SetGEMMBlockSizes(sizes)) }(be.GEMMBlockSizes())
+	require.NoError(t, be.SetGEMMBlockSizes(GEMMBlockSizes{MC: 8, KC: 16, NC: 32}))
+
+	rng := rand.New(rand.NewSource(42))
+	for _, simd := range []string{SIMDAuto, SIMDOff} {
+		require.NoError(t, SetSIMD(simd))
+		for _, dims := range [][4]int{{1, 1, 1, 1}, {1, 128, 70, 32}, {3, 17, 5, 2}, {4, 16, 16, 16}, {33, 65, 100, 12},
+			{2, 200, 64, 64}} {
+			m, n, k, groupSize := dims[0], dims[1], dims[2], dims[3]
+			for _, symmetric := range []bool{true, false} {
+				t.Run(fmt.Sprintf("%s/m=%d,n=%d,k=%d,group=%d/symmetric=%v", simd, m, n, k, groupSize, symmetric), func(t *testing.T) {
+					lhs := make([]float32, m*k)
+					for i := range lhs {
+						lhs[i] = rng.Float32()*2 - 1
+					}
+					rhsValues := make([]float32, k*n)
+					for i := range rhsValues {
+						rhsValues[i] = (rng.Float32()*2 - 0.5) * float32(1+i%n)
+					}
+					rhs, err := QuantizeWeightInt4(rhsValues, k, n, groupSize, symmetric)
+					require.NoError(t, err)
+					want := naiveMatMulFloat32(m, n, k, lhs, dequantizeInt4(rhs))
+
+					output := make([]float32, m*n)
+					for i := range output {
+						output[i] = 1 // It must be overwritten.
+					}
+					require.NoError(t, be.MatMulDequantizeInt4(lhs, m, rhs, output))
+					for i, value := range want {
+						require.InDeltaf(t, value, output[i], 1e-3*float64(1+i%n), "mismatch at flat index %d", i)
+					}
+				})
+			}
+		}
+	}
+	require.NoError(t, SetSIMD(SIMDAuto))
+
+	// Invalid parameters.
+	rhs := &QuantizedWeightInt4{K: 2, N: 3, GroupSize: 1, Values: make([]uint8, 4), Scales: make([]float32, 3)}
+	require.Error(t, be.MatMulDequantizeInt4(make([]float32, 2), 1, rhs, make([]float32, 3)))
+	rhs.Scales = make([]float32, 6)
+	rhs.ZeroPoints = []uint8{8}
+	require.Error(t, be.MatMulDequantizeInt4(make([]float32, 2), 1, rhs, make([]float32, 3)))
+	rhs.ZeroPoints = nil
+	require.Error(t, be.MatMulDequantizeInt4(make([]float32, 3), 1, rhs, make([]float32, 3)))
+	rhs.GroupSize = 0
+	require.Error(t, be.MatMulDequantizeInt4(make([]float32, 2), 1, rhs, make([]float32, 3)))
+	rhs.GroupSize = 1
+	require.NoError(t, be.MatMulDequantizeInt4(make([]float32, 2), 1, rhs, make([]float32, 3)))
+}
+
+func BenchmarkMatMulDequantizeInt4(b *testing.B) {
+	be, ok := backend.(*Backend)
+	if !ok {
+		b.Skip("Skipping benchmark because backend is not a SimpleGo Backend")
+	}
+	const k, n, groupSize = 1024, 1024, 32
+	rhsFloat32 := make([]float32, k*n)
+	for i := range rhsFloat32 {
+		rhsFloat32[i] = float32(i%255-127) * 0.01
+	}
+	rhs, err := QuantizeWeightInt4(rhsFloat32, k, n, groupSize, true)
+	require.NoError(b, err)
+	for _, m := range []int{1, 64} {
+		lhs := make([]float32, m*k)
+		for i := range lhs {
+			lhs[i] = float32(i%7) * 0.1
+		}
+		output := make([]float32, m*n)
+		b.Run(fmt.Sprintf("m=%d/int4", m), func(b *testing.B) {
+			for range b.N {
+				require.NoError(b, be.MatMulDequantizeInt4(lhs, m, rhs, output))
+			}
+		})
+		b.Run(fmt.Sprintf("m=%d/float32", m), func(b *testing.B) {
+			for range b.N {
+				clear(output)
+				if m == 1 {
+					gemvFloat32(k, n, lhs, 0, rhsFloat32, 0, n, output, 0)
+				} else {
+					gemmFloat32(defaultGEMMBlockSizes, m, n, k, lhs, 0, k, rhsFloat32, 0, n, output, 0, n)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"github.com/pkg/errors"
+)
+
+// QuantizedWeightInt8 is an int8 weight matrix [K, N], used as the RHS of a matrix multiplication
+// [M, K] × [K, N] → [M, N], quantized per output channel as in tensors.QuantizeInt8PerChannel (with axis 1):
+// a weight w[k, n] is represented by q, with `w ≈ (q - ZeroPoints[n]) * Scales[n]`.
+//
+// See Backend.MatMulDequantizeInt8.
+type QuantizedWeightInt8 struct {
+	// K and N are the dimensions of the weight matrix.
+	K, N int
+
+	// Values holds the K×N quantized weights, row-major.
+	Values []int8
+
+	// Scales and ZeroPoints of the weights, either with one value for the whole matrix, or with N values, one
+	// per output channel. ZeroPoints can also be left empty, for symmetric quantization.
+	Scales     []float32
+	ZeroPoints []int32
+}
+
+// MatMulDequantizeInt8 computes output[M, N] = lhs[M, K] × rhs, for float32 activations and weight-only int8
+// quantized weights, as used to run quantized models.
+//
+// The weights are dequantized on the fly, inside the GEMM: each panel of the weights is dequantized while it is
+// packed (see gemmPackBDequantizeInt8), so the full float32 copy of the weights is never materialized. With a
+// few rows (e.g. single-token decoder steps) it streams over the int8 weights instead, and applies the
+// per-channel scales to the accumulators, so only a quarter of the memory of the float32 weights is read.
+//
+// The rows (or the columns, for few rows) are split among the backend workers.
+func (b *Backend) MatMulDequantizeInt8(lhs []float32, m int, rhs *QuantizedWeightInt8, output []float32) error {
+	k, n := rhs.K, rhs.N
+	if len(lhs) != m*k || len(output) != m*n || len(rhs.Values) != k*n {
+		return errors.Errorf("MatMulDequantizeInt8: expected %d×%d lhs, %d×%d rhs and %d×%d output values, "+
+			"got %d, %d and %d", m, k, k, n, m, n, len(lhs), len(rhs.Values), len(output))
+	}
+	scales, offsets, err := rhs.perChannelParams()
+	if err != nil {
+		return err
+	}
+	clear(output)
+
+	if m < gemmMR {
+		// Few rows: the packing of the weights would cost as much as the multiplication itself.
+		numColBlocks := (n + gemvNB - 1) / gemvNB
+		parallelizeDotGeneral(b, m, numColBlocks, gemvNB*k, func(row, blockStart, blockEnd int) {
+			colStart := blockStart * gemvNB
+			colEnd := min(blockEnd*gemvNB, n)
+			for j := colStart; j < colEnd; j += gemvNB {
+				width := min(gemvNB, colEnd-j)
+				gemvDequantizeInt8Block(k, lhs[row*k:(row+1)*k], rhs.Values[j:], n,
+					scales[j:j+width], offsets[j:j+width], output[row*n+j:row*n+j+width])
+			}
+		})
+		return nil
+	}
+	blockSizes := b.gemmBlockSizes
+	parallelizeDotGeneral(b, 1, m, n*k, func(_, rowStart, rowEnd int) {
+		gemmDequantizeInt8(blockSizes, rowEnd-rowStart, n, k,
+			lhs, rowStart*k, k,
+			rhs.Values, 0, n, scales, offsets,
+			output, rowStart*n, n)
+	})
+	return nil
+}
+
+// perChannelParams returns the scale and the offset (-zeroPoint * scale) of each of the N output channels, such
+// that a weight q of the column n is dequantized as `q * scales[n] + offsets[n]`.
+func (w *QuantizedWeightInt8) perChannelParams() (scales, offsets []float32, err error) {
+	n := w.N
+	if len(w.Scales) != 1 && len(w.Scales) != n {
+		return nil, nil, errors.Errorf("MatMulDequantizeInt8: expected 1 or %d Scales, got %d", n, len(w.Scales))
+	}
+	if len(w.ZeroPoints) > 1 && len(w.ZeroPoints) != n {
+		return nil, nil, errors.Errorf("MatMulDequantizeInt8: expected 0, 1 or %d ZeroPoints, got %d",
+			n, len(w.ZeroPoints))
+	}
+	scales = make([]float32, n)
+	offsets = make([]float32, n)
+	for j := range n {
+		scales[j] = w.Scales[0]
+		if len(w.Scales) == n {
+			scales[j] = w.Scales[j]
+		}
+		var zeroPoint int32
+		switch len(w.ZeroPoints) {
+		case 1:
+			zeroPoint = w.ZeroPoints[0]
+		case n:
+			zeroPoint = w.ZeroPoints[j]
+		}
+		offsets[j] = -float32(zeroPoint) * scales[j]
+	}
+	return scales, offsets, nil
+}
+
+// gemmDequantizeInt8 computes C += A·dequantize(B), like gemmFloat32, where B is a [k, n] int8 matrix whose
+// column j is dequantized as `q * scales[j] + offsets[j]`.
+func gemmDequantizeInt8(sizes GEMMBlockSizes, m, n, k int,
+	a []float32, aIdx, lda int,
+	b []int8, bIdx, ldb int, scales, offsets []float32,
+	c []float32, cIdx, ldc int) {
+	if m == 0 || n == 0 || k == 0 {
+		return
+	}
+	mc, kc, nc := min(sizes.MC, m), min(sizes.KC, k), min(sizes.NC, n)
+	aPackedBuf := getGemmPackedBuffer(roundUp(mc, gemmMR) * kc)
+	bPackedBuf := getGemmPackedBuffer(roundUp(nc, gemmNR) * kc)
+	defer gemmPackedPool.Put(aPackedBuf)
+	defer gemmPackedPool.Put(bPackedBuf)
+	aPacked, bPacked := *aPackedBuf, *bPackedBuf
+
+	for jc := 0; jc < n; jc += sizes.NC {
+		ncBlock := min(sizes.NC, n-jc)
+		for pc := 0; pc < k; pc += sizes.KC {
+			kcBlock := min(sizes.KC, k-pc)
+			gemmPackBDequantizeInt8(kcBlock, ncBlock, b, bIdx+pc*ldb+jc, ldb,
+				scales[jc:jc+ncBlock], offsets[jc:jc+ncBlock], bPacked)
+			for ic := 0; ic < m; ic += sizes.MC {
+				mcBlock := min(sizes.MC, m-ic)
+				gemmPackA(mcBlock, kcBlock, a, aIdx+ic*lda+pc, lda, aPacked)
+				gemmMacroKernel(mcBlock, ncBlock, kcBlock, aPacked, bPacked, c, cIdx+ic*ldc+jc, ldc)
+			}
+		}
+	}
+}
+
+// gemmPackBDequantizeInt8 packs the int8 block B[kc, nc] like gemmPackB, dequantizing the values of each column j
+// as `q * scales[j] + offsets[j]`. Full slivers are packed with AVX2, if available.
+func gemmPackBDequantizeInt8(kc, nc int, b []int8, bIdx, ldb int, scales, offsets []float32, packed []float32) {
+	packedIdx := 0
+	for jr := 0; jr < nc; jr += gemmNR {
+		nr := min(gemmNR, nc-jr)
+		sliverScales, sliverOffsets := scales[jr:jr+nr], offsets[jr:jr+nr]
+		if hasAVX2 && nr == gemmNR {
+			gemmPackBDequantizeInt8SliverAVX2(kc, b[bIdx+jr:], ldb, sliverScales, sliverOffsets, packed[packedIdx:])
+			packedIdx += kc * gemmNR
+			continue
+		}
+		for p := range kc {
+			dst := packed[packedIdx+p*gemmNR : packedIdx+(p+1)*gemmNR]
+			src := b[bIdx+p*ldb+jr : bIdx+p*ldb+jr+nr]
+			for j, q := range src {
+				dst[j] = float32(q)*sliverScales[j] + sliverOffsets[j]
+			}
+			clear(dst[nr:])
+		}
+		packedIdx += kc * gemmNR
+	}
+}
+
+// gemvDequantizeInt8Block computes c += a·dequantize(B[:, 0:len(c)]), for len(c) <= gemvNB, where the column j of
+// the int8 matrix B (with leading dimension ldb) is dequantized as `q * scales[j] + offsets[j]`.
+//
+// The products with the int8 values are accumulated first (with AVX2 if available), and the scales and offsets
+// are applied at the end: Σ_p a[p]·(q[p, j]·scale + offset) = scale·Σ_p a[p]·q[p, j] + offset·Σ_p a[p].
+func gemvDequantizeInt8Block(k int, a []float32, b []int8, ldb int, scales, offsets, c []float32) {
+	width := len(c)
+	var acc [gemvNB]float32
+	sums := acc[:width]
+	var sumA float32
+	for _, aValue := range a[:k] {
+		sumA += aValue
+	}
+	if hasAVX2 && width == gemvNB {
+		gemvDequantizeInt8AVX2(k, a, b, ldb, sums)
+	} else {
+		for p, aValue := range a[:k] {
+			row := b[p*ldb : p*ldb+width]
+			for j, q := range row {
+				sums[j] += aValue * float32(q)
+			}
+		}
+	}
+	for j, sum := range sums {
+		c[j] += sum*scales[j] + sumA*offsets[j]
+	}
+}
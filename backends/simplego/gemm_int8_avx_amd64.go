@@ -0,0 +1,57 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !noasm && amd64
+
+package simplego
+
+import (
+	"runtime"
+	"unsafe"
+)
+
+// gemvDequantizeInt8_64_avx2_asm is implemented in gemm_int8_avx_amd64.s.
+// It computes sums[0:64] += a[0:k]·B[0:k, 0:64], where B is int8 with leading dimension ldb.
+//
+//go:noescape
+func gemvDequantizeInt8_64_avx2_asm(k int64, a, b unsafe.Pointer, ldb int64, sums unsafe.Pointer)
+
+// gemmPackBDequantizeInt8Sliver16_avx2_asm is implemented in gemm_int8_avx_amd64.s.
+// It packs and dequantizes a sliver of 16 columns of the int8 B[0:kc, 0:16], see gemmPackBDequantizeInt8.
+//
+//go:noescape
+func gemmPackBDequantizeInt8Sliver16_avx2_asm(kc int64, b unsafe.Pointer, ldb int64, scales, offsets, packed unsafe.Pointer)
+
+// gemvDequantizeInt8AVX2 computes sums[0:gemvNB] += a[0:k]·B[0:k, 0:gemvNB] using AVX2, where B is int8 with
+// leading dimension ldb.
+func gemvDequantizeInt8AVX2(k int, a []float32, b []int8, ldb int, sums []float32) {
+	if k == 0 {
+		return
+	}
+	_ = a[k-1]
+	_ = b[(k-1)*ldb+gemvNB-1]
+	_ = sums[gemvNB-1]
+	gemvDequantizeInt8_64_avx2_asm(int64(k), unsafe.Pointer(&a[0]), unsafe.Pointer(&b[0]), int64(ldb), unsafe.Pointer(&sums[0]))
+	runtime.KeepAlive(a)
+	runtime.KeepAlive(b)
+	runtime.KeepAlive(sums)
+}
+
+// gemmPackBDequantizeInt8SliverAVX2 packs a sliver of gemmNR columns of the int8 B[0:kc, 0:gemmNR] using AVX2,
+// dequantizing the column j as `q * scales[j] + offsets[j]`.
+func gemmPackBDequantizeInt8SliverAVX2(kc int, b []int8, ldb int, scales, offsets, packed []float32) {
+	if kc == 0 {
+		return
+	}
+	_ = b[(kc-1)*ldb+gemmNR-1]
+	_ = scales[gemmNR-1]
+	_ = offsets[gemmNR-1]
+	_ = packed[kc*gemmNR-1]
+	gemmPackBDequantizeInt8Sliver16_avx2_asm(int64(kc), unsafe.Pointer(&b[0]), int64(ldb),
+		unsafe.Pointer(&scales[0]), unsafe.Pointer(&offsets[0]), unsafe.Pointer(&packed[0]))
+	runtime.KeepAlive(b)
+	runtime.KeepAlive(scales)
+	runtime.KeepAlive(offsets)
+	runtime.KeepAlive(packed)
+}
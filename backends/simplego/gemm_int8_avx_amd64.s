@@ -0,0 +1,108 @@
+//go:build !noasm && amd64
+
+// Kernels for the matrix multiplication with int8 quantized weights (see gemm_int8.go): the int8 values are
+// sign-extended to int32 (VPMOVSXBD) and converted to float32 (VCVTDQ2PS) on the fly.
+
+#include "textflag.h"
+
+// func gemvDequantizeInt8_64_avx2_asm(k int64, a, b unsafe.Pointer, ldb int64, sums unsafe.Pointer)
+// It computes sums[0:64] += a[0:k]·B[0:k, 0:64], where B is int8 with leading dimension ldb. The 64 sums are
+// held in 8 ymm registers (Y0-Y7).
+TEXT ·gemvDequantizeInt8_64_avx2_asm(SB), NOSPLIT, $0-40
+	MOVQ k+0(FP), CX
+	MOVQ a+8(FP), SI
+	MOVQ b+16(FP), DI
+	MOVQ ldb+24(FP), R8
+	MOVQ sums+32(FP), DX
+
+	VMOVUPS (DX), Y0
+	VMOVUPS 32(DX), Y1
+	VMOVUPS 64(DX), Y2
+	VMOVUPS 96(DX), Y3
+	VMOVUPS 128(DX), Y4
+	VMOVUPS 160(DX), Y5
+	VMOVUPS 192(DX), Y6
+	VMOVUPS 224(DX), Y7
+
+	TESTQ CX, CX
+	JZ    gemv_store
+
+gemv_loop:
+	VBROADCASTSS (SI), Y8
+	VPMOVSXBD    (DI), Y9
+	VPMOVSXBD    8(DI), Y10
+	VPMOVSXBD    16(DI), Y11
+	VPMOVSXBD    24(DI), Y12
+	VCVTDQ2PS    Y9, Y9
+	VCVTDQ2PS    Y10, Y10
+	VCVTDQ2PS    Y11, Y11
+	VCVTDQ2PS    Y12, Y12
+	VFMADD231PS  Y9, Y8, Y0
+	VFMADD231PS  Y10, Y8, Y1
+	VFMADD231PS  Y11, Y8, Y2
+	VFMADD231PS  Y12, Y8, Y3
+	VPMOVSXBD    32(DI), Y9
+	VPMOVSXBD    40(DI), Y10
+	VPMOVSXBD    48(DI), Y11
+	VPMOVSXBD    56(DI), Y12
+	VCVTDQ2PS    Y9, Y9
+	VCVTDQ2PS    Y10, Y10
+	VCVTDQ2PS    Y11, Y11
+	VCVTDQ2PS    Y12, Y12
+	VFMADD231PS  Y9, Y8, Y4
+	VFMADD231PS  Y10, Y8, Y5
+	VFMADD231PS  Y11, Y8, Y6
+	VFMADD231PS  Y12, Y8, Y7
+	ADDQ         R8, DI
+	ADDQ         $4, SI
+	DECQ         CX
+	JNZ          gemv_loop
+
+gemv_store:
+	VMOVUPS Y0, (DX)
+	VMOVUPS Y1, 32(DX)
+	VMOVUPS Y2, 64(DX)
+	VMOVUPS Y3, 96(DX)
+	VMOVUPS Y4, 128(DX)
+	VMOVUPS Y5, 160(DX)
+	VMOVUPS Y6, 192(DX)
+	VMOVUPS Y7, 224(DX)
+	VZEROUPPER
+	RET
+
+// func gemmPackBDequantizeInt8Sliver16_avx2_asm(kc int64, b unsafe.Pointer, ldb int64, scales, offsets, packed unsafe.Pointer)
+// It packs a sliver of 16 columns of the int8 B[0:kc, 0:16] (with leading dimension ldb), dequantizing the
+// column j as `q * scales[j] + offsets[j]`. The scales and offsets are held in registers (Y12-Y15).
+TEXT ·gemmPackBDequantizeInt8Sliver16_avx2_asm(SB), NOSPLIT, $0-48
+	MOVQ kc+0(FP), CX
+	MOVQ b+8(FP), SI
+	MOVQ ldb+16(FP), R8
+	MOVQ scales+24(FP), AX
+	MOVQ offsets+32(FP), BX
+	MOVQ packed+40(FP), DI
+
+	VMOVUPS (AX), Y12
+	VMOVUPS 32(AX), Y13
+	VMOVUPS (BX), Y14
+	VMOVUPS 32(BX), Y15
+
+	TESTQ CX, CX
+	JZ    pack_done
+
+pack_loop:
+	VPMOVSXBD   (SI), Y0
+	VPMOVSXBD   8(SI), Y1
+	VCVTDQ2PS   Y0, Y0
+	VCVTDQ2PS   Y1, Y1
+	VFMADD213PS Y14, Y12, Y0
+	VFMADD213PS Y15, Y13, Y1
+	VMOVUPS     Y0, (DI)
+	VMOVUPS     Y1, 32(DI)
+	ADDQ        R8, SI
+	ADDQ        $64, DI
+	DECQ        CX
+	JNZ         pack_loop
+
+pack_done:
+	VZEROUPPER
+	RET
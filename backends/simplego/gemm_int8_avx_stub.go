@@ -0,0 +1,17 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build noasm || !amd64
+
+package simplego
+
+// gemvDequantizeInt8AVX2 stub for non-AMD64 platforms.
+func gemvDequantizeInt8AVX2(k int, a []float32, b []int8, ldb int, sums []float32) {
+	panic("AVX2 not available")
+}
+
+// gemmPackBDequantizeInt8SliverAVX2 stub for non-AMD64 platforms.
+func gemmPackBDequantizeInt8SliverAVX2(kc int, b []int8, ldb int, scales, offsets, packed []float32) {
+	panic("AVX2 not available")
+}
@@ -0,0 +1,115 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/gomlx/gomlx/pkg/core/tensors"
+	"github.com/gomlx/gopjrt/dtypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatMulDequantizeInt8(t *testing.T) {
+	be, ok := backend.(*Backend)
+	if !ok {
+		t.Skip("Skipping test because backend is not a SimpleGo Backend")
+	}
+	// Use small block sizes, to exercise multiple blocks in each axis.
+	defer func(sizes GEMMBlockSizes) { require.NoError(t, be.SetGEMMBlockSizes(sizes)) }(be.GEMMBlockSizes())
+	require.NoError(t, be.SetGEMMBlockSizes(GEMMBlockSizes{MC: 8, KC: 16, NC: 32}))
+
+	rng := rand.New(rand.NewSource(42))
+	for _, simd := range []string{SIMDAuto, SIMDOff} {
+		require.NoError(t, SetSIMD(simd))
+		for _, dims := range [][3]int{{1, 1, 1}, {1, 100, 70}, {3, 17, 5}, {4, 16, 16}, {33, 65, 100}} {
+			m, n, k := dims[0], dims[1], dims[2]
+			for _, symmetric := range []bool{true, false} {
+				t.Run(fmt.Sprintf("%s/m=%d,n=%d,k=%d/symmetric=%v", simd, m, n, k, symmetric), func(t *testing.T) {
+					lhs := make([]float32, m*k)
+					for i := range lhs {
+						lhs[i] = rng.Float32()*2 - 1
+					}
+					rhsValues := make([]float32, k*n)
+					for i := range rhsValues {
+						rhsValues[i] = (rng.Float32()*2 - 0.5) * float32(1+i%n)
+					}
+					rhsQ, params, err := tensors.QuantizeInt8PerChannel(tensors.FromFlatDataAndDimensions(rhsValues, k, n), 1, symmetric)
+					require.NoError(t, err)
+					rhsDequantized, err := tensors.DequantizeInt8(rhsQ, params, dtypes.Float32)
+					require.NoError(t, err)
+					want := naiveMatMulFloat32(m, n, k, lhs, tensors.MustCopyFlatData[float32](rhsDequantized))
+
+					rhs := &QuantizedWeightInt8{
+						K: k, N: n,
+						Values:     tensors.MustCopyFlatData[int8](rhsQ),
+						Scales:     params.Scales,
+						ZeroPoints: params.ZeroPoints,
+					}
+					output := make([]float32, m*n)
+					for i := range output {
+						output[i] = 1 // It must be overwritten.
+					}
+					require.NoError(t, be.MatMulDequantizeInt8(lhs, m, rhs, output))
+					for i, value := range want {
+						require.InDeltaf(t, value, output[i], 1e-3*float64(1+i%n), "mismatch at flat index %d", i)
+					}
+				})
+			}
+		}
+	}
+	require.NoError(t, SetSIMD(SIMDAuto))
+
+	// Invalid parameters.
+	rhs := &QuantizedWeightInt8{K: 2, N: 3, Values: make([]int8, 6), Scales: []float32{1, 2}}
+	require.Error(t, be.MatMulDequantizeInt8(make([]float32, 2), 1, rhs, make([]float32, 3)))
+	rhs.Scales = []float32{1}
+	rhs.ZeroPoints = []int32{0, 1}
+	require.Error(t, be.MatMulDequantizeInt8(make([]float32, 2), 1, rhs, make([]float32, 3)))
+	rhs.ZeroPoints = nil
+	require.Error(t, be.MatMulDequantizeInt8(make([]float32, 3), 1, rhs, make([]float32, 3)))
+	require.NoError(t, be.MatMulDequantizeInt8(make([]float32, 2), 1, rhs, make([]float32, 3)))
+}
+
+func BenchmarkMatMulDequantizeInt8(b *testing.B) {
+	be, ok := backend.(*Backend)
+	if !ok {
+		b.Skip("Skipping benchmark because backend is not a SimpleGo Backend")
+	}
+	const k, n = 1024, 1024
+	rhs := &QuantizedWeightInt8{K: k, N: n, Values: make([]int8, k*n), Scales: make([]float32, n)}
+	rhsFloat32 := make([]float32, k*n)
+	for i := range rhs.Values {
+		rhs.Values[i] = int8(i%255 - 127)
+		rhsFloat32[i] = float32(rhs.Values[i]) * 0.01
+	}
+	for j := range rhs.Scales {
+		rhs.Scales[j] = 0.01
+	}
+	for _, m := range []int{1, 64} {
+		lhs := make([]float32, m*k)
+		for i := range lhs {
+			lhs[i] = float32(i%7) * 0.1
+		}
+		output := make([]float32, m*n)
+		b.Run(fmt.Sprintf("m=%d/int8", m), func(b *testing.B) {
+			for range b.N {
+				require.NoError(b, be.MatMulDequantizeInt8(lhs, m, rhs, output))
+			}
+		})
+		b.Run(fmt.Sprintf("m=%d/float32", m), func(b *testing.B) {
+			for range b.N {
+				clear(output)
+				if m == 1 {
+					gemvFloat32(k, n, lhs, 0, rhsFloat32, 0, n, output, 0)
+				} else {
+					gemmFloat32(defaultGEMMBlockSizes, m, n, k, lhs, 0, k, rhsFloat32, 0, n, output, 0, n)
+				}
+			}
+		})
+	}
+}
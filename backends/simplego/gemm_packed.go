@@ -0,0 +1,186 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"sync"
+)
+
+// PackedWeight holds a float32 weight matrix [K, N], used as the RHS of a matrix multiplication
+// [M, K] × [K, N] → [M, N], prepacked in the panel layout used by gemmFloat32.
+//
+// This avoids packing the weights on every execution, in inference where the weights never change.
+// See Backend.PackWeight.
+type PackedWeight struct {
+	// K and N are the dimensions of the original weight matrix.
+	K, N int
+
+	// sizes are the block sizes used to pack the weights, and to multiply them.
+	sizes GEMMBlockSizes
+
+	// panels holds the packed panels of B, for each block of columns (nc) and for each block of the contracting
+	// axis (kc), in that order. See gemmPackB for the layout of each panel.
+	panels [][]float32
+}
+
+// PackWeightForGEMM packs a 2D float32 weight tensor [K, N] in the GEMM panel layout, with the default block sizes.
+// It returns nil if the buffer is not a 2D float32 tensor.
+func PackWeightForGEMM(buf *Buffer) *PackedWeight {
+	return packBufferForGEMM(defaultGEMMBlockSizes, buf)
+}
+
+// packBufferForGEMM implements PackWeightForGEMM with the given block sizes.
+func packBufferForGEMM(sizes GEMMBlockSizes, buf *Buffer) *PackedWeight {
+	flat, ok := buf.flat.([]float32)
+	if !ok || buf.shape.Rank() != 2 {
+		return nil
+	}
+	return packWeightForGEMM(sizes, flat, buf.shape.Dimensions[0], buf.shape.Dimensions[1])
+}
+
+// packWeightForGEMM packs the row-major weight matrix [k, n] given by its flat values in the GEMM panel layout,
+// with the given block sizes.
+func packWeightForGEMM(sizes GEMMBlockSizes, flat []float32, k, n int) *PackedWeight {
+	pw := &PackedWeight{K: k, N: n, sizes: sizes}
+	for jc := 0; jc < n; jc += sizes.NC {
+		ncBlock := min(sizes.NC, n-jc)
+		for pc := 0; pc < k; pc += sizes.KC {
+			kcBlock := min(sizes.KC, k-pc)
+			panelSize := roundUp(ncBlock, gemmNR) * kcBlock
+			panel := makeAligned[float32](panelSize, panelSize)
+			gemmPackB(kcBlock, ncBlock, flat, pc*n+jc, n, panel)
+			pw.panels = append(pw.panels, panel)
+		}
+	}
+	return pw
+}
+
+// Bytes returns the memory used by the packed weight.
+func (pw *PackedWeight) Bytes() int64 {
+	var size int64
+	for _, panel := range pw.panels {
+		size += int64(len(panel)) * 4
+	}
+	return size
+}
+
+// gemmFloat32PackedB computes C += A·B, like gemmFloat32, but with B given by its prepacked panels: the block sizes
+// used are the ones B was packed with.
+func gemmFloat32PackedB(m int, a []float32, aIdx, lda int, b *PackedWeight, c []float32, cIdx, ldc int) {
+	n, k := b.N, b.K
+	if m == 0 || n == 0 || k == 0 {
+		return
+	}
+	sizes := b.sizes
+	mc := min(sizes.MC, m)
+	aPackedBuf := getGemmPackedBuffer(roundUp(mc, gemmMR) * min(sizes.KC, k))
+	defer gemmPackedPool.Put(aPackedBuf)
+	aPacked := *aPackedBuf
+
+	panelIdx := 0
+	for jc := 0; jc < n; jc += sizes.NC {
+		ncBlock := min(sizes.NC, n-jc)
+		for pc := 0; pc < k; pc += sizes.KC {
+			kcBlock := min(sizes.KC, k-pc)
+			bPacked := b.panels[panelIdx]
+			panelIdx++
+			for ic := 0; ic < m; ic += sizes.MC {
+				mcBlock := min(sizes.MC, m-ic)
+				gemmPackA(mcBlock, kcBlock, a, aIdx+ic*lda+pc, lda, aPacked)
+				gemmMacroKernel(mcBlock, ncBlock, kcBlock, aPacked, bPacked, c, cIdx+ic*ldc+jc, ldc)
+			}
+		}
+	}
+}
+
+// PackedWeightCache holds the weights packed with Backend.PackWeight.
+// Keys are the original buffer addresses, like PreBlockedWeightCache.
+type PackedWeightCache struct {
+	mu    sync.RWMutex
+	cache map[uintptr]*PackedWeight
+}
+
+// NewPackedWeightCache creates a new packed weight cache.
+func NewPackedWeightCache() *PackedWeightCache {
+	return &PackedWeightCache{
+		cache: make(map[uintptr]*PackedWeight),
+	}
+}
+
+// Get retrieves the packed weight for the given buffer, if available.
+func (c *PackedWeightCache) Get(buf *Buffer) *PackedWeight {
+	key := bufferKey(buf)
+	if key == 0 {
+		return nil
+	}
+	c.mu.RLock()
+	pw := c.cache[key]
+	c.mu.RUnlock()
+	return pw
+}
+
+// Set stores the packed weight for the given buffer.
+func (c *PackedWeightCache) Set(buf *Buffer, pw *PackedWeight) {
+	key := bufferKey(buf)
+	if key == 0 {
+		return
+	}
+	c.mu.Lock()
+	c.cache[key] = pw
+	c.mu.Unlock()
+}
+
+// Invalidate removes the packed weight of the buffer from the cache.
+// Call this when the underlying buffer data has changed. It is called automatically when the buffer is finalized.
+func (c *PackedWeightCache) Invalidate(buf *Buffer) {
+	key := bufferKey(buf)
+	if key == 0 {
+		return
+	}
+	c.mu.Lock()
+	delete(c.cache, key)
+	c.mu.Unlock()
+}
+
+// Clear removes all entries from the cache.
+func (c *PackedWeightCache) Clear() {
+	c.mu.Lock()
+	c.cache = make(map[uintptr]*PackedWeight)
+	c.mu.Unlock()
+}
+
+// PackWeight packs a float32 weight buffer [K, N] in the GEMM panel layout, and caches it: matrix multiplications
+// [M, K] × [K, N] that take the fast path will then skip packing the weights.
+// Call this at model load time for the weight tensors used in matmuls.
+//
+// The buffer contents must not change afterward -- or call Backend.InvalidatePackedWeight.
+//
+// It returns the packed weight, or nil if the buffer is not a 2D float32 tensor.
+func (b *Backend) PackWeight(buf *Buffer) *PackedWeight {
+	if pw := b.packedWeightCache.Get(buf); pw != nil {
+		return pw
+	}
+	pw := packBufferForGEMM(b.gemmBlockSizes, buf)
+	if pw != nil {
+		b.packedWeightCache.Set(buf, pw)
+	}
+	return pw
+}
+
+// InvalidatePackedWeight removes the packed version of the buffer, created with PackWeight.
+func (b *Backend) InvalidatePackedWeight(buf *Buffer) {
+	b.packedWeightCache.Invalidate(buf)
+}
+
+// PackedWeightStats returns statistics about the packed weight cache.
+func (b *Backend) PackedWeightStats() (count int, totalBytes int64) {
+	b.packedWeightCache.mu.RLock()
+	defer b.packedWeightCache.mu.RUnlock()
+	count = len(b.packedWeightCache.cache)
+	for _, pw := range b.packedWeightCache.cache {
+		totalBytes += pw.Bytes()
+	}
+	return
+}
@@ -0,0 +1,177 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/gomlx/gomlx/pkg/core/shapes"
+	"github.com/gomlx/gopjrt/dtypes"
+	"github.com/stretchr/testify/require"
+)
+
+// naiveMatMulFloat32 returns A[m,k]·B[k,n], accumulated in float64.
+func naiveMatMulFloat32(m, n, k int, a, b []float32) []float32 {
+	c := make([]float32, m*n)
+	for i := range m {
+		for j := range n {
+			var sum float64
+			for p := range k {
+				sum += float64(a[i*k+p]) * float64(b[p*n+j])
+			}
+			c[i*n+j] = float32(sum)
+		}
+	}
+	return c
+}
+
+func TestGEMMFloat32(t *testing.T) {
+	// Use small block sizes, to exercise multiple blocks in each axis.
+	sizes := GEMMBlockSizes{MC: 8, KC: 16, NC: 32}
+
+	rng := rand.New(rand.NewSource(42))
+	for _, simd := range []string{SIMDAuto, SIMDOff} {
+		require.NoError(t, SetSIMD(simd))
+		for _, dims := range [][3]int{{1, 1, 1}, {4, 16, 16}, {3, 17, 5}, {8, 32, 16}, {13, 70, 37}, {33, 65, 100}} {
+			m, n, k := dims[0], dims[1], dims[2]
+			t.Run(fmt.Sprintf("%s/m=%d,n=%d,k=%d", simd, m, n, k), func(t *testing.T) {
+				a := make([]float32, m*k)
+				b := make([]float32, k*n)
+				for i := range a {
+					a[i] = rng.Float32()*2 - 1
+				}
+				for i := range b {
+					b[i] = rng.Float32()*2 - 1
+				}
+				want := naiveMatMulFloat32(m, n, k, a, b)
+
+				// C starts with ones, and it is embedded in a larger matrix (ldc > n), to check that GEMM accumulates
+				// and respects the leading dimension.
+				const padding = 3
+				ldc := n + padding
+				c := make([]float32, m*ldc)
+				for i := range c {
+					c[i] = 1
+				}
+				gemmFloat32(sizes, m, n, k, a, 0, k, b, 0, n, c, 0, ldc)
+				for i := range m {
+					for j := range ldc {
+						if j >= n {
+							require.Equalf(t, float32(1), c[i*ldc+j], "padding at (%d, %d) was changed", i, j)
+							continue
+						}
+						require.InDeltaf(t, want[i*n+j]+1, c[i*ldc+j], 1e-4, "mismatch at (%d, %d)", i, j)
+					}
+				}
+			})
+		}
+	}
+	require.NoError(t, SetSIMD(SIMDAuto))
+}
+
+func BenchmarkGEMMFloat32(b *testing.B) {
+	for _, size := range []int{64, 256, 1024} {
+		m, n, k := size, size, size
+		lhs := make([]float32, m*k)
+		rhs := make([]float32, k*n)
+		out := make([]float32, m*n)
+		for i := range lhs {
+			lhs[i] = float32(i%7) * 0.1
+		}
+		for i := range rhs {
+			rhs[i] = float32(i%5) * 0.1
+		}
+		b.Run(fmt.Sprintf("%d", size), func(b *testing.B) {
+			for range b.N {
+				gemmFloat32(defaultGEMMBlockSizes, m, n, k, lhs, 0, k, rhs, 0, n, out, 0, n)
+			}
+			b.ReportMetric(2*float64(m*n*k)*float64(b.N)/b.Elapsed().Seconds()/1e9, "GFLOPS")
+		})
+	}
+}
+
+func TestPackWeight(t *testing.T) {
+	be, ok := backend.(*Backend)
+	if !ok {
+		t.Skip("Skipping test because backend is not a SimpleGo Backend")
+	}
+	defer func(sizes GEMMBlockSizes) { require.NoError(t, be.SetGEMMBlockSizes(sizes)) }(be.GEMMBlockSizes())
+	require.NoError(t, be.SetGEMMBlockSizes(GEMMBlockSizes{MC: 8, KC: 16, NC: 32}))
+
+	M, K, N := 21, 40, 70
+	lhs := be.NewBuffer(shapes.Make(dtypes.Float32, M, K))
+	rhs := be.NewBuffer(shapes.Make(dtypes.Float32, K, N))
+	lhsFlat, rhsFlat := lhs.flat.([]float32), rhs.flat.([]float32)
+	for i := range lhsFlat {
+		lhsFlat[i] = float32(i%11) * 0.1
+	}
+	for i := range rhsFlat {
+		rhsFlat[i] = float32(i%7) * 0.1
+	}
+	want := naiveMatMulFloat32(M, N, K, lhsFlat, rhsFlat)
+
+	require.Nil(t, be.PackWeight(be.NewBuffer(shapes.Make(dtypes.Float64, K, N))))
+	pw := be.PackWeight(rhs)
+	require.NotNil(t, pw)
+	require.Same(t, pw, be.PackWeight(rhs))
+	count, bytes := be.PackedWeightStats()
+	require.Equal(t, 1, count)
+	require.Equal(t, pw.Bytes(), bytes)
+
+	// Changing the block sizes after packing must not matter.
+	require.NoError(t, be.SetGEMMBlockSizes(GEMMBlockSizes{MC: 8, KC: 32, NC: 64}))
+	// Clearing the original weights checks that the packed version is the one used.
+	clear(rhsFlat)
+	params := &dotGeneralNodeData{
+		lhsContractingAxes: []int{1},
+		rhsContractingAxes: []int{0},
+		lhsBatchAxes:       []int{},
+		rhsBatchAxes:       []int{},
+		batchSize:          1,
+		lhsCrossSize:       M,
+		rhsCrossSize:       N,
+		contractingSize:    K,
+	}
+	output := be.NewBuffer(shapes.Make(dtypes.Float32, M, N))
+	output.Zeros()
+	require.True(t, canUseFastPath(lhs, rhs, params))
+	execDotGeneralFastPathFloat32(be, lhs, rhs, params, output, nil)
+	require.InDeltaSlice(t, want, output.flat.([]float32), 1e-4)
+
+	require.NoError(t, be.BufferFinalize(rhs))
+	count, _ = be.PackedWeightStats()
+	require.Equal(t, 0, count)
+}
+
+func TestGEMMBlockSizes(t *testing.T) {
+	backendIface, err := New("")
+	require.NoError(t, err)
+	be := backendIface.(*Backend)
+	defer be.Finalize()
+	require.Equal(t, defaultGEMMBlockSizes, be.GEMMBlockSizes())
+
+	require.Error(t, be.SetGEMMBlockSizes(GEMMBlockSizes{MC: 0, KC: 256, NC: 2048}))
+	require.Error(t, be.SetGEMMBlockSizes(GEMMBlockSizes{MC: 130, KC: 256, NC: 2048}))
+	require.Error(t, be.SetGEMMBlockSizes(GEMMBlockSizes{MC: 128, KC: 256, NC: 2004}))
+	require.NoError(t, be.SetGEMMBlockSizes(GEMMBlockSizes{MC: 64, KC: 100, NC: 512}))
+	require.Equal(t, GEMMBlockSizes{MC: 64, KC: 100, NC: 512}, be.GEMMBlockSizes())
+
+	// The autotuning with the "gemm_autotune" option only changes the block sizes of the new backend.
+	tunedIface, err := New("gemm_autotune")
+	require.NoError(t, err)
+	tunedBackend := tunedIface.(*Backend)
+	defer tunedBackend.Finalize()
+	tuned := tunedBackend.GEMMBlockSizes()
+	require.Contains(t, gemmAutotuneKCs, tuned.KC)
+	require.Contains(t, gemmAutotuneMCs, tuned.MC)
+	require.Contains(t, gemmAutotuneNCs, tuned.NC)
+	require.Equal(t, GEMMBlockSizes{MC: 64, KC: 100, NC: 512}, be.GEMMBlockSizes())
+
+	// The result is cached.
+	require.Equal(t, tuned, be.AutotuneGEMM())
+	require.Equal(t, tuned, be.GEMMBlockSizes())
+}
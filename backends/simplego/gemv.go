@@ -0,0 +1,77 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+// gemvNB is the number of columns of B (and of c) processed in each block by gemvFloat32: the block of c is
+// accumulated in registers while streaming over the rows of B.
+const gemvNB = 64
+
+// gemvFloat32 computes c += a·B, where a is a vector of size k, B is a [k, n] row-major matrix and c is a vector
+// of size n. B is given by its flat slice, the index of its first element and its leading dimension (the stride
+// between rows).
+//
+// This is the case of single-token decoder steps, where packing B (as gemmFloat32 does) would cost as much as the
+// multiplication itself, since each element of B is used only once.
+func gemvFloat32(k, n int,
+	a []float32, aIdx int,
+	b []float32, bIdx, ldb int,
+	c []float32, cIdx int) {
+	if n == 0 || k == 0 {
+		return
+	}
+	a = a[aIdx : aIdx+k]
+	j := 0
+	if hasAVX512 || hasAVX2 {
+		for ; j+gemvNB <= n; j += gemvNB {
+			gemvMicroKernelFloat32(k, a, b[bIdx+j:], ldb, c[cIdx+j:cIdx+j+gemvNB])
+		}
+	}
+	for ; j < n; j += gemvNB {
+		gemvBlockGo(k, a, b[bIdx+j:], ldb, c[cIdx+j:cIdx+min(j+gemvNB, n)])
+	}
+}
+
+// gemvMicroKernelFloat32 computes c[0:gemvNB] += a·B[:, 0:gemvNB], using the SIMD kernel enabled.
+func gemvMicroKernelFloat32(k int, a, b []float32, ldb int, c []float32) {
+	switch {
+	case hasAVX512:
+		gemvMicroKernelAVX512(k, a, b, ldb, c)
+	case hasAVX2:
+		gemvMicroKernelAVX2(k, a, b, ldb, c)
+	default:
+		gemvBlockGo(k, a, b, ldb, c)
+	}
+}
+
+// gemvBlockGo computes c += a·B[:, 0:len(c)], for len(c) <= gemvNB, in pure Go.
+//
+// It accumulates 4 rows of B at a time, so the partial sums are loaded and stored once per 4 rows.
+func gemvBlockGo(k int, a, b []float32, ldb int, c []float32) {
+	width := len(c)
+	var acc [gemvNB]float32
+	sums := acc[:width]
+	a = a[:k]
+	p := 0
+	for ; p+4 <= k; p += 4 {
+		a0, a1, a2, a3 := a[p], a[p+1], a[p+2], a[p+3]
+		row0 := b[p*ldb : p*ldb+width]
+		row1 := b[(p+1)*ldb : (p+1)*ldb+width]
+		row2 := b[(p+2)*ldb : (p+2)*ldb+width]
+		row3 := b[(p+3)*ldb : (p+3)*ldb+width]
+		for j := range sums {
+			sums[j] += a0*row0[j] + a1*row1[j] + a2*row2[j] + a3*row3[j]
+		}
+	}
+	for ; p < k; p++ {
+		aValue := a[p]
+		row := b[p*ldb : p*ldb+width]
+		for j, bValue := range row {
+			sums[j] += aValue * bValue
+		}
+	}
+	for j, sum := range sums {
+		c[j] += sum
+	}
+}
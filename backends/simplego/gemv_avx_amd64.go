@@ -0,0 +1,52 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !noasm && amd64
+
+package simplego
+
+import (
+	"runtime"
+	"unsafe"
+)
+
+// gemvMicroKernel64_avx512_asm is implemented in gemv_avx_amd64.s.
+// It computes c[0:64] += a[0:k]·B[0:k, 0:64], where B has leading dimension ldb, see gemvMicroKernelFloat32.
+//
+//go:noescape
+func gemvMicroKernel64_avx512_asm(k int64, a, b unsafe.Pointer, ldb int64, c unsafe.Pointer)
+
+// gemvMicroKernel64_avx2_asm is implemented in gemv_avx_amd64.s.
+// Same as gemvMicroKernel64_avx512_asm, using AVX2 and FMA.
+//
+//go:noescape
+func gemvMicroKernel64_avx2_asm(k int64, a, b unsafe.Pointer, ldb int64, c unsafe.Pointer)
+
+// gemvMicroKernelAVX512 is the AVX-512 version of gemvMicroKernelFloat32.
+func gemvMicroKernelAVX512(k int, a, b []float32, ldb int, c []float32) {
+	if k == 0 {
+		return
+	}
+	_ = a[k-1]
+	_ = b[(k-1)*ldb+gemvNB-1]
+	_ = c[gemvNB-1]
+	gemvMicroKernel64_avx512_asm(int64(k), unsafe.Pointer(&a[0]), unsafe.Pointer(&b[0]), int64(ldb), unsafe.Pointer(&c[0]))
+	runtime.KeepAlive(a)
+	runtime.KeepAlive(b)
+	runtime.KeepAlive(c)
+}
+
+// gemvMicroKernelAVX2 is the AVX2 version of gemvMicroKernelFloat32.
+func gemvMicroKernelAVX2(k int, a, b []float32, ldb int, c []float32) {
+	if k == 0 {
+		return
+	}
+	_ = a[k-1]
+	_ = b[(k-1)*ldb+gemvNB-1]
+	_ = c[gemvNB-1]
+	gemvMicroKernel64_avx2_asm(int64(k), unsafe.Pointer(&a[0]), unsafe.Pointer(&b[0]), int64(ldb), unsafe.Pointer(&c[0]))
+	runtime.KeepAlive(a)
+	runtime.KeepAlive(b)
+	runtime.KeepAlive(c)
+}
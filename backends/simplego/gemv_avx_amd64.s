@@ -0,0 +1,118 @@
+//go:build !noasm && amd64
+
+// GEMV micro-kernels for AMD64: they compute c[0:64] += a·B[:, 0:64], streaming over the k rows of B
+// while the 64 values of c are held in registers.
+
+#include "textflag.h"
+
+// func gemvMicroKernel64_avx512_asm(k int64, a, b unsafe.Pointer, ldb int64, c unsafe.Pointer)
+// The 64 values of c are held in 4 zmm registers. The rows of B are unrolled by 2, with separate
+// accumulators (Z0-Z3 and Z4-Z7) to hide the FMA latency.
+TEXT ·gemvMicroKernel64_avx512_asm(SB), NOSPLIT, $0-40
+	MOVQ k+0(FP), CX
+	MOVQ a+8(FP), SI
+	MOVQ b+16(FP), DI
+	MOVQ ldb+24(FP), R8
+	MOVQ c+32(FP), DX
+	SHLQ $2, R8
+
+	VMOVUPS (DX), Z0
+	VMOVUPS 64(DX), Z1
+	VMOVUPS 128(DX), Z2
+	VMOVUPS 192(DX), Z3
+	VPXORD  Z4, Z4, Z4
+	VPXORD  Z5, Z5, Z5
+	VPXORD  Z6, Z6, Z6
+	VPXORD  Z7, Z7, Z7
+
+	CMPQ CX, $2
+	JL   avx512_tail
+
+avx512_loop2:
+	VBROADCASTSS (SI), Z8
+	VBROADCASTSS 4(SI), Z9
+	VFMADD231PS  (DI), Z8, Z0
+	VFMADD231PS  64(DI), Z8, Z1
+	VFMADD231PS  128(DI), Z8, Z2
+	VFMADD231PS  192(DI), Z8, Z3
+	ADDQ         R8, DI
+	VFMADD231PS  (DI), Z9, Z4
+	VFMADD231PS  64(DI), Z9, Z5
+	VFMADD231PS  128(DI), Z9, Z6
+	VFMADD231PS  192(DI), Z9, Z7
+	ADDQ         R8, DI
+	ADDQ         $8, SI
+	SUBQ         $2, CX
+	CMPQ         CX, $2
+	JGE          avx512_loop2
+
+avx512_tail:
+	TESTQ        CX, CX
+	JZ           avx512_store
+	VBROADCASTSS (SI), Z8
+	VFMADD231PS  (DI), Z8, Z0
+	VFMADD231PS  64(DI), Z8, Z1
+	VFMADD231PS  128(DI), Z8, Z2
+	VFMADD231PS  192(DI), Z8, Z3
+
+avx512_store:
+	VADDPS  Z4, Z0, Z0
+	VADDPS  Z5, Z1, Z1
+	VADDPS  Z6, Z2, Z2
+	VADDPS  Z7, Z3, Z3
+	VMOVUPS Z0, (DX)
+	VMOVUPS Z1, 64(DX)
+	VMOVUPS Z2, 128(DX)
+	VMOVUPS Z3, 192(DX)
+	VZEROUPPER
+	RET
+
+// func gemvMicroKernel64_avx2_asm(k int64, a, b unsafe.Pointer, ldb int64, c unsafe.Pointer)
+// The 64 values of c are held in 8 ymm registers (Y0-Y7), which are already enough independent
+// accumulators to hide the FMA latency.
+TEXT ·gemvMicroKernel64_avx2_asm(SB), NOSPLIT, $0-40
+	MOVQ k+0(FP), CX
+	MOVQ a+8(FP), SI
+	MOVQ b+16(FP), DI
+	MOVQ ldb+24(FP), R8
+	MOVQ c+32(FP), DX
+	SHLQ $2, R8
+
+	VMOVUPS (DX), Y0
+	VMOVUPS 32(DX), Y1
+	VMOVUPS 64(DX), Y2
+	VMOVUPS 96(DX), Y3
+	VMOVUPS 128(DX), Y4
+	VMOVUPS 160(DX), Y5
+	VMOVUPS 192(DX), Y6
+	VMOVUPS 224(DX), Y7
+
+	TESTQ CX, CX
+	JZ    avx2_store
+
+avx2_loop:
+	VBROADCASTSS (SI), Y8
+	VFMADD231PS  (DI), Y8, Y0
+	VFMADD231PS  32(DI), Y8, Y1
+	VFMADD231PS  64(DI), Y8, Y2
+	VFMADD231PS  96(DI), Y8, Y3
+	VFMADD231PS  128(DI), Y8, Y4
+	VFMADD231PS  160(DI), Y8, Y5
+	VFMADD231PS  192(DI), Y8, Y6
+	VFMADD231PS  224(DI), Y8, Y7
+	ADDQ         R8, DI
+	ADDQ         $4, SI
+	DECQ         CX
+	JNZ          avx2_loop
+
+avx2_store:
+	VMOVUPS Y0, (DX)
+	VMOVUPS Y1, 32(DX)
+	VMOVUPS Y2, 64(DX)
+	VMOVUPS Y3, 96(DX)
+	VMOVUPS Y4, 128(DX)
+	VMOVUPS Y5, 160(DX)
+	VMOVUPS Y6, 192(DX)
+	VMOVUPS Y7, 224(DX)
+	VZEROUPPER
+	RET
@@ -0,0 +1,17 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build noasm || !amd64
+
+package simplego
+
+// gemvMicroKernelAVX512 stub for non-AMD64 platforms.
+func gemvMicroKernelAVX512(k int, a, b []float32, ldb int, c []float32) {
+	panic("AVX-512 not available")
+}
+
+// gemvMicroKernelAVX2 stub for non-AMD64 platforms.
+func gemvMicroKernelAVX2(k int, a, b []float32, ldb int, c []float32) {
+	panic("AVX2 not available")
+}
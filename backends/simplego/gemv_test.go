@@ -0,0 +1,84 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGEMVFloat32(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	simdOptions := []string{SIMDAuto, SIMDOff}
+	if slices.Contains(AvailableSIMD(), SIMDAVX2) {
+		// Exercise the AVX2 kernel also in machines with AVX-512.
+		simdOptions = append(simdOptions, SIMDAVX2)
+	}
+	for _, simd := range simdOptions {
+		require.NoError(t, SetSIMD(simd))
+		for _, dims := range [][2]int{{1, 1}, {5, 64}, {17, 65}, {64, 128}, {33, 200}} {
+			k, n := dims[0], dims[1]
+			t.Run(fmt.Sprintf("%s/k=%d,n=%d", simd, k, n), func(t *testing.T) {
+				// B is embedded in a larger matrix (ldb > n), to check that GEMV respects the leading dimension.
+				const padding = 3
+				ldb := n + padding
+				a := make([]float32, k)
+				b := make([]float32, k*ldb)
+				bDense := make([]float32, k*n)
+				for i := range a {
+					a[i] = rng.Float32()*2 - 1
+				}
+				for p := range k {
+					for j := range n {
+						b[p*ldb+j] = rng.Float32()*2 - 1
+						bDense[p*n+j] = b[p*ldb+j]
+					}
+				}
+				want := naiveMatMulFloat32(1, n, k, a, bDense)
+
+				// c starts with ones, and it has extra values at the end, to check that GEMV accumulates and
+				// doesn't write past n.
+				c := make([]float32, n+padding)
+				for i := range c {
+					c[i] = 1
+				}
+				gemvFloat32(k, n, a, 0, b, 0, ldb, c, 0)
+				for j := range c {
+					if j >= n {
+						require.Equalf(t, float32(1), c[j], "padding at %d was changed", j)
+						continue
+					}
+					require.InDeltaf(t, want[j]+1, c[j], 1e-4, "mismatch at %d", j)
+				}
+			})
+		}
+	}
+	require.NoError(t, SetSIMD(SIMDAuto))
+}
+
+func BenchmarkGEMVFloat32(b *testing.B) {
+	for _, size := range []int{256, 1024, 4096} {
+		k, n := size, size
+		lhs := make([]float32, k)
+		rhs := make([]float32, k*n)
+		out := make([]float32, n)
+		for i := range lhs {
+			lhs[i] = float32(i%7) * 0.1
+		}
+		for i := range rhs {
+			rhs[i] = float32(i%5) * 0.1
+		}
+		b.Run(fmt.Sprintf("%d", size), func(b *testing.B) {
+			for range b.N {
+				gemvFloat32(k, n, lhs, 0, rhs, 0, n, out, 0)
+			}
+			b.ReportMetric(2*float64(k*n)*float64(b.N)/b.Elapsed().Seconds()/1e9, "GFLOPS")
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"reflect"
+
+	"github.com/gomlx/gopjrt/dtypes"
+	"github.com/pkg/errors"
+)
+
+// HugePagesPolicy defines whether the memory of large buffers (e.g. the weights and the KV caches of large models)
+// is backed by huge pages, see Backend.SetHugePagesPolicy.
+//
+// Huge pages (2MB on x86-64 and most arm64 Linux kernels, instead of 4KB) reduce the misses of the TLB (the
+// cache of the translations of virtual addresses) when scanning multi-GB weights.
+type HugePagesPolicy int
+
+const (
+	// HugePagesOff leaves the pages to the operating system (on Linux, transparent huge pages may still be used,
+	// depending on /sys/kernel/mm/transparent_hugepage/enabled). This is the default.
+	HugePagesOff HugePagesPolicy = iota
+
+	// HugePagesTransparent aligns large buffers to the huge page size and advises the kernel to back them with
+	// transparent huge pages (madvise(MADV_HUGEPAGE)). It only has effect if transparent huge pages are enabled
+	// as "always" or "madvise".
+	HugePagesTransparent
+
+	// HugePagesExplicit allocates large buffers from the pool of huge pages reserved by the administrator
+	// (mmap(MAP_HUGETLB), see /proc/sys/vm/nr_hugepages), which are guaranteed to be huge pages. If there are not
+	// enough free huge pages, it falls back to HugePagesTransparent.
+	//
+	// The memory is outside the Go heap, and it is unmapped when the buffer is garbage collected: so the slices
+	// returned by Backend.BufferData must not be used after the buffer is finalized (as documented there).
+	HugePagesExplicit
+)
+
+// hugePageSize is the size of the huge pages assumed: 2MB is the default on Linux for x86-64 and for arm64 with
+// 4KB base pages.
+const hugePageSize = 2 << 20
+
+// hugePagesMinBufferBytes is the minimum size of the buffers backed by huge pages: smaller buffers would waste
+// too much memory rounding up to the huge pages.
+const hugePagesMinBufferBytes = 32 << 20
+
+// String implements fmt.Stringer, with the values used in the "huge_pages" configuration.
+func (p HugePagesPolicy) String() string {
+	switch p {
+	case HugePagesOff:
+		return "off"
+	case HugePagesTransparent:
+		return "transparent"
+	case HugePagesExplicit:
+		return "explicit"
+	default:
+		return "unknown"
+	}
+}
+
+// parseHugePagesPolicy parses the value of the "huge_pages" configuration.
+func parseHugePagesPolicy(value string) (HugePagesPolicy, error) {
+	for _, policy := range []HugePagesPolicy{HugePagesOff, HugePagesTransparent, HugePagesExplicit} {
+		if value == policy.String() {
+			return policy, nil
+		}
+	}
+	return HugePagesOff, errors.Errorf("invalid value %q for huge pages policy: valid values are off, transparent or explicit",
+		value)
+}
+
+// SetHugePagesPolicy sets whether the memory of large buffers (of at least 32MB) is backed by huge pages. It can
+// also be set with the "huge_pages=off|transparent|explicit" configuration, see New.
+//
+// It is only implemented for Linux, elsewhere it has no effect. It should be set before any executions: it only
+// affects the buffers allocated afterward.
+func (b *Backend) SetHugePagesPolicy(policy HugePagesPolicy) error {
+	if policy != HugePagesOff && policy != HugePagesTransparent && policy != HugePagesExplicit {
+		return errors.Errorf("SetHugePagesPolicy: invalid policy %d", policy)
+	}
+	b.hugePagesPolicy = policy
+	b.bufferPool.hugePages = policy
+	return nil
+}
+
+// makeHugePagesFlat is like makeAlignedFlat, but backed by huge pages, according to the policy. It returns false
+// if the huge pages are not available (or not implemented), in which case the caller should allocate the flat
+// normally.
+//
+// The release function must be called once the flat is no longer used, it is nil if the memory is managed by the
+// garbage collector.
+func makeHugePagesFlat(policy HugePagesPolicy, dtype dtypes.DType, length, capacity int) (
+	flat reflect.Value, release func(), ok bool) {
+	goType := dtype.GoType()
+	ptr, release, ok := allocateHugePages(policy, capacity*int(goType.Size()))
+	if !ok {
+		return reflect.Value{}, nil, false
+	}
+	return reflect.SliceAt(goType, ptr, capacity).Slice(0, length), release, true
+}
@@ -0,0 +1,14 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+
+package simplego
+
+import "unsafe"
+
+// allocateHugePages is not implemented outside Linux.
+func allocateHugePages(policy HugePagesPolicy, numBytes int) (ptr unsafe.Pointer, release func(), ok bool) {
+	return nil, nil, false
+}
@@ -0,0 +1,50 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux
+
+package simplego
+
+import (
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"k8s.io/klog/v2"
+)
+
+// logHugeTLBFallback logs (once) that the explicit huge pages are not available.
+var logHugeTLBFallback sync.Once
+
+// allocateHugePages allocates numBytes aligned to hugePageSize, backed by huge pages according to the policy.
+//
+// With HugePagesExplicit the memory is mapped with MAP_HUGETLB, and release unmaps it. If it fails (typically
+// because there are not enough huge pages reserved), or with HugePagesTransparent, the memory is allocated in the
+// Go heap and advised with MADV_HUGEPAGE: it is best-effort, the kernel may still use normal pages.
+func allocateHugePages(policy HugePagesPolicy, numBytes int) (ptr unsafe.Pointer, release func(), ok bool) {
+	if policy == HugePagesOff {
+		return nil, nil, false
+	}
+	size := roundUp(numBytes, hugePageSize)
+	if policy == HugePagesExplicit {
+		mapping, err := syscall.Mmap(-1, 0, size, syscall.PROT_READ|syscall.PROT_WRITE,
+			syscall.MAP_PRIVATE|syscall.MAP_ANONYMOUS|syscall.MAP_HUGETLB)
+		if err == nil {
+			return unsafe.Pointer(unsafe.SliceData(mapping)), func() { _ = syscall.Munmap(mapping) }, true
+		}
+		logHugeTLBFallback.Do(func() {
+			klog.V(1).Infof("SimpleGo: failed to allocate explicit huge pages (see /proc/sys/vm/nr_hugepages), "+
+				"using transparent huge pages instead: %v", err)
+		})
+	}
+
+	// Transparent huge pages: the kernel only uses huge pages for the aligned huge pages of the range.
+	raw := make([]byte, size+hugePageSize)
+	offset := -uintptr(unsafe.Pointer(unsafe.SliceData(raw))) & (hugePageSize - 1)
+	aligned := raw[offset : offset+uintptr(size)]
+	if err := syscall.Madvise(aligned, syscall.MADV_HUGEPAGE); err != nil {
+		klog.V(2).Infof("SimpleGo: failed to advise transparent huge pages: %v", err)
+	}
+	return unsafe.Pointer(unsafe.SliceData(aligned)), nil, true
+}
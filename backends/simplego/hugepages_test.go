@@ -0,0 +1,57 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"runtime"
+	"testing"
+	"unsafe"
+
+	"github.com/gomlx/gopjrt/dtypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHugePages(t *testing.T) {
+	for _, value := range []string{"off", "transparent", "explicit"} {
+		policy, err := parseHugePagesPolicy(value)
+		require.NoError(t, err)
+		require.Equal(t, value, policy.String())
+	}
+	_, err := parseHugePagesPolicy("always")
+	require.Error(t, err)
+	_, err = New("huge_pages=always")
+	require.Error(t, err)
+
+	for _, policy := range []HugePagesPolicy{HugePagesTransparent, HugePagesExplicit} {
+		t.Run(policy.String(), func(t *testing.T) {
+			backendIface, err := New("huge_pages=" + policy.String())
+			require.NoError(t, err)
+			be := backendIface.(*Backend)
+			defer be.Finalize()
+			require.Equal(t, policy, be.hugePagesPolicy)
+			require.Equal(t, policy, be.bufferPool.hugePages)
+			require.Error(t, be.SetHugePagesPolicy(HugePagesPolicy(7)))
+
+			// Small buffers are not affected.
+			small := be.getBuffer(dtypes.Float32, 1024)
+			require.Zero(t, be.BufferPoolStats().HugePagesAllocs)
+			be.putBuffer(small)
+
+			buf := be.getBuffer(dtypes.Float32, hugePagesMinBufferBytes/4)
+			flat := buf.flat.([]float32)
+			for i := range flat {
+				flat[i] = float32(i)
+			}
+			require.Equal(t, float32(len(flat)-1), flat[len(flat)-1])
+			if runtime.GOOS != "linux" {
+				require.Zero(t, be.BufferPoolStats().HugePagesAllocs)
+				return
+			}
+			require.Equal(t, int64(1), be.BufferPoolStats().HugePagesAllocs)
+			require.Zero(t, uintptr(unsafe.Pointer(&flat[0]))%hugePageSize)
+			be.putBuffer(buf)
+		})
+	}
+}
@@ -0,0 +1,24 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build darwin && cgo && accelerate
+
+package cblas
+
+/*
+#cgo CFLAGS: -DACCELERATE_NEW_LAPACK
+#cgo LDFLAGS: -framework Accelerate
+#include <Accelerate/Accelerate.h>
+
+// gomlx_sgemm calls cblas_sgemm for row-major matrices, with A not transposed.
+void gomlx_sgemm(int transB, int m, int n, int k, const float *a, int lda, const float *b, int ldb,
+		float *c, int ldc) {
+	cblas_sgemm(CblasRowMajor, CblasNoTrans, transB ? CblasTrans : CblasNoTrans, m, n, k,
+			1.0f, a, lda, b, ldb, 0.0f, c, ldc);
+}
+*/
+import "C"
+
+// Library is the name of the library linked.
+const Library = Accelerate
@@ -0,0 +1,34 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build cgo && (openblas || mkl) && !(darwin && accelerate)
+
+package cblas
+
+/*
+#cgo openblas,!mkl LDFLAGS: -lopenblas
+#cgo mkl LDFLAGS: -lmkl_rt
+#cgo mkl CFLAGS: -DGOMLX_MKL
+
+#ifdef GOMLX_MKL
+#include <mkl_cblas.h>
+#define GOMLX_BLAS_LIBRARY "MKL"
+#else
+#include <cblas.h>
+#define GOMLX_BLAS_LIBRARY "OpenBLAS"
+#endif
+
+static const char *gomlx_blas_library(void) { return GOMLX_BLAS_LIBRARY; }
+
+// gomlx_sgemm calls cblas_sgemm for row-major matrices, with A not transposed.
+void gomlx_sgemm(int transB, int m, int n, int k, const float *a, int lda, const float *b, int ldb,
+		float *c, int ldc) {
+	cblas_sgemm(CblasRowMajor, CblasNoTrans, transB ? CblasTrans : CblasNoTrans, m, n, k,
+			1.0f, a, lda, b, ldb, 0.0f, c, ldc);
+}
+*/
+import "C"
+
+// Library is the name of the library linked.
+var Library = C.GoString(C.gomlx_blas_library())
@@ -0,0 +1,23 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package cblas binds the cblas_sgemm of an external BLAS library for the SimpleGo backend. It is a separate
+// package because Go doesn't allow cgo in packages with Go assembly files, like simplego.
+//
+// The library is selected with build tags, and it requires cgo:
+//
+//   - "accelerate" (macOS only): Apple's Accelerate framework, that uses the AMX units of Apple Silicon.
+//   - "openblas": OpenBLAS.
+//   - "mkl": Intel MKL.
+//
+// The compiler and linker flags to find OpenBLAS and MKL can be given with CGO_CFLAGS and CGO_LDFLAGS.
+// Without any of the tags (or without cgo), Library is "" and Sgemm panics.
+package cblas
+
+// Names of the libraries, see Library.
+const (
+	Accelerate = "Accelerate"
+	OpenBLAS   = "OpenBLAS"
+	MKL        = "MKL"
+)
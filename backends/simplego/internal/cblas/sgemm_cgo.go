@@ -0,0 +1,35 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build cgo && (openblas || mkl || (darwin && accelerate))
+
+package cblas
+
+// // Defined along with the includes of the library.
+// void gomlx_sgemm(int transB, int m, int n, int k, const float *a, int lda, const float *b, int ldb, float *c, int ldc);
+import "C"
+
+import (
+	"unsafe"
+)
+
+// Sgemm computes C = A·B (or A·Bᵀ if transB) with cblas_sgemm, where A is [m, k], B is [k, n] (or [n, k]) and C is
+// [m, n], all row-major with the given leading dimensions.
+func Sgemm(transB bool, m, n, k int, a []float32, lda int, b []float32, ldb int, c []float32, ldc int) {
+	if m == 0 || n == 0 {
+		return
+	}
+	if k == 0 {
+		clear(c[:(m-1)*ldc+n])
+		return
+	}
+	var cTransB C.int
+	if transB {
+		cTransB = 1
+	}
+	C.gomlx_sgemm(cTransB, C.int(m), C.int(n), C.int(k),
+		(*C.float)(unsafe.Pointer(&a[0])), C.int(lda),
+		(*C.float)(unsafe.Pointer(&b[0])), C.int(ldb),
+		(*C.float)(unsafe.Pointer(&c[0])), C.int(ldc))
+}
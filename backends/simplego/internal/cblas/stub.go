@@ -0,0 +1,15 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !(cgo && (openblas || mkl || (darwin && accelerate)))
+
+package cblas
+
+// Library is the name of the library linked: none.
+const Library = ""
+
+// Sgemm is not available without a library.
+func Sgemm(transB bool, m, n, k int, a []float32, lda int, b []float32, ldb int, c []float32, ldc int) {
+	panic("cblas: no BLAS library linked")
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// NUMAPolicy defines how the backend places the memory of the buffers and runs the DotGeneral workers on servers
+// with multiple NUMA nodes (e.g. multi-socket servers), see Backend.SetNUMAPolicy.
+type NUMAPolicy int
+
+const (
+	// NUMAOff leaves the placement of the memory and of the workers to the operating system. This is the default.
+	NUMAOff NUMAPolicy = iota
+
+	// NUMALocal spreads the memory of large buffers across the NUMA nodes, in contiguous blocks (the first
+	// 1/numNodes of the buffer on the first node, and so on), and pins each DotGeneral worker to the node owning
+	// the block of rows it works on. So the workers read their rows of the LHS and write their rows of the output
+	// without crossing the interconnect between the nodes.
+	//
+	// It is only implemented for Linux, and it has no effect if there is only one NUMA node.
+	NUMALocal
+)
+
+// numaMinBufferBytes is the minimum size of the buffers spread across the NUMA nodes with NUMALocal.
+const numaMinBufferBytes = 4 << 20
+
+// SetNUMAPolicy sets how the memory of the buffers and the DotGeneral workers are placed on servers with multiple
+// NUMA nodes. It can also be set with the "numa" configuration, see New.
+//
+// It should be set before any executions: it only affects the buffers allocated afterward.
+func (b *Backend) SetNUMAPolicy(policy NUMAPolicy) error {
+	if policy != NUMAOff && policy != NUMALocal {
+		return errors.Errorf("SetNUMAPolicy: invalid policy %d", policy)
+	}
+	b.numaPolicy = policy
+	b.bufferPool.numaNodes = 0
+	if policy == NUMALocal {
+		b.bufferPool.numaNodes = len(getNUMATopology())
+	}
+	return nil
+}
+
+// NUMANodes returns the number of NUMA nodes found in the system, or 0 if the topology is not known.
+func NUMANodes() int {
+	return len(getNUMATopology())
+}
+
+// getNUMATopology returns the CPUs of each NUMA node, or nil if not known.
+var getNUMATopology = sync.OnceValue(readNUMATopology)
+
+// numaNodeForRows returns the node owning the rows [start, end) of a total of numRows, when they are spread in
+// contiguous blocks across numNodes.
+func numaNodeForRows(start, end, numRows, numNodes int) int {
+	return min((start+end)/2*numNodes/numRows, numNodes-1)
+}
+
+// parseCPUList parses a list of CPUs in the format used by Linux (e.g.: "0-3,8-11,16").
+func parseCPUList(list string) ([]int, error) {
+	var cpus []int
+	list = strings.TrimSpace(list)
+	if list == "" {
+		return nil, nil
+	}
+	for _, part := range strings.Split(list, ",") {
+		first, last, isRange := strings.Cut(part, "-")
+		firstCPU, err := strconv.Atoi(first)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid CPU list %q", list)
+		}
+		lastCPU := firstCPU
+		if isRange {
+			lastCPU, err = strconv.Atoi(last)
+			if err != nil || lastCPU < firstCPU {
+				return nil, errors.Errorf("invalid range %q in CPU list %q", part, list)
+			}
+		}
+		for cpu := firstCPU; cpu <= lastCPU; cpu++ {
+			cpus = append(cpus, cpu)
+		}
+	}
+	return cpus, nil
+}
@@ -0,0 +1,18 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+
+package simplego
+
+import "unsafe"
+
+// readNUMATopology is not implemented outside Linux.
+func readNUMATopology() [][]int { return nil }
+
+// pinToNUMANode is not implemented outside Linux.
+func pinToNUMANode(node int) (unpin func()) { return func() {} }
+
+// distributeOverNUMANodes is not implemented outside Linux.
+func distributeOverNUMANodes(ptr unsafe.Pointer, numBytes, numNodes int) {}
@@ -0,0 +1,121 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux
+
+package simplego
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"k8s.io/klog/v2"
+)
+
+// Linux memory policy constants, from linux/mempolicy.h.
+const (
+	mpolPreferred = 1
+	mpolMFMove    = 1 << 1
+)
+
+// readNUMATopology reads the CPUs of each NUMA node from sysfs.
+func readNUMATopology() [][]int {
+	paths, err := filepath.Glob("/sys/devices/system/node/node[0-9]*")
+	if err != nil || len(paths) == 0 {
+		return nil
+	}
+	var topology [][]int
+	for _, path := range paths {
+		node, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(path), "node"))
+		if err != nil {
+			continue
+		}
+		cpuList, err := os.ReadFile(filepath.Join(path, "cpulist"))
+		if err != nil {
+			klog.V(1).Infof("SimpleGo: failed to read the CPUs of NUMA node %d: %v", node, err)
+			return nil
+		}
+		cpus, err := parseCPUList(string(cpuList))
+		if err != nil {
+			klog.V(1).Infof("SimpleGo: failed to parse the CPUs of NUMA node %d: %v", node, err)
+			return nil
+		}
+		for len(topology) <= node {
+			topology = append(topology, nil)
+		}
+		topology[node] = cpus
+	}
+	return topology
+}
+
+// cpuMask is a Linux cpu_set_t.
+type cpuMask [16]uint64
+
+// pinToNUMANode locks the current goroutine to its thread, and restricts the thread to the CPUs of the NUMA node.
+// It returns a function that restores the previous CPU affinity and unlocks the thread.
+//
+// It is best-effort: if the affinity can't be changed, the goroutine runs anywhere.
+func pinToNUMANode(node int) (unpin func()) {
+	topology := getNUMATopology()
+	if node >= len(topology) || len(topology[node]) == 0 {
+		return func() {}
+	}
+	var previous, mask cpuMask
+	for _, cpu := range topology[node] {
+		if cpu < len(mask)*64 {
+			mask[cpu/64] |= 1 << (cpu % 64)
+		}
+	}
+	runtime.LockOSThread()
+	if schedAffinity(syscall.SYS_SCHED_GETAFFINITY, &previous) != nil {
+		runtime.UnlockOSThread()
+		return func() {}
+	}
+	if schedAffinity(syscall.SYS_SCHED_SETAFFINITY, &mask) != nil {
+		runtime.UnlockOSThread()
+		return func() {}
+	}
+	return func() {
+		_ = schedAffinity(syscall.SYS_SCHED_SETAFFINITY, &previous)
+		runtime.UnlockOSThread()
+	}
+}
+
+// schedAffinity gets or sets the CPU affinity of the current thread.
+func schedAffinity(trap uintptr, mask *cpuMask) error {
+	_, _, errno := syscall.RawSyscall(trap, 0, unsafe.Sizeof(*mask), uintptr(unsafe.Pointer(mask)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// distributeOverNUMANodes moves the memory [ptr, ptr+numBytes) to numNodes NUMA nodes, in contiguous blocks: the
+// first 1/numNodes of the memory to the first node, and so on. Pages crossing the blocks boundaries are left
+// where they are.
+//
+// It is best-effort: errors are ignored.
+func distributeOverNUMANodes(ptr unsafe.Pointer, numBytes, numNodes int) {
+	pageSize := uintptr(os.Getpagesize())
+	base := uintptr(ptr)
+	for node := range min(numNodes, 64) {
+		start := (base + uintptr(node*numBytes/numNodes) + pageSize - 1) &^ (pageSize - 1)
+		end := (base + uintptr((node+1)*numBytes/numNodes)) &^ (pageSize - 1)
+		if end <= start {
+			continue
+		}
+		nodeMask := uint64(1) << node
+		_, _, errno := syscall.Syscall6(syscall.SYS_MBIND, start, end-start, mpolPreferred,
+			uintptr(unsafe.Pointer(&nodeMask)), 64, mpolMFMove)
+		if errno != 0 {
+			klog.V(2).Infof("SimpleGo: failed to move memory to NUMA node %d: %v", node, errno)
+			return
+		}
+	}
+}
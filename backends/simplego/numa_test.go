@@ -0,0 +1,57 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"testing"
+
+	"github.com/gomlx/gopjrt/dtypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCPUList(t *testing.T) {
+	cpus, err := parseCPUList("0-3,8-9,16\n")
+	require.NoError(t, err)
+	require.Equal(t, []int{0, 1, 2, 3, 8, 9, 16}, cpus)
+	cpus, err = parseCPUList("")
+	require.NoError(t, err)
+	require.Empty(t, cpus)
+	_, err = parseCPUList("3-1")
+	require.Error(t, err)
+	_, err = parseCPUList("a")
+	require.Error(t, err)
+}
+
+func TestNUMALocal(t *testing.T) {
+	// Rows split in 4 tasks over 2 nodes.
+	require.Equal(t, []int{0, 0, 1, 1}, []int{
+		numaNodeForRows(0, 25, 100, 2), numaNodeForRows(25, 50, 100, 2),
+		numaNodeForRows(50, 75, 100, 2), numaNodeForRows(75, 100, 100, 2)})
+	require.Equal(t, 2, numaNodeForRows(99, 100, 100, 3))
+
+	backendIface, err := New("numa")
+	require.NoError(t, err)
+	be := backendIface.(*Backend)
+	defer be.Finalize()
+	require.Equal(t, NUMALocal, be.numaPolicy)
+	require.Equal(t, NUMANodes(), be.bufferPool.numaNodes)
+	require.Error(t, be.SetNUMAPolicy(NUMAPolicy(7)))
+
+	if NUMANodes() == 0 {
+		t.Skip("NUMA topology not available")
+	}
+	unpin := pinToNUMANode(0)
+	unpin()
+
+	// Force the spreading of a large buffer over the (possibly single) node.
+	be.bufferPool.numaNodes = 2
+	buf := be.getBuffer(dtypes.Float32, numaMinBufferBytes)
+	flat := buf.flat.([]float32)
+	for i := range flat {
+		flat[i] = float32(i)
+	}
+	require.Equal(t, float32(len(flat)-1), flat[len(flat)-1])
+	be.putBuffer(buf)
+}
@@ -0,0 +1,204 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"os"
+	"runtime"
+	"strconv"
+	"sync"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+// Environment variables with the default parallelism configuration of new backends. The corresponding
+// configuration options (see New) take precedence over them.
+const (
+	// ParallelismEnvVar sets the maximum number of parallel workers, like the "parallelism" configuration,
+	// see Backend.SetMaxParallelism.
+	ParallelismEnvVar = "GOMLX_SIMPLEGO_PARALLELISM"
+
+	// CoresEnvVar selects the cores the workers are sized for ("all" or "performance"), like the "cores"
+	// configuration, see Backend.SetCoresPolicy.
+	CoresEnvVar = "GOMLX_SIMPLEGO_CORES"
+
+	// DotGeneralParallelMinWorkEnvVar sets the minimum work of a DotGeneral before it is parallelized, like the
+	// "dotgeneral_parallel_min_work" configuration, see Backend.SetDotGeneralParallelMinWork.
+	DotGeneralParallelMinWorkEnvVar = "GOMLX_SIMPLEGO_DOTGENERAL_PARALLEL_MIN_WORK"
+
+	// UnaryParallelMinSizeEnvVar sets the minimum size of the unary ops before they are parallelized, like the
+	// "unary_parallel_min_size" configuration, see Backend.SetUnaryParallelMinSize.
+	UnaryParallelMinSizeEnvVar = "GOMLX_SIMPLEGO_UNARY_PARALLEL_MIN_SIZE"
+)
+
+// CoresPolicy defines the CPU cores the number of workers of the backend is sized for, on CPUs with
+// performance (P) and efficiency (E) cores, like Apple's M-series or Intel's hybrid CPUs.
+// See Backend.SetCoresPolicy.
+type CoresPolicy int
+
+const (
+	// CoresAll sizes the workers to use all the cores (limited by GOMAXPROCS). This is the default.
+	CoresAll CoresPolicy = iota
+
+	// CoresPerformance sizes the workers to use only as many cores as there are performance cores. The compute
+	// bound kernels split their work in equal parts, so the slower efficiency cores often delay the whole op.
+	//
+	// It has no effect if the performance cores can't be identified (see PerformanceCores). The workers are not
+	// pinned to the performance cores: the operating system tends to schedule the busy threads on them.
+	CoresPerformance
+)
+
+// coresPolicyNames are the names of the CoresPolicy values in the "cores" configuration and in CoresEnvVar.
+var coresPolicyNames = map[string]CoresPolicy{
+	"all":         CoresAll,
+	"performance": CoresPerformance,
+}
+
+// parseCoresPolicy converts the name of a CoresPolicy ("all" or "performance").
+func parseCoresPolicy(name string) (CoresPolicy, error) {
+	policy, found := coresPolicyNames[name]
+	if !found {
+		return CoresAll, errors.Errorf("invalid cores policy %q, valid values are \"all\" or \"performance\"", name)
+	}
+	return policy, nil
+}
+
+// PerformanceCores returns the number of logical CPUs in the performance cores, on CPUs with performance and
+// efficiency cores. It returns 0 if all cores are of the same type, or if it is not known (only Linux and macOS
+// are supported).
+func PerformanceCores() int {
+	return getPerformanceCores()
+}
+
+// getPerformanceCores caches readPerformanceCores.
+var getPerformanceCores = sync.OnceValue(readPerformanceCores)
+
+// defaultMaxParallelism is the maximum number of parallel workers used for the given CoresPolicy.
+func defaultMaxParallelism(policy CoresPolicy) int {
+	parallelism := runtime.GOMAXPROCS(0)
+	if numPerformance := PerformanceCores(); policy == CoresPerformance && numPerformance > 0 {
+		parallelism = min(parallelism, numPerformance)
+	}
+	return parallelism
+}
+
+// SetMaxParallelism sets the soft limit on the number of parallel workers used by the ops: if 0 parallelism is
+// disabled, and if -1 it is unlimited.
+//
+// The default is GOMAXPROCS (see SetCoresPolicy), and it can also be set with the "parallelism=#workers"
+// configuration (see New) or with the GOMLX_SIMPLEGO_PARALLELISM environment variable (see ParallelismEnvVar).
+//
+// It should be set before any executions.
+func (b *Backend) SetMaxParallelism(maxParallelism int) error {
+	if maxParallelism < -1 {
+		return errors.Errorf("SetMaxParallelism: invalid parallelism %d, it must be >= -1", maxParallelism)
+	}
+	b.workers.SetMaxParallelism(maxParallelism)
+	return nil
+}
+
+// MaxParallelism returns the soft limit on the number of parallel workers, see SetMaxParallelism.
+func (b *Backend) MaxParallelism() int {
+	return b.workers.MaxParallelism()
+}
+
+// SetCoresPolicy sets the cores the number of workers is sized for, and resets the maximum parallelism
+// accordingly (see SetMaxParallelism). It can also be set with the "cores=all|performance" configuration (see
+// New) or with the GOMLX_SIMPLEGO_CORES environment variable (see CoresEnvVar).
+//
+// It should be set before any executions.
+func (b *Backend) SetCoresPolicy(policy CoresPolicy) error {
+	if policy != CoresAll && policy != CoresPerformance {
+		return errors.Errorf("SetCoresPolicy: invalid policy %d", policy)
+	}
+	b.workers.SetMaxParallelism(defaultMaxParallelism(policy))
+	return nil
+}
+
+// SetDotGeneralParallelMinWork sets the minimum amount of work, measured in multiply-adds, of a DotGeneral (or of
+// each of the parallel tasks it is split into) before it is parallelized. Convolutions executed as matrix
+// multiplications use it as well.
+//
+// The default is DotGeneralParallelMinWork, and it can also be set with the "dotgeneral_parallel_min_work"
+// configuration (see New) or with the GOMLX_SIMPLEGO_DOTGENERAL_PARALLEL_MIN_WORK environment variable.
+func (b *Backend) SetDotGeneralParallelMinWork(minWork int) error {
+	if minWork < 1 {
+		return errors.Errorf("SetDotGeneralParallelMinWork: invalid minimum work %d, it must be >= 1", minWork)
+	}
+	b.dotGeneralParallelMinWork = minWork
+	return nil
+}
+
+// UnaryParallelMinSize is the default minimum number of elements of the unary ops with parallel implementations
+// (e.g. Erf) before they are parallelized. They are split in chunks of this size.
+var UnaryParallelMinSize = 4096
+
+// SetUnaryParallelMinSize sets the minimum number of elements of the unary ops with parallel implementations before
+// they are parallelized, and the size of the chunks they are split into.
+//
+// The default is UnaryParallelMinSize, and it can also be set with the "unary_parallel_min_size" configuration
+// (see New) or with the GOMLX_SIMPLEGO_UNARY_PARALLEL_MIN_SIZE environment variable.
+func (b *Backend) SetUnaryParallelMinSize(minSize int) error {
+	if minSize < 1 {
+		return errors.Errorf("SetUnaryParallelMinSize: invalid minimum size %d, it must be >= 1", minSize)
+	}
+	b.unaryParallelMinSize = minSize
+	return nil
+}
+
+// setParallelismOption sets the parallelism configuration option key (as in New) to value.
+// It returns false if key is not a parallelism option.
+func (b *Backend) setParallelismOption(key, value string) (bool, error) {
+	var err error
+	switch key {
+	case "parallelism", "dotgeneral_parallel_min_work", "unary_parallel_min_size":
+		var vInt int
+		vInt, err = strconv.Atoi(value)
+		if err != nil {
+			return true, errors.Wrapf(err, "invalid value for %q in SimpleGo backend config: needs an int, got %q",
+				key, value)
+		}
+		switch key {
+		case "parallelism":
+			err = b.SetMaxParallelism(vInt)
+		case "dotgeneral_parallel_min_work":
+			err = b.SetDotGeneralParallelMinWork(vInt)
+		default:
+			err = b.SetUnaryParallelMinSize(vInt)
+		}
+	case "cores":
+		var policy CoresPolicy
+		policy, err = parseCoresPolicy(value)
+		if err == nil {
+			err = b.SetCoresPolicy(policy)
+		}
+	default:
+		return false, nil
+	}
+	return true, err
+}
+
+// parallelismEnvVars maps the environment variables to the corresponding configuration options.
+var parallelismEnvVars = []struct{ envVar, key string }{
+	{CoresEnvVar, "cores"},
+	{ParallelismEnvVar, "parallelism"},
+	{DotGeneralParallelMinWorkEnvVar, "dotgeneral_parallel_min_work"},
+	{UnaryParallelMinSizeEnvVar, "unary_parallel_min_size"},
+}
+
+// configureParallelismFromEnv sets the parallelism options given by the environment variables.
+// Invalid values are logged and ignored.
+func (b *Backend) configureParallelismFromEnv() {
+	for _, option := range parallelismEnvVars {
+		value, found := os.LookupEnv(option.envVar)
+		if !found || value == "" {
+			continue
+		}
+		if _, err := b.setParallelismOption(option.key, value); err != nil {
+			klog.Errorf("SimpleGo backend: ignoring invalid %s=%q: %v", option.envVar, value, err)
+		}
+	}
+}
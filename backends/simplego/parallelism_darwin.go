@@ -0,0 +1,32 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build darwin
+
+package simplego
+
+import "syscall"
+
+// readPerformanceCores reads the number of logical CPUs in the performance cores (the "performance level" 0)
+// from sysctl.
+func readPerformanceCores() int {
+	if sysctlInt("hw.nperflevels") < 2 {
+		return 0
+	}
+	return sysctlInt("hw.perflevel0.logicalcpu")
+}
+
+// sysctlInt returns the integer value of the sysctl name, or 0 if not available.
+func sysctlInt(name string) int {
+	value, err := syscall.Sysctl(name)
+	if err != nil {
+		return 0
+	}
+	// The value is returned as the little-endian bytes of the integer, without the trailing zero byte.
+	var result int
+	for ii := len(value) - 1; ii >= 0; ii-- {
+		result = result<<8 | int(value[ii])
+	}
+	return result
+}
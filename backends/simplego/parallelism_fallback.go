@@ -0,0 +1,10 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux && !darwin
+
+package simplego
+
+// readPerformanceCores is not implemented outside Linux and macOS.
+func readPerformanceCores() int { return 0 }
@@ -0,0 +1,52 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux
+
+package simplego
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// readPerformanceCores reads the number of logical CPUs in the performance cores from sysfs.
+func readPerformanceCores() int {
+	// Intel hybrid CPUs: the performance cores are the ones of the "cpu_core" PMU.
+	if cpuList, err := os.ReadFile("/sys/devices/cpu_core/cpus"); err == nil {
+		if cpus, err := parseCPUList(string(cpuList)); err == nil && len(cpus) > 0 {
+			return len(cpus)
+		}
+	}
+
+	// ARM big.LITTLE (and DynamIQ): the performance cores are the ones with the largest capacity.
+	paths, err := filepath.Glob("/sys/devices/system/cpu/cpu[0-9]*/cpu_capacity")
+	if err != nil || len(paths) == 0 {
+		return 0
+	}
+	var maxCapacity, numMax int
+	for _, path := range paths {
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return 0
+		}
+		capacity, err := strconv.Atoi(strings.TrimSpace(string(contents)))
+		if err != nil {
+			return 0
+		}
+		if capacity > maxCapacity {
+			maxCapacity, numMax = capacity, 0
+		}
+		if capacity == maxCapacity {
+			numMax++
+		}
+	}
+	if numMax == len(paths) {
+		// All cores are the same.
+		return 0
+	}
+	return numMax
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"runtime"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParallelismConfig(t *testing.T) {
+	defaultBackend, err := New("")
+	require.NoError(t, err)
+	defer defaultBackend.Finalize()
+	b := defaultBackend.(*Backend)
+	require.Equal(t, runtime.GOMAXPROCS(0), b.MaxParallelism())
+	require.Equal(t, DotGeneralParallelMinWork, b.dotGeneralParallelMinWork)
+	require.Equal(t, UnaryParallelMinSize, b.unaryParallelMinSize)
+	require.GreaterOrEqual(t, PerformanceCores(), 0)
+
+	configured, err := New("cores=performance,parallelism=3,dotgeneral_parallel_min_work=100,unary_parallel_min_size=10")
+	require.NoError(t, err)
+	defer configured.Finalize()
+	b = configured.(*Backend)
+	require.Equal(t, 3, b.MaxParallelism())
+	require.Equal(t, 100, b.dotGeneralParallelMinWork)
+	require.Equal(t, 10, b.unaryParallelMinSize)
+	require.NoError(t, b.SetCoresPolicy(CoresPerformance))
+	require.Equal(t, defaultMaxParallelism(CoresPerformance), b.MaxParallelism())
+	require.LessOrEqual(t, b.MaxParallelism(), runtime.GOMAXPROCS(0))
+	require.Error(t, b.SetCoresPolicy(CoresPolicy(7)))
+
+	for _, config := range []string{"cores=some", "parallelism=-2", "dotgeneral_parallel_min_work=0",
+		"unary_parallel_min_size=x"} {
+		_, err = New(config)
+		require.Errorf(t, err, "config %q", config)
+	}
+
+	// Environment variables: the configuration takes precedence, and invalid values are ignored.
+	t.Setenv(ParallelismEnvVar, "2")
+	t.Setenv(DotGeneralParallelMinWorkEnvVar, "1000")
+	t.Setenv(UnaryParallelMinSizeEnvVar, "-1")
+	fromEnv, err := New("dotgeneral_parallel_min_work=500")
+	require.NoError(t, err)
+	defer fromEnv.Finalize()
+	b = fromEnv.(*Backend)
+	require.Equal(t, 2, b.MaxParallelism())
+	require.Equal(t, 500, b.dotGeneralParallelMinWork)
+	require.Equal(t, UnaryParallelMinSize, b.unaryParallelMinSize)
+}
+
+func TestParallelizeDotGeneral_MinWork(t *testing.T) {
+	be, err := New("parallelism=4,dotgeneral_parallel_min_work=1000")
+	require.NoError(t, err)
+	defer be.Finalize()
+	b := be.(*Backend)
+
+	// countTasks returns the number of tasks parallelizeDotGeneral splits 100 rows of rowWork into.
+	countTasks := func(rowWork int) int {
+		var numTasks atomic.Int32
+		parallelizeDotGeneral(b, 1, 100, rowWork, func(_, _, _ int) { numTasks.Add(1) })
+		return int(numTasks.Load())
+	}
+	require.Equal(t, 1, countTasks(10))  // 1000 multiply-adds: inline.
+	require.Equal(t, 2, countTasks(20))  // 2000 multiply-adds: 2 tasks.
+	require.Equal(t, 4, countTasks(100)) // Limited by the parallelism.
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"context"
+	"runtime/pprof"
+	"runtime/trace"
+	"strings"
+
+	"github.com/gomlx/gomlx/backends"
+	"github.com/gomlx/gomlx/pkg/core/shapes"
+)
+
+// Keys of the runtime/pprof labels used to tag the execution, see Backend.SetProfileLabels.
+const (
+	// ProfileLabelOp is the label with the type of the op executed, e.g. "DotGeneral".
+	ProfileLabelOp = "gomlx_op"
+
+	// ProfileLabelShape is the label with the output shape of the op executed.
+	ProfileLabelShape = "gomlx_shape"
+
+	// ProfileLabelKernel is the label with the algorithm (kernel) used by the op, for the ops with more than one.
+	// E.g. for DotGeneral: "gemm", "gemv", "strassen", "small", "large", "openblas", etc.
+	ProfileLabelKernel = "gomlx_kernel"
+
+	// ProfileLabelSIMD is the label with the families of SIMD kernels enabled when the kernel was executed
+	// (joined by "+", or "off" if none), see EnabledSIMD.
+	ProfileLabelSIMD = "gomlx_simd"
+)
+
+// SetProfileLabels enables or disables tagging the execution of the ops with runtime/pprof labels, so CPU profiles
+// attribute the time to specific ops, shapes and kernels. E.g.: "go tool pprof -tagfocus=gomlx_op=DotGeneral" or
+// "go tool pprof -tags" to list the time per label. See ProfileLabelOp, ProfileLabelShape, ProfileLabelKernel and
+// ProfileLabelSIMD. It can also be enabled with the "profile_labels" configuration, see New.
+//
+// The labels replace the ones of the goroutine (and of the workers it starts) during the execution of each op.
+// It has a small cost per op, so it is disabled by default.
+//
+// Independently of it, while a runtime/trace is being collected, the execution of each op is traced as a region
+// (named "simplego.<op>"), and the kernels chosen are logged as events with the ProfileLabelKernel key.
+func (b *Backend) SetProfileLabels(enabled bool) {
+	b.profileLabels = enabled
+}
+
+// executeWithProfiling calls fn, that executes node, tagged with the pprof labels of the node (if enabled with
+// Backend.SetProfileLabels) and within a trace region (if tracing).
+func (b *Backend) executeWithProfiling(node *Node, fn func()) {
+	tracing := trace.IsEnabled()
+	if !b.profileLabels && !tracing {
+		fn()
+		return
+	}
+	ctx := context.Background()
+	if tracing {
+		defer trace.StartRegion(ctx, "simplego."+node.opType.String()).End()
+	}
+	if !b.profileLabels {
+		fn()
+		return
+	}
+	pprof.Do(ctx, pprof.Labels(ProfileLabelOp, node.opType.String(), ProfileLabelShape, node.shape.String()),
+		func(context.Context) { fn() })
+}
+
+// profileKernel tags the remaining of the execution of the current op with the kernel used (see
+// ProfileLabelKernel), if enabled with Backend.SetProfileLabels, and logs it if tracing. shape is the output shape
+// of the op.
+func profileKernel(backend *Backend, opType backends.OpType, shape shapes.Shape, kernel string) {
+	ctx := context.Background()
+	if trace.IsEnabled() {
+		trace.Log(ctx, ProfileLabelKernel, kernel)
+	}
+	if !backend.profileLabels {
+		return
+	}
+	simd := strings.Join(EnabledSIMD(), "+")
+	if simd == "" {
+		simd = SIMDOff
+	}
+	// The labels are restored to the ones of the op at the end of its execution, see executeWithProfiling.
+	pprof.SetGoroutineLabels(pprof.WithLabels(ctx, pprof.Labels(
+		ProfileLabelOp, opType.String(), ProfileLabelShape, shape.String(),
+		ProfileLabelKernel, kernel, ProfileLabelSIMD, simd)))
+}
+
+// profileDotGeneralKernel calls profileKernel for a DotGeneral with the given output.
+func profileDotGeneralKernel(backend *Backend, output *Buffer, kernel string) {
+	profileKernel(backend, backends.OpTypeDotGeneral, output.shape, kernel)
+}
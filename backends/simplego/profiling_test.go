@@ -0,0 +1,56 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"bytes"
+	"fmt"
+	"runtime/pprof"
+	"testing"
+
+	"github.com/gomlx/gomlx/backends"
+	"github.com/gomlx/gomlx/pkg/core/shapes"
+	"github.com/gomlx/gopjrt/dtypes"
+	"github.com/stretchr/testify/require"
+)
+
+// goroutineProfile returns the goroutine profile with the labels of each goroutine.
+func goroutineProfile(t *testing.T) string {
+	var buf bytes.Buffer
+	require.NoError(t, pprof.Lookup("goroutine").WriteTo(&buf, 1))
+	return buf.String()
+}
+
+func TestProfileLabels(t *testing.T) {
+	profilingBackend, err := New("profile_labels")
+	require.NoError(t, err)
+	defer profilingBackend.Finalize()
+	b := profilingBackend.(*Backend)
+
+	node := &Node{opType: backends.OpTypeDotGeneral, shape: shapes.Make(dtypes.Float32, 2, 3)}
+	opLabels := fmt.Sprintf("%q:%q", ProfileLabelOp, "DotGeneral")
+	kernelLabels := fmt.Sprintf("%q:%q", ProfileLabelKernel, "gemm")
+	var whileOp, whileKernel string
+	b.executeWithProfiling(node, func() {
+		whileOp = goroutineProfile(t)
+		profileKernel(b, node.opType, node.shape, "gemm")
+		whileKernel = goroutineProfile(t)
+	})
+	require.Contains(t, whileOp, opLabels)
+	require.Contains(t, whileOp, fmt.Sprintf("%q:%q", ProfileLabelShape, node.shape.String()))
+	require.NotContains(t, whileOp, kernelLabels)
+	require.Contains(t, whileKernel, kernelLabels)
+	require.Contains(t, whileKernel, fmt.Sprintf("%q:", ProfileLabelSIMD))
+	// The labels are removed at the end of the op.
+	require.NotContains(t, goroutineProfile(t), opLabels)
+
+	// Disabled: no labels.
+	b.SetProfileLabels(false)
+	b.executeWithProfiling(node, func() {
+		profileKernel(b, node.opType, node.shape, "gemm")
+		whileKernel = goroutineProfile(t)
+	})
+	require.NotContains(t, whileKernel, opLabels)
+}
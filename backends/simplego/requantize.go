@@ -0,0 +1,118 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// RequantizationParams describes the epilogue of a quantized matrix multiplication lhs[M, K] × rhs[K, N], with
+// int8 values quantized as in tensors.QuantizeInt8: a value x is represented by q, with `x ≈ (q - zeroPoint) * scale`.
+//
+// See RequantizeInt32ToInt8.
+type RequantizationParams struct {
+	// ContractingSize is the size K of the contracting axis.
+	ContractingSize int
+
+	// LHSScale and LHSZeroPoint of the lhs, quantized per-tensor (e.g. the activations).
+	LHSScale     float32
+	LHSZeroPoint int32
+
+	// RHSScales and RHSZeroPoints of the rhs (e.g. the weights), either with one value for the whole tensor, or with
+	// N values, one per output channel.
+	RHSScales     []float32
+	RHSZeroPoints []int32
+
+	// LHSRowSums holds, for each row m, Σ_k lhs[m, k]. It is only used (and required) if any of the RHSZeroPoints
+	// is not 0.
+	LHSRowSums []int32
+
+	// RHSColumnSums holds, for each column n, Σ_k rhs[k, n]. It is only used (and required) if LHSZeroPoint
+	// is not 0. Since it only depends on the rhs, it can be calculated once for the weights.
+	RHSColumnSums []int32
+
+	// OutputScale and OutputZeroPoint of the int8 output.
+	OutputScale     float32
+	OutputZeroPoint int32
+}
+
+// RequantizeInt32ToInt8 converts the int32 accumulators of a quantized matrix multiplication (the [M, N] output
+// of an int8×int8→int32 DotGeneral) to int8 values quantized with params.OutputScale and params.OutputZeroPoint.
+//
+// It subtracts the contributions of the zero-points from the accumulators, rescales them with
+// LHSScale * RHSScales[n] / OutputScale, and rounds and clamps them to the int8 range.
+func RequantizeInt32ToInt8(acc []int32, m, n int, params *RequantizationParams, output []int8) error {
+	if len(acc) != m*n || len(output) != m*n {
+		return errors.Errorf("RequantizeInt32ToInt8: expected %d×%d=%d accumulators and outputs, got %d and %d",
+			m, n, m*n, len(acc), len(output))
+	}
+	if params.OutputScale == 0 {
+		return errors.New("RequantizeInt32ToInt8: OutputScale cannot be 0")
+	}
+	perColumn := func(name string, length int) (func(j int) int, error) {
+		switch length {
+		case 1:
+			return func(int) int { return 0 }, nil
+		case n:
+			return func(j int) int { return j }, nil
+		}
+		return nil, errors.Errorf("RequantizeInt32ToInt8: expected 1 or %d %s, got %d", n, name, length)
+	}
+	scaleIdx, err := perColumn("RHSScales", len(params.RHSScales))
+	if err != nil {
+		return err
+	}
+	zeroPoints := params.RHSZeroPoints
+	if len(zeroPoints) == 0 {
+		zeroPoints = []int32{0}
+	}
+	zeroPointIdx, err := perColumn("RHSZeroPoints", len(zeroPoints))
+	if err != nil {
+		return err
+	}
+
+	// Per column values: multiplier and the constant part of the zero-points correction.
+	lhsZeroPoint := params.LHSZeroPoint
+	if lhsZeroPoint != 0 && len(params.RHSColumnSums) != n {
+		return errors.Errorf("RequantizeInt32ToInt8: LHSZeroPoint=%d requires %d RHSColumnSums, got %d",
+			lhsZeroPoint, n, len(params.RHSColumnSums))
+	}
+	multipliers := make([]float32, n)
+	offsets := make([]int32, n)
+	colZeroPoints := make([]int32, n)
+	hasRHSZeroPoints := false
+	for j := range n {
+		multipliers[j] = params.LHSScale * params.RHSScales[scaleIdx(j)] / params.OutputScale
+		rhsZeroPoint := zeroPoints[zeroPointIdx(j)]
+		colZeroPoints[j] = rhsZeroPoint
+		hasRHSZeroPoints = hasRHSZeroPoints || rhsZeroPoint != 0
+		offsets[j] = int32(params.ContractingSize) * lhsZeroPoint * rhsZeroPoint
+		if lhsZeroPoint != 0 {
+			offsets[j] -= lhsZeroPoint * params.RHSColumnSums[j]
+		}
+	}
+	if hasRHSZeroPoints && len(params.LHSRowSums) != m {
+		return errors.Errorf("RequantizeInt32ToInt8: non-zero RHSZeroPoints require %d LHSRowSums, got %d",
+			m, len(params.LHSRowSums))
+	}
+
+	outputZeroPoint := float64(params.OutputZeroPoint)
+	for i := range m {
+		accRow := acc[i*n : (i+1)*n]
+		outputRow := output[i*n : (i+1)*n]
+		var rowSum int32
+		if hasRHSZeroPoints {
+			rowSum = params.LHSRowSums[i]
+		}
+		for j, v := range accRow {
+			v += offsets[j] - colZeroPoints[j]*rowSum
+			q := math.Round(float64(float32(v)*multipliers[j])) + outputZeroPoint
+			outputRow[j] = int8(min(max(q, -128), 127))
+		}
+	}
+	return nil
+}
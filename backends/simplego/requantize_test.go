@@ -0,0 +1,96 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/gomlx/gomlx/pkg/core/tensors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequantizeInt32ToInt8(t *testing.T) {
+	const M, K, N = 5, 70, 6
+	rng := rand.New(rand.NewSource(42))
+	lhsValues := make([]float32, M*K)
+	for i := range lhsValues {
+		lhsValues[i] = rng.Float32() * 2 // Skewed: only positive values, like after a ReLU.
+	}
+	rhsValues := make([]float32, K*N)
+	for i := range rhsValues {
+		rhsValues[i] = (rng.Float32()*2 - 1) * float32(1+i%N)
+	}
+	lhs := tensors.FromFlatDataAndDimensions(lhsValues, M, K)
+	rhs := tensors.FromFlatDataAndDimensions(rhsValues, K, N)
+
+	for _, symmetric := range []bool{true, false} {
+		lhsQ, lhsParams, err := tensors.QuantizeInt8(lhs, false)
+		require.NoError(t, err)
+		rhsQ, rhsParams, err := tensors.QuantizeInt8PerChannel(rhs, 1, symmetric)
+		require.NoError(t, err)
+		lhsFlat := tensors.MustCopyFlatData[int8](lhsQ)
+		rhsFlat := tensors.MustCopyFlatData[int8](rhsQ)
+
+		// Accumulators, row and column sums.
+		acc := make([]int32, M*N)
+		lhsRowSums := make([]int32, M)
+		rhsColumnSums := make([]int32, N)
+		rhsTransposed := make([]int8, N*K)
+		for k := range K {
+			for j := range N {
+				rhsTransposed[j*K+k] = rhsFlat[k*N+j]
+				rhsColumnSums[j] += int32(rhsFlat[k*N+j])
+			}
+		}
+		for i := range M {
+			for k := range K {
+				lhsRowSums[i] += int32(lhsFlat[i*K+k])
+			}
+			for j := range N {
+				acc[i*N+j] = dotProductInt8Go(lhsFlat[i*K:(i+1)*K], rhsTransposed[j*K:(j+1)*K])
+			}
+		}
+
+		// Expected: the float result of the dequantized values, quantized with the output parameters.
+		const outputScale, outputZeroPoint = 0.5, -3
+		want := make([]int8, M*N)
+		for i := range M {
+			for j := range N {
+				var sum float64
+				for k := range K {
+					x := float64(int32(lhsFlat[i*K+k])-lhsParams.ZeroPoints[0]) * float64(lhsParams.Scales[0])
+					w := float64(int32(rhsFlat[k*N+j])-rhsParams.ZeroPoints[j]) * float64(rhsParams.Scales[j])
+					sum += x * w
+				}
+				want[i*N+j] = int8(min(max(math.Round(sum/outputScale)+outputZeroPoint, -128), 127))
+			}
+		}
+
+		params := &RequantizationParams{
+			ContractingSize: K,
+			LHSScale:        lhsParams.Scales[0],
+			LHSZeroPoint:    lhsParams.ZeroPoints[0],
+			RHSScales:       rhsParams.Scales,
+			RHSZeroPoints:   rhsParams.ZeroPoints,
+			LHSRowSums:      lhsRowSums,
+			RHSColumnSums:   rhsColumnSums,
+			OutputScale:     outputScale,
+			OutputZeroPoint: outputZeroPoint,
+		}
+		got := make([]int8, M*N)
+		require.NoError(t, RequantizeInt32ToInt8(acc, M, N, params, got))
+		for i := range want {
+			// Allow for differences in rounding, due to the float32 multipliers.
+			require.InDeltaf(t, want[i], got[i], 1, "symmetric=%v, mismatch at %d", symmetric, i)
+		}
+
+		// Missing sums.
+		params.RHSColumnSums = nil
+		require.Error(t, RequantizeInt32ToInt8(acc, M, N, params, got))
+	}
+	require.Error(t, RequantizeInt32ToInt8(make([]int32, 3), M, N, &RequantizationParams{}, make([]int8, M*N)))
+}
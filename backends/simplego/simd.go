@@ -0,0 +1,172 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+// SIMDEnvVar is the environment variable that selects the SIMD kernels used by the SimpleGo backend.
+// It takes the same values as SetSIMD, separated by commas. E.g.: GOMLX_SIMD=off or GOMLX_SIMD=avx2.
+const SIMDEnvVar = "GOMLX_SIMD"
+
+// Names of the families of SIMD kernels that can be selected with SetSIMD or with GOMLX_SIMD.
+const (
+	// SIMDOff disables all SIMD kernels: only the pure Go versions are used.
+	SIMDOff = "off"
+
+	// SIMDAuto enables all SIMD kernels supported by the CPU. This is the default.
+	SIMDAuto = "auto"
+
+	// SIMDNEON selects the ARM64 NEON float32 and int8 kernels.
+	SIMDNEON = "neon"
+
+	// SIMDFP16 selects the ARM64 NEON Float16 (FMLAL) and BFloat16 (BFMLAL) kernels.
+	SIMDFP16 = "fp16"
+
+	// SIMDSVE selects the ARM64 SVE (Scalable Vector Extension) float32 kernels, used on CPUs with vectors of
+	// 256 bits or more.
+	SIMDSVE = "sve"
+
+	// SIMDSME selects the ARM64 SME (Scalable Matrix Extension) float32 matrix multiplication kernels, based on
+	// outer products (FMOPA).
+	SIMDSME = "sme"
+
+	// SIMDAVX2 selects the AMD64 AVX2 (with FMA) kernels.
+	SIMDAVX2 = "avx2"
+
+	// SIMDAVX512 selects the AMD64 AVX-512F kernels.
+	SIMDAVX512 = "avx512"
+
+	// SIMDVNNI selects the AMD64 int8 kernels, using AVX-512 VNNI or AVX-VNNI.
+	SIMDVNNI = "vnni"
+)
+
+// simdNames lists all families of SIMD kernels, in the order they are reported.
+var simdNames = []string{SIMDNEON, SIMDFP16, SIMDSVE, SIMDSME, SIMDAVX2, SIMDAVX512, SIMDVNNI}
+
+// simdFamily is a family of SIMD kernels registered with registerSIMD.
+type simdFamily struct {
+	// flags enable the kernels of the family (e.g. hasAVX2).
+	flags []*bool
+
+	// detected holds the initial value of the flags: whether the CPU (and the OS) support them.
+	detected []bool
+}
+
+// simdRegistry holds the families of SIMD kernels available on this platform, keyed by their names.
+var simdRegistry = make(map[string]*simdFamily)
+
+// registerSIMD registers the flags that enable the family of SIMD kernels with the given name.
+// The current value of the flags is taken as whether the CPU supports them.
+//
+// It should be called in the initialization of a package variable (e.g. "var _ = registerSIMD(...)"), so that all
+// families are registered before GOMLX_SIMD is applied in init().
+func registerSIMD(name string, flags ...*bool) bool {
+	family := &simdFamily{flags: flags}
+	for _, flag := range flags {
+		family.detected = append(family.detected, *flag)
+	}
+	simdRegistry[name] = family
+	return true
+}
+
+// available returns whether the CPU supports any of the kernels of the family.
+func (f *simdFamily) available() bool {
+	return slices.Contains(f.detected, true)
+}
+
+// enabled returns whether any of the kernels of the family is enabled.
+func (f *simdFamily) enabled() bool {
+	for _, flag := range f.flags {
+		if *flag {
+			return true
+		}
+	}
+	return false
+}
+
+// setEnabled enables the kernels of the family supported by the CPU, or disables all of them.
+func (f *simdFamily) setEnabled(enabled bool) {
+	for ii, flag := range f.flags {
+		*flag = enabled && f.detected[ii]
+	}
+}
+
+func init() {
+	if value, found := os.LookupEnv(SIMDEnvVar); found {
+		if err := SetSIMD(strings.Split(value, ",")...); err != nil {
+			klog.Errorf("SimpleGo backend: ignoring invalid %s=%q: %v", SIMDEnvVar, value, err)
+		}
+	}
+}
+
+// SetSIMD selects the families of SIMD kernels used by the SimpleGo backend, which is useful for debugging and
+// benchmarking. Only the families listed are enabled, and the operations fall back to the remaining ones (or to
+// pure Go). The accepted values are:
+//
+//   - SIMDAuto ("auto") or no values: enable all SIMD kernels supported by the CPU (the default).
+//   - SIMDOff ("off"): disable all SIMD kernels.
+//   - Any of SIMDNEON ("neon"), SIMDFP16 ("fp16"), SIMDSVE ("sve"), SIMDSME ("sme"), SIMDAVX2 ("avx2"),
+//     SIMDAVX512 ("avx512") or SIMDVNNI ("vnni").
+//
+// It returns an error, and nothing is changed, if a value is unknown or if the family is not supported by the CPU:
+// kernels can't be forced where they are not available.
+//
+// The same can be configured with the environment variable GOMLX_SIMD (see SIMDEnvVar).
+//
+// It changes global state of the package, and it must not be called while computations are executing.
+func SetSIMD(names ...string) error {
+	all := len(names) == 0
+	selected := make(map[string]bool)
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch {
+		case name == SIMDAuto:
+			all = true
+		case name == SIMDOff || name == "":
+			// Nothing is selected.
+		case !slices.Contains(simdNames, name):
+			return errors.Errorf("unknown SIMD kernels %q, valid values are %q, %q or any of %q",
+				name, SIMDAuto, SIMDOff, simdNames)
+		case simdRegistry[name] == nil || !simdRegistry[name].available():
+			return errors.Errorf("SIMD kernels %q are not supported in this platform/CPU, available SIMD kernels: %q",
+				name, AvailableSIMD())
+		default:
+			selected[name] = true
+		}
+	}
+	for name, family := range simdRegistry {
+		family.setEnabled(all || selected[name])
+	}
+	return nil
+}
+
+// AvailableSIMD returns the families of SIMD kernels supported by this platform and CPU.
+func AvailableSIMD() []string {
+	var names []string
+	for _, name := range simdNames {
+		if family := simdRegistry[name]; family != nil && family.available() {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// EnabledSIMD returns the families of SIMD kernels currently enabled. See SetSIMD.
+func EnabledSIMD() []string {
+	var names []string
+	for _, name := range simdNames {
+		if family := simdRegistry[name]; family != nil && family.enabled() {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,69 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gomlx/gomlx/pkg/core/graph"
+	"github.com/gomlx/gomlx/pkg/core/tensors"
+	"github.com/gomlx/gomlx/pkg/support/xslices"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetSIMD(t *testing.T) {
+	defer func() { require.NoError(t, SetSIMD(SIMDAuto)) }()
+	available := AvailableSIMD()
+	fmt.Printf("\tavailable SIMD kernels: %q\n", available)
+
+	require.NoError(t, SetSIMD(SIMDOff))
+	require.Empty(t, EnabledSIMD())
+	require.False(t, hasNEON || hasAVX2 || hasAVX512)
+
+	require.NoError(t, SetSIMD())
+	require.Equal(t, available, EnabledSIMD())
+
+	for _, name := range available {
+		require.NoError(t, SetSIMD(SIMDOff))
+		require.NoError(t, SetSIMD(" "+name+" "))
+		require.Equal(t, []string{name}, EnabledSIMD())
+	}
+
+	// Errors don't change the current selection.
+	require.NoError(t, SetSIMD(SIMDAuto))
+	require.Error(t, SetSIMD("mmx"))
+	for _, name := range simdNames {
+		if family := simdRegistry[name]; family == nil || !family.available() {
+			require.Error(t, SetSIMD(name))
+		}
+	}
+	require.Equal(t, available, EnabledSIMD())
+}
+
+func TestDotGeneral_SIMDOff(t *testing.T) {
+	goBackend, ok := backend.(*Backend)
+	if !ok {
+		fmt.Printf("Skipping %s, it is meant only for the Go backend, instead backend is ", backend.Name())
+		t.SkipNow()
+		return
+	}
+	defer func() {
+		goBackend.dotGeneralForceProblemSize = unknownProblemSize
+		require.NoError(t, SetSIMD(SIMDAuto))
+	}()
+	goBackend.dotGeneralForceProblemSize = largeProblemSize
+
+	// Batch axes in the middle, so it doesn't take the fast path.
+	lhs := tensors.FromFlatDataAndDimensions(xslices.Iota(float32(0), 37*3*70), 37, 3, 70)
+	rhs := tensors.FromFlatDataAndDimensions(xslices.Iota(float32(0), 70*3*45), 70, 3, 45)
+	dotFn := func(lhs, rhs *graph.Node) *graph.Node {
+		return graph.MulScalar(graph.Einsum("ibj,jbk->bik", lhs, rhs), 1e-6)
+	}
+	want := graph.MustExecOnce(backend, dotFn, lhs, rhs)
+	require.NoError(t, SetSIMD(SIMDOff))
+	got := graph.MustExecOnce(backend, dotFn, lhs, rhs)
+	require.True(t, got.InDelta(want, 1e-2), "SIMD and pure Go versions of DotGeneral differ")
+}
@@ -59,13 +59,23 @@ func New(config string) (backends.Backend, error) {
 			key, value = part[0:eqPos], part[eqPos+1:]
 		}
 		switch key {
-		case "parallelism":
-			vInt, err := strconv.Atoi(value)
+		case "parallelism", "cores", "dotgeneral_parallel_min_work", "unary_parallel_min_size":
+			// Number of workers and minimum sizes of the ops before they are parallelized, see
+			// Backend.SetMaxParallelism, Backend.SetCoresPolicy, Backend.SetDotGeneralParallelMinWork and
+			// Backend.SetUnaryParallelMinSize.
+			if _, err := b.setParallelismOption(key, value); err != nil {
+				return nil, err
+			}
+			if key == "parallelism" {
+				fmt.Printf("SimpleGo backend: parallelism set to %d\n", b.workers.MaxParallelism())
+			}
+		case "buffer_pool_max_bytes":
+			// Limits the memory held by the free buffers kept for reuse, see Backend.BufferPoolStats.
+			vInt, err := strconv.ParseInt(value, 10, 64)
 			if err != nil {
 				return nil, errors.Wrapf(err, "invalid value for %q in SimpleGo backend config: needs an int, got %q", key, value)
 			}
-			b.workers.SetMaxParallelism(vInt)
-			fmt.Printf("SimpleGo backend: parallelism set to %d\n", vInt)
+			b.bufferPool.maxBytes.Store(vInt)
 		case "dotgeneral_small":
 			// This will force DotGeneral operation to use the version designed for smaller matrices.
 			b.dotGeneralForceProblemSize = smallProblemSize
@@ -76,6 +86,41 @@ func New(config string) (backends.Backend, error) {
 			// This will force every DotGeneral operation to be executed with both versions and the outputs compared.
 			// This is used exclusively for debugging purposes.
 			b.dotGeneralForceProblemSize = checkProblemSize
+		case "dotgeneral_nofusion":
+			// This disables the fusion of the bias and activations following a DotGeneral into it,
+			// see Builder.fuseDotGeneralEpilogues.
+			b.dotGeneralNoFusion = true
+		case "dotgeneral_compensated":
+			// Uses compensated summation for float32 DotGeneral with long contractions,
+			// see Backend.SetDotGeneralCompensatedSummation.
+			b.SetDotGeneralCompensatedSummation(true)
+		case "dotgeneral_strassen":
+			// Uses Strassen for huge float32 matrix multiplications, see Backend.SetDotGeneralStrassen.
+			b.SetDotGeneralStrassen(true)
+		case "dotgeneral_accelerate":
+			// Uses Apple's Accelerate for large float32/float16 matrix multiplications (only in macOS, when built
+			// with cgo and the "accelerate" tag), see Backend.SetDotGeneralAccelerate.
+			if err := b.SetDotGeneralAccelerate(true); err != nil {
+				return nil, err
+			}
+		case "dotgeneral_noblas":
+			// Disables delegating large matrix multiplications to the BLAS library (OpenBLAS or MKL), if one was
+			// linked, see Backend.SetDotGeneralBLAS.
+			_ = b.SetDotGeneralBLAS(false)
+		case "numa":
+			// Spreads large buffers across the NUMA nodes and pins the DotGeneral workers to the node owning
+			// their rows, see NUMALocal.
+			_ = b.SetNUMAPolicy(NUMALocal)
+		case "huge_pages":
+			// Backs large buffers with huge pages (only in Linux), see Backend.SetHugePagesPolicy.
+			policy, err := parseHugePagesPolicy(value)
+			if err != nil {
+				return nil, err
+			}
+			_ = b.SetHugePagesPolicy(policy)
+		case "profile_labels":
+			// Tags the execution of the ops with runtime/pprof labels, see Backend.SetProfileLabels.
+			b.SetProfileLabels(true)
 		case "ops_sequential":
 			// This will force the ops to be executed sequentially.
 			// The default is running parallel if it's the only thing executing, otherwise sequentially.
@@ -84,11 +129,14 @@ func New(config string) (backends.Backend, error) {
 			// This will force the ops to be executed in parallel where possible.
 			// The default is running parallel if it's the only thing executing, otherwise sequentially.
 			b.opsExecutionType = opsExecutionParallel
+		case "gemm_autotune":
+			// Benchmarks and selects the block sizes of the GEMM used by DotGeneral, see Backend.AutotuneGEMM.
+			b.AutotuneGEMM()
 		case "":
 			// No-op, just skip.
 		default:
 			return nil, errors.Errorf("unknown configuration option %q for SimpleGo (go) backend -- valid configuration options are: "+
-				"parallelism=#workers, dotgeneral_small, dotgeneral_large, dotgeneral_check, ops_sequential, ops_parallel; see code for documentation", key)
+				"parallelism=#workers, cores=all|performance, dotgeneral_parallel_min_work=#multiply_adds, unary_parallel_min_size=#elements, buffer_pool_max_bytes=#bytes, dotgeneral_small, dotgeneral_large, dotgeneral_check, dotgeneral_nofusion, dotgeneral_compensated, dotgeneral_strassen, dotgeneral_accelerate, dotgeneral_noblas, numa, huge_pages=off|transparent|explicit, profile_labels, ops_sequential, ops_parallel, gemm_autotune; see code for documentation", key)
 		}
 	}
 	return b, nil
@@ -97,22 +145,64 @@ func New(config string) (backends.Backend, error) {
 func newDefaultBackend() *Backend {
 	b := &Backend{}
 	b.workers.Initialize()
+	b.dotGeneralParallelMinWork = DotGeneralParallelMinWork
+	b.unaryParallelMinSize = UnaryParallelMinSize
+	b.configureParallelismFromEnv()
+	b.bufferPool.maxBytes.Store(DefaultBufferPoolMaxBytes)
+	b.dotGeneralBLAS = hasBLAS
 	b.preBlockedWeightCache = NewPreBlockedWeightCache()
+	b.packedWeightCache = NewPackedWeightCache()
+	b.gemmBlockSizes = defaultGEMMBlockSizes
 	return b
 }
 
 // Backend implements the backends.Backend interface.
 type Backend struct {
-	// bufferPools are a map to pools of buffers that can be reused.
-	// The underlying type is map[bufferPoolKey]*sync.Pool.
-	bufferPools sync.Map
-	workers     workersPool
+	// bufferPool holds the free buffers to be reused.
+	bufferPool bufferPool
+	workers    workersPool
+
+	// dotGeneralParallelMinWork is the minimum work of a DotGeneral before it is parallelized, see
+	// SetDotGeneralParallelMinWork.
+	dotGeneralParallelMinWork int
+
+	// unaryParallelMinSize is the minimum size of the unary ops before they are parallelized, see
+	// SetUnaryParallelMinSize.
+	unaryParallelMinSize int
 
 	numLiveExecutions atomic.Int32
 
 	// dotGeneralForceProblemSize allows a DotGeneral algorithm to always be used.
 	dotGeneralForceProblemSize dotGeneralProblemSizeType
 
+	// dotGeneralNoFusion disables the fusion of epilogues (bias and activation) into DotGeneral.
+	dotGeneralNoFusion bool
+
+	// dotGeneralCompensated enables the compensated summation of long contractions, see
+	// SetDotGeneralCompensatedSummation.
+	dotGeneralCompensated bool
+
+	// dotGeneralStrassen enables the Strassen algorithm for huge matrix multiplications, see SetDotGeneralStrassen.
+	dotGeneralStrassen bool
+
+	// dotGeneralAccelerate routes large matrix multiplications to Apple's Accelerate, see SetDotGeneralAccelerate.
+	dotGeneralAccelerate bool
+
+	// dotGeneralBLAS delegates large matrix multiplications to the BLAS library linked, see SetDotGeneralBLAS.
+	dotGeneralBLAS bool
+
+	// numaPolicy defines the placement of buffers and workers on multiple NUMA nodes, see SetNUMAPolicy.
+	numaPolicy NUMAPolicy
+
+	// hugePagesPolicy defines whether large buffers are backed by huge pages, see SetHugePagesPolicy.
+	hugePagesPolicy HugePagesPolicy
+
+	// profileLabels enables tagging the execution of the ops with pprof labels, see SetProfileLabels.
+	profileLabels bool
+
+	// gemmBlockSizes are the block sizes of the GEMM used by DotGeneral, see SetGEMMBlockSizes.
+	gemmBlockSizes GEMMBlockSizes
+
 	// opsExecutionType defines how to execute the ops of a computation.
 	opsExecutionType opsExecutionType
 
@@ -120,6 +210,9 @@ type Backend struct {
 	// This allows skipping the blocking step for constant weights (model parameters).
 	preBlockedWeightCache *PreBlockedWeightCache
 
+	// packedWeightCache holds the weights packed for the GEMM of the DotGeneral fast path, see PackWeight.
+	packedWeightCache *PackedWeightCache
+
 	// isFinalized is true if the backend has been isFinalized.
 	isFinalized bool
 }
@@ -175,7 +268,7 @@ func notImplementedError(opType backends.OpType) error {
 // Finalize releases all the associated resources immediately, and makes the backend invalid.
 func (b *Backend) Finalize() {
 	b.isFinalized = true
-	b.bufferPools.Clear()
+	b.bufferPool.clear()
 }
 
 // IsFinalized returns true if the backend has been isFinalized.
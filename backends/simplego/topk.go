@@ -0,0 +1,110 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+// TopKFloat32 returns the k largest values of x, sorted in decreasing order, and their indices in x.
+// Ties are broken by the lowest index first, and NaNs are considered smaller than any other value, as in tensors.TopK.
+// If k is larger than len(x), it returns all the values of x sorted.
+//
+// It is meant for host-side top-k/top-p sampling and log-probability reporting over the logits of large
+// vocabularies: it does a partial selection, without sorting x. It keeps a min-heap with the best k values seen so far,
+// and the SIMD kernels (AVX2 or NEON) are used to skip over the values that are not greater than the smallest of them,
+// which is most of x when k is small.
+func TopKFloat32(x []float32, k int) (values []float32, indices []int32) {
+	k = min(k, len(x))
+	if k <= 0 {
+		return []float32{}, []int32{}
+	}
+	h := topKFloat32Heap{values: make([]float32, k), indices: make([]int32, k)}
+	copy(h.values, x[:k])
+	for ii := range k {
+		h.indices[ii] = int32(ii)
+	}
+	for ii := k/2 - 1; ii >= 0; ii-- {
+		h.siftDown(ii, k)
+	}
+
+	var groupSize int
+	var findGreater func(x []float32, threshold float32) int
+	switch {
+	case hasAVX2:
+		groupSize, findGreater = 8, findGreaterFloat32AVX2
+	case hasNEON:
+		groupSize, findGreater = 4, findGreaterFloat32NEON
+	default:
+		groupSize = len(x)
+	}
+	idx := k
+	for idx < len(x) {
+		if threshold := h.values[0]; findGreater != nil && threshold == threshold {
+			// Skip the groups of values that can't enter the top-k.
+			idx += findGreater(x[idx:], threshold)
+		}
+		for end := min(idx+groupSize, len(x)); idx < end; idx++ {
+			// x[idx] has a larger index than all values in the heap, so it loses ties: it only enters the top-k if it is
+			// strictly greater than the smallest value of the heap (or if that one is a NaN).
+			value := x[idx]
+			if value != value {
+				continue
+			}
+			if smallest := h.values[0]; smallest != smallest || value > smallest {
+				h.values[0], h.indices[0] = value, int32(idx)
+				h.siftDown(0, k)
+			}
+		}
+	}
+
+	// Sort the top-k in decreasing order: the smallest is repeatedly moved to the end (heap sort).
+	for size := k - 1; size > 0; size-- {
+		h.swap(0, size)
+		h.siftDown(0, size)
+	}
+	return h.values, h.indices
+}
+
+// topKFloat32Heap is a min-heap of values and their indices, where the root holds the "worst" of them (see less).
+type topKFloat32Heap struct {
+	values  []float32
+	indices []int32
+}
+
+// less returns whether the element ii is worse than the element jj: NaNs are the worst, and ties are broken by the
+// highest index.
+func (h *topKFloat32Heap) less(ii, jj int) bool {
+	valueI, valueJ := h.values[ii], h.values[jj]
+	nanI, nanJ := valueI != valueI, valueJ != valueJ
+	switch {
+	case nanI != nanJ:
+		return nanI
+	case !nanI && valueI != valueJ:
+		return valueI < valueJ
+	default:
+		return h.indices[ii] > h.indices[jj]
+	}
+}
+
+func (h *topKFloat32Heap) swap(ii, jj int) {
+	h.values[ii], h.values[jj] = h.values[jj], h.values[ii]
+	h.indices[ii], h.indices[jj] = h.indices[jj], h.indices[ii]
+}
+
+// siftDown moves the element ii down the heap with the given size until the heap property is restored.
+func (h *topKFloat32Heap) siftDown(ii, size int) {
+	for {
+		smallest := ii
+		left, right := 2*ii+1, 2*ii+2
+		if left < size && h.less(left, smallest) {
+			smallest = left
+		}
+		if right < size && h.less(right, smallest) {
+			smallest = right
+		}
+		if smallest == ii {
+			return
+		}
+		h.swap(ii, smallest)
+		ii = smallest
+	}
+}
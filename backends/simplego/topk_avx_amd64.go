@@ -0,0 +1,31 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !noasm && amd64
+
+package simplego
+
+import (
+	"runtime"
+	"unsafe"
+)
+
+// findGreaterFloat32_avx2_asm is implemented in topk_avx_amd64.s.
+// It returns the start of the first group of 8 values (n must be a multiple of 8) that contains a value greater
+// than threshold, or n if none does.
+//
+//go:noescape
+func findGreaterFloat32_avx2_asm(x unsafe.Pointer, n int64, threshold float32) int64
+
+// findGreaterFloat32AVX2 returns the start of the first group of 8 values of x that contains a value greater than
+// threshold. The search stops at the largest multiple of 8 of len(x), which is returned if there is none.
+func findGreaterFloat32AVX2(x []float32, threshold float32) int {
+	n := len(x) &^ 7
+	if n == 0 {
+		return 0
+	}
+	idx := findGreaterFloat32_avx2_asm(unsafe.Pointer(&x[0]), int64(n), threshold)
+	runtime.KeepAlive(x)
+	return int(idx)
+}
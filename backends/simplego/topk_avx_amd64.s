@@ -0,0 +1,50 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !noasm && amd64
+
+#include "textflag.h"
+
+// func findGreaterFloat32_avx2_asm(x unsafe.Pointer, n int64, threshold float32) int64
+//
+// Returns the start of the first group of 8 values (n must be a multiple of 8) that contains a value greater than
+// threshold, or n if none does. NaNs are never greater.
+//
+// It is used by TopKFloat32 to skip over the values that can't enter the top-k: 32 values are tested per iteration.
+TEXT ·findGreaterFloat32_avx2_asm(SB), NOSPLIT, $0-32
+	MOVQ         x+0(FP), SI
+	MOVQ         n+8(FP), CX
+	VBROADCASTSS threshold+16(FP), Y1
+	XORQ         AX, AX
+	MOVQ         CX, DX
+	SUBQ         $32, DX // DX: last start of a group of 32 values.
+
+greater_loop32:
+	CMPQ   AX, DX
+	JG     greater_loop8
+	VCMPPS $0x11, (SI)(AX*4), Y1, Y2   // Y2: threshold < x (ordered, so false for NaNs).
+	VCMPPS $0x11, 32(SI)(AX*4), Y1, Y3
+	VCMPPS $0x11, 64(SI)(AX*4), Y1, Y4
+	VCMPPS $0x11, 96(SI)(AX*4), Y1, Y5
+	VORPS  Y3, Y2, Y2
+	VORPS  Y5, Y4, Y4
+	VORPS  Y4, Y2, Y2
+	VPTEST Y2, Y2
+	JNZ    greater_loop8 // Find which group of 8 values has it.
+	ADDQ   $32, AX
+	JMP    greater_loop32
+
+greater_loop8:
+	CMPQ   AX, CX
+	JGE    greater_done
+	VCMPPS $0x11, (SI)(AX*4), Y1, Y2
+	VPTEST Y2, Y2
+	JNZ    greater_done
+	ADDQ   $8, AX
+	JMP    greater_loop8
+
+greater_done:
+	MOVQ AX, ret+24(FP)
+	VZEROUPPER
+	RET
@@ -0,0 +1,12 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build noasm || !amd64
+
+package simplego
+
+// findGreaterFloat32AVX2 stub for non-AMD64 platforms.
+func findGreaterFloat32AVX2(x []float32, threshold float32) int {
+	panic("AVX2 not available")
+}
@@ -0,0 +1,31 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !noasm && arm64
+
+package simplego
+
+import (
+	"runtime"
+	"unsafe"
+)
+
+// findGreaterFloat32_neon_asm is implemented in topk_neon_arm64.s.
+// It returns the start of the first group of 4 values (n must be a multiple of 4) that contains a value greater
+// than threshold, or n if none does.
+//
+//go:noescape
+func findGreaterFloat32_neon_asm(x unsafe.Pointer, n int64, threshold float32) int64
+
+// findGreaterFloat32NEON returns the start of the first group of 4 values of x that contains a value greater than
+// threshold. The search stops at the largest multiple of 4 of len(x), which is returned if there is none.
+func findGreaterFloat32NEON(x []float32, threshold float32) int {
+	n := len(x) &^ 3
+	if n == 0 {
+		return 0
+	}
+	idx := findGreaterFloat32_neon_asm(unsafe.Pointer(&x[0]), int64(n), threshold)
+	runtime.KeepAlive(x)
+	return int(idx)
+}
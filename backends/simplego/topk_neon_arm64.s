@@ -0,0 +1,55 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !noasm && arm64
+
+#include "textflag.h"
+
+// func findGreaterFloat32_neon_asm(x unsafe.Pointer, n int64, threshold float32) int64
+//
+// Returns the start of the first group of 4 values (n must be a multiple of 4) that contains a value greater than
+// threshold, or n if none does. NaNs are never greater.
+//
+// It is used by TopKFloat32 to skip over the values that can't enter the top-k: 16 values are tested per iteration.
+TEXT ·findGreaterFloat32_neon_asm(SB), NOSPLIT, $0-32
+	MOVD  x+0(FP), R0
+	MOVD  n+8(FP), R1
+	FMOVS threshold+16(FP), F1
+	WORD  $0x4e040421 // dup v1.4s, v1.s[0]
+	MOVD  $0, R2
+	SUB   $16, R1, R3 // R3: last start of a group of 16 values.
+
+greater_loop16:
+	CMP   R3, R2
+	BGT   greater_loop4
+	ADD   R2<<2, R0, R5
+	WORD  $0x4c4028a4 // ld1 {v4.4s, v5.4s, v6.4s, v7.4s}, [x5]
+	WORD  $0x6ea1e484 // fcmgt v4.4s, v4.4s, v1.4s
+	WORD  $0x6ea1e4a5 // fcmgt v5.4s, v5.4s, v1.4s
+	WORD  $0x6ea1e4c6 // fcmgt v6.4s, v6.4s, v1.4s
+	WORD  $0x6ea1e4e7 // fcmgt v7.4s, v7.4s, v1.4s
+	WORD  $0x4ea51c84 // orr v4.16b, v4.16b, v5.16b
+	WORD  $0x4ea71cc6 // orr v6.16b, v6.16b, v7.16b
+	WORD  $0x4ea61c84 // orr v4.16b, v4.16b, v6.16b
+	WORD  $0x6eb0a884 // umaxv s4, v4.4s
+	WORD  $0x1e260086 // fmov w6, s4
+	CBNZW R6, greater_loop4 // Find which group of 4 values has it.
+	ADD   $16, R2
+	B     greater_loop16
+
+greater_loop4:
+	CMP   R1, R2
+	BGE   greater_done
+	ADD   R2<<2, R0, R5
+	WORD  $0x4c4078a2 // ld1 {v2.4s}, [x5]
+	WORD  $0x6ea1e443 // fcmgt v3.4s, v2.4s, v1.4s
+	WORD  $0x6eb0a863 // umaxv s3, v3.4s
+	WORD  $0x1e260064 // fmov w4, s3
+	CBNZW R4, greater_done
+	ADD   $4, R2
+	B     greater_loop4
+
+greater_done:
+	MOVD R2, ret+24(FP)
+	RET
@@ -0,0 +1,12 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build noasm || !arm64
+
+package simplego
+
+// findGreaterFloat32NEON stub for non-ARM64 platforms.
+func findGreaterFloat32NEON(x []float32, threshold float32) int {
+	panic("NEON not available")
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simplego
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/gomlx/gomlx/pkg/core/tensors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopKFloat32(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	for _, simd := range []string{SIMDAuto, SIMDOff} {
+		require.NoError(t, SetSIMD(simd))
+		for _, size := range []int{1, 7, 40, 333, 5000} {
+			for _, k := range []int{1, 3, 10, 64, size} {
+				t.Run(fmt.Sprintf("%s/size=%d/k=%d", simd, size, k), func(t *testing.T) {
+					x := make([]float32, size)
+					for ii := range x {
+						// Few distinct values, to have many ties.
+						x[ii] = float32(rng.Intn(50)) - 25
+					}
+					if size > 10 {
+						x[3], x[size/2] = float32(math.NaN()), float32(math.NaN())
+						x[size-1] = 1000
+					}
+					_, wantIndices, err := tensors.TopK(tensors.FromValue(x), min(k, size), 0)
+					require.NoError(t, err)
+
+					values, indices := TopKFloat32(x, k)
+					require.Equal(t, wantIndices.Value(), indices)
+					require.Len(t, values, len(indices))
+					for ii, idx := range indices {
+						require.Equal(t, math.Float32bits(x[idx]), math.Float32bits(values[ii]))
+					}
+				})
+			}
+		}
+	}
+	require.NoError(t, SetSIMD(SIMDAuto))
+
+	// All NaNs, and k = 0.
+	nan := float32(math.NaN())
+	_, indices := TopKFloat32([]float32{nan, nan, 1, nan, nan, nan, nan, nan, nan, nan}, 3)
+	require.Equal(t, []int32{2, 0, 1}, indices)
+	values, indices := TopKFloat32([]float32{1, 2}, 0)
+	require.Empty(t, values)
+	require.Empty(t, indices)
+}
+
+func BenchmarkTopKFloat32(b *testing.B) {
+	const vocabSize = 128 * 1024
+	x := make([]float32, vocabSize)
+	rng := rand.New(rand.NewSource(42))
+	for ii := range x {
+		x[ii] = float32(rng.NormFloat64())
+	}
+	for _, k := range []int{1, 50, 1000} {
+		for _, simd := range []string{SIMDAuto, SIMDOff} {
+			b.Run(fmt.Sprintf("k=%d/%s", k, simd), func(b *testing.B) {
+				require.NoError(b, SetSIMD(simd))
+				defer func() { require.NoError(b, SetSIMD(SIMDAuto)) }()
+				for range b.N {
+					_, _ = TopKFloat32(x, k)
+				}
+			})
+		}
+	}
+}
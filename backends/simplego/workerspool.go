@@ -1,7 +1,6 @@
 package simplego
 
 import (
-	"runtime"
 	"sync"
 	"sync/atomic"
 )
@@ -18,9 +17,10 @@ type workersPool struct {
 	extraParallelism atomic.Int32
 }
 
-// Initialize should be called before use.
+// Initialize should be called before use. The maxParallelism is initialized to GOMAXPROCS, see
+// Backend.SetCoresPolicy.
 func (w *workersPool) Initialize() {
-	w.maxParallelism = runtime.NumCPU()
+	w.maxParallelism = defaultMaxParallelism(CoresAll)
 	w.cond = sync.Cond{L: &w.mu}
 }
 
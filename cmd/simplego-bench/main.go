@@ -0,0 +1,420 @@
+// simplego-bench benchmarks the matrix multiplication kernels of the SimpleGo backend (package
+// github.com/gomlx/gomlx/backends/simplego): it sweeps problem sizes, dtypes and variants of the SIMD kernels, and
+// reports the GFLOPs and the memory bandwidth of each case, optionally to a JSON report that can be compared across
+// machines or commits.
+//
+// See simplego-bench -help for details.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gomlx/gopjrt/dtypes"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"github.com/gomlx/gomlx/backends/simplego"
+	"github.com/gomlx/gomlx/internal/must"
+	"github.com/gomlx/gomlx/pkg/core/graph"
+	"github.com/gomlx/gomlx/pkg/core/tensors"
+)
+
+var (
+	flagConfig = flag.String("config", "", "Configuration of the SimpleGo backend, as in GOMLX_BACKEND=go:<config>, "+
+		"e.g. \"ops_sequential,dotgeneral_large\".")
+	flagSizes = flag.String("sizes", "1x4096x4096,16x4096x4096,128x1024x1024,512x512x512,1024x1024x1024",
+		"Comma-separated list of the sizes MxKxN of the matrix multiplications [M, K] × [K, N] to benchmark.")
+	flagDTypes = flag.String("dtypes", "float32,float16,bfloat16,int8,int4,fp8",
+		"Comma-separated list of the dtypes to benchmark: float32, float16 and bfloat16 are benchmarked with the "+
+			"DotGeneral of a graph, and the weight-only quantized int8, int4, fp8 (E4M3) and fp8e5m2 with the "+
+			"MatMulDequantize* methods of the backend, with float32 activations.")
+	flagKernels = flag.String("kernels", "all",
+		"Comma-separated list of the variants of the SIMD kernels to benchmark, each one a list of families joined "+
+			"by \"+\" (e.g. \"neon+fp16\"), passed to simplego.SetSIMD. \"scalar\" (or \"off\") disables all SIMD kernels, "+
+			"\"auto\" enables all the ones supported, and \"all\" expands to scalar, each supported family and auto.")
+	flagMinTime  = flag.Duration("min_time", time.Second, "Minimum time spent measuring each case.")
+	flagMinIters = flag.Int("min_iters", 3, "Minimum number of iterations measured for each case.")
+	flagReport   = flag.String("report", "", "If set, the results are also written as JSON to the given file "+
+		"(\"-\" for the standard output, in which case the table is not printed).")
+)
+
+// Report is the machine-readable report of a run, written with -report.
+type Report struct {
+	Time      time.Time `json:"time"`
+	GOOS      string    `json:"goos"`
+	GOARCH    string    `json:"goarch"`
+	GoVersion string    `json:"go_version"`
+	NumCPU    int       `json:"num_cpu"`
+	Config    string    `json:"config"`
+	SIMD      []string  `json:"available_simd"`
+	BLAS      string    `json:"blas,omitempty"`
+	Parallel  int       `json:"max_parallelism"`
+	MinTime   string    `json:"min_time"`
+	MinIters  int       `json:"min_iters"`
+	Results   []*Result `json:"results"`
+}
+
+// Result of one benchmarked case.
+type Result struct {
+	Kernels    string  `json:"kernels"`
+	DType      string  `json:"dtype"`
+	M          int     `json:"m"`
+	K          int     `json:"k"`
+	N          int     `json:"n"`
+	Iterations int     `json:"iterations"`
+	NsPerOp    float64 `json:"ns_per_op"`
+	GFLOPs     float64 `json:"gflops"`
+	GBps       float64 `json:"gb_per_s"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// size of a matrix multiplication [M, K] × [K, N].
+type size struct{ m, k, n int }
+
+func main() {
+	klog.InitFlags(nil)
+	flag.Usage = func() {
+		pf := func(format string, args ...any) {
+			_ = must.M1(fmt.Fprintf(flag.CommandLine.Output(), format, args...))
+		}
+		pf("Usage of simplego-bench (%q):\n", os.Args[0])
+		pf("\n\t$ simplego-bench [flags...]\n" +
+			"\nsimplego-bench benchmarks the matrix multiplication kernels of the SimpleGo backend, for each combination " +
+			"of size (-sizes), dtype (-dtypes) and SIMD kernels (-kernels), and prints the GFLOPs and the memory " +
+			"bandwidth (counting each operand and the output once) of each case.\n\n" +
+			"Flags:\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	sizes, err := parseSizes(*flagSizes)
+	if err != nil {
+		klog.Fatalf("Invalid -sizes: %+v", err)
+	}
+	dtypeNames := splitList(*flagDTypes)
+	for _, name := range dtypeNames {
+		if !isGraphDType(name) && !isQuantizedDType(name) {
+			klog.Fatalf("Invalid -dtypes: unknown dtype %q", name)
+		}
+	}
+	variants := parseKernels(*flagKernels)
+
+	backend, err := simplego.New(*flagConfig)
+	if err != nil {
+		klog.Fatalf("Failed to create SimpleGo backend: %+v", err)
+	}
+	defer backend.Finalize()
+	goBackend := backend.(*simplego.Backend)
+
+	report := &Report{
+		Time:      time.Now(),
+		GOOS:      runtime.GOOS,
+		GOARCH:    runtime.GOARCH,
+		NumCPU:    runtime.NumCPU(),
+		GoVersion: runtime.Version(),
+		Config:    *flagConfig,
+		SIMD:      simplego.AvailableSIMD(),
+		BLAS:      simplego.BLASLibrary(),
+		Parallel:  goBackend.MaxParallelism(),
+		MinTime:   flagMinTime.String(),
+		MinIters:  *flagMinIters,
+	}
+	printTable := *flagReport != "-"
+	if printTable {
+		fmt.Printf("SimpleGo backend on %s/%s, %d CPUs, available SIMD kernels: %q\n\n",
+			report.GOOS, report.GOARCH, report.NumCPU, report.SIMD)
+		fmt.Printf(rowFormat, "kernels", "dtype", "M", "K", "N", "time/op", "GFLOPs", "GB/s")
+	}
+
+	for _, variant := range variants {
+		if err := simplego.SetSIMD(strings.Split(variant, "+")...); err != nil {
+			klog.Warningf("Skipping kernels %q: %v", variant, err)
+			continue
+		}
+		for _, dtypeName := range dtypeNames {
+			bench := newBenchmark(goBackend, dtypeName)
+			for _, sz := range sizes {
+				result := bench.run(sz)
+				result.Kernels = variantName(variant)
+				report.Results = append(report.Results, result)
+				if printTable {
+					printResult(result)
+				}
+			}
+			bench.finalize()
+		}
+	}
+	must.M(simplego.SetSIMD(simplego.SIMDAuto))
+
+	if *flagReport != "" {
+		if err := writeReport(report, *flagReport); err != nil {
+			klog.Fatalf("Failed to write report: %+v", err)
+		}
+	}
+}
+
+// splitList splits a comma-separated list, dropping empty values.
+func splitList(list string) []string {
+	var values []string
+	for _, value := range strings.Split(list, ",") {
+		if value = strings.ToLower(strings.TrimSpace(value)); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// parseSizes parses the list of sizes "MxKxN,...".
+func parseSizes(list string) ([]size, error) {
+	var sizes []size
+	for _, value := range splitList(list) {
+		parts := strings.Split(value, "x")
+		if len(parts) != 3 {
+			return nil, errors.Errorf("size %q is not in the MxKxN format", value)
+		}
+		var dims [3]int
+		for i, part := range parts {
+			dim, err := strconv.Atoi(part)
+			if err != nil || dim <= 0 {
+				return nil, errors.Errorf("invalid dimension %q in size %q", part, value)
+			}
+			dims[i] = dim
+		}
+		sizes = append(sizes, size{m: dims[0], k: dims[1], n: dims[2]})
+	}
+	if len(sizes) == 0 {
+		return nil, errors.New("no sizes given")
+	}
+	return sizes, nil
+}
+
+// parseKernels returns the list of variants of SIMD kernels, expanding "all", and mapping "scalar" to
+// simplego.SIMDOff.
+func parseKernels(list string) []string {
+	var variants []string
+	for _, value := range splitList(list) {
+		switch value {
+		case "all":
+			variants = append(variants, simplego.SIMDOff)
+			variants = append(variants, simplego.AvailableSIMD()...)
+			variants = append(variants, simplego.SIMDAuto)
+		case "scalar":
+			variants = append(variants, simplego.SIMDOff)
+		default:
+			variants = append(variants, value)
+		}
+	}
+	return variants
+}
+
+// variantName returns the name of the variant of kernels used in the results.
+func variantName(variant string) string {
+	if variant == simplego.SIMDOff {
+		return "scalar"
+	}
+	return variant
+}
+
+// rowFormat is the format of the rows of the table of results.
+const rowFormat = "%-12s %-9s %6v %6v %6v %12v %9v %9v\n"
+
+func printResult(result *Result) {
+	if result.Error != "" {
+		fmt.Printf("%-12s %-9s %6d %6d %6d error: %s\n",
+			result.Kernels, result.DType, result.M, result.K, result.N, result.Error)
+		return
+	}
+	fmt.Printf(rowFormat, result.Kernels, result.DType, result.M, result.K, result.N,
+		time.Duration(result.NsPerOp).Round(time.Microsecond/10),
+		fmt.Sprintf("%.1f", result.GFLOPs), fmt.Sprintf("%.1f", result.GBps))
+}
+
+func writeReport(report *Report, path string) error {
+	contents, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to encode report")
+	}
+	contents = append(contents, '\n')
+	if path == "-" {
+		_, err = os.Stdout.Write(contents)
+		return errors.Wrap(err, "failed to write report")
+	}
+	return errors.Wrapf(os.WriteFile(path, contents, 0o644), "failed to write report to %q", path)
+}
+
+// measure runs fn once to warm up (e.g. to compile the graph), and then repeatedly for at least -min_time and
+// -min_iters iterations, returning the mean time per iteration.
+func measure(fn func() error) (perOp time.Duration, iterations int, err error) {
+	if err = fn(); err != nil {
+		return
+	}
+	start := time.Now()
+	for iterations < *flagMinIters || time.Since(start) < *flagMinTime {
+		if err = fn(); err != nil {
+			return
+		}
+		iterations++
+	}
+	perOp = time.Since(start) / time.Duration(iterations)
+	return
+}
+
+// randomValues returns length random values in [-1, 1).
+func randomValues(rng *rand.Rand, length int) []float32 {
+	values := make([]float32, length)
+	for i := range values {
+		values[i] = rng.Float32()*2 - 1
+	}
+	return values
+}
+
+// benchmark of the matrix multiplication of one dtype.
+type benchmark struct {
+	backend   *simplego.Backend
+	dtypeName string
+	rng       *rand.Rand
+
+	// matMul executes the graph with the matrix multiplication, for the dtypes benchmarked with a graph.
+	matMul, convert *graph.Exec
+}
+
+func newBenchmark(backend *simplego.Backend, dtypeName string) *benchmark {
+	bench := &benchmark{backend: backend, dtypeName: dtypeName, rng: rand.New(rand.NewPCG(42, 0))}
+	if isGraphDType(dtypeName) {
+		dtype := graphDType(dtypeName)
+		bench.matMul = graph.MustNewExec(backend, graph.MatMul)
+		bench.convert = graph.MustNewExec(backend, func(x *graph.Node) *graph.Node {
+			return graph.ConvertDType(x, dtype)
+		})
+	}
+	return bench
+}
+
+func (bench *benchmark) finalize() {
+	if bench.matMul != nil {
+		bench.matMul.Finalize()
+		bench.convert.Finalize()
+	}
+}
+
+// run the benchmark of one size.
+func (bench *benchmark) run(sz size) *Result {
+	result := &Result{DType: bench.dtypeName, M: sz.m, K: sz.k, N: sz.n}
+	var fn func() error
+	var bytes int
+	if bench.matMul != nil {
+		var lhs, rhs *tensors.Tensor
+		fn, bytes, lhs, rhs = bench.graphMatMul(sz)
+		defer func() {
+			lhs.MustFinalizeAll()
+			rhs.MustFinalizeAll()
+		}()
+	} else {
+		var err error
+		fn, bytes, err = bench.quantizedMatMul(sz)
+		if err != nil {
+			result.Error = err.Error()
+			return result
+		}
+	}
+	perOp, iterations, err := measure(fn)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	seconds := perOp.Seconds()
+	result.Iterations = iterations
+	result.NsPerOp = float64(perOp.Nanoseconds())
+	result.GFLOPs = 2 * float64(sz.m) * float64(sz.k) * float64(sz.n) / seconds / 1e9
+	result.GBps = float64(bytes) / seconds / 1e9
+	return result
+}
+
+// isGraphDType returns whether the dtype is benchmarked with the DotGeneral of a graph.
+func isGraphDType(name string) bool {
+	return name == "float32" || name == "float16" || name == "bfloat16"
+}
+
+// graphDType returns the dtype of the operands of the graph.
+func graphDType(name string) dtypes.DType {
+	switch name {
+	case "float16":
+		return dtypes.Float16
+	case "bfloat16":
+		return dtypes.BFloat16
+	default:
+		return dtypes.Float32
+	}
+}
+
+// isQuantizedDType returns whether the dtype is a weight-only quantized one, benchmarked with the
+// MatMulDequantize* methods.
+func isQuantizedDType(name string) bool {
+	return name == "int8" || name == "int4" || name == "fp8" || name == "fp8e5m2"
+}
+
+// graphMatMul returns the function that executes the matrix multiplication graph with operands of the given
+// size, the number of bytes of the operands and the output, and the operands, to be finalized by the caller.
+func (bench *benchmark) graphMatMul(sz size) (fn func() error, bytes int, lhs, rhs *tensors.Tensor) {
+	dtype := graphDType(bench.dtypeName)
+	lhs = bench.convert.MustExec(tensors.FromFlatDataAndDimensions(randomValues(bench.rng, sz.m*sz.k), sz.m, sz.k))[0]
+	rhs = bench.convert.MustExec(tensors.FromFlatDataAndDimensions(randomValues(bench.rng, sz.k*sz.n), sz.k, sz.n))[0]
+	bytes = (sz.m*sz.k + sz.k*sz.n + sz.m*sz.n) * dtype.Size()
+	fn = func() error {
+		outputs, err := bench.matMul.Exec(lhs, rhs)
+		if err != nil {
+			return err
+		}
+		return outputs[0].FinalizeAll()
+	}
+	return
+}
+
+// quantizedMatMul returns the function that executes the matrix multiplication with float32 activations and
+// quantized weights of the given size, and the number of bytes of the operands (with the quantized weights and
+// their scales) and the output.
+func (bench *benchmark) quantizedMatMul(sz size) (fn func() error, bytes int, err error) {
+	const int4GroupSize = 32
+	lhs := randomValues(bench.rng, sz.m*sz.k)
+	rhsValues := randomValues(bench.rng, sz.k*sz.n)
+	output := make([]float32, sz.m*sz.n)
+	bytes = 4 * (len(lhs) + len(output))
+	switch bench.dtypeName {
+	case "int8":
+		rhs := &simplego.QuantizedWeightInt8{K: sz.k, N: sz.n, Values: make([]int8, sz.k*sz.n), Scales: []float32{1.0 / 127}}
+		for i, value := range rhsValues {
+			rhs.Values[i] = int8(value * 127)
+		}
+		bytes += len(rhs.Values) + 4*len(rhs.Scales)
+		fn = func() error { return bench.backend.MatMulDequantizeInt8(lhs, sz.m, rhs, output) }
+	case "int4":
+		var rhs *simplego.QuantizedWeightInt4
+		rhs, err = simplego.QuantizeWeightInt4(rhsValues, sz.k, sz.n, min(int4GroupSize, sz.k), true)
+		if err != nil {
+			return
+		}
+		bytes += len(rhs.Values) + 4*len(rhs.Scales) + len(rhs.ZeroPoints)
+		fn = func() error { return bench.backend.MatMulDequantizeInt4(lhs, sz.m, rhs, output) }
+	default:
+		format := simplego.FP8E4M3
+		if bench.dtypeName == "fp8e5m2" {
+			format = simplego.FP8E5M2
+		}
+		var rhs *simplego.QuantizedWeightFP8
+		rhs, err = simplego.QuantizeWeightFP8(rhsValues, sz.k, sz.n, format)
+		if err != nil {
+			return
+		}
+		bytes += len(rhs.Values) + 4*len(rhs.Scales)
+		fn = func() error { return bench.backend.MatMulDequantizeFP8(lhs, sz.m, rhs, output) }
+	}
+	return
+}
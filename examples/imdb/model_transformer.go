@@ -1,6 +1,7 @@
 package imdb
 
 import (
+	"github.com/gomlx/gomlx/internal/exceptions"
 	. "github.com/gomlx/gomlx/pkg/core/graph"
 	"github.com/gomlx/gomlx/pkg/ml/context"
 	"github.com/gomlx/gomlx/pkg/ml/layers"
@@ -97,6 +98,7 @@ func TransformerLayers(ctx *context.Context, embed, mask *Node) *Node {
 	if dropoutRate > 0.0 {
 		dropoutNode = Scalar(g, dtype, dropoutRate)
 	}
+	attDropoutRate := context.GetParamOr(ctx, "transformer_att_dropout_rate", 0.0)
 
 	// Create positional embedding variable: it is 1 in every axis, but for the
 	// sequence dimension -- there will be one embedding per position.
@@ -111,6 +113,14 @@ func TransformerLayers(ctx *context.Context, embed, mask *Node) *Node {
 	numAttLayers := context.GetParamOr(ctx, "transformer_num_att_layers", 1)
 	numAttHeads := context.GetParamOr(ctx, "transformer_num_att_heads", 2)
 	attKeySize := context.GetParamOr(ctx, "transformer_att_key_size", 8)
+	normStyle := context.GetParamOr(ctx, "transformer_norm_style", "post")
+	switch normStyle {
+	case "post":
+	case "pre":
+		return preNormTransformerLayers(ctx, embed, mask, numAttLayers, numAttHeads, attKeySize, attDropoutRate, dropoutNode)
+	default:
+		exceptions.Panicf(`invalid transformer_norm_style %q -- valid values are "pre" or "post"`, normStyle)
+	}
 	for layerNum := range numAttLayers {
 		// Each layer in its own scope.
 		ctx := ctx.Inf("%03d_attention_layer", layerNum)
@@ -118,7 +128,8 @@ func TransformerLayers(ctx *context.Context, embed, mask *Node) *Node {
 		embed = layers.MultiHeadAttention(ctx.In("000_attention"), embed, embed, embed, numAttHeads, attKeySize).
 			SetKeyMask(mask).SetQueryMask(mask).
 			SetOutputDim(embedSize).
-			SetValueHeadDim(embedSize).Done()
+			SetValueHeadDim(embedSize).
+			Dropout(attDropoutRate).Done()
 		if dropoutNode != nil {
 			embed = layers.Dropout(ctx.In("001_dropout"), embed, dropoutNode)
 		}
@@ -141,6 +152,42 @@ func TransformerLayers(ctx *context.Context, embed, mask *Node) *Node {
 	return embed
 }
 
+// preNormTransformerLayers is the pre-norm variant of TransformerLayers: the normalization is applied to the
+// input of each sub-layer (attention and FNN), whose output is then added to the residual stream.
+// A final normalization is applied to the output of the last layer.
+//
+// This is the layout used by T5 and most modern transformer models, and it is usually more stable to train
+// with deeper stacks.
+func preNormTransformerLayers(ctx *context.Context, embed, mask *Node,
+	numAttLayers, numAttHeads, attKeySize int, attDropoutRate float64, dropoutNode *Node) *Node {
+	embedSize := embed.Shape().Dimensions[2]
+	for layerNum := range numAttLayers {
+		// Each layer in its own scope.
+		ctx := ctx.Inf("%03d_attention_layer", layerNum)
+
+		// Attention sub-layer.
+		x := NormalizeSequence(ctx.In("000_normalization"), embed)
+		x = layers.MultiHeadAttention(ctx.In("001_attention"), x, x, x, numAttHeads, attKeySize).
+			SetKeyMask(mask).SetQueryMask(mask).
+			SetOutputDim(embedSize).
+			SetValueHeadDim(embedSize).
+			Dropout(attDropoutRate).Done()
+		if dropoutNode != nil {
+			x = layers.Dropout(ctx.In("002_dropout"), x, dropoutNode)
+		}
+		embed = Add(embed, x)
+
+		// FNN sub-layer.
+		x = NormalizeSequence(ctx.In("003_normalization"), embed)
+		x = fnn.New(ctx.In("004_fnn"), x, embedSize).NumHiddenLayers(1, embedSize).Done()
+		if dropoutNode != nil {
+			x = layers.Dropout(ctx.In("005_dropout"), x, dropoutNode)
+		}
+		embed = Add(embed, x)
+	}
+	return NormalizeSequence(ctx.In("final_normalization"), embed)
+}
+
 /*
 // MaskedWordTaskGraph builds the computation graph for the predicting a hidden word unsupervised task.
 func MaskedWordTaskGraph(ctx *context.Context, tokens, embed, mask *Node,
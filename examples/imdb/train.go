@@ -109,11 +109,13 @@ func CreateDefaultContext() *context.Context {
 		"cnn_normalization": "",  // Set to "none" for no normalization. If "" it falls back to layers.ParamNormalization.
 
 		// Transformers
-		"transformer_max_att_len":    200,  // Maximum attention length: input will be split in ranges of this size.
-		"transformer_num_att_heads":  2,    // umber of attention heads,/ if --model=transformer.
-		"transformer_num_att_layers": 1,    // Number of stacked attention layers, if --model=transformer.
-		"transformer_att_key_size":   8,    // Dimension of the Key/Query attention embedding.
-		"transformer_dropout_rate":   -1.0, // Set to 0.0 for no dropout. If < 0 it falls back to layers.ParamDropoutRate.
+		"transformer_max_att_len":      200,    // Maximum attention length: input will be split in ranges of this size.
+		"transformer_num_att_heads":    2,      // umber of attention heads,/ if --model=transformer.
+		"transformer_num_att_layers":   1,      // Number of stacked attention layers, if --model=transformer.
+		"transformer_att_key_size":     8,      // Dimension of the Key/Query attention embedding.
+		"transformer_dropout_rate":     -1.0,   // Set to 0.0 for no dropout. If < 0 it falls back to layers.ParamDropoutRate.
+		"transformer_att_dropout_rate": 0.0,    // Dropout on the attention coefficients, only applied during training.
+		"transformer_norm_style":       "post", // Where to normalize in each layer: "post" (after the residual) or "pre" (before each sub-layer).
 	})
 	return ctx
 }
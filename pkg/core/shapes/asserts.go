@@ -186,3 +186,77 @@ func CheckScalar(shaped HasShape) error {
 func AssertScalar(shaped HasShape) {
 	shaped.Shape().AssertScalar()
 }
+
+// NamedDims binds names of axes (e.g. "batch", "seq") to dimensions, so that CheckNamedDims and
+// AssertNamedDims can check that the dimensions are consistent across several shapes.
+//
+// The first time a name is seen its dimension is bound, and from then on any axis with the same name must
+// have the same dimension. It can also be initialized with known bindings, e.g.: `NamedDims{"hidden": 768}`.
+//
+// Example:
+//
+//	dims := shapes.NamedDims{}
+//	dims.AssertNamedDims(query, "batch", "seq", "hidden")
+//	dims.AssertNamedDims(mask, "batch", "seq")
+//	batchSize := dims["batch"]
+type NamedDims map[string]int
+
+// CheckNamedDims checks that the shape has the given rank and dimensions, where each dimension is either
+// an int (a fixed dimension, or -1 for an unchecked axis) or a string (the name of the axis, see NamedDims).
+//
+// It returns an error describing the first mismatch, and binds new names only if the whole shape matches.
+func (nd NamedDims) CheckNamedDims(shaped HasShape, dimensions ...any) error {
+	s := shaped.Shape()
+	if s.Rank() != len(dimensions) {
+		return errors.Errorf("shape (%s) has incompatible rank %d (wanted %d, %v)", s, s.Rank(), len(dimensions), dimensions)
+	}
+	newBindings := make(map[string]int)
+	for axis, wantDim := range dimensions {
+		dim := s.Dimensions[axis]
+		switch want := wantDim.(type) {
+		case int:
+			if want != UncheckedAxis && dim != want {
+				return errors.Errorf("shape (%s) axis %d has dimension %d, wanted %d (shape wanted=%v)", s, axis, dim, want, dimensions)
+			}
+		case string:
+			bound, found := nd[want]
+			if !found {
+				bound, found = newBindings[want]
+			}
+			if found && dim != bound {
+				return errors.Errorf("shape (%s) axis %d (%q) has dimension %d, but %q has dimension %d (shape wanted=%v)",
+					s, axis, want, dim, want, bound, dimensions)
+			}
+			newBindings[want] = dim
+		default:
+			return errors.Errorf("invalid dimension %v (type %T) for axis %d, it must be an int or a string with the axis name",
+				wantDim, wantDim, axis)
+		}
+	}
+	for name, dim := range newBindings {
+		nd[name] = dim
+	}
+	return nil
+}
+
+// AssertNamedDims is like CheckNamedDims, but it panics if the shape doesn't match.
+func (nd NamedDims) AssertNamedDims(shaped HasShape, dimensions ...any) {
+	err := nd.CheckNamedDims(shaped, dimensions...)
+	if err != nil {
+		panic(fmt.Sprintf("shapes.AssertNamedDims(%v): %+v", dimensions, err))
+	}
+}
+
+// CheckNamedDims checks that the shape has the given rank and dimensions, where each dimension is either
+// an int (a fixed dimension, or -1 for an unchecked axis) or a string with the name of the axis: axes with
+// the same name must have the same dimension.
+//
+// Use NamedDims to check the names consistently across several shapes.
+func CheckNamedDims(shaped HasShape, dimensions ...any) error {
+	return NamedDims{}.CheckNamedDims(shaped, dimensions...)
+}
+
+// AssertNamedDims is like CheckNamedDims, but it panics if the shape doesn't match.
+func AssertNamedDims(shaped HasShape, dimensions ...any) {
+	NamedDims{}.AssertNamedDims(shaped, dimensions...)
+}
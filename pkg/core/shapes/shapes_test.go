@@ -85,3 +85,27 @@ func TestFromAnyValue(t *testing.T) {
 	shape, err = FromAnyValue([][]float32{{1, 2, 3}, {4, 5}})
 	require.Errorf(t, err, "irregular shape should have returned an error, instead got shape %s", shape)
 }
+
+func TestNamedDims(t *testing.T) {
+	query := Make(dtypes.Float32, 2, 5, 8)
+	mask := Make(dtypes.Bool, 2, 5)
+	require.NoError(t, CheckNamedDims(query, "batch", "seq", 8))
+	require.NoError(t, CheckNamedDims(query, "batch", -1, "hidden"))
+	require.Error(t, CheckNamedDims(query, "batch", "seq", 7))
+	require.Error(t, CheckNamedDims(query, "batch", "batch", "hidden"))
+	require.Error(t, CheckNamedDims(query, "batch", "seq"))
+	require.Error(t, CheckNamedDims(query, "batch", "seq", 1.0))
+
+	dims := NamedDims{"hidden": 8}
+	require.NoError(t, dims.CheckNamedDims(query, "batch", "seq", "hidden"))
+	require.NoError(t, dims.CheckNamedDims(mask, "batch", "seq"))
+	require.Equal(t, NamedDims{"batch": 2, "seq": 5, "hidden": 8}, dims)
+	err := dims.CheckNamedDims(Make(dtypes.Bool, 2, 6), "batch", "seq")
+	require.ErrorContains(t, err, `axis 1 ("seq") has dimension 6, but "seq" has dimension 5`)
+	require.Panics(t, func() { dims.AssertNamedDims(Make(dtypes.Float32, 3), "batch") })
+
+	// Names are only bound if the whole shape matches.
+	dims = NamedDims{}
+	require.Error(t, dims.CheckNamedDims(query, "batch", "seq", 3))
+	require.Empty(t, dims)
+}
@@ -0,0 +1,100 @@
+package tensors
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+
+	"github.com/gomlx/gopjrt/dtypes/bfloat16"
+	"github.com/pkg/errors"
+	"github.com/x448/float16"
+)
+
+// AllCloseMaxReported is the maximum number of mismatched elements reported in the error returned by AllClose.
+var AllCloseMaxReported = 5
+
+// AllClose checks that the tensors have the same shape and that every element satisfies
+// `|a - b| <= atol + rtol * |b|`, as in NumPy's `allclose`. NaNs are never considered close, and infinities
+// are only close to an infinity of the same sign.
+//
+// It returns nil if the tensors are close, or otherwise an error with a report of the mismatches: the number of
+// differing elements, the maximum absolute difference, and the indices and values of the first
+// AllCloseMaxReported differing elements.
+//
+// It supports all float and integer dtypes, and a and b may have different dtypes.
+// It triggers a synchronous transfer from device to local of the tensors that are only on-device.
+func AllClose(a, b *Tensor, rtol, atol float64) error {
+	if !reflect.DeepEqual(a.Shape().Dimensions, b.Shape().Dimensions) {
+		return errors.Errorf("AllClose: tensors have different shapes %s and %s", a.Shape(), b.Shape())
+	}
+	aValues, err := toFloat64Slice(a)
+	if err != nil {
+		return err
+	}
+	bValues, err := toFloat64Slice(b)
+	if err != nil {
+		return err
+	}
+
+	var numMismatches int
+	var maxDiff float64
+	var report strings.Builder
+	for ii, aValue := range aValues {
+		bValue := bValues[ii]
+		diff := math.Abs(aValue - bValue)
+		if aValue == bValue || diff <= atol+rtol*math.Abs(bValue) {
+			continue
+		}
+		numMismatches++
+		if !math.IsNaN(diff) {
+			maxDiff = max(maxDiff, diff)
+		}
+		if numMismatches <= AllCloseMaxReported {
+			_, _ = fmt.Fprintf(&report, "\n\t%v: %g != %g", flatIndexToCoordinates(ii, a.Shape().Dimensions), aValue, bValue)
+		}
+	}
+	if numMismatches == 0 {
+		return nil
+	}
+	if numMismatches > AllCloseMaxReported {
+		_, _ = fmt.Fprintf(&report, "\n\t...")
+	}
+	return errors.Errorf("AllClose(rtol=%g, atol=%g): %d of %d elements differ, max absolute difference %g:%s",
+		rtol, atol, numMismatches, len(aValues), maxDiff, report.String())
+}
+
+// flatIndexToCoordinates converts a flat index of a tensor with the given dimensions (in row-major order)
+// to the coordinates of the element.
+func flatIndexToCoordinates(flatIdx int, dimensions []int) []int {
+	coordinates := make([]int, len(dimensions))
+	for axis := len(dimensions) - 1; axis >= 0; axis-- {
+		coordinates[axis] = flatIdx % dimensions[axis]
+		flatIdx /= dimensions[axis]
+	}
+	return coordinates
+}
+
+// toFloat64Slice returns a copy of the flat data of a float or integer tensor converted to float64.
+func toFloat64Slice(t *Tensor) (converted []float64, err error) {
+	dtype := t.DType()
+	if !dtype.IsFloat() && !dtype.IsInt() {
+		return nil, errors.Errorf("tensor dtype %s not supported, only float and integer dtypes", dtype)
+	}
+	err = t.ConstFlatData(func(flatAny any) {
+		switch flat := flatAny.(type) {
+		case []float16.Float16:
+			converted = convertFlat(flat, func(v float16.Float16) float64 { return float64(v.Float32()) })
+		case []bfloat16.BFloat16:
+			converted = convertFlat(flat, func(v bfloat16.BFloat16) float64 { return float64(v.Float32()) })
+		default:
+			flatV := reflect.ValueOf(flatAny)
+			converted = make([]float64, flatV.Len())
+			float64Type := reflect.TypeOf(float64(0))
+			for ii := range converted {
+				converted[ii] = flatV.Index(ii).Convert(float64Type).Float()
+			}
+		}
+	})
+	return
+}
@@ -0,0 +1,27 @@
+package tensors
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllClose(t *testing.T) {
+	a := FromValue([][]float32{{1, 2}, {3, 4}})
+	require.NoError(t, AllClose(a, FromValue([][]float64{{1, 2.001}, {3, 4}}), 0, 1e-2))
+	require.NoError(t, AllClose(a, FromValue([][]float32{{1, 2}, {3, 4.004}}), 1e-3, 0))
+	require.NoError(t, AllClose(FromValue([]int32{1, 2}), FromValue([]float32{1, 2}), 0, 0))
+	require.NoError(t, AllClose(FromValue([]float32{float32(math.Inf(1))}), FromValue([]float32{float32(math.Inf(1))}), 0, 0))
+
+	err := AllClose(a, FromValue([][]float32{{1, 2.5}, {3, 3}}), 0, 0.1)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "2 of 4 elements differ")
+	require.Contains(t, err.Error(), "max absolute difference 1")
+	require.Contains(t, err.Error(), "[0 1]: 2 != 2.5")
+	require.Contains(t, err.Error(), "[1 1]: 4 != 3")
+
+	require.Error(t, AllClose(FromValue([]float32{float32(math.NaN())}), FromValue([]float32{float32(math.NaN())}), 0, 1))
+	require.Error(t, AllClose(a, FromValue([]float32{1, 2, 3, 4}), 0, 1))
+	require.Error(t, AllClose(FromValue([]bool{true}), FromValue([]bool{true}), 0, 1))
+}
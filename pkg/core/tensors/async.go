@@ -0,0 +1,72 @@
+package tensors
+
+import (
+	"github.com/gomlx/gomlx/backends"
+)
+
+// Transfer is a handle (a "future") to a transfer of a Tensor started asynchronously with
+// Tensor.MaterializeOnDeviceAsync or Tensor.MaterializeLocalAsync.
+//
+// It allows overlapping the transfer with other work, e.g. uploading the inputs of the next step of a
+// generation loop while the current step's outputs are being sampled.
+type Transfer struct {
+	done chan struct{}
+	err  error
+}
+
+// startTransfer runs transferFn in a separate goroutine, and returns the Transfer handle to wait for it.
+func startTransfer(transferFn func() error) *Transfer {
+	tr := &Transfer{done: make(chan struct{})}
+	go func() {
+		defer close(tr.done)
+		tr.err = transferFn()
+	}()
+	return tr
+}
+
+// Wait blocks until the transfer is finished, and returns its error, if any.
+// It can be called any number of times, from any goroutine.
+func (tr *Transfer) Wait() error {
+	<-tr.done
+	return tr.err
+}
+
+// Done returns a channel that is closed when the transfer is finished. Useful with `select`.
+func (tr *Transfer) Done() <-chan struct{} {
+	return tr.done
+}
+
+// MaterializeOnDeviceAsync starts MaterializeOnDevice in a separate goroutine, and returns immediately.
+// Use the returned Transfer to wait for it to finish.
+//
+// The Tensor is locked before it returns, until the transfer is finished, so other accesses to it wait for the
+// transfer.
+func (t *Tensor) MaterializeOnDeviceAsync(backend backends.Backend, share bool, deviceNum backends.DeviceNum) *Transfer {
+	t.mu.Lock()
+	return startTransfer(func() error {
+		defer t.mu.Unlock()
+		if err := t.CheckValid(); err != nil {
+			return err
+		}
+		return t.lockedMaterializeOnDevice(backend, share, deviceNum)
+	})
+}
+
+// MaterializeLocalAsync starts MaterializeLocal in a separate goroutine, and returns immediately.
+// Use the returned Transfer to wait for it to finish.
+//
+// The Tensor is locked before it returns, until the transfer is finished, so other accesses to it wait for the
+// transfer.
+func (t *Tensor) MaterializeLocalAsync() *Transfer {
+	t.mu.Lock()
+	return startTransfer(func() error {
+		defer t.mu.Unlock()
+		if err := t.CheckValid(); err != nil {
+			return err
+		}
+		if t.isShared {
+			return nil
+		}
+		return t.lockedMaterializeLocal()
+	})
+}
@@ -0,0 +1,37 @@
+package tensors_test
+
+import (
+	"testing"
+
+	"github.com/gomlx/gomlx/pkg/core/tensors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferAsync(t *testing.T) {
+	setupTest(t)
+	x := tensors.FromValue([][]float32{{1, 2}, {3, 4}})
+	upload := x.MaterializeOnDeviceAsync(backend, false, 0)
+	require.NoError(t, upload.Wait())
+	<-upload.Done()
+	require.True(t, x.IsOnDevice(0))
+
+	// Drop the local copy, and bring it back asynchronously.
+	x.FinalizeLocal()
+	if !x.IsShared() {
+		require.False(t, x.IsLocal())
+	}
+	require.NoError(t, x.MaterializeLocalAsync().Wait())
+	require.True(t, x.IsLocal())
+	require.Equal(t, [][]float32{{1, 2}, {3, 4}}, x.Value())
+
+	// The tensor is locked when the transfer starts: accesses without waiting see its result.
+	x.FinalizeLocal()
+	download := x.MaterializeLocalAsync()
+	require.Equal(t, [][]float32{{1, 2}, {3, 4}}, x.Value())
+	require.NoError(t, download.Wait())
+
+	// Errors are reported by Wait.
+	x.MustFinalizeAll()
+	require.Error(t, x.MaterializeLocalAsync().Wait())
+	require.Error(t, x.MaterializeOnDeviceAsync(backend, false, 0).Wait())
+}
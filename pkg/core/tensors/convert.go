@@ -0,0 +1,236 @@
+package tensors
+
+import (
+	"math"
+
+	"github.com/gomlx/gopjrt/dtypes"
+	"github.com/gomlx/gopjrt/dtypes/bfloat16"
+	"github.com/pkg/errors"
+	"github.com/x448/float16"
+)
+
+// ToFloat32Slice returns a copy of the flat data of a Tensor converted to float32.
+//
+// It supports the floating point dtypes (Float16, BFloat16, Float32 and Float64) and the integer dtypes
+// (Int8 to Int64 and Uint8 to Uint64), and returns an error for any other dtype.
+// Values not exactly representable in float32 are rounded to the nearest float32.
+//
+// It triggers a synchronous transfer from device to local if the tensor is only on-device.
+func ToFloat32Slice(t *Tensor) (converted []float32, err error) {
+	var supported bool
+	err = t.ConstFlatData(func(flatAny any) {
+		supported = true
+		switch flat := flatAny.(type) {
+		case []float32:
+			converted = convertFlat(flat, func(v float32) float32 { return v })
+		case []float64:
+			converted = convertFlat(flat, func(v float64) float32 { return float32(v) })
+		case []float16.Float16:
+			converted = convertFlat(flat, func(v float16.Float16) float32 { return v.Float32() })
+		case []bfloat16.BFloat16:
+			converted = convertFlat(flat, func(v bfloat16.BFloat16) float32 { return v.Float32() })
+		default:
+			converted, supported = convertIntegerFlat[float32](flatAny)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !supported {
+		return nil, errors.Errorf("ToFloat32Slice: tensor dtype %s not supported", t.DType())
+	}
+	return converted, nil
+}
+
+// ToInt32Slice returns a copy of the flat data of an integer Tensor converted to int32.
+//
+// It supports the integer dtypes (Int8 to Int64 and Uint8 to Uint64), and returns an error for any other dtype.
+// Values out of the int32 range are truncated, as with a Go conversion.
+//
+// It triggers a synchronous transfer from device to local if the tensor is only on-device.
+func ToInt32Slice(t *Tensor) (converted []int32, err error) {
+	var supported bool
+	err = t.ConstFlatData(func(flatAny any) {
+		converted, supported = convertIntegerFlat[int32](flatAny)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !supported {
+		return nil, errors.Errorf("ToInt32Slice: tensor dtype %s not supported", t.DType())
+	}
+	return converted, nil
+}
+
+// integer is the set of the Go types of the integer dtypes.
+type integer interface {
+	int8 | int16 | int32 | int64 | uint8 | uint16 | uint32 | uint64
+}
+
+// convertIntegerFlat converts a flat slice of any of the Go integer types to the type To.
+// It returns false if flatAny is not a slice of a supported integer type.
+func convertIntegerFlat[To integer | float32](flatAny any) ([]To, bool) {
+	switch flat := flatAny.(type) {
+	case []int8:
+		return convertFlat(flat, func(v int8) To { return To(v) }), true
+	case []int16:
+		return convertFlat(flat, func(v int16) To { return To(v) }), true
+	case []int32:
+		return convertFlat(flat, func(v int32) To { return To(v) }), true
+	case []int64:
+		return convertFlat(flat, func(v int64) To { return To(v) }), true
+	case []uint8:
+		return convertFlat(flat, func(v uint8) To { return To(v) }), true
+	case []uint16:
+		return convertFlat(flat, func(v uint16) To { return To(v) }), true
+	case []uint32:
+		return convertFlat(flat, func(v uint32) To { return To(v) }), true
+	case []uint64:
+		return convertFlat(flat, func(v uint64) To { return To(v) }), true
+	default:
+		return nil, false
+	}
+}
+
+// FromFloat32Slice creates a Tensor with the given dtype and dimensions from float32 values, converting them
+// to the dtype.
+//
+// It supports the dtypes Float16, BFloat16, Float32 and Float64, and returns an error for any other dtype, or if
+// the number of values doesn't match the dimensions.
+func FromFloat32Slice(dtype dtypes.DType, values []float32, dimensions ...int) (*Tensor, error) {
+	size := 1
+	for _, dim := range dimensions {
+		size *= dim
+	}
+	if size != len(values) {
+		return nil, errors.Errorf("FromFloat32Slice: %d values given, but dimensions %v require %d", len(values), dimensions, size)
+	}
+	switch dtype {
+	case dtypes.Float32:
+		return FromFlatDataAndDimensions(convertFlat(values, func(v float32) float32 { return v }), dimensions...), nil
+	case dtypes.Float64:
+		return FromFlatDataAndDimensions(convertFlat(values, func(v float32) float64 { return float64(v) }), dimensions...), nil
+	case dtypes.Float16:
+		return FromFlatDataAndDimensions(convertFlat(values, float16.Fromfloat32), dimensions...), nil
+	case dtypes.BFloat16:
+		return FromFlatDataAndDimensions(convertFlat(values, bfloat16.FromFloat32), dimensions...), nil
+	default:
+		return nil, errors.Errorf("FromFloat32Slice: dtype %s not supported", dtype)
+	}
+}
+
+// ConvertDType returns a new local Tensor with the values of t converted to the given dtype, on the host.
+// E.g.: to load float32 weights into a BFloat16 model.
+//
+// It supports conversions among float and integer dtypes. Conversions from float to integer truncate
+// the values, and conversions among integers wrap around, as in Go. It returns an error for any other dtype.
+//
+// It triggers a synchronous transfer from device to local if the tensor is only on-device.
+func ConvertDType(t *Tensor, dtype dtypes.DType) (*Tensor, error) {
+	if !dtype.IsFloat() && !dtype.IsInt() {
+		return nil, errors.Errorf("ConvertDType: dtype %s not supported, only float and integer dtypes", dtype)
+	}
+	if t.DType().IsInt() && dtype.IsInt() {
+		// Converted directly: float64 can't represent all the Int64 and Uint64 values.
+		var converted *Tensor
+		err := t.ConstFlatData(func(flatAny any) {
+			converted = integerTensorFromFlat(dtype, flatAny, t.Shape().Dimensions)
+		})
+		if err != nil {
+			return nil, errors.WithMessage(err, "ConvertDType")
+		}
+		return converted, nil
+	}
+	values, err := toFloat64Slice(t)
+	if err != nil {
+		return nil, errors.WithMessage(err, "ConvertDType")
+	}
+	return fromFloat64Slice(dtype, values, t.Shape().Dimensions)
+}
+
+// integerTensorFromFlat creates a tensor of the given integer dtype, converting the values of the flat slice of
+// integers with Go conversions.
+func integerTensorFromFlat(dtype dtypes.DType, flatAny any, dimensions []int) *Tensor {
+	switch dtype {
+	case dtypes.Int8:
+		return integerTensorFromFlatAs[int8](flatAny, dimensions)
+	case dtypes.Int16:
+		return integerTensorFromFlatAs[int16](flatAny, dimensions)
+	case dtypes.Int32:
+		return integerTensorFromFlatAs[int32](flatAny, dimensions)
+	case dtypes.Int64:
+		return integerTensorFromFlatAs[int64](flatAny, dimensions)
+	case dtypes.Uint8:
+		return integerTensorFromFlatAs[uint8](flatAny, dimensions)
+	case dtypes.Uint16:
+		return integerTensorFromFlatAs[uint16](flatAny, dimensions)
+	case dtypes.Uint32:
+		return integerTensorFromFlatAs[uint32](flatAny, dimensions)
+	default:
+		return integerTensorFromFlatAs[uint64](flatAny, dimensions)
+	}
+}
+
+func integerTensorFromFlatAs[To integer](flatAny any, dimensions []int) *Tensor {
+	converted, _ := convertIntegerFlat[To](flatAny)
+	return FromFlatDataAndDimensions(converted, dimensions...)
+}
+
+// fromFloat64Slice creates a tensor of the given float or integer dtype, converting the values from float64.
+func fromFloat64Slice(dtype dtypes.DType, values []float64, dimensions []int) (*Tensor, error) {
+	switch dtype {
+	case dtypes.Float64:
+		return FromFlatDataAndDimensions(values, dimensions...), nil
+	case dtypes.Float32:
+		return FromFlatDataAndDimensions(convertFlat(values, func(v float64) float32 { return float32(v) }), dimensions...), nil
+	case dtypes.Float16:
+		return FromFlatDataAndDimensions(convertFlat(values, float16FromFloat64), dimensions...), nil
+	case dtypes.BFloat16:
+		return FromFlatDataAndDimensions(convertFlat(values, bfloat16.FromFloat64), dimensions...), nil
+	case dtypes.Int8:
+		return FromFlatDataAndDimensions(convertFlat(values, func(v float64) int8 { return int8(v) }), dimensions...), nil
+	case dtypes.Int16:
+		return FromFlatDataAndDimensions(convertFlat(values, func(v float64) int16 { return int16(v) }), dimensions...), nil
+	case dtypes.Int32:
+		return FromFlatDataAndDimensions(convertFlat(values, func(v float64) int32 { return int32(v) }), dimensions...), nil
+	case dtypes.Int64:
+		return FromFlatDataAndDimensions(convertFlat(values, func(v float64) int64 { return int64(v) }), dimensions...), nil
+	case dtypes.Uint8:
+		return FromFlatDataAndDimensions(convertFlat(values, func(v float64) uint8 { return uint8(v) }), dimensions...), nil
+	case dtypes.Uint16:
+		return FromFlatDataAndDimensions(convertFlat(values, func(v float64) uint16 { return uint16(v) }), dimensions...), nil
+	case dtypes.Uint32:
+		return FromFlatDataAndDimensions(convertFlat(values, func(v float64) uint32 { return uint32(v) }), dimensions...), nil
+	case dtypes.Uint64:
+		return FromFlatDataAndDimensions(convertFlat(values, func(v float64) uint64 { return uint64(v) }), dimensions...), nil
+	default:
+		return nil, errors.Errorf("dtype %s not supported", dtype)
+	}
+}
+
+// float16FromFloat64 converts v to Float16, rounding to the nearest value (ties to even) only once.
+//
+// Converting through float32 with the usual rounding could round twice (e.g. 1+2⁻¹¹+2⁻⁴⁰ is rounded to the tie
+// 1+2⁻¹¹ in float32, and then down to 1), so it is rounded to float32 with "round to odd" instead: inexact values
+// are truncated and their last bit set, which preserves the direction of the rounding for the Float16 conversion.
+func float16FromFloat64(v float64) float16.Float16 {
+	f32 := float32(v)
+	if float64(f32) != v && !math.IsNaN(v) && !math.IsInf(float64(f32), 0) {
+		bits := math.Float32bits(f32)
+		if math.Abs(float64(f32)) > math.Abs(v) {
+			// Truncate towards zero.
+			bits--
+		}
+		f32 = math.Float32frombits(bits | 1)
+	}
+	return float16.Fromfloat32(f32)
+}
+
+// convertFlat returns a new slice with the values of flat converted with convertFn.
+func convertFlat[From, To any](flat []From, convertFn func(From) To) []To {
+	converted := make([]To, len(flat))
+	for ii, v := range flat {
+		converted[ii] = convertFn(v)
+	}
+	return converted
+}
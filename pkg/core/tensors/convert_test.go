@@ -0,0 +1,119 @@
+package tensors
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gomlx/gopjrt/dtypes"
+	"github.com/gomlx/gopjrt/dtypes/bfloat16"
+	"github.com/stretchr/testify/require"
+	"github.com/x448/float16"
+)
+
+func TestToFloat32Slice(t *testing.T) {
+	want := []float32{1, -2.5, 0.125}
+	got, err := ToFloat32Slice(FromValue([]float64{1, -2.5, 0.125}))
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+
+	got, err = ToFloat32Slice(FromFlatDataAndDimensions([]bfloat16.BFloat16{bfloat16.FromFloat32(1), bfloat16.FromFloat32(-2.5), bfloat16.FromFloat32(0.125)}, 3))
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+
+	got, err = ToFloat32Slice(FromFlatDataAndDimensions([]float16.Float16{float16.Fromfloat32(1), float16.Fromfloat32(-2.5), float16.Fromfloat32(0.125)}, 3))
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+
+	got, err = ToFloat32Slice(FromValue([]int64{1, -2, 7}))
+	require.NoError(t, err)
+	require.Equal(t, []float32{1, -2, 7}, got)
+
+	got, err = ToFloat32Slice(FromFlatDataAndDimensions([]uint16{1, 2, 65535}, 3))
+	require.NoError(t, err)
+	require.Equal(t, []float32{1, 2, 65535}, got)
+
+	_, err = ToFloat32Slice(FromValue([]bool{true}))
+	require.Error(t, err)
+	_, err = ToFloat32Slice(FromValue([]complex64{1}))
+	require.Error(t, err)
+}
+
+func TestToInt32Slice(t *testing.T) {
+	want := []int32{1, -2, 7}
+	for _, tensor := range []*Tensor{
+		FromValue([]int32{1, -2, 7}),
+		FromValue([]int64{1, -2, 7}),
+		FromFlatDataAndDimensions([]int8{1, -2, 7}, 3),
+		FromFlatDataAndDimensions([]int16{1, -2, 7}, 3),
+	} {
+		got, err := ToInt32Slice(tensor)
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+	}
+	got, err := ToInt32Slice(FromValue([]uint8{1, 2, 255}))
+	require.NoError(t, err)
+	require.Equal(t, []int32{1, 2, 255}, got)
+
+	_, err = ToInt32Slice(FromValue([]float32{1}))
+	require.Error(t, err)
+	_, err = ToInt32Slice(FromValue([]bool{true}))
+	require.Error(t, err)
+}
+
+func TestFromFloat32Slice(t *testing.T) {
+	values := []float32{1, -2.5, 0.125, 4}
+	for _, dtype := range []dtypes.DType{dtypes.Float32, dtypes.Float64, dtypes.Float16, dtypes.BFloat16} {
+		tensor, err := FromFloat32Slice(dtype, values, 2, 2)
+		require.NoError(t, err)
+		require.Equal(t, dtype, tensor.DType())
+		require.NoError(t, tensor.Shape().CheckDims(2, 2))
+		got, err := ToFloat32Slice(tensor)
+		require.NoError(t, err)
+		require.Equal(t, values, got)
+	}
+	_, err := FromFloat32Slice(dtypes.Int32, values, 4)
+	require.Error(t, err)
+	_, err = FromFloat32Slice(dtypes.Float32, values, 3)
+	require.Error(t, err)
+}
+
+func TestConvertDType(t *testing.T) {
+	x := FromValue([][]float32{{1, -2.5}, {0.125, 300}})
+	for _, dtype := range []dtypes.DType{dtypes.Float64, dtypes.Float16, dtypes.BFloat16} {
+		converted, err := ConvertDType(x, dtype)
+		require.NoError(t, err)
+		require.Equal(t, dtype, converted.DType())
+		require.NoError(t, converted.Shape().CheckDims(2, 2))
+		back, err := ConvertDType(converted, dtypes.Float32)
+		require.NoError(t, err)
+		require.Equal(t, x.Value(), back.Value())
+	}
+
+	converted, err := ConvertDType(x, dtypes.Int32)
+	require.NoError(t, err)
+	require.Equal(t, [][]int32{{1, -2}, {0, 300}}, converted.Value())
+
+	// Integers beyond 2^53 are not representable in float64, so they are converted directly.
+	const large = int64(1)<<53 + 1
+	converted, err = ConvertDType(FromValue([]int64{large, -large}), dtypes.Int64)
+	require.NoError(t, err)
+	require.Equal(t, []int64{large, -large}, converted.Value())
+	converted, err = ConvertDType(FromValue([]int64{large, -1}), dtypes.Uint64)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{uint64(large), math.MaxUint64}, converted.Value())
+	converted, err = ConvertDType(FromValue([]uint64{math.MaxUint64, 300}), dtypes.Int8)
+	require.NoError(t, err)
+	require.Equal(t, []int8{-1, 44}, converted.Value())
+
+	// Float16 is rounded only once: through float32, 1+2⁻¹¹+2⁻⁴⁰ would be rounded to the tie 1+2⁻¹¹, and then to 1.
+	converted, err = ConvertDType(FromValue([]float64{1 + 0x1p-11 + 0x1p-40, -(1 + 0x1p-11 + 0x1p-40), 1 + 0x1p-11}),
+		dtypes.Float16)
+	require.NoError(t, err)
+	require.Equal(t, []float16.Float16{float16.Fromfloat32(1 + 0x1p-10), float16.Fromfloat32(-(1 + 0x1p-10)),
+		float16.Fromfloat32(1)}, converted.Value())
+
+	_, err = ConvertDType(x, dtypes.Bool)
+	require.Error(t, err)
+	_, err = ConvertDType(FromValue([]bool{true}), dtypes.Float32)
+	require.Error(t, err)
+}
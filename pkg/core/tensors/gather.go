@@ -0,0 +1,100 @@
+package tensors
+
+import (
+	"reflect"
+	"slices"
+
+	"github.com/gomlx/gomlx/pkg/core/shapes"
+	"github.com/pkg/errors"
+)
+
+// GatherRows returns a new local Tensor with the rows (the indices of the first axis) of t selected by indices,
+// in the given order. Indices can be repeated. E.g.: to reorder the beams of a `[numBeams, ...]` cache in beam search.
+//
+// It works on the host, without building a computation graph, and it supports any dtype.
+func GatherRows(t *Tensor, indices []int) (*Tensor, error) {
+	if t.Rank() == 0 {
+		return nil, errors.Errorf("GatherRows requires a tensor with rank >= 1, got shape %s", t.Shape())
+	}
+	numRows := t.Shape().Dimensions[0]
+	for ii, idx := range indices {
+		if idx < 0 || idx >= numRows {
+			return nil, errors.Errorf("GatherRows: indices[%d]=%d out of range for tensor shaped %s", ii, idx, t.Shape())
+		}
+	}
+	dims := slices.Clone(t.Shape().Dimensions)
+	dims[0] = len(indices)
+	result := FromShape(shapes.Make(t.DType(), dims...))
+	if result.Size() == 0 {
+		return result, nil
+	}
+	rowSize := t.Size() / numRows
+	var err error
+	errSrc := t.ConstFlatData(func(srcFlat any) {
+		srcV := reflect.ValueOf(srcFlat)
+		err = result.MutableFlatData(func(dstFlat any) {
+			dstV := reflect.ValueOf(dstFlat)
+			for ii, idx := range indices {
+				reflect.Copy(dstV.Slice(ii*rowSize, (ii+1)*rowSize), srcV.Slice(idx*rowSize, (idx+1)*rowSize))
+			}
+		})
+	})
+	if errSrc != nil {
+		return nil, errSrc
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ScatterRows writes, in place, the rows of src into the rows of dst selected by indices: the row i of src is
+// written to the row indices[i] of dst. If indices are repeated, the last row written wins.
+// E.g.: to write the updated entries of a compacted batch back to the full batch.
+//
+// dst and src must have the same dtype, and the same dimensions except for the first axis, whose dimension
+// in src must be len(indices).
+//
+// dst and src can be the same tensor, e.g. to permute its rows in place: src is then cloned first.
+//
+// It works on the host, without building a computation graph, and it supports any dtype.
+// Any on-device copy of dst is invalidated.
+func ScatterRows(dst *Tensor, indices []int, src *Tensor) error {
+	if dst.Rank() == 0 || src.Rank() != dst.Rank() || dst.DType() != src.DType() ||
+		!slices.Equal(dst.Shape().Dimensions[1:], src.Shape().Dimensions[1:]) || src.Shape().Dimensions[0] != len(indices) {
+		return errors.Errorf("ScatterRows: incompatible shapes for dst=%s, src=%s and %d indices", dst.Shape(), src.Shape(), len(indices))
+	}
+	numRows := dst.Shape().Dimensions[0]
+	for ii, idx := range indices {
+		if idx < 0 || idx >= numRows {
+			return errors.Errorf("ScatterRows: indices[%d]=%d out of range for dst shaped %s", ii, idx, dst.Shape())
+		}
+	}
+	if src.Size() == 0 {
+		return nil
+	}
+	if src == dst {
+		// Writing the rows while reading them would overwrite rows not read yet (and lock the tensor twice).
+		clone, err := src.LocalClone()
+		if err != nil {
+			return errors.WithMessage(err, "ScatterRows: failed to clone src, which is the same tensor as dst")
+		}
+		defer clone.FinalizeAll()
+		src = clone
+	}
+	rowSize := src.Size() / len(indices)
+	var err error
+	errSrc := src.ConstFlatData(func(srcFlat any) {
+		srcV := reflect.ValueOf(srcFlat)
+		err = dst.MutableFlatData(func(dstFlat any) {
+			dstV := reflect.ValueOf(dstFlat)
+			for ii, idx := range indices {
+				reflect.Copy(dstV.Slice(idx*rowSize, (idx+1)*rowSize), srcV.Slice(ii*rowSize, (ii+1)*rowSize))
+			}
+		})
+	})
+	if errSrc != nil {
+		return errSrc
+	}
+	return err
+}
@@ -0,0 +1,39 @@
+package tensors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGatherRows(t *testing.T) {
+	x := FromValue([][]int32{{0, 1}, {2, 3}, {4, 5}})
+	got, err := GatherRows(x, []int{2, 0, 2})
+	require.NoError(t, err)
+	require.Equal(t, [][]int32{{4, 5}, {0, 1}, {4, 5}}, got.Value())
+
+	got, err = GatherRows(FromValue([]float64{1, 2, 3}), []int{1})
+	require.NoError(t, err)
+	require.Equal(t, []float64{2}, got.Value())
+
+	_, err = GatherRows(x, []int{3})
+	require.Error(t, err)
+	_, err = GatherRows(FromScalar(1.0), []int{0})
+	require.Error(t, err)
+}
+
+func TestScatterRows(t *testing.T) {
+	dst := FromValue([][]int32{{0, 1}, {2, 3}, {4, 5}})
+	require.NoError(t, ScatterRows(dst, []int{2, 0}, FromValue([][]int32{{7, 8}, {9, 10}})))
+	require.Equal(t, [][]int32{{9, 10}, {2, 3}, {7, 8}}, dst.Value())
+
+	require.Error(t, ScatterRows(dst, []int{0}, FromValue([][]int32{{7, 8, 9}})))
+	require.Error(t, ScatterRows(dst, []int{0, 1}, FromValue([][]int32{{7, 8}})))
+	require.Error(t, ScatterRows(dst, []int{0}, FromValue([][]float32{{7, 8}})))
+	require.Error(t, ScatterRows(dst, []int{3}, FromValue([][]int32{{7, 8}})))
+
+	// Permuting the rows in place: src and dst are the same tensor.
+	dst = FromValue([][]int32{{0, 1}, {2, 3}, {4, 5}})
+	require.NoError(t, ScatterRows(dst, []int{1, 2, 0}, dst))
+	require.Equal(t, [][]int32{{4, 5}, {0, 1}, {2, 3}}, dst.Value())
+}
@@ -0,0 +1,90 @@
+package tensors
+
+import (
+	"slices"
+
+	"github.com/gomlx/gopjrt/dtypes"
+	"github.com/pkg/errors"
+)
+
+// OneHot converts an integer tensor of ids to a one-hot encoding on the host, with a new last axis of
+// dimension depth: the output is shaped `[<ids dimensions...>, depth]`, with 1 at the position of each id
+// and 0 elsewhere.
+//
+// The dtype of the output can be any float, integer or Bool (true/false instead of 1/0).
+// Ids out of the range `[0, depth)` are encoded as all zeros.
+//
+// See graph.OneHot for the graph version.
+func OneHot(ids *Tensor, depth int, dtype dtypes.DType) (*Tensor, error) {
+	if depth <= 0 {
+		return nil, errors.Errorf("OneHot: depth must be > 0, got %d", depth)
+	}
+	if dtype != dtypes.Bool && !dtype.IsFloat() && !dtype.IsInt() {
+		return nil, errors.Errorf("OneHot: output dtype %s not supported", dtype)
+	}
+	var positions []int
+	var supported bool
+	err := ids.ConstFlatData(func(flatAny any) {
+		positions, supported = oneHotPositions(flatAny, depth)
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "OneHot")
+	}
+	if !supported {
+		return nil, errors.Errorf("OneHot requires integer ids, got dtype %s", ids.DType())
+	}
+	dims := append(slices.Clone(ids.Shape().Dimensions), depth)
+	if dtype == dtypes.Bool {
+		flat := make([]bool, len(positions)*depth)
+		for ii, pos := range positions {
+			if pos >= 0 {
+				flat[ii*depth+pos] = true
+			}
+		}
+		return FromFlatDataAndDimensions(flat, dims...), nil
+	}
+	flat := make([]float64, len(positions)*depth)
+	for ii, pos := range positions {
+		if pos >= 0 {
+			flat[ii*depth+pos] = 1
+		}
+	}
+	return fromFloat64Slice(dtype, flat, dims)
+}
+
+// oneHotPositions returns the position of the 1 in the one-hot encoding of each id of the flat slice, or -1 for
+// the ids out of the range [0, depth).
+// The ids are checked in their original type, so large ids can't wrap around into the range.
+// It returns false if flatAny is not a slice of a supported integer type.
+func oneHotPositions(flatAny any, depth int) ([]int, bool) {
+	switch flat := flatAny.(type) {
+	case []int8:
+		return oneHotPositionsOf(flat, depth), true
+	case []int16:
+		return oneHotPositionsOf(flat, depth), true
+	case []int32:
+		return oneHotPositionsOf(flat, depth), true
+	case []int64:
+		return oneHotPositionsOf(flat, depth), true
+	case []uint8:
+		return oneHotPositionsOf(flat, depth), true
+	case []uint16:
+		return oneHotPositionsOf(flat, depth), true
+	case []uint32:
+		return oneHotPositionsOf(flat, depth), true
+	case []uint64:
+		return oneHotPositionsOf(flat, depth), true
+	default:
+		return nil, false
+	}
+}
+
+// oneHotPositionsOf implements oneHotPositions for a slice of ids of type T.
+func oneHotPositionsOf[T integer](ids []T, depth int) []int {
+	return convertFlat(ids, func(id T) int {
+		if id < 0 || uint64(id) >= uint64(depth) {
+			return -1
+		}
+		return int(id)
+	})
+}
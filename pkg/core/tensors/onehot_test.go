@@ -0,0 +1,36 @@
+package tensors
+
+import (
+	"testing"
+
+	"github.com/gomlx/gopjrt/dtypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOneHot(t *testing.T) {
+	ids := FromValue([][]int64{{0, 2}, {1, 3}})
+	got, err := OneHot(ids, 3, dtypes.Float32)
+	require.NoError(t, err)
+	require.Equal(t, [][][]float32{{{1, 0, 0}, {0, 0, 1}}, {{0, 1, 0}, {0, 0, 0}}}, got.Value())
+
+	got, err = OneHot(FromValue([]int32{1}), 2, dtypes.Bool)
+	require.NoError(t, err)
+	require.Equal(t, [][]bool{{false, true}}, got.Value())
+
+	got, err = OneHot(FromScalar(int32(1)), 2, dtypes.Int32)
+	require.NoError(t, err)
+	require.Equal(t, []int32{0, 1}, got.Value())
+
+	// Ids out of the range must not wrap around into it.
+	got, err = OneHot(FromValue([]int64{1 << 32, 1<<32 + 1, -1, 1}), 2, dtypes.Float32)
+	require.NoError(t, err)
+	require.Equal(t, [][]float32{{0, 0}, {0, 0}, {0, 0}, {0, 1}}, got.Value())
+	got, err = OneHot(FromValue([]uint64{1<<64 - 1, 0}), 2, dtypes.Int8)
+	require.NoError(t, err)
+	require.Equal(t, [][]int8{{0, 0}, {1, 0}}, got.Value())
+
+	_, err = OneHot(FromValue([]float32{1}), 2, dtypes.Float32)
+	require.Error(t, err)
+	_, err = OneHot(ids, 0, dtypes.Float32)
+	require.Error(t, err)
+}
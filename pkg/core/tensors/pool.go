@@ -0,0 +1,75 @@
+package tensors
+
+import (
+	"sync"
+
+	"github.com/gomlx/gomlx/pkg/core/shapes"
+)
+
+// Pool recycles local tensors of the same shapes, to avoid allocating new tensors at every step of loops that
+// feed small inputs to a graph (e.g. the `[batchSize, 1]` token ids at each step of a generation loop).
+//
+// Get returns a tensor of the requested shape, either a recycled one or a newly created one, and Put returns
+// a tensor to the pool once it is no longer needed. The contents of a recycled tensor are not cleared: the
+// caller is expected to overwrite them, e.g. with MutableFlatData, which also invalidates any stale
+// on-device copy.
+//
+// It is safe for concurrent use. The zero value is an empty pool ready to use.
+type Pool struct {
+	mu   sync.Mutex
+	free map[string][]*Tensor
+}
+
+// Get returns a local tensor with the given shape, recycled from the pool if available.
+func (p *Pool) Get(shape shapes.Shape) *Tensor {
+	key := shape.String()
+	p.mu.Lock()
+	if free := p.free[key]; len(free) > 0 {
+		t := free[len(free)-1]
+		p.free[key] = free[:len(free)-1]
+		p.mu.Unlock()
+		return t
+	}
+	p.mu.Unlock()
+	return FromShape(shape)
+}
+
+// Put returns the tensor to the pool, so it can be recycled by Get. The caller must not use it afterward.
+//
+// Tensors that are no longer valid or that have no local copy (e.g. because they were donated to a graph
+// execution) are ignored.
+func (p *Pool) Put(t *Tensor) {
+	if t == nil || t.CheckValid() != nil || !t.IsLocal() || t.IsShared() {
+		return
+	}
+	key := t.Shape().String()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.free == nil {
+		p.free = make(map[string][]*Tensor)
+	}
+	p.free[key] = append(p.free[key], t)
+}
+
+// Len returns the number of tensors available in the pool.
+func (p *Pool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var n int
+	for _, free := range p.free {
+		n += len(free)
+	}
+	return n
+}
+
+// Clear finalizes all tensors in the pool, immediately freeing their memory.
+func (p *Pool) Clear() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, free := range p.free {
+		for _, t := range free {
+			_ = t.FinalizeAll()
+		}
+	}
+	p.free = nil
+}
@@ -0,0 +1,36 @@
+package tensors
+
+import (
+	"testing"
+
+	"github.com/gomlx/gomlx/pkg/core/shapes"
+	"github.com/gomlx/gopjrt/dtypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPool(t *testing.T) {
+	var pool Pool
+	shape := shapes.Make(dtypes.Int32, 2, 1)
+	a := pool.Get(shape)
+	require.True(t, a.Shape().Equal(shape))
+	MustMutableFlatData(a, func(flat []int32) { flat[0], flat[1] = 3, 7 })
+	pool.Put(a)
+	require.Equal(t, 1, pool.Len())
+
+	// Only tensors of the same shape are recycled.
+	b := pool.Get(shapes.Make(dtypes.Int32, 3, 1))
+	require.NotSame(t, a, b)
+	require.Same(t, a, pool.Get(shape))
+	require.Equal(t, 0, pool.Len())
+	require.NotSame(t, a, pool.Get(shape))
+
+	// Invalid tensors are not recycled.
+	b.MustFinalizeAll()
+	pool.Put(b)
+	require.Equal(t, 0, pool.Len())
+
+	pool.Put(a)
+	pool.Clear()
+	require.Equal(t, 0, pool.Len())
+	require.False(t, a.Ok())
+}
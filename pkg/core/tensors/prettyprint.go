@@ -3,6 +3,7 @@ package tensors
 import (
 	"bytes"
 	"fmt"
+	"math"
 	"reflect"
 	"strings"
 
@@ -193,3 +194,45 @@ func (t *Tensor) GoStr() string {
 	}
 	return fmt.Sprintf("%s: %s", t.shape, xslices.SliceToGoStr(value))
 }
+
+// DebugSummary returns a multi-line summary of the Tensor for debugging: its shape, statistics of its values and
+// a truncated preview of the values (see Summary).
+//
+// For float and integer dtypes the statistics include the min, max and mean of the finite values, and the number
+// of NaN and +/-Inf values. They are useful when debugging divergences between backends or converted weights.
+func (t *Tensor) DebugSummary(precision int) string {
+	t.AssertValid()
+	var buf bytes.Buffer
+	w := func(format string, args ...any) { _, _ = fmt.Fprintf(&buf, format, args...) }
+	w("%s", t.Shape())
+	values, err := toFloat64Slice(t)
+	if err == nil {
+		var numNaN, numInf, numFinite int
+		var minValue, maxValue, sum float64
+		for _, v := range values {
+			switch {
+			case math.IsNaN(v):
+				numNaN++
+			case math.IsInf(v, 0):
+				numInf++
+			default:
+				if numFinite == 0 || v < minValue {
+					minValue = v
+				}
+				if numFinite == 0 || v > maxValue {
+					maxValue = v
+				}
+				sum += v
+				numFinite++
+			}
+		}
+		if numFinite > 0 {
+			w(": min=%.*g, max=%.*g, mean=%.*g", precision, minValue, precision, maxValue, precision, sum/float64(numFinite))
+		} else {
+			w(": no finite values")
+		}
+		w(", #NaN=%d, #Inf=%d", numNaN, numInf)
+	}
+	w("\n%s", t.Summary(precision))
+	return buf.String()
+}
@@ -0,0 +1,16 @@
+package tensors
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugSummary(t *testing.T) {
+	got := FromValue([]float32{1, 2, float32(math.NaN()), 6, float32(math.Inf(-1))}).DebugSummary(3)
+	require.Equal(t, "(Float32)[5]: min=1, max=6, mean=3, #NaN=1, #Inf=1\n[5]float32{1, 2, NaN, 6, -Inf}", got)
+
+	got = FromValue([][]bool{{true, false}}).DebugSummary(3)
+	require.Equal(t, "(Bool)[1 2]\n[1][2]bool{{true, false}}", got)
+}
@@ -0,0 +1,142 @@
+package tensors
+
+import (
+	"math"
+
+	"github.com/gomlx/gopjrt/dtypes"
+	"github.com/pkg/errors"
+)
+
+// QuantizationParams holds the parameters of an int8 affine quantization: a float value x is represented
+// by `q = clamp(round(x / scale) + zeroPoint, -128, 127)`, and recovered as `x ≈ (q - zeroPoint) * scale`.
+type QuantizationParams struct {
+	// Axis of the per-channel quantization: there is one scale and zero-point for each index of the axis.
+	// It is -1 for per-tensor quantization, with only one scale and zero-point.
+	Axis int
+
+	// Scales and ZeroPoints, one per channel for per-channel quantization, or only one for per-tensor quantization.
+	Scales     []float32
+	ZeroPoints []int32
+}
+
+// QuantizeInt8 quantizes a float tensor to Int8 with one scale and zero-point for the whole tensor.
+//
+// If symmetric is true, the zero-point is always 0 and the range `[-max(|x|), max(|x|)]` is mapped to `[-127, 127]`,
+// which is the usual for weights. Otherwise, the range `[min(x, 0), max(x, 0)]` is mapped to `[-128, 127]`,
+// which is more precise for skewed distributions (e.g. activations after a ReLU).
+//
+// It returns the Int8 tensor and the quantization parameters to use with DequantizeInt8.
+func QuantizeInt8(t *Tensor, symmetric bool) (quantized *Tensor, params *QuantizationParams, err error) {
+	return quantizeInt8(t, -1, symmetric)
+}
+
+// QuantizeInt8PerChannel quantizes a float tensor to Int8 with one scale and zero-point for each index of the
+// given axis (the "channels"), e.g. the output axis of the weights of a dense layer. The axis can be negative,
+// counted from the end.
+//
+// See QuantizeInt8 for the meaning of symmetric.
+func QuantizeInt8PerChannel(t *Tensor, axis int, symmetric bool) (quantized *Tensor, params *QuantizationParams, err error) {
+	rank := t.Rank()
+	adjustedAxis := axis
+	if adjustedAxis < 0 {
+		adjustedAxis += rank
+	}
+	if adjustedAxis < 0 || adjustedAxis >= rank {
+		return nil, nil, errors.Errorf("QuantizeInt8PerChannel: invalid axis %d for tensor shaped %s", axis, t.Shape())
+	}
+	return quantizeInt8(t, adjustedAxis, symmetric)
+}
+
+// channelIndexFn returns a function that maps a flat index of a tensor with the given dimensions to the index
+// in the given axis, and the number of channels. If axis is -1, there is only one channel.
+func channelIndexFn(dimensions []int, axis int) (fn func(flatIdx int) int, numChannels int) {
+	if axis < 0 {
+		return func(int) int { return 0 }, 1
+	}
+	innerSize := 1
+	for _, dim := range dimensions[axis+1:] {
+		innerSize *= dim
+	}
+	numChannels = dimensions[axis]
+	return func(flatIdx int) int { return (flatIdx / innerSize) % numChannels }, numChannels
+}
+
+func quantizeInt8(t *Tensor, axis int, symmetric bool) (quantized *Tensor, params *QuantizationParams, err error) {
+	if !t.DType().IsFloat() {
+		return nil, nil, errors.Errorf("QuantizeInt8: dtype %s not supported, only float dtypes", t.DType())
+	}
+	values, err := toFloat64Slice(t)
+	if err != nil {
+		return nil, nil, err
+	}
+	channelOf, numChannels := channelIndexFn(t.Shape().Dimensions, axis)
+
+	// Range of each channel, always including 0, so that 0 is exactly representable.
+	minValues := make([]float64, numChannels)
+	maxValues := make([]float64, numChannels)
+	for ii, v := range values {
+		c := channelOf(ii)
+		minValues[c] = min(minValues[c], v)
+		maxValues[c] = max(maxValues[c], v)
+	}
+	params = &QuantizationParams{
+		Axis:       axis,
+		Scales:     make([]float32, numChannels),
+		ZeroPoints: make([]int32, numChannels),
+	}
+	for c := range numChannels {
+		var scale float64
+		if symmetric {
+			scale = max(-minValues[c], maxValues[c]) / 127
+		} else {
+			scale = (maxValues[c] - minValues[c]) / 255
+		}
+		if scale == 0 {
+			scale = 1 // All values are 0.
+		}
+		params.Scales[c] = float32(scale)
+		if !symmetric {
+			params.ZeroPoints[c] = int32(min(max(math.Round(-128-minValues[c]/scale), -128), 127))
+		}
+	}
+
+	quantizedValues := make([]int8, len(values))
+	for ii, v := range values {
+		c := channelOf(ii)
+		q := math.Round(v/float64(params.Scales[c])) + float64(params.ZeroPoints[c])
+		quantizedValues[ii] = int8(min(max(q, -128), 127))
+	}
+	quantized = FromFlatDataAndDimensions(quantizedValues, t.Shape().Dimensions...)
+	return quantized, params, nil
+}
+
+// DequantizeInt8 converts an Int8 tensor quantized with QuantizeInt8 or QuantizeInt8PerChannel back to the given
+// float dtype, using the quantization params returned by them.
+func DequantizeInt8(quantized *Tensor, params *QuantizationParams, dtype dtypes.DType) (*Tensor, error) {
+	if quantized.DType() != dtypes.Int8 {
+		return nil, errors.Errorf("DequantizeInt8: quantized tensor must be Int8, got %s", quantized.DType())
+	}
+	if !dtype.IsFloat() {
+		return nil, errors.Errorf("DequantizeInt8: dtype %s not supported, only float dtypes", dtype)
+	}
+	if params.Axis >= quantized.Rank() {
+		return nil, errors.Errorf("DequantizeInt8: invalid axis %d for tensor shaped %s", params.Axis, quantized.Shape())
+	}
+	channelOf, numChannels := channelIndexFn(quantized.Shape().Dimensions, params.Axis)
+	if len(params.Scales) != numChannels || len(params.ZeroPoints) != numChannels {
+		return nil, errors.Errorf("DequantizeInt8: expected %d scales and zero-points, got %d and %d",
+			numChannels, len(params.Scales), len(params.ZeroPoints))
+	}
+	var values []float64
+	err := ConstFlatData(quantized, func(flat []int8) {
+		values = make([]float64, len(flat))
+		for ii, q := range flat {
+			c := channelOf(ii)
+			values[ii] = float64(int32(q)-params.ZeroPoints[c]) * float64(params.Scales[c])
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fromFloat64Slice(dtype, values, quantized.Shape().Dimensions)
+}
@@ -0,0 +1,53 @@
+package tensors
+
+import (
+	"testing"
+
+	"github.com/gomlx/gopjrt/dtypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuantizeInt8(t *testing.T) {
+	x := FromValue([][]float32{{-1, 0, 0.5}, {2, 0.25, -0.125}})
+	for _, symmetric := range []bool{true, false} {
+		quantized, params, err := QuantizeInt8(x, symmetric)
+		require.NoError(t, err)
+		require.Equal(t, dtypes.Int8, quantized.DType())
+		require.Equal(t, -1, params.Axis)
+		require.Len(t, params.Scales, 1)
+		if symmetric {
+			require.Equal(t, int32(0), params.ZeroPoints[0])
+			require.InDelta(t, 2.0/127, params.Scales[0], 1e-6)
+		} else {
+			require.InDelta(t, 3.0/255, params.Scales[0], 1e-6)
+		}
+		dequantized, err := DequantizeInt8(quantized, params, dtypes.Float32)
+		require.NoError(t, err)
+		require.NoError(t, AllClose(dequantized, x, 0, float64(params.Scales[0])/2+1e-6))
+		// Zero is exactly representable.
+		require.Equal(t, float32(0), dequantized.Value().([][]float32)[0][1])
+	}
+}
+
+func TestQuantizeInt8PerChannel(t *testing.T) {
+	x := FromValue([][]float32{{-1, 100}, {0.5, -50}, {0, 25}})
+	quantized, params, err := QuantizeInt8PerChannel(x, -1, true)
+	require.NoError(t, err)
+	require.Equal(t, 1, params.Axis)
+	require.Len(t, params.Scales, 2)
+	require.InDelta(t, 1.0/127, params.Scales[0], 1e-6)
+	require.InDelta(t, 100.0/127, params.Scales[1], 1e-5)
+	require.Equal(t, [][]int8{{-127, 127}, {64, -64}, {0, 32}}, quantized.Value())
+	dequantized, err := DequantizeInt8(quantized, params, dtypes.Float64)
+	require.NoError(t, err)
+	require.Equal(t, dtypes.Float64, dequantized.DType())
+	require.NoError(t, AllClose(dequantized, x, 0.01, 0))
+
+	_, _, err = QuantizeInt8PerChannel(x, 2, true)
+	require.Error(t, err)
+	_, _, err = QuantizeInt8(FromValue([]int32{1}), true)
+	require.Error(t, err)
+	params.Scales = params.Scales[:1]
+	_, err = DequantizeInt8(quantized, params, dtypes.Float32)
+	require.Error(t, err)
+}
@@ -0,0 +1,51 @@
+package tensors
+
+import (
+	"math/rand/v2"
+
+	"github.com/gomlx/gopjrt/dtypes"
+	"github.com/pkg/errors"
+)
+
+// RandomNormal creates a Tensor with the given float dtype and dimensions, filled with random values from a
+// normal distribution with mean 0 and standard deviation 1.
+//
+// The values are generated on the host, with a PCG generator initialized with seed, so the same seed always
+// generates the same values, independent of the backend. Useful for reproducible tests.
+//
+// It returns an error if dtype is not a float dtype.
+func RandomNormal(dtype dtypes.DType, seed uint64, dimensions ...int) (*Tensor, error) {
+	rng := rand.New(rand.NewPCG(seed, 0))
+	return randomFloatTensor("RandomNormal", dtype, rng.NormFloat64, dimensions)
+}
+
+// RandomUniform creates a Tensor with the given float dtype and dimensions, filled with random values uniformly
+// distributed in the range [0, 1) -- for Float16 and BFloat16 the rounding may yield 1.
+//
+// The values are generated on the host, with a PCG generator initialized with seed, so the same seed always
+// generates the same values, independent of the backend. Useful for reproducible tests.
+//
+// It returns an error if dtype is not a float dtype.
+func RandomUniform(dtype dtypes.DType, seed uint64, dimensions ...int) (*Tensor, error) {
+	rng := rand.New(rand.NewPCG(seed, 0))
+	return randomFloatTensor("RandomUniform", dtype, rng.Float64, dimensions)
+}
+
+// randomFloatTensor creates a tensor with values generated by randFn.
+func randomFloatTensor(funcName string, dtype dtypes.DType, randFn func() float64, dimensions []int) (*Tensor, error) {
+	if !dtype.IsFloat() {
+		return nil, errors.Errorf("%s: dtype %s not supported, only float dtypes", funcName, dtype)
+	}
+	size := 1
+	for _, dim := range dimensions {
+		if dim < 0 {
+			return nil, errors.Errorf("%s: invalid dimensions %v", funcName, dimensions)
+		}
+		size *= dim
+	}
+	values := make([]float64, size)
+	for ii := range values {
+		values[ii] = randFn()
+	}
+	return fromFloat64Slice(dtype, values, dimensions)
+}
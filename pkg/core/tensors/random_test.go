@@ -0,0 +1,48 @@
+package tensors
+
+import (
+	"testing"
+
+	"github.com/gomlx/gopjrt/dtypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRandom(t *testing.T) {
+	for _, dtype := range []dtypes.DType{dtypes.Float32, dtypes.Float64, dtypes.Float16, dtypes.BFloat16} {
+		uniform, err := RandomUniform(dtype, 42, 100, 10)
+		require.NoError(t, err)
+		require.Equal(t, dtype, uniform.DType())
+		require.NoError(t, uniform.Shape().CheckDims(100, 10))
+		values, err := ToFloat32Slice(uniform)
+		require.NoError(t, err)
+		var sum float32
+		for _, v := range values {
+			require.True(t, v >= 0 && v <= 1)
+			sum += v
+		}
+		require.InDelta(t, 0.5, sum/float32(len(values)), 0.05)
+
+		normal, err := RandomNormal(dtype, 42, 1000)
+		require.NoError(t, err)
+		values, err = ToFloat32Slice(normal)
+		require.NoError(t, err)
+		var sumSq float32
+		for _, v := range values {
+			sumSq += v * v
+		}
+		require.InDelta(t, 1, sumSq/float32(len(values)), 0.15)
+	}
+
+	// Same seed, same values; different seed, different values.
+	a, err := RandomNormal(dtypes.Float32, 1, 5)
+	require.NoError(t, err)
+	b, err := RandomNormal(dtypes.Float32, 1, 5)
+	require.NoError(t, err)
+	require.True(t, a.Equal(b))
+	b, err = RandomNormal(dtypes.Float32, 2, 5)
+	require.NoError(t, err)
+	require.False(t, a.Equal(b))
+
+	_, err = RandomUniform(dtypes.Int32, 1, 5)
+	require.Error(t, err)
+}
@@ -0,0 +1,69 @@
+package tensors
+
+import (
+	"reflect"
+	"slices"
+
+	"github.com/gomlx/gomlx/pkg/core/shapes"
+	"github.com/pkg/errors"
+)
+
+// SliceAxis returns a new local Tensor with the elements from start (inclusive) to end (exclusive) of the given
+// axis of t, and all the elements of the other axes. It works on the host, without building a computation graph,
+// and it supports any dtype.
+//
+// The axis can be negative, in which case it's counted from the end. start and end must satisfy
+// `0 <= start <= end <= t.Shape().Dim(axis)`, otherwise an error is returned.
+//
+// It triggers a synchronous transfer from device to local if the tensor is only on-device.
+func SliceAxis(t *Tensor, axis, start, end int) (*Tensor, error) {
+	shape := t.Shape()
+	rank := shape.Rank()
+	adjustedAxis := axis
+	if adjustedAxis < 0 {
+		adjustedAxis += rank
+	}
+	if adjustedAxis < 0 || adjustedAxis >= rank {
+		return nil, errors.Errorf("SliceAxis: invalid axis %d for tensor shaped %s", axis, shape)
+	}
+	axisDim := shape.Dimensions[adjustedAxis]
+	if start < 0 || end < start || end > axisDim {
+		return nil, errors.Errorf("SliceAxis: invalid range [%d, %d) for axis %d of tensor shaped %s", start, end, axis, shape)
+	}
+
+	dims := slices.Clone(shape.Dimensions)
+	dims[adjustedAxis] = end - start
+	result := FromShape(shapes.Make(shape.DType, dims...))
+	if result.Size() == 0 {
+		return result, nil
+	}
+
+	// Copy one contiguous block of (end-start)*innerSize elements for each combination of the outer axes.
+	outerSize := 1
+	for _, dim := range shape.Dimensions[:adjustedAxis] {
+		outerSize *= dim
+	}
+	innerSize := 1
+	for _, dim := range shape.Dimensions[adjustedAxis+1:] {
+		innerSize *= dim
+	}
+	blockSize := (end - start) * innerSize
+	var err error
+	errSrc := t.ConstFlatData(func(srcFlat any) {
+		srcV := reflect.ValueOf(srcFlat)
+		err = result.MutableFlatData(func(dstFlat any) {
+			dstV := reflect.ValueOf(dstFlat)
+			for outerIdx := range outerSize {
+				srcStart := (outerIdx*axisDim + start) * innerSize
+				reflect.Copy(dstV.Slice(outerIdx*blockSize, (outerIdx+1)*blockSize), srcV.Slice(srcStart, srcStart+blockSize))
+			}
+		})
+	})
+	if errSrc != nil {
+		return nil, errSrc
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
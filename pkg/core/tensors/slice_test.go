@@ -0,0 +1,34 @@
+package tensors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSliceAxis(t *testing.T) {
+	x := FromValue([][][]int32{
+		{{0, 1}, {2, 3}, {4, 5}},
+		{{6, 7}, {8, 9}, {10, 11}},
+	})
+	got, err := SliceAxis(x, 1, 1, 3)
+	require.NoError(t, err)
+	require.Equal(t, [][][]int32{{{2, 3}, {4, 5}}, {{8, 9}, {10, 11}}}, got.Value())
+
+	got, err = SliceAxis(x, -1, 1, 2)
+	require.NoError(t, err)
+	require.Equal(t, [][][]int32{{{1}, {3}, {5}}, {{7}, {9}, {11}}}, got.Value())
+
+	got, err = SliceAxis(FromValue([][]float64{{1, 2}, {3, 4}}), 0, 1, 2)
+	require.NoError(t, err)
+	require.Equal(t, [][]float64{{3, 4}}, got.Value())
+
+	got, err = SliceAxis(x, 0, 1, 1)
+	require.NoError(t, err)
+	require.NoError(t, got.Shape().CheckDims(0, 3, 2))
+
+	_, err = SliceAxis(x, 3, 0, 1)
+	require.Error(t, err)
+	_, err = SliceAxis(x, 1, 2, 4)
+	require.Error(t, err)
+}
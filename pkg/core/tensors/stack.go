@@ -0,0 +1,73 @@
+package tensors
+
+import (
+	"reflect"
+	"slices"
+
+	"github.com/gomlx/gomlx/pkg/core/shapes"
+	"github.com/pkg/errors"
+)
+
+// Stack returns a new local Tensor with the given tensors stacked along a new axis, inserted at the given position.
+// It works on the host, without building a computation graph, and it supports any dtype.
+//
+// All tensors must have the same shape. The axis can be from 0 to the rank of the tensors (inclusive), or negative,
+// in which case it's counted from the end: -1 means a new last axis.
+//
+// It triggers a synchronous transfer from device to local of the tensors that are only on-device.
+func Stack(ts []*Tensor, axis int) (*Tensor, error) {
+	if len(ts) == 0 {
+		return nil, errors.New("Stack requires at least one tensor")
+	}
+	shape := ts[0].Shape()
+	for ii, t := range ts[1:] {
+		if !t.Shape().Equal(shape) {
+			return nil, errors.Errorf("Stack requires all tensors to have the same shape, but tensor #0 is shaped %s "+
+				"and tensor #%d is shaped %s", shape, ii+1, t.Shape())
+		}
+	}
+	rank := shape.Rank()
+	adjustedAxis := axis
+	if adjustedAxis < 0 {
+		adjustedAxis += rank + 1
+	}
+	if adjustedAxis < 0 || adjustedAxis > rank {
+		return nil, errors.Errorf("Stack: invalid axis %d for tensors of rank %d", axis, rank)
+	}
+
+	dims := slices.Insert(slices.Clone(shape.Dimensions), adjustedAxis, len(ts))
+	result := FromShape(shapes.Make(shape.DType, dims...))
+	if result.Size() == 0 {
+		return result, nil
+	}
+
+	// Each tensor contributes one contiguous block of innerSize elements for each combination of the outer axes.
+	outerSize := 1
+	for _, dim := range shape.Dimensions[:adjustedAxis] {
+		outerSize *= dim
+	}
+	innerSize := shape.Size() / outerSize
+	var err error
+	errDst := result.MutableFlatData(func(dstFlat any) {
+		dstV := reflect.ValueOf(dstFlat)
+		for tIdx, t := range ts {
+			err = t.ConstFlatData(func(srcFlat any) {
+				srcV := reflect.ValueOf(srcFlat)
+				for outerIdx := range outerSize {
+					dstStart := (outerIdx*len(ts) + tIdx) * innerSize
+					reflect.Copy(dstV.Slice(dstStart, dstStart+innerSize), srcV.Slice(outerIdx*innerSize, (outerIdx+1)*innerSize))
+				}
+			})
+			if err != nil {
+				return
+			}
+		}
+	})
+	if errDst != nil {
+		return nil, errDst
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
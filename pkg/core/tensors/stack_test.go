@@ -0,0 +1,34 @@
+package tensors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStack(t *testing.T) {
+	a := FromValue([][]int32{{0, 1}, {2, 3}})
+	b := FromValue([][]int32{{4, 5}, {6, 7}})
+	got, err := Stack([]*Tensor{a, b}, 0)
+	require.NoError(t, err)
+	require.Equal(t, [][][]int32{{{0, 1}, {2, 3}}, {{4, 5}, {6, 7}}}, got.Value())
+
+	got, err = Stack([]*Tensor{a, b}, 1)
+	require.NoError(t, err)
+	require.Equal(t, [][][]int32{{{0, 1}, {4, 5}}, {{2, 3}, {6, 7}}}, got.Value())
+
+	got, err = Stack([]*Tensor{a, b}, -1)
+	require.NoError(t, err)
+	require.Equal(t, [][][]int32{{{0, 4}, {1, 5}}, {{2, 6}, {3, 7}}}, got.Value())
+
+	got, err = Stack([]*Tensor{FromScalar(1.0), FromScalar(2.0), FromScalar(3.0)}, 0)
+	require.NoError(t, err)
+	require.Equal(t, []float64{1, 2, 3}, got.Value())
+
+	_, err = Stack([]*Tensor{a, FromValue([]int32{1, 2})}, 0)
+	require.Error(t, err)
+	_, err = Stack([]*Tensor{a, b}, 3)
+	require.Error(t, err)
+	_, err = Stack(nil, 0)
+	require.Error(t, err)
+}
@@ -0,0 +1,124 @@
+package tensors
+
+import (
+	"cmp"
+	"reflect"
+	"slices"
+
+	"github.com/gomlx/gomlx/pkg/core/shapes"
+	"github.com/gomlx/gopjrt/dtypes/bfloat16"
+	"github.com/pkg/errors"
+	"github.com/x448/float16"
+)
+
+// TopK returns the k largest values of t along the given axis, sorted in decreasing order, and their indices
+// (as an Int32 tensor) in the axis. Both are shaped like t, except the axis, which has dimension k.
+// Ties are broken by the lowest index first, and NaNs are considered smaller than any other value.
+//
+// The axis can be negative, in which case it's counted from the end. It works on the host, without building a
+// computation graph, and it supports all float and integer dtypes.
+func TopK(t *Tensor, k, axis int) (values, indices *Tensor, err error) {
+	shape := t.Shape()
+	rank := shape.Rank()
+	adjustedAxis := axis
+	if adjustedAxis < 0 {
+		adjustedAxis += rank
+	}
+	if adjustedAxis < 0 || adjustedAxis >= rank {
+		return nil, nil, errors.Errorf("TopK: invalid axis %d for tensor shaped %s", axis, shape)
+	}
+	axisDim := shape.Dimensions[adjustedAxis]
+	if k < 0 || k > axisDim {
+		return nil, nil, errors.Errorf("TopK: k=%d must be between 0 and %d, the dimension of axis %d", k, axisDim, axis)
+	}
+	dims := slices.Clone(shape.Dimensions)
+	dims[adjustedAxis] = k
+	values = FromShape(shapes.Make(shape.DType, dims...))
+	indicesFlat := make([]int32, values.Size())
+	if values.Size() > 0 {
+		outerSize := 1
+		for _, dim := range shape.Dimensions[:adjustedAxis] {
+			outerSize *= dim
+		}
+		innerSize := shape.Size() / (outerSize * axisDim)
+		candidates := make([]int, axisDim)
+		var errDst error
+		err = t.ConstFlatData(func(srcFlat any) {
+			compareFn, supported := topKCompareFn(srcFlat)
+			if !supported {
+				errDst = errors.Errorf("TopK: dtype %s not supported, only float and integer dtypes", shape.DType)
+				return
+			}
+			srcV := reflect.ValueOf(srcFlat)
+			errDst = values.MutableFlatData(func(dstFlat any) {
+				dstV := reflect.ValueOf(dstFlat)
+				for outerIdx := range outerSize {
+					for innerIdx := range innerSize {
+						srcOffset := outerIdx*axisDim*innerSize + innerIdx
+						for ii := range candidates {
+							candidates[ii] = ii
+						}
+						slices.SortStableFunc(candidates, func(a, b int) int {
+							return compareFn(srcOffset+a*innerSize, srcOffset+b*innerSize)
+						})
+						dstOffset := outerIdx*k*innerSize + innerIdx
+						for ii, idx := range candidates[:k] {
+							dstIdx := dstOffset + ii*innerSize
+							dstV.Index(dstIdx).Set(srcV.Index(srcOffset + idx*innerSize))
+							indicesFlat[dstIdx] = int32(idx)
+						}
+					}
+				}
+			})
+		})
+		if err == nil {
+			err = errDst
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	indices = FromFlatDataAndDimensions(indicesFlat, dims...)
+	return values, indices, nil
+}
+
+// topKCompareFn returns a function that compares the values of the flat slice at two indices in decreasing order,
+// with the values in their native type: float64 can't represent all the Int64 and Uint64 values.
+// The half-precision values are compared as float32, which represents them exactly.
+// It returns false if flatAny is not a slice of a supported float or integer type.
+func topKCompareFn(flatAny any) (func(a, b int) int, bool) {
+	switch flat := flatAny.(type) {
+	case []float32:
+		return topKCompareOf(flat), true
+	case []float64:
+		return topKCompareOf(flat), true
+	case []float16.Float16:
+		return topKCompareOf(convertFlat(flat, func(v float16.Float16) float32 { return v.Float32() })), true
+	case []bfloat16.BFloat16:
+		return topKCompareOf(convertFlat(flat, func(v bfloat16.BFloat16) float32 { return v.Float32() })), true
+	case []int8:
+		return topKCompareOf(flat), true
+	case []int16:
+		return topKCompareOf(flat), true
+	case []int32:
+		return topKCompareOf(flat), true
+	case []int64:
+		return topKCompareOf(flat), true
+	case []uint8:
+		return topKCompareOf(flat), true
+	case []uint16:
+		return topKCompareOf(flat), true
+	case []uint32:
+		return topKCompareOf(flat), true
+	case []uint64:
+		return topKCompareOf(flat), true
+	default:
+		return nil, false
+	}
+}
+
+// topKCompareOf implements topKCompareFn for the keys of type T.
+// cmp.Compare considers NaNs smaller than any other value, so they go last.
+func topKCompareOf[T cmp.Ordered](keys []T) func(a, b int) int {
+	return func(a, b int) int { return cmp.Compare(keys[b], keys[a]) }
+}
@@ -0,0 +1,39 @@
+package tensors
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopK(t *testing.T) {
+	x := FromValue([][]float32{{1, 5, 3, 5}, {float32(math.NaN()), -1, 2, 0}})
+	values, indices, err := TopK(x, 2, -1)
+	require.NoError(t, err)
+	require.Equal(t, [][]float32{{5, 5}, {2, 0}}, values.Value())
+	require.Equal(t, [][]int32{{1, 3}, {2, 3}}, indices.Value())
+
+	values, indices, err = TopK(FromValue([][]int64{{1, 7}, {4, 2}, {3, 9}}), 1, 0)
+	require.NoError(t, err)
+	require.Equal(t, [][]int64{{4, 9}}, values.Value())
+	require.Equal(t, [][]int32{{1, 2}}, indices.Value())
+
+	// Large integers that float64 can't tell apart must keep their order.
+	const big = int64(1) << 62
+	values, indices, err = TopK(FromValue([]int64{big, big + 2, big + 1, big + 3}), 3, 0)
+	require.NoError(t, err)
+	require.Equal(t, []int64{big + 3, big + 2, big + 1}, values.Value())
+	require.Equal(t, []int32{3, 1, 2}, indices.Value())
+	values, indices, err = TopK(FromValue([]uint64{1<<64 - 2, 1<<64 - 1}), 1, 0)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{1<<64 - 1}, values.Value())
+	require.Equal(t, []int32{1}, indices.Value())
+
+	_, _, err = TopK(x, 5, 1)
+	require.Error(t, err)
+	_, _, err = TopK(x, 1, 2)
+	require.Error(t, err)
+	_, _, err = TopK(FromValue([]bool{true}), 1, 0)
+	require.Error(t, err)
+}
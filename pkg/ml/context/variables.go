@@ -513,6 +513,18 @@ func (v *Variable) ValueGraph(g *Graph) *Node {
 	return node
 }
 
+// InitialValueGraph returns the Node of the Graph that holds the value of the variable at the start of the
+// graph execution, that is, ignoring any changes made with [SetValueGraph].
+//
+// It's a computation graph building function, and panics on errors.
+func (v *Variable) InitialValueGraph(g *Graph) *Node {
+	node, err := v.paramNode(g)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
 // SetValueGraph sets the value (a graph [*Node]) of the variable for the current graph.
 //
 // This is used to "communicate" among different parts of the graph building that this value Node should
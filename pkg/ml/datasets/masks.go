@@ -0,0 +1,119 @@
+package datasets
+
+import (
+	"math"
+
+	"github.com/gomlx/gomlx/pkg/core/tensors"
+	"github.com/gomlx/gopjrt/dtypes"
+	"github.com/pkg/errors"
+)
+
+// CausalMask returns a tensor shaped `[numQueries, cacheLen+numQueries]` with the causal mask for numQueries
+// new queries attending to cacheLen cached keys followed by the keys of the queries themselves: the query i can
+// attend to the keys 0 to cacheLen+i (inclusive).
+//
+// The dtype can be Bool (true where the query can attend to the key), or any float or integer dtype (1 where the
+// query can attend to the key, 0 elsewhere). See AdditiveMask for masks added to the attention logits.
+//
+// It's built on the host, and can be fed as an input to a graph, e.g. to layers.MultiHeadAttention with
+// SetQueryKeyMatrixMask (after broadcasting it to the batch dimension), or to backends that take the masks as inputs.
+func CausalMask(numQueries, cacheLen int, dtype dtypes.DType) (*tensors.Tensor, error) {
+	if numQueries < 0 || cacheLen < 0 {
+		return nil, errors.Errorf("CausalMask requires numQueries >= 0 and cacheLen >= 0, got %d and %d",
+			numQueries, cacheLen)
+	}
+	if err := checkMaskDType("CausalMask", dtype); err != nil {
+		return nil, err
+	}
+	numKeys := cacheLen + numQueries
+	mask := make([]bool, numQueries*numKeys)
+	for q := range numQueries {
+		for k := range cacheLen + q + 1 {
+			mask[q*numKeys+k] = true
+		}
+	}
+	return maskWithDType(mask, dtype, numQueries, numKeys)
+}
+
+// CausalPaddingMask combines a padding mask with a causal mask. attentionMask is a Bool tensor shaped
+// `[batchSize, numKeys]`, set to true for the tokens and false for the padding (e.g., as returned by PadAndStack),
+// and the returned tensor is shaped `[batchSize, numQueries, numKeys]`, with the given dtype, as in CausalMask.
+//
+// The queries are aligned to the end of the keys, as in CausalMask: the query i can attend to the non-padding keys
+// 0 to numKeys-numQueries+i (inclusive).
+func CausalPaddingMask(attentionMask *tensors.Tensor, numQueries int, dtype dtypes.DType) (*tensors.Tensor, error) {
+	if attentionMask.DType() != dtypes.Bool || attentionMask.Rank() != 2 {
+		return nil, errors.Errorf("CausalPaddingMask requires attentionMask to be a Bool tensor shaped "+
+			"[batchSize, numKeys], got %s", attentionMask.Shape())
+	}
+	batchSize, numKeys := attentionMask.Shape().Dimensions[0], attentionMask.Shape().Dimensions[1]
+	if numQueries < 0 || numQueries > numKeys {
+		return nil, errors.Errorf("CausalPaddingMask requires 0 <= numQueries <= numKeys (%d), got %d", numKeys, numQueries)
+	}
+	if err := checkMaskDType("CausalPaddingMask", dtype); err != nil {
+		return nil, err
+	}
+	cacheLen := numKeys - numQueries
+	mask := make([]bool, batchSize*numQueries*numKeys)
+	err := tensors.ConstFlatData(attentionMask, func(flat []bool) {
+		for b := range batchSize {
+			for q := range numQueries {
+				row := mask[(b*numQueries+q)*numKeys:]
+				for k := range cacheLen + q + 1 {
+					row[k] = flat[b*numKeys+k]
+				}
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return maskWithDType(mask, dtype, batchSize, numQueries, numKeys)
+}
+
+// AdditiveMask converts a Bool mask (e.g. from CausalMask or CausalPaddingMask) to an additive mask of the given
+// float dtype: 0 where the mask is true and -inf where it is false. It is added to the attention logits before the
+// softmax, which is how some backends (e.g. ONNX Runtime) take float masks.
+func AdditiveMask(mask *tensors.Tensor, dtype dtypes.DType) (*tensors.Tensor, error) {
+	if mask.DType() != dtypes.Bool {
+		return nil, errors.Errorf("AdditiveMask requires a Bool mask, got %s", mask.Shape())
+	}
+	if !dtype.IsFloat() {
+		return nil, errors.Errorf("AdditiveMask requires a float dtype, got %s", dtype)
+	}
+	var additive []float64
+	err := tensors.ConstFlatData(mask, func(flat []bool) {
+		additive = make([]float64, len(flat))
+		for ii, attend := range flat {
+			if !attend {
+				additive[ii] = math.Inf(-1)
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tensors.ConvertDType(tensors.FromFlatDataAndDimensions(additive, mask.Shape().Dimensions...), dtype)
+}
+
+// checkMaskDType returns an error if the dtype is not supported for the masks.
+func checkMaskDType(funcName string, dtype dtypes.DType) error {
+	if dtype != dtypes.Bool && !dtype.IsFloat() && !dtype.IsInt() {
+		return errors.Errorf("%s: mask dtype %s not supported, only Bool, float and integer dtypes", funcName, dtype)
+	}
+	return nil
+}
+
+// maskWithDType creates the mask tensor with the given dtype: Bool, or 1/0 for the float and integer dtypes.
+func maskWithDType(mask []bool, dtype dtypes.DType, dimensions ...int) (*tensors.Tensor, error) {
+	if dtype == dtypes.Bool {
+		return tensors.FromFlatDataAndDimensions(mask, dimensions...), nil
+	}
+	values := make([]int8, len(mask))
+	for ii, attend := range mask {
+		if attend {
+			values[ii] = 1
+		}
+	}
+	return tensors.ConvertDType(tensors.FromFlatDataAndDimensions(values, dimensions...), dtype)
+}
@@ -0,0 +1,91 @@
+package datasets
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gomlx/gomlx/pkg/core/tensors"
+	"github.com/gomlx/gopjrt/dtypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCausalMask(t *testing.T) {
+	mask, err := CausalMask(3, 0, dtypes.Bool)
+	require.NoError(t, err)
+	require.Equal(t, [][]bool{{true, false, false}, {true, true, false}, {true, true, true}}, mask.Value())
+	mask, err = CausalMask(2, 2, dtypes.Bool)
+	require.NoError(t, err)
+	require.Equal(t, [][]bool{{true, true, true, false}, {true, true, true, true}}, mask.Value())
+
+	mask, err = CausalMask(2, 1, dtypes.Float32)
+	require.NoError(t, err)
+	require.Equal(t, [][]float32{{1, 1, 0}, {1, 1, 1}}, mask.Value())
+	mask, err = CausalMask(2, 1, dtypes.Int64)
+	require.NoError(t, err)
+	require.Equal(t, [][]int64{{1, 1, 0}, {1, 1, 1}}, mask.Value())
+	mask, err = CausalMask(2, 1, dtypes.Uint8)
+	require.NoError(t, err)
+	require.Equal(t, [][]uint8{{1, 1, 0}, {1, 1, 1}}, mask.Value())
+	mask, err = CausalMask(0, 2, dtypes.Bool)
+	require.NoError(t, err)
+	require.NoError(t, mask.Shape().CheckDims(0, 2))
+
+	_, err = CausalMask(-1, 0, dtypes.Bool)
+	require.Error(t, err)
+	_, err = CausalMask(1, -1, dtypes.Bool)
+	require.Error(t, err)
+	_, err = CausalMask(1, 0, dtypes.Complex64)
+	require.Error(t, err)
+}
+
+func TestAdditiveMask(t *testing.T) {
+	mask, err := CausalMask(2, 1, dtypes.Bool)
+	require.NoError(t, err)
+	inf := math.Inf(-1)
+	additive, err := AdditiveMask(mask, dtypes.Float64)
+	require.NoError(t, err)
+	require.Equal(t, [][]float64{{0, 0, inf}, {0, 0, 0}}, additive.Value())
+	additive, err = AdditiveMask(mask, dtypes.Float32)
+	require.NoError(t, err)
+	require.Equal(t, [][]float32{{0, 0, float32(inf)}, {0, 0, 0}}, additive.Value())
+
+	_, err = AdditiveMask(mask, dtypes.Int32)
+	require.Error(t, err)
+	floatMask, err := CausalMask(2, 1, dtypes.Float32)
+	require.NoError(t, err)
+	_, err = AdditiveMask(floatMask, dtypes.Float32)
+	require.Error(t, err)
+}
+
+func TestCausalPaddingMask(t *testing.T) {
+	_, attentionMask, err := PadAndStack([][]int32{{1, 2, 3}, {4, 5}}, 0, PadLeft, 0)
+	require.NoError(t, err)
+	mask, err := CausalPaddingMask(attentionMask, 3, dtypes.Bool)
+	require.NoError(t, err)
+	require.Equal(t, [][][]bool{
+		{{true, false, false}, {true, true, false}, {true, true, true}},
+		{{false, false, false}, {false, true, false}, {false, true, true}},
+	}, mask.Value())
+
+	// Only the last query, e.g. when generating with cached keys.
+	mask, err = CausalPaddingMask(attentionMask, 1, dtypes.Bool)
+	require.NoError(t, err)
+	require.Equal(t, [][][]bool{{{true, true, true}}, {{false, true, true}}}, mask.Value())
+
+	mask, err = CausalPaddingMask(attentionMask, 1, dtypes.Float16)
+	require.NoError(t, err)
+	require.Equal(t, dtypes.Float16, mask.DType())
+	asFloat32, err := tensors.ConvertDType(mask, dtypes.Float32)
+	require.NoError(t, err)
+	require.Equal(t, [][][]float32{{{1, 1, 1}}, {{0, 1, 1}}}, asFloat32.Value())
+	mask, err = CausalPaddingMask(attentionMask, 1, dtypes.Int32)
+	require.NoError(t, err)
+	require.Equal(t, [][][]int32{{{1, 1, 1}}, {{0, 1, 1}}}, mask.Value())
+
+	_, err = CausalPaddingMask(tensors.FromValue([][]int32{{1}}), 1, dtypes.Bool)
+	require.Error(t, err)
+	_, err = CausalPaddingMask(attentionMask, 4, dtypes.Bool)
+	require.Error(t, err)
+	_, err = CausalPaddingMask(attentionMask, 1, dtypes.Complex128)
+	require.Error(t, err)
+}
@@ -0,0 +1,63 @@
+package datasets
+
+import (
+	"github.com/gomlx/gomlx/pkg/core/tensors"
+	"github.com/pkg/errors"
+)
+
+// PadSide defines on which side of a sequence the padding is added by PadAndStack.
+type PadSide int
+
+const (
+	// PadRight adds the padding after the sequence, the usual for encoders and training.
+	PadRight PadSide = iota
+
+	// PadLeft adds the padding before the sequence, so that the last tokens of all sequences are aligned,
+	// the usual for generation with decoder-only models.
+	PadLeft
+)
+
+// PadAndStack pads the sequences with padID to the same length and stacks them into a batch.
+//
+// It returns inputIDs, an Int32 tensor shaped `[len(sequences), maxLen]`, and attentionMask, a Bool tensor
+// with the same shape, set to true for the tokens of the sequences and false for the padding.
+//
+// If maxLen is 0, the length of the longest sequence is used. It returns an error if there are no sequences,
+// or if any sequence is longer than maxLen.
+func PadAndStack(sequences [][]int32, padID int32, side PadSide, maxLen int) (inputIDs, attentionMask *tensors.Tensor, err error) {
+	if len(sequences) == 0 {
+		return nil, nil, errors.New("PadAndStack requires at least one sequence")
+	}
+	if maxLen < 0 {
+		return nil, nil, errors.Errorf("PadAndStack requires maxLen >= 0, got %d", maxLen)
+	}
+	if maxLen == 0 {
+		for _, seq := range sequences {
+			maxLen = max(maxLen, len(seq))
+		}
+	}
+	batchSize := len(sequences)
+	ids := make([]int32, batchSize*maxLen)
+	mask := make([]bool, batchSize*maxLen)
+	for ii, seq := range sequences {
+		if len(seq) > maxLen {
+			return nil, nil, errors.Errorf("PadAndStack: sequence #%d has length %d > maxLen=%d", ii, len(seq), maxLen)
+		}
+		rowIDs := ids[ii*maxLen : (ii+1)*maxLen]
+		rowMask := mask[ii*maxLen : (ii+1)*maxLen]
+		start := 0
+		if side == PadLeft {
+			start = maxLen - len(seq)
+		}
+		for jj := range rowIDs {
+			rowIDs[jj] = padID
+		}
+		copy(rowIDs[start:], seq)
+		for jj := range seq {
+			rowMask[start+jj] = true
+		}
+	}
+	inputIDs = tensors.FromFlatDataAndDimensions(ids, batchSize, maxLen)
+	attentionMask = tensors.FromFlatDataAndDimensions(mask, batchSize, maxLen)
+	return
+}
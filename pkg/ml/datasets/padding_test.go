@@ -0,0 +1,25 @@
+package datasets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPadAndStack(t *testing.T) {
+	sequences := [][]int32{{1, 2, 3}, {4}, {}}
+	inputIDs, attentionMask, err := PadAndStack(sequences, 0, PadRight, 0)
+	require.NoError(t, err)
+	require.Equal(t, [][]int32{{1, 2, 3}, {4, 0, 0}, {0, 0, 0}}, inputIDs.Value())
+	require.Equal(t, [][]bool{{true, true, true}, {true, false, false}, {false, false, false}}, attentionMask.Value())
+
+	inputIDs, attentionMask, err = PadAndStack(sequences, -1, PadLeft, 4)
+	require.NoError(t, err)
+	require.Equal(t, [][]int32{{-1, 1, 2, 3}, {-1, -1, -1, 4}, {-1, -1, -1, -1}}, inputIDs.Value())
+	require.Equal(t, [][]bool{{false, true, true, true}, {false, false, false, true}, {false, false, false, false}}, attentionMask.Value())
+
+	_, _, err = PadAndStack(sequences, 0, PadRight, 2)
+	require.Error(t, err)
+	_, _, err = PadAndStack(nil, 0, PadRight, 2)
+	require.Error(t, err)
+}
@@ -78,6 +78,8 @@ func DenseWithBias(ctx *context.Context, input *Node, outputDimensions ...int) *
 //
 // It automatically adds regularization to the weights (not to biases) configured in hyperparameters -- see regularizers.FromContext.
 //
+// Optionally, it adds LoRA adapters to the weights (and freezes the original ones), see ParamLoRARank.
+//
 // It the input has shape `[<batch dimensions...>, featureDimension]`, the output will have
 // shape `[<batch dimensions...>, <outputDimensions...>]`.
 //
@@ -107,6 +109,12 @@ func Dense(ctx *context.Context, input *Node, useBias bool, outputDimensions ...
 		regularizer(ctx, g, weightsVar)
 	}
 	weights := weightsVar.ValueGraph(g)
+	loraUpdate := loraFromContext(ctx, g, weightsVar.Shape())
+	if loraUpdate != nil {
+		// With LoRA, the original weights are frozen and only the low-rank update is trained.
+		weightsVar.SetTrainable(false)
+		weights = Add(weights, loraUpdate)
+	}
 	var output *Node
 	if inputRank <= 2 && len(outputDimensions) == 1 {
 		// Vanilla version: input = [batch_size, feature_size], output = [batch_size, output_dim].
@@ -134,6 +142,9 @@ func Dense(ctx *context.Context, input *Node, useBias bool, outputDimensions ...
 	// Add bias: it takes no regularizer by default.
 	if useBias {
 		biasVar := ctx.VariableWithShape("biases", shapes.Make(inputShape.DType, outputDimensions...))
+		if loraUpdate != nil {
+			biasVar.SetTrainable(false)
+		}
 		bias := biasVar.ValueGraph(g)
 		expandedBiasShape := output.Shape().Clone()
 		for ii := range expandedBiasShape.Dimensions[:output.Rank()-len(outputDimensions)] {
@@ -0,0 +1,97 @@
+package layers
+
+import (
+	"strings"
+
+	. "github.com/gomlx/gomlx/pkg/core/graph"
+	"github.com/gomlx/gomlx/pkg/core/shapes"
+	"github.com/gomlx/gomlx/pkg/ml/context"
+	"github.com/gomlx/gomlx/pkg/ml/context/initializers"
+)
+
+const (
+	// ParamLoRARank context hyperparameter enables LoRA (Low-Rank Adaptation, https://arxiv.org/abs/2106.09685)
+	// adapters in the Dense layers (and hence in the FNN and MultiHeadAttention projections) selected by
+	// ParamLoRATargets.
+	//
+	// When enabled, the original weights and biases of the selected layers are frozen (marked as not trainable),
+	// and a trainable low-rank update `(alpha/rank) * A·B` is added to the weights, where A is shaped
+	// `[inputFeatures, rank]` and B is shaped `[rank, <outputDimensions...>]`. B is initialized with zeros, so
+	// initially the layer behaves exactly as the original one.
+	//
+	// The adapter variables are named LoRAVariableA and LoRAVariableB, in the same scope as the layer weights.
+	// See LoRAVariables to list (and export) them.
+	//
+	// The value should be an int. The default is `0`, which disables LoRA.
+	ParamLoRARank = "lora_rank"
+
+	// ParamLoRAAlpha context hyperparameter is the scaling factor of LoRA adapters: the low-rank update is
+	// scaled by `alpha/rank`. See ParamLoRARank.
+	//
+	// The value should be a float64. The default is `0.0`, which means alpha is set to the rank (a scaling of 1).
+	ParamLoRAAlpha = "lora_alpha"
+
+	// ParamLoRATargets context hyperparameter selects which Dense layers get LoRA adapters: it's a comma-separated
+	// list of substrings matched against the scope of the layer. E.g.: "query,value" would select the
+	// query and value projections of MultiHeadAttention.
+	//
+	// The value should be a string. The default is `""`, which selects all Dense layers.
+	ParamLoRATargets = "lora_targets"
+
+	// LoRAVariableA is the name of the variable holding the LoRA "A" (down-projection) matrix.
+	LoRAVariableA = "lora_a"
+
+	// LoRAVariableB is the name of the variable holding the LoRA "B" (up-projection) matrix.
+	LoRAVariableB = "lora_b"
+)
+
+// loraTargeted returns whether the Dense layer in the current ctx scope is selected by ParamLoRATargets.
+func loraTargeted(ctx *context.Context) bool {
+	targets := context.GetParamOr(ctx, ParamLoRATargets, "")
+	if targets == "" {
+		return true
+	}
+	scope := ctx.Scope()
+	for _, target := range strings.Split(targets, ",") {
+		target = strings.TrimSpace(target)
+		if target != "" && strings.Contains(scope, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// loraFromContext returns the scaled low-rank update to the Dense weights shaped weightsShape, or nil if LoRA
+// is not enabled for the layer in the current ctx scope. See ParamLoRARank.
+func loraFromContext(ctx *context.Context, g *Graph, weightsShape shapes.Shape) *Node {
+	rank := context.GetParamOr(ctx, ParamLoRARank, 0)
+	if rank <= 0 || !loraTargeted(ctx) {
+		return nil
+	}
+	alpha := context.GetParamOr(ctx, ParamLoRAAlpha, 0.0)
+	if alpha <= 0 {
+		alpha = float64(rank)
+	}
+	inputDim := weightsShape.Dimensions[0]
+	outputDims := weightsShape.Dimensions[1:]
+	a := ctx.VariableWithShape(LoRAVariableA, shapes.Make(weightsShape.DType, inputDim, rank)).ValueGraph(g)
+	bDims := append([]int{rank}, outputDims...)
+	b := ctx.WithInitializer(initializers.Zero).
+		VariableWithShape(LoRAVariableB, shapes.Make(weightsShape.DType, bDims...)).ValueGraph(g)
+	update := Dot(a, Reshape(b, rank, -1))
+	update = Reshape(update, weightsShape.Dimensions...)
+	return MulScalar(update, alpha/float64(rank))
+}
+
+// LoRAVariables returns all LoRA adapter variables (see ParamLoRARank) under the current ctx scope.
+//
+// This can be used to export only the adapters, or to inspect them.
+func LoRAVariables(ctx *context.Context) []*context.Variable {
+	var vars []*context.Variable
+	for v := range ctx.IterVariablesInScope() {
+		if v.Name() == LoRAVariableA || v.Name() == LoRAVariableB {
+			vars = append(vars, v)
+		}
+	}
+	return vars
+}
@@ -0,0 +1,49 @@
+package layers
+
+import (
+	"testing"
+
+	. "github.com/gomlx/gomlx/pkg/core/graph"
+	"github.com/gomlx/gomlx/pkg/core/graph/graphtest"
+	"github.com/gomlx/gomlx/pkg/core/tensors"
+	"github.com/gomlx/gomlx/pkg/ml/context"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDenseLoRA(t *testing.T) {
+	backend := graphtest.BuildTestBackend()
+	ctx := context.New().WithInitializer(IotaP1Initializer)
+	ctx.SetParam(ParamLoRARank, 2)
+	ctx.SetParam(ParamLoRAAlpha, 4.0)
+	ctx.SetParam(ParamLoRATargets, "first")
+	exec := context.MustNewExec(backend, ctx, func(ctx *context.Context, x *Node) (*Node, *Node) {
+		return DenseWithBias(ctx.In("first"), x, 3), DenseWithBias(ctx.In("second"), x, 3)
+	})
+
+	// weights=[[1, 1, 1], [2, 2, 2]], biases=[1, 2, 3], lora_a=[[1, 1], [2, 2]], lora_b=0.
+	x := [][]float32{{1, 2}}
+	outputs := exec.MustExec(x)
+	require.Equal(t, [][]float32{{6, 7, 8}}, outputs[0].Value())
+	require.Equal(t, [][]float32{{6, 7, 8}}, outputs[1].Value())
+
+	// Only the targeted layer gets adapters, and its original variables are frozen.
+	loraVars := LoRAVariables(ctx)
+	require.Len(t, loraVars, 2)
+	for _, v := range loraVars {
+		require.Equal(t, "/first/dense", v.Scope())
+		require.True(t, v.Trainable)
+	}
+	require.NoError(t, ctx.GetVariableByScopeAndName("/first/dense", LoRAVariableA).Shape().CheckDims(2, 2))
+	require.NoError(t, ctx.GetVariableByScopeAndName("/first/dense", LoRAVariableB).Shape().CheckDims(2, 3))
+	require.False(t, ctx.GetVariableByScopeAndName("/first/dense", "weights").Trainable)
+	require.False(t, ctx.GetVariableByScopeAndName("/first/dense", "biases").Trainable)
+	require.True(t, ctx.GetVariableByScopeAndName("/second/dense", "weights").Trainable)
+	require.Nil(t, ctx.GetVariableByScopeAndName("/second/dense", LoRAVariableA))
+
+	// With lora_b=1, the update is (alpha/rank) * A·B = 2 * [[2, 2, 2], [4, 4, 4]].
+	ctx.GetVariableByScopeAndName("/first/dense", LoRAVariableB).
+		MustSetValue(tensors.FromValue([][]float32{{1, 1, 1}, {1, 1, 1}}))
+	outputs = exec.MustExec(x)
+	require.Equal(t, [][]float32{{26, 27, 28}}, outputs[0].Value())
+	require.Equal(t, [][]float32{{6, 7, 8}}, outputs[1].Value())
+}
@@ -0,0 +1,230 @@
+// Package moe implements a Mixture-of-Experts (MoE) feed-forward block, as used in Switch Transformers
+// (https://arxiv.org/abs/2101.03961) and other sparse models.
+//
+// A learned router selects for each input element (e.g. each token) the top-k experts, and the output is the
+// gate-weighted sum of the selected experts' outputs. Each expert is a feed-forward network with one hidden
+// layer. During training, an auxiliary load-balancing loss encourages the router to spread the inputs evenly
+// among the experts.
+//
+// E.g.: the feed-forward block of a transformer layer:
+//
+//	func TransformerFFN(ctx *context.Context, x *Node) *Node {
+//		embedDim := x.Shape().Dim(-1)
+//		return moe.New(ctx.In("moe"), x, embedDim).
+//			NumExperts(8).
+//			TopK(2).
+//			NumHiddenNodes(4 * embedDim).
+//			Done()
+//	}
+package moe
+
+import (
+	"slices"
+
+	"github.com/gomlx/gomlx/internal/exceptions"
+	. "github.com/gomlx/gomlx/pkg/core/graph"
+	"github.com/gomlx/gomlx/pkg/core/shapes"
+	"github.com/gomlx/gomlx/pkg/ml/context"
+	"github.com/gomlx/gomlx/pkg/ml/context/initializers"
+	"github.com/gomlx/gomlx/pkg/ml/layers"
+	"github.com/gomlx/gomlx/pkg/ml/layers/activations"
+	"github.com/gomlx/gomlx/pkg/ml/layers/regularizers"
+	"github.com/gomlx/gomlx/pkg/ml/train"
+)
+
+const (
+	// ParamNumExperts is the hyperparameter that defines the default number of experts.
+	// The default is 8 (int).
+	ParamNumExperts = "moe_num_experts"
+
+	// ParamTopK is the hyperparameter that defines the default number of experts selected for each input element.
+	// The default is 1 (int), as in Switch Transformers.
+	ParamTopK = "moe_top_k"
+
+	// ParamNumHiddenNodes is the hyperparameter that defines the default number of hidden nodes of each expert.
+	// The default is 0 (int), which means 4 times the input feature dimension.
+	ParamNumHiddenNodes = "moe_num_hidden_nodes"
+
+	// ParamLoadBalancingLoss is the hyperparameter that defines the default weight of the auxiliary
+	// load-balancing loss, added to the training loss with train.AddLoss.
+	// The default is 0.01 (float64), set to 0 to disable it.
+	ParamLoadBalancingLoss = "moe_load_balancing_loss"
+)
+
+// Config is created with New and can be configured with its methods, or simply setting the corresponding
+// hyperparameters in the context.
+type Config struct {
+	ctx                 *context.Context
+	input               *Node
+	outputDim           int
+	numExperts, topK    int
+	numHiddenNodes      int
+	activation          activations.Type
+	loadBalancingWeight float64
+	regularizer         regularizers.Regularizer
+}
+
+// New creates a configuration for a Mixture-of-Experts feed-forward block.
+// This can be further configured through various methods and when finished,
+// call Done to actually add the MoE computation graph and get the output.
+//
+// The input is expected to have shape `[<batch dimensions...>, featureDimension]`, the output will have
+// shape `[<batch dimensions...>, outputDim]`. Each element of the batch dimensions (e.g. each token) is
+// routed independently.
+//
+// Configuration options have defaults, but can also be configured through hyperparameters
+// set in the context. See corresponding configuration methods for details.
+func New(ctx *context.Context, input *Node, outputDim int) *Config {
+	if input.Rank() < 2 {
+		exceptions.Panicf("moe: input must be rank at least 2, got input.shape=%s", input.Shape())
+	}
+	if outputDim <= 0 {
+		exceptions.Panicf("moe: outputDim must be > 0, got %d", outputDim)
+	}
+	c := &Config{
+		ctx:                 ctx,
+		input:               input,
+		outputDim:           outputDim,
+		numExperts:          context.GetParamOr(ctx, ParamNumExperts, 8),
+		topK:                context.GetParamOr(ctx, ParamTopK, 1),
+		numHiddenNodes:      context.GetParamOr(ctx, ParamNumHiddenNodes, 0),
+		activation:          activations.FromName(context.GetParamOr(ctx, activations.ParamActivation, "relu")),
+		loadBalancingWeight: context.GetParamOr(ctx, ParamLoadBalancingLoss, 0.01),
+		regularizer:         regularizers.FromContext(ctx),
+	}
+	if c.numHiddenNodes <= 0 {
+		c.numHiddenNodes = 4 * input.Shape().Dim(-1)
+	}
+	return c
+}
+
+// NumExperts sets the number of experts.
+//
+// The default is 8, but it will be overridden if the hyperparameter ParamNumExperts is set in the context.
+func (c *Config) NumExperts(numExperts int) *Config {
+	if numExperts < 1 {
+		exceptions.Panicf("moe: numExperts must be >= 1, got %d", numExperts)
+	}
+	c.numExperts = numExperts
+	return c
+}
+
+// TopK sets the number of experts selected for each input element. The gates of the selected experts are
+// renormalized to sum to 1 if topK > 1.
+//
+// The default is 1, but it will be overridden if the hyperparameter ParamTopK is set in the context.
+func (c *Config) TopK(topK int) *Config {
+	if topK < 1 {
+		exceptions.Panicf("moe: topK must be >= 1, got %d", topK)
+	}
+	c.topK = topK
+	return c
+}
+
+// NumHiddenNodes sets the number of hidden nodes of each expert.
+//
+// The default is 4 times the input feature dimension, but it will be overridden if the hyperparameter
+// ParamNumHiddenNodes is set in the context.
+func (c *Config) NumHiddenNodes(numHiddenNodes int) *Config {
+	if numHiddenNodes < 1 {
+		exceptions.Panicf("moe: numHiddenNodes must be >= 1, got %d", numHiddenNodes)
+	}
+	c.numHiddenNodes = numHiddenNodes
+	return c
+}
+
+// Activation sets the activation used in the hidden layer of the experts.
+//
+// The default is "relu", but it can be overridden by setting the hyperparameter activations.ParamActivation
+// (="activation") in the context.
+func (c *Config) Activation(activation activations.Type) *Config {
+	c.activation = activation
+	return c
+}
+
+// LoadBalancingLoss sets the weight of the auxiliary load-balancing loss (eq. 4 of the Switch Transformers paper),
+// added with train.AddLoss during training. Set to 0 to disable it.
+//
+// The default is 0.01, but it will be overridden if the hyperparameter ParamLoadBalancingLoss is set in the context.
+func (c *Config) LoadBalancingLoss(weight float64) *Config {
+	if weight < 0 {
+		exceptions.Panicf("moe: load-balancing loss weight must be >= 0, got %g", weight)
+	}
+	c.loadBalancingWeight = weight
+	return c
+}
+
+// Regularizer to be applied to the learned weights of the experts (but not the biases).
+//
+// The default is regularizers.FromContext, which is configured by regularizers.ParamL1 and regularizers.ParamL2.
+func (c *Config) Regularizer(regularizer regularizers.Regularizer) *Config {
+	c.regularizer = regularizer
+	return c
+}
+
+// Done takes the configuration and apply the MoE block as configured.
+//
+// Notice all experts are evaluated for all input elements, and the non-selected ones are multiplied by zero:
+// this keeps the computation graph static (there is no token dispatching), at the cost of not saving compute.
+func (c *Config) Done() *Node {
+	ctx := c.ctx
+	x := c.input
+	g := x.Graph()
+	dtype := x.DType()
+	if c.topK > c.numExperts {
+		exceptions.Panicf("moe: topK (%d) must be <= numExperts (%d)", c.topK, c.numExperts)
+	}
+
+	// Flatten batch dimensions: x is shaped [numElements, featureDim].
+	outputDims := slices.Clone(x.Shape().Dimensions)
+	outputDims[len(outputDims)-1] = c.outputDim
+	featureDim := x.Shape().Dim(-1)
+	x = Reshape(x, -1, featureDim)
+
+	// Router: gates are shaped [numElements, numExperts], and are non-zero only for the top-k experts.
+	routerLogits := layers.Dense(ctx.In("router"), x, false, c.numExperts)
+	probs := Softmax(routerLogits, -1)
+	selected := ZerosLike(probs)
+	remaining := probs
+	for range c.topK {
+		oneHot := OneHot(ArgMax(remaining, -1), c.numExperts, dtype)
+		selected = Add(selected, oneHot)
+		remaining = Sub(remaining, MulScalar(oneHot, 2)) // Probabilities are <= 1, so this excludes the expert.
+	}
+	gates := Mul(probs, selected)
+	if c.topK > 1 {
+		gates = Div(gates, ReduceAndKeep(gates, ReduceSum, -1))
+	}
+
+	// Auxiliary load-balancing loss: numExperts * \sum_e{fraction_routed_e * mean_prob_e}.
+	if c.loadBalancingWeight > 0 && ctx.IsTraining(g) {
+		fractionRouted := DivScalar(ReduceMean(selected, 0), c.topK)
+		meanProbs := ReduceMean(probs, 0)
+		loss := MulScalar(ReduceAllSum(Mul(fractionRouted, meanProbs)), float64(c.numExperts)*c.loadBalancingWeight)
+		train.AddLoss(ctx, loss)
+	}
+
+	// Experts: all evaluated at once, with the experts axis "e".
+	expertsCtx := ctx.In("experts")
+	hiddenWeightsVar := expertsCtx.VariableWithShape("hidden_weights",
+		shapes.Make(dtype, c.numExperts, featureDim, c.numHiddenNodes))
+	outputWeightsVar := expertsCtx.VariableWithShape("output_weights",
+		shapes.Make(dtype, c.numExperts, c.numHiddenNodes, c.outputDim))
+	if c.regularizer != nil {
+		c.regularizer(expertsCtx, g, hiddenWeightsVar, outputWeightsVar)
+	}
+	hiddenBiases := expertsCtx.WithInitializer(initializers.Zero).
+		VariableWithShape("hidden_biases", shapes.Make(dtype, c.numExperts, c.numHiddenNodes)).ValueGraph(g)
+	outputBiases := expertsCtx.WithInitializer(initializers.Zero).
+		VariableWithShape("output_biases", shapes.Make(dtype, c.numExperts, c.outputDim)).ValueGraph(g)
+
+	hidden := Einsum("nf,efh->neh", x, hiddenWeightsVar.ValueGraph(g))
+	hidden = Add(hidden, InsertAxes(hiddenBiases, 0))
+	hidden = activations.Apply(c.activation, hidden)
+	expertsOutput := Einsum("neh,eho->neo", hidden, outputWeightsVar.ValueGraph(g))
+	expertsOutput = Add(expertsOutput, InsertAxes(outputBiases, 0))
+
+	// Combine experts with the gates.
+	output := Einsum("ne,neo->no", gates, expertsOutput)
+	return Reshape(output, outputDims...)
+}
@@ -0,0 +1,105 @@
+package moe
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/gomlx/gomlx/pkg/core/graph"
+	"github.com/gomlx/gomlx/pkg/core/graph/graphtest"
+	"github.com/gomlx/gomlx/pkg/core/tensors"
+	"github.com/gomlx/gomlx/pkg/ml/context"
+	"github.com/gomlx/gomlx/pkg/ml/context/initializers"
+	"github.com/gomlx/gomlx/pkg/ml/layers/activations"
+	"github.com/gomlx/gomlx/pkg/ml/train"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/gomlx/gomlx/backends/default"
+)
+
+func TestMoE(t *testing.T) {
+	backend := graphtest.BuildTestBackend()
+	x := [][][]float32{
+		{{1, 0.5}, {-1, 2}, {0.3, 0.1}},
+		{{0, 1}, {1, 0}, {-0.5, 0.5}},
+	}
+
+	t.Run("single expert", func(t *testing.T) {
+		// With only one expert, it should be the same as a FNN with one hidden layer.
+		ctx := context.New()
+		ctx = ctx.WithInitializer(initializers.RandomNormalFn(ctx, 1.0))
+		got := context.MustExecOnce(backend, ctx, func(ctx *context.Context, x *Node) *Node {
+			return New(ctx, x, 4).NumExperts(1).NumHiddenNodes(3).Done()
+		}, x)
+		want := context.MustExecOnce(backend, ctx.Reuse().In("experts"), func(ctx *context.Context, x *Node) *Node {
+			g := x.Graph()
+			value := func(name string) *Node {
+				v := ctx.GetVariable(name).ValueGraph(g)
+				return Squeeze(v, 0)
+			}
+			hidden := activations.Relu(Add(Einsum("bsf,fh->bsh", x, value("hidden_weights")), InsertAxes(value("hidden_biases"), 0, 0)))
+			return Add(Einsum("bsh,ho->bso", hidden, value("output_weights")), InsertAxes(value("output_biases"), 0, 0))
+		}, x)
+		require.NoError(t, got.Shape().CheckDims(2, 3, 4))
+		require.Truef(t, got.InDelta(want, 1e-4), "got %s, want %s", got, want)
+	})
+
+	t.Run("top-2 with load-balancing loss", func(t *testing.T) {
+		ctx := context.New()
+		ctx = ctx.WithInitializer(initializers.RandomNormalFn(ctx, 1.0))
+		outputs := context.MustExecOnceN(backend, ctx, func(ctx *context.Context, x *Node) []*Node {
+			ctx.SetTraining(x.Graph(), true)
+			output := New(ctx, x, 5).NumExperts(4).TopK(2).LoadBalancingLoss(1.0).Done()
+			return []*Node{output, train.GetLosses(ctx, x.Graph())}
+		}, x)
+		require.NoError(t, outputs[0].Shape().CheckDims(2, 3, 5))
+		loss := tensors.ToScalar[float32](outputs[1])
+		require.False(t, math.IsNaN(float64(loss)))
+		require.Greater(t, loss, float32(0))
+	})
+
+	t.Run("top-2 routing", func(t *testing.T) {
+		// The router logits are the log of the probabilities: for the element [1, 0] they are [0.5, 0.3, 0.2], so
+		// experts 0 and 1 are selected, with gates 0.5/0.8 and 0.3/0.8; for the element [0, 1] they are
+		// [0.1, 0.3, 0.6], so experts 2 and 1 are selected, with gates 0.6/0.9 and 0.3/0.9.
+		routerWeights := [][]float32{
+			{float32(math.Log(0.5)), float32(math.Log(0.3)), float32(math.Log(0.2))},
+			{float32(math.Log(0.1)), float32(math.Log(0.3)), float32(math.Log(0.6))},
+		}
+		routingX := [][]float32{{1, 0}, {0, 1}}
+		newRoutingContext := func(hiddenWeights [][][]float32, hiddenBiases [][]float32, outputWeights [][][]float32) *context.Context {
+			ctx := context.New()
+			ctx.In("router").In("dense").VariableWithValue("weights", routerWeights)
+			expertsCtx := ctx.In("experts")
+			expertsCtx.VariableWithValue("hidden_weights", hiddenWeights)
+			expertsCtx.VariableWithValue("hidden_biases", hiddenBiases)
+			expertsCtx.VariableWithValue("output_weights", outputWeights)
+			// The variables set above are reused, and the output biases are created (with zeros).
+			return ctx.Checked(false)
+		}
+		moeFn := func(outputDim int) func(ctx *context.Context, x *Node) *Node {
+			return func(ctx *context.Context, x *Node) *Node {
+				return New(ctx, x, outputDim).NumExperts(3).TopK(2).NumHiddenNodes(1).
+					Activation(activations.TypeNone).LoadBalancingLoss(0).Done()
+			}
+		}
+
+		// Each expert outputs the one-hot encoding of its index, so the output is the gates: it shows the
+		// experts selected and their renormalized weights.
+		ctx := newRoutingContext(
+			[][][]float32{{{0}, {0}}, {{0}, {0}}, {{0}, {0}}},
+			[][]float32{{1}, {1}, {1}},
+			[][][]float32{{{1, 0, 0}}, {{0, 1, 0}}, {{0, 0, 1}}})
+		gates := context.MustExecOnce(backend, ctx, moeFn(3), routingX)
+		wantGates := [][]float32{{0.5 / 0.8, 0.3 / 0.8, 0}, {0, 0.3 / 0.9, 0.6 / 0.9}}
+		require.Truef(t, gates.InDelta(tensors.FromValue(wantGates), 1e-5), "got gates %s, want %v", gates, wantGates)
+
+		// Experts 0, 1 and 2 output 1, 2 and 3 times the first feature plus 10, 20 and 30 times the second one.
+		ctx = newRoutingContext(
+			[][][]float32{{{1}, {10}}, {{2}, {20}}, {{3}, {30}}},
+			[][]float32{{0}, {0}, {0}},
+			[][][]float32{{{1}}, {{1}}, {{1}}})
+		output := context.MustExecOnce(backend, ctx, moeFn(1), routingX)
+		wantOutput := [][]float32{{0.5/0.8*1 + 0.3/0.8*2}, {0.6/0.9*30 + 0.3/0.9*20}}
+		require.Truef(t, output.InDelta(tensors.FromValue(wantOutput), 1e-4), "got %s, want %v", output, wantOutput)
+	})
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/gomlx/gomlx/pkg/core/shapes"
 	"github.com/gomlx/gomlx/pkg/ml/context"
 	"github.com/gomlx/gomlx/pkg/support/xslices"
+	"github.com/gomlx/gopjrt/dtypes"
 )
 
 // This file contains all parts of the layers.MultiHeadAttention implementation.
@@ -37,6 +38,7 @@ type MultiHeadAttentionBuilder struct {
 	g                 *Graph
 	query, key, value *Node
 	numHeads          int
+	numKVHeads        int
 	keyQueryDim       int
 	valueDim          int
 	outputDim         int
@@ -46,11 +48,17 @@ type MultiHeadAttentionBuilder struct {
 
 	useProjectionBias bool
 	dropoutRate       float64
+	keyBlockSize      int
+	temperature       float64
+	logitSoftCap      float64
 
 	// Mask related attributes.
 	keyMask, queryMask *Node
 	queryKeyMatrixMask *Node
 	useCausalMask      bool
+	causalMaskOffset   *Node
+	slidingWindow      int
+	numGlobalTokens    int
 }
 
 // MultiHeadAttention defines a multi-head attention layers, as described in the paper
@@ -95,11 +103,13 @@ func MultiHeadAttention(ctx *context.Context, query, key, value *Node, numHeads
 		key:               key,
 		value:             value,
 		numHeads:          numHeads,
+		numKVHeads:        numHeads,
 		valueDim:          headDim,
 		keyQueryDim:       headDim,
 		innerKeyAxes:      innerKeyAxes,
 		innerQueryAxes:    innerQueryAxes,
 		useProjectionBias: true,
+		temperature:       1,
 	}
 
 	if queryShape.Rank() < 3 {
@@ -143,6 +153,22 @@ func (b *MultiHeadAttentionBuilder) SetValueHeadDim(valueDim int) *MultiHeadAtte
 	return b
 }
 
+// SetNumKVHeads configures grouped-query attention (GQA), where the key and value are projected to
+// fewer heads than the query, and each key/value head is shared by a group of numHeads/numKVHeads
+// consecutive query heads.
+// It reduces the size of the key/value projections (and of any cached key/values) used by many
+// modern efficient architectures.
+//
+// numKVHeads must divide numHeads. It defaults to numHeads, which is the usual multi-head attention.
+// Setting it to 1 is what is called multi-query attention (MQA).
+func (b *MultiHeadAttentionBuilder) SetNumKVHeads(numKVHeads int) *MultiHeadAttentionBuilder {
+	if numKVHeads <= 0 || b.numHeads%numKVHeads != 0 {
+		Panicf("MultiHeadAttention's numKVHeads (%d) must be > 0 and divide numHeads (%d)", numKVHeads, b.numHeads)
+	}
+	b.numKVHeads = numKVHeads
+	return b
+}
+
 // SetOutputDim defines the output dimension of the final projection, from the flattened
 // attention heads. It defaults to the value of the last dimension of `values` passed as input
 // (`inputValueDim`).
@@ -213,7 +239,7 @@ func (b *MultiHeadAttentionBuilder) SetQueryKeyMatrixMask(queryKeyMatrixMask *No
 	if b.keyMask != nil || b.queryMask != nil {
 		Panicf("a mask can be set either with SetKeyMask and SetQueryMask separately or with SetKeyQueryMatrixMask, but not both")
 	}
-	if queryKeyMatrixMask.Shape().Equal(b.attentionShape) {
+	if queryKeyMatrixMask.Shape().EqualDimensions(b.attentionShape) {
 		// Simplest case: queryKeyMatrixMask provided with attentionShape.
 		b.queryKeyMatrixMask = queryKeyMatrixMask
 		return b
@@ -225,20 +251,26 @@ func (b *MultiHeadAttentionBuilder) SetQueryKeyMatrixMask(queryKeyMatrixMask *No
 		shapeWithoutHeads.Dimensions[ii] = shapeWithoutHeads.Dimensions[ii+1]
 	}
 	shapeWithoutHeads.Dimensions = shapeWithoutHeads.Dimensions[0 : b.attentionShape.Rank()-1]
-	if !queryKeyMatrixMask.Shape().Equal(shapeWithoutHeads) {
+	if !queryKeyMatrixMask.Shape().EqualDimensions(shapeWithoutHeads) {
 		Panicf("invalid shape for queryKeyMatrixMask %s: expected either %s (with per-head mask) or %s",
 			queryKeyMatrixMask.Shape(), b.attentionShape, shapeWithoutHeads)
 	}
 
 	// Broadcast numHeads axes.
 	queryKeyMatrixMask = InsertAxes(queryKeyMatrixMask, 1+b.innerQueryAxes)
-	queryKeyMatrixMask = BroadcastToDims(queryKeyMatrixMask, b.attentionShape.Dimensions...)
+	b.queryKeyMatrixMask = BroadcastToDims(queryKeyMatrixMask, b.attentionShape.Dimensions...)
 	return b
 }
 
 // UseCausalMask adds a mask where a query can only attend to keys with lower indices than itself.
-// It assumes that query and key are either the same or have the same inner shape, and there is
-// only one inner rank -- so key/query should have rank-3 shape `[batch, inner_dim, key/query_dim]`.
+// It assumes that there is only one inner rank -- so key/query should have rank-3 shape
+// `[batch, inner_dim, key/query_dim]`.
+//
+// If there are more keys than queries, the queries are assumed to be the last elements of the sequence, that is,
+// query `i` is at position `i + numKeys - numQueries`. This is the case for incremental decoding, where the keys
+// include the cached keys of the previous elements, and it guarantees that decoding incrementally matches
+// decoding the full sequence. See UseCausalMaskWithOffset if the position of the queries is only known
+// dynamically.
 //
 // This mask can be used in combination (logical-and) with other masks.
 func (b *MultiHeadAttentionBuilder) UseCausalMask() *MultiHeadAttentionBuilder {
@@ -249,11 +281,73 @@ func (b *MultiHeadAttentionBuilder) UseCausalMask() *MultiHeadAttentionBuilder {
 		Panicf("MultiHeadAttention's UseCausalMask requires key and query to be rank-3,"+
 			" instead got query.shape=%s and key.shape=%s", queryShape, keyShape)
 	}
-	if !reflect.DeepEqual(keyShape.Dimensions[:keyShape.Rank()-1], queryShape.Dimensions[:queryShape.Rank()-1]) {
-		Panicf("MultiHeadAttention's UseCausalMask requires inner shapes of query and key be the same,"+
-			" instead got query.shape=%s and key.shape=%s", queryShape, keyShape)
+	if keyShape.Dimensions[0] != queryShape.Dimensions[0] || keyShape.Dimensions[1] < queryShape.Dimensions[1] {
+		Panicf("MultiHeadAttention's UseCausalMask requires query and key to have the same batch size, and at least"+
+			" as many keys as queries, instead got query.shape=%s and key.shape=%s", queryShape, keyShape)
+	}
+	b.useCausalMask = true
+	b.causalMaskOffset = nil
+	return b
+}
+
+// UseCausalMaskWithOffset is like UseCausalMask, but query `i` is at position `i + offset`, and it can only attend
+// to keys with position lower or equal to that.
+//
+// The offset is a scalar integer node, typically the number of elements already stored in a key/value cache.
+// This allows the keys (and values) to be a fixed size buffer, with only its first `offset + numQueries` elements
+// filled, and still keep the computation graph static.
+//
+// This mask can be used in combination (logical-and) with other masks.
+func (b *MultiHeadAttentionBuilder) UseCausalMaskWithOffset(offset *Node) *MultiHeadAttentionBuilder {
+	queryShape := b.query.Shape()
+	keyShape := b.key.Shape()
+	if queryShape.Rank() != 3 || keyShape.Rank() != 3 || keyShape.Dimensions[0] != queryShape.Dimensions[0] {
+		Panicf("MultiHeadAttention's UseCausalMaskWithOffset requires key and query to be rank-3, with the same"+
+			" batch size, instead got query.shape=%s and key.shape=%s", queryShape, keyShape)
+	}
+	if !offset.IsScalar() || !offset.DType().IsInt() {
+		Panicf("MultiHeadAttention's UseCausalMaskWithOffset requires offset to be an integer scalar, got %s",
+			offset.Shape())
 	}
 	b.useCausalMask = true
+	b.causalMaskOffset = offset
+	return b
+}
+
+// UseSlidingWindow restricts each query to attend only to the keys within windowSize positions of itself (before or
+// after), as in the local attention of Longformer (https://arxiv.org/abs/2004.05150) and LongT5
+// (https://arxiv.org/abs/2112.07916). Query positions are aligned to the key positions as in UseCausalMask.
+// See also SetNumGlobalTokens.
+//
+// By itself this is only a mask, and the cost is still quadratic on the sequence length. Combined with
+// UseBlockedAttention, the queries are also split in blocks, and each block of queries only visits the keys
+// within its window (and the global tokens), making the cost linear on the sequence length.
+//
+// It requires key and query to be rank-3, that is, only one inner axis (the sequence).
+// This mask can be used in combination (logical-and) with other masks.
+func (b *MultiHeadAttentionBuilder) UseSlidingWindow(windowSize int) *MultiHeadAttentionBuilder {
+	if b.query.Rank() != 3 || b.key.Rank() != 3 {
+		Panicf("MultiHeadAttention's UseSlidingWindow requires key and query to be rank-3,"+
+			" instead got query.shape=%s and key.shape=%s", b.query.Shape(), b.key.Shape())
+	}
+	if windowSize <= 0 {
+		Panicf("MultiHeadAttention's UseSlidingWindow requires windowSize > 0, got %d", windowSize)
+	}
+	b.slidingWindow = windowSize
+	return b
+}
+
+// SetNumGlobalTokens sets the first numGlobalTokens elements of the sequence as "global tokens" for the sliding
+// window attention (see UseSlidingWindow): global keys are attended by every query, and global queries attend to
+// every key, regardless of the window. Typically, these are a "[CLS]" token or the question in a question-answering
+// task, as in Longformer (https://arxiv.org/abs/2004.05150).
+//
+// It is only used if UseSlidingWindow is also set. Default is 0.
+func (b *MultiHeadAttentionBuilder) SetNumGlobalTokens(numGlobalTokens int) *MultiHeadAttentionBuilder {
+	if numGlobalTokens < 0 {
+		Panicf("MultiHeadAttention's SetNumGlobalTokens requires numGlobalTokens >= 0, got %d", numGlobalTokens)
+	}
+	b.numGlobalTokens = numGlobalTokens
 	return b
 }
 
@@ -274,6 +368,49 @@ func (b *MultiHeadAttentionBuilder) Dropout(rate float64) *MultiHeadAttentionBui
 	return b
 }
 
+// SetTemperature sets the temperature of the attention softmax: the attention logits are divided by
+// the temperature before the softmax. Values > 1 make the attention smoother, and values < 1 sharper.
+// Default is 1.
+func (b *MultiHeadAttentionBuilder) SetTemperature(temperature float64) *MultiHeadAttentionBuilder {
+	if temperature <= 0 {
+		Panicf("MultiHeadAttention's SetTemperature requires temperature > 0, got %g", temperature)
+	}
+	b.temperature = temperature
+	return b
+}
+
+// SetLogitSoftCap sets a soft-cap on the attention logits: they are replaced by `softCap * tanh(logits / softCap)`,
+// smoothly limiting them to the range (-softCap, +softCap). It is applied after the temperature, and it is used
+// by some architectures (e.g. Gemma 2, with softCap=50) to stabilize training and long-context inference.
+//
+// If softCap is <= 0, soft-capping is disabled. This is the default.
+func (b *MultiHeadAttentionBuilder) SetLogitSoftCap(softCap float64) *MultiHeadAttentionBuilder {
+	b.logitSoftCap = softCap
+	return b
+}
+
+// UseBlockedAttention computes the attention in blocks of keyBlockSize keys at a time, using an "online softmax"
+// (as in FlashAttention, https://arxiv.org/abs/2205.14135) to combine the partial results.
+// This way the full `[batch, <query_elements>, num_heads, <key_elements>]` attention logits and coefficients are
+// never materialized, only one block of keys at a time, which reduces memory usage for long sequences.
+//
+// The result is the same as the default attention, up to floating point rounding. But since the coefficients are
+// never fully built, it can only be used with Done, and DoneWithCoefficients will panic.
+// It also requires key and query to be rank-3, that is, only one inner axis (the sequence).
+//
+// If UseSlidingWindow is also set, the queries are split in blocks of keyBlockSize as well, and each block of
+// queries only visits the keys within its window.
+//
+// If keyBlockSize is <= 0, the blocked attention is disabled. This is the default.
+func (b *MultiHeadAttentionBuilder) UseBlockedAttention(keyBlockSize int) *MultiHeadAttentionBuilder {
+	if keyBlockSize > 0 && (b.query.Rank() != 3 || b.key.Rank() != 3) {
+		Panicf("MultiHeadAttention's UseBlockedAttention requires key and query to be rank-3,"+
+			" instead got query.shape=%s and key.shape=%s", b.query.Shape(), b.key.Shape())
+	}
+	b.keyBlockSize = keyBlockSize
+	return b
+}
+
 // nextNAxes enumerates the next n consecutive axis, starting from nextAxis. It returns
 // the string with the axis concatenated.
 func nextNAxes(n int, nextAxis rune) string {
@@ -294,12 +431,11 @@ func nextNAxes(n int, nextAxis rune) string {
 // `coefficients` is shaped `[batch_size, <query_elements>, <num_heads>, <key_elements>]`
 // with the attention weights (from 0 to 1).
 func (b *MultiHeadAttentionBuilder) DoneWithCoefficients() (attentionOutput, attentionCoefficients *Node) {
-	projectedKey := Dense(b.ctx.In("key"), b.key, true, b.numHeads, b.keyQueryDim)
-	projectedQuery := Dense(b.ctx.In("query"), b.query, true, b.numHeads, b.keyQueryDim)
-	projectedValue := Dense(b.ctx.In("value"), b.value, true, b.numHeads, b.valueDim)
-
-	// LearnedScale attentionLogits by 1/sqrt(keyQueryDim).
-	projectedQuery = Mul(projectedQuery, ConstAs(projectedQuery, 1.0/math.Sqrt(float64(b.keyQueryDim))))
+	if b.keyBlockSize > 0 {
+		Panicf("MultiHeadAttention's DoneWithCoefficients can't be used with UseBlockedAttention, since " +
+			"the attention coefficients are never fully materialized -- use Done instead")
+	}
+	projectedQuery, projectedKey, projectedValue := b.projectInputs()
 
 	// Build equation for attention Einsum.
 	batchAxis := 'b'
@@ -326,8 +462,7 @@ func (b *MultiHeadAttentionBuilder) DoneWithCoefficients() (attentionOutput, att
 	// Attention logits: outer product of key/query inner dimensions, with a dot-product of their projections.
 	// Shape: [batch, <query_elements>, num_heads, <key_elements>]
 	attentionLogits := Einsum(attentionEquation, projectedQuery, projectedKey)
-	normalizingFactor := math.Sqrt(float64(b.keyQueryDim))
-	attentionLogits = DivScalar(attentionLogits, normalizingFactor)
+	attentionLogits = b.scaleLogits(attentionLogits)
 	//fmt.Printf("\tattentionLogits: %s\n", attentionLogits.Shape())
 
 	mask := b.buildMask()
@@ -355,19 +490,183 @@ func (b *MultiHeadAttentionBuilder) DoneWithCoefficients() (attentionOutput, att
 		batchAxis, queryInnerAxes, headsAxis, projectionAxis)
 	//fmt.Printf("\toutputEquation (coef x value): %s\n", outputEquation)
 	attentionOutput = Einsum(outputEquation, attentionCoefficients, projectedValue)
+	attentionOutput = b.projectOutput(attentionOutput)
+	return attentionOutput, attentionCoefficients
+}
+
+// projectInputs projects query, key and value to their per-head embeddings, shaped
+// `[batch, <elements>, num_heads, dim]`.
+func (b *MultiHeadAttentionBuilder) projectInputs() (projectedQuery, projectedKey, projectedValue *Node) {
+	projectedKey = Dense(b.ctx.In("key"), b.key, true, b.numKVHeads, b.keyQueryDim)
+	projectedQuery = Dense(b.ctx.In("query"), b.query, true, b.numHeads, b.keyQueryDim)
+	projectedValue = Dense(b.ctx.In("value"), b.value, true, b.numKVHeads, b.valueDim)
+	if b.numKVHeads != b.numHeads {
+		// Grouped-query attention: repeat each key/value head for its group of query heads.
+		groupSize := b.numHeads / b.numKVHeads
+		projectedKey = repeatHeads(projectedKey, groupSize)
+		projectedValue = repeatHeads(projectedValue, groupSize)
+	}
+
+	// LearnedScale attentionLogits by 1/sqrt(keyQueryDim).
+	projectedQuery = Mul(projectedQuery, ConstAs(projectedQuery, 1.0/math.Sqrt(float64(b.keyQueryDim))))
+	return
+}
 
-	// Final projection: flatten the heads and then do a final projection to the final
-	// outputDim (set with `SetOutputDim`).
-	//fmt.Printf("\tattentionOutput (1): %s\n", attentionOutput.Shape())
+// scaleLogits normalizes the attention logits by 1/sqrt(keyQueryDim), and applies the temperature and the
+// soft-cap, if configured.
+func (b *MultiHeadAttentionBuilder) scaleLogits(logits *Node) *Node {
+	logits = DivScalar(logits, math.Sqrt(float64(b.keyQueryDim))*b.temperature)
+	if b.logitSoftCap > 0 {
+		logits = MulScalar(Tanh(DivScalar(logits, b.logitSoftCap)), b.logitSoftCap)
+	}
+	return logits
+}
+
+// projectOutput takes the attention output per head, shaped `[batch, <query_elements>, num_heads, value_dim]`,
+// flattens the heads and then does a final projection to the final outputDim (set with `SetOutputDim`).
+func (b *MultiHeadAttentionBuilder) projectOutput(attentionOutput *Node) *Node {
 	flatDims := make([]int, attentionOutput.Rank()-1)
 	copy(flatDims, attentionOutput.Shape().Dimensions[:len(flatDims)])
 	flatDims[len(flatDims)-1] *= attentionOutput.Shape().Dimensions[attentionOutput.Rank()-1]
 	// New shape: `[batch, <query_elements>, num_head*value_dim]`
 	attentionOutput = Reshape(attentionOutput, flatDims...)
 	// Final shape: `[batch, <query_elements>, outputDim]`
-	attentionOutput = Dense(b.ctx.In("output"), attentionOutput, b.useProjectionBias, b.outputDim)
+	return Dense(b.ctx.In("output"), attentionOutput, b.useProjectionBias, b.outputDim)
+}
 
-	return attentionOutput, attentionCoefficients
+// doneBlocked implements the attention with an online softmax over blocks of keys. See UseBlockedAttention.
+//
+// For each block it keeps the running maximum of the logits (runningMax), the running sum of the exponentials
+// of the logits (runningSum), and the running (not normalized) weighted sum of the values (accumulator), all
+// rescaled whenever the running maximum changes.
+func (b *MultiHeadAttentionBuilder) doneBlocked() *Node {
+	projectedQuery, projectedKey, projectedValue := b.projectInputs()
+	numQueries := projectedQuery.Shape().Dimensions[1]
+	var outputs []*Node
+	for _, r := range b.blockedQueryRanges() {
+		blockQuery := projectedQuery
+		if r.queryStart != 0 || r.queryEnd != numQueries {
+			blockQuery = Slice(projectedQuery, AxisRange(), AxisRange(r.queryStart, r.queryEnd))
+		}
+		outputs = append(outputs, b.blockedAttention(blockQuery, projectedKey, projectedValue, r))
+	}
+	attentionOutput := outputs[0]
+	if len(outputs) > 1 {
+		attentionOutput = Concatenate(outputs, 1)
+	}
+	return b.projectOutput(attentionOutput)
+}
+
+// attentionRange is a range of queries and the ranges of keys they attend to, used by the blocked attention.
+type attentionRange struct {
+	queryStart, queryEnd int
+	keyRanges            [][2]int
+}
+
+// blockedQueryRanges splits the queries in ranges, each attending only to the keys that are not masked out
+// by the sliding window (if one is used).
+//
+// Without a sliding window, or if the position of the queries is only known dynamically (UseCausalMaskWithOffset),
+// all queries attend to all keys.
+func (b *MultiHeadAttentionBuilder) blockedQueryRanges() []attentionRange {
+	numQueries := b.query.Shape().Dimensions[1]
+	numKeys := b.key.Shape().Dimensions[1]
+	allKeys := [][2]int{{0, numKeys}}
+	if b.slidingWindow <= 0 || b.causalMaskOffset != nil {
+		return []attentionRange{{0, numQueries, allKeys}}
+	}
+
+	// Query i is at position i+offset.
+	offset := numKeys - numQueries
+	var ranges []attentionRange
+	queryStart := 0
+	if numGlobalQueries := min(max(b.numGlobalTokens-offset, 0), numQueries); numGlobalQueries > 0 {
+		// Global queries attend to all keys.
+		ranges = append(ranges, attentionRange{0, numGlobalQueries, allKeys})
+		queryStart = numGlobalQueries
+	}
+	for ; queryStart < numQueries; queryStart += b.keyBlockSize {
+		queryEnd := min(queryStart+b.keyBlockSize, numQueries)
+		keyStart := max(queryStart+offset-b.slidingWindow, 0)
+		keyEnd := min(queryEnd+offset+b.slidingWindow, numKeys)
+		if b.useCausalMask {
+			keyEnd = min(keyEnd, queryEnd+offset)
+		}
+		var keyRanges [][2]int
+		if globalEnd := min(b.numGlobalTokens, keyStart); globalEnd > 0 {
+			keyRanges = append(keyRanges, [2]int{0, globalEnd})
+		}
+		keyRanges = append(keyRanges, [2]int{keyStart, keyEnd})
+		ranges = append(ranges, attentionRange{queryStart, queryEnd, keyRanges})
+	}
+	return ranges
+}
+
+// blockedAttention calculates the attention of the projected query (the queries in the range r), over the keys in
+// r.keyRanges, one block of keys at a time, using the "online softmax".
+//
+// It returns the attention output shaped `[batch, r.queryEnd-r.queryStart, num_heads, value_dim]`.
+func (b *MultiHeadAttentionBuilder) blockedAttention(projectedQuery, projectedKey, projectedValue *Node, r attentionRange) *Node {
+	dtype := projectedQuery.DType()
+
+	// Shapes: runningMax and runningSum are [batch, query_elements, num_heads], the accumulator is
+	// [batch, query_elements, num_heads, value_dim].
+	lowest := Infinity(b.g, dtype, -1)
+	queryDims := projectedQuery.Shape().Dimensions[:3]
+	runningMax := BroadcastToDims(lowest, queryDims...)
+	runningSum := Zeros(b.g, shapes.Make(dtype, queryDims...))
+	accumulator := Zeros(b.g, shapes.Make(dtype, queryDims[0], queryDims[1], queryDims[2], b.valueDim))
+	for _, keyRange := range r.keyRanges {
+		for blockStart := keyRange[0]; blockStart < keyRange[1]; blockStart += b.keyBlockSize {
+			blockEnd := min(blockStart+b.keyBlockSize, keyRange[1])
+			blockKey := Slice(projectedKey, AxisRange(), AxisRange(blockStart, blockEnd))
+			blockValue := Slice(projectedValue, AxisRange(), AxisRange(blockStart, blockEnd))
+
+			// Logits for the block: [batch, query_elements, num_heads, block_size].
+			logits := Einsum("bqhd,bkhd->bqhk", projectedQuery, blockKey)
+			logits = b.scaleLogits(logits)
+			mask := b.buildBlockMask(r.queryStart, r.queryEnd, blockStart, blockEnd)
+			if mask != nil {
+				logits = Where(mask, logits, BroadcastToDims(lowest, logits.Shape().Dimensions...))
+			}
+
+			// Online softmax update: newMax is -inf while all keys seen so far are masked, in which case we shift
+			// by 0 instead, to avoid the NaNs of "-inf - -inf" -- numerator and rescale will be 0 for those.
+			newMax := StopGradient(Max(runningMax, ReduceMax(logits, -1)))
+			shift := Where(IsFinite(newMax), newMax, ZerosLike(newMax))
+			rescale := Exp(Sub(runningMax, shift))
+			numerator := Exp(Sub(logits, InsertAxes(shift, -1)))
+			runningSum = Add(Mul(runningSum, rescale), ReduceSum(numerator, -1))
+			if b.dropoutRate > 0 {
+				// Dropping the numerator is equivalent to dropping the normalized coefficients.
+				numerator = Dropout(b.ctx, numerator, ConstAs(numerator, b.dropoutRate))
+			}
+			accumulator = Add(
+				Mul(accumulator, InsertAxes(rescale, -1)),
+				Einsum("bqhk,bkhd->bqhd", numerator, blockValue))
+			runningMax = newMax
+		}
+	}
+
+	// Normalize: queries that had all keys masked get 0 (as with MaskedSoftmax).
+	validSum := GreaterThan(runningSum, ZerosLike(runningSum))
+	runningSum = Where(validSum, runningSum, OnesLike(runningSum))
+	return Div(accumulator, InsertAxes(runningSum, -1))
+}
+
+// repeatHeads repeats each head of x numRepeats times, consecutively.
+// x is shaped `[batch, <elements>, num_heads, dim]`, and the result is shaped `[batch, <elements>, num_heads*numRepeats, dim]`.
+func repeatHeads(x *Node, numRepeats int) *Node {
+	headsAxis := x.Rank() - 2
+	finalDims := x.Shape().Clone().Dimensions
+	finalDims[headsAxis] *= numRepeats
+
+	// Insert the group axis after the heads axis, broadcast it and merge it into the heads axis.
+	x = InsertAxes(x, headsAxis+1)
+	broadcastDims := x.Shape().Clone().Dimensions
+	broadcastDims[headsAxis+1] = numRepeats
+	x = BroadcastToDims(x, broadcastDims...)
+	return Reshape(x, finalDims...)
 }
 
 // Done or DoneWithCoefficients should be called after all optional settings are configured.
@@ -376,6 +675,9 @@ func (b *MultiHeadAttentionBuilder) DoneWithCoefficients() (attentionOutput, att
 // `output` will be shaped `[batch_size, <query_elements>, output_dim]`, where `output_dim`
 // can be configured by `SetOutputDim`.
 func (b *MultiHeadAttentionBuilder) Done() (output *Node) {
+	if b.keyBlockSize > 0 {
+		return b.doneBlocked()
+	}
 	output, _ = b.DoneWithCoefficients()
 	return output
 }
@@ -405,18 +707,74 @@ func (b *MultiHeadAttentionBuilder) buildMask() (mask *Node) {
 		mask = b.queryKeyMatrixMask
 	}
 
-	// Combine causal mask.
+	// Combine causal and sliding window masks.
+	numQueries, numKeys := b.query.Shape().Dimensions[1], b.key.Shape().Dimensions[1]
 	if b.useCausalMask {
-		causalMask := b.buildCausalMask()
+		mask = andMatrixMask(mask, b.buildCausalMatrix(0, numQueries, 0, numKeys), b.attentionShape.Dimensions)
+	}
+	if b.slidingWindow > 0 {
+		mask = andMatrixMask(mask, b.buildSlidingWindowMatrix(0, numQueries, 0, numKeys), b.attentionShape.Dimensions)
+	}
+	return
+}
+
+// andMatrixMask combines (logical-and) mask with the `[query_elements, key_elements]` matrix mask, broadcast
+// to the given dimensions. If mask is nil, it returns the broadcast matrix mask.
+func andMatrixMask(mask, matrix *Node, dims []int) *Node {
+	matrix = BroadcastToDims(InsertAxes(matrix, 0, 1), dims...) // Add batch and numHeads axes.
+	if mask == nil {
+		return matrix
+	}
+	return LogicalAnd(mask, matrix)
+}
+
+// buildBlockMask returns the mask for the queries in the range [queryStart, queryEnd) and the keys in the range
+// [keyStart, keyEnd), shaped `[batch, queryEnd-queryStart, num_heads, keyEnd-keyStart]`, or nil if there is no mask.
+// It's used by the blocked attention, and it assumes key and query are rank-3.
+func (b *MultiHeadAttentionBuilder) buildBlockMask(queryStart, queryEnd, keyStart, keyEnd int) (mask *Node) {
+	blockDims := b.attentionShape.Clone().Dimensions
+	blockDims[1] = queryEnd - queryStart
+	blockDims[3] = keyEnd - keyStart
+	if b.queryKeyMatrixMask != nil {
+		mask = Slice(b.queryKeyMatrixMask, AxisRange(), AxisRange(queryStart, queryEnd), AxisRange(),
+			AxisRange(keyStart, keyEnd))
+	}
+	if b.keyMask != nil {
+		// b.keyMask.shape=`[batch, key_elements]` or `[batch, num_heads, key_elements]`.
+		keyMask := sliceLastAxis(b.keyMask, keyStart, keyEnd)
+		keyMask = InsertAxes(keyMask, xslices.SliceWithValue(len(blockDims)-keyMask.Rank(), 1)...)
+		mask = BroadcastToDims(keyMask, blockDims...)
+	}
+	if b.queryMask != nil {
+		// b.queryMask.shape=`[batch, query_elements]` or `[batch, num_heads, query_elements]`.
+		queryMask := sliceLastAxis(b.queryMask, queryStart, queryEnd)
+		queryMask = InsertAxes(queryMask, xslices.SliceWithValue(len(blockDims)-queryMask.Rank(), -1)...)
+		queryMask = BroadcastToDims(queryMask, blockDims...)
 		if mask == nil {
-			mask = causalMask
+			mask = queryMask
 		} else {
-			mask = LogicalAnd(mask, causalMask)
+			mask = LogicalAnd(mask, queryMask)
 		}
 	}
+	if b.useCausalMask {
+		mask = andMatrixMask(mask, b.buildCausalMatrix(queryStart, queryEnd, keyStart, keyEnd), blockDims)
+	}
+	if b.slidingWindow > 0 {
+		mask = andMatrixMask(mask, b.buildSlidingWindowMatrix(queryStart, queryEnd, keyStart, keyEnd), blockDims)
+	}
 	return
 }
 
+// sliceLastAxis returns x[..., start:end].
+func sliceLastAxis(x *Node, start, end int) *Node {
+	axesSpec := make([]SliceAxisSpec, x.Rank())
+	for axis := range axesSpec {
+		axesSpec[axis] = AxisRange()
+	}
+	axesSpec[x.Rank()-1] = AxisRange(start, end)
+	return Slice(x, axesSpec...)
+}
+
 // buildMaskFromSplitMasks creates cross mask from split queryMask and keyMask.
 // The shape should be `[batch, <query_elements>, num_heads, <key_elements>]`.
 func (b *MultiHeadAttentionBuilder) buildMaskFromSplitMasks() (mask *Node) {
@@ -446,16 +804,47 @@ func (b *MultiHeadAttentionBuilder) buildMaskFromSplitMasks() (mask *Node) {
 	return LogicalAnd(queryMask, keyMask)
 }
 
-// buildCausalMask creates a mask where queries can only attend to keys with "smaller index" than itself.
-func (b *MultiHeadAttentionBuilder) buildCausalMask() (mask *Node) {
-	keyShape := b.key.Shape()
-	dim := keyShape.Dimensions[1] // Same as queryShape.Dimensions[1].
+// buildPositions returns the positions of the queries in the range [queryStart, queryEnd) and of the keys in the
+// range [keyStart, keyEnd), both shaped `[queryEnd-queryStart, keyEnd-keyStart]`.
+//
+// Query i is at position i+offset, where the offset is given by UseCausalMaskWithOffset, or it is
+// numKeys-numQueries otherwise, that is, the queries are aligned to the end of the keys.
+func (b *MultiHeadAttentionBuilder) buildPositions(queryStart, queryEnd, keyStart, keyEnd int) (queryPos, keyPos *Node) {
+	iotaShape := shapes.Make(dtypes.Int32, queryEnd-queryStart, keyEnd-keyStart)
+	queryPos = Iota(b.g, iotaShape, 0)
+	offset := queryStart
+	if b.causalMaskOffset != nil {
+		queryPos = Add(queryPos, ConvertDType(b.causalMaskOffset, dtypes.Int32))
+	} else {
+		offset += b.key.Shape().Dimensions[1] - b.query.Shape().Dimensions[1]
+	}
+	if offset != 0 {
+		queryPos = AddScalar(queryPos, offset)
+	}
+	keyPos = Iota(b.g, iotaShape, 1)
+	if keyStart != 0 {
+		keyPos = AddScalar(keyPos, keyStart)
+	}
+	return
+}
 
-	// mask is [<query_elements>, <key_elements>]
-	mask = LowerTriangular(b.g, dim)
+// buildCausalMatrix creates a `[queryEnd-queryStart, keyEnd-keyStart]` mask, where queries can only attend to keys
+// with position lower or equal to its own.
+func (b *MultiHeadAttentionBuilder) buildCausalMatrix(queryStart, queryEnd, keyStart, keyEnd int) *Node {
+	queryPos, keyPos := b.buildPositions(queryStart, queryEnd, keyStart, keyEnd)
+	return GreaterOrEqual(queryPos, keyPos)
+}
 
-	// Broadcast mask to target shape of `[batch, <query_elements>, numHeads, <key_elements>]`
-	mask = InsertAxes(mask, 0, 1)                                // Add batch and numHeads axes.
-	mask = BroadcastToDims(mask, b.attentionShape.Dimensions...) // Broadcast to target dimensions.
-	return
+// buildSlidingWindowMatrix creates a `[queryEnd-queryStart, keyEnd-keyStart]` mask, where queries can only attend
+// to keys within the sliding window or to global tokens, and global queries attend to all keys.
+func (b *MultiHeadAttentionBuilder) buildSlidingWindowMatrix(queryStart, queryEnd, keyStart, keyEnd int) *Node {
+	queryPos, keyPos := b.buildPositions(queryStart, queryEnd, keyStart, keyEnd)
+	distance := Sub(queryPos, keyPos)
+	window := Scalar(b.g, dtypes.Int32, b.slidingWindow)
+	mask := LogicalAnd(LessOrEqual(distance, window), GreaterOrEqual(distance, Neg(window)))
+	if b.numGlobalTokens > 0 {
+		numGlobal := Scalar(b.g, dtypes.Int32, b.numGlobalTokens)
+		mask = LogicalOr(mask, LogicalOr(LessThan(keyPos, numGlobal), LessThan(queryPos, numGlobal)))
+	}
+	return mask
 }
@@ -35,6 +35,7 @@ import (
 	"github.com/gomlx/gomlx/pkg/ml/train/optimizers"
 	"github.com/gomlx/gomlx/pkg/support/xslices"
 	"github.com/gomlx/gomlx/ui/commandline"
+	"github.com/gomlx/gopjrt/dtypes"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -81,6 +82,262 @@ func TestMultiHeadAttentionGraph(t *testing.T) {
 		}, xslices.Epsilon)
 }
 
+// repeatHeadsOnHost repeats each head (the one-before-last axis) of a float32 tensor numRepeats times.
+func repeatHeadsOnHost(t *tensors.Tensor, numRepeats int) *tensors.Tensor {
+	dims := t.Shape().Clone().Dimensions
+	numHeads, headDim := dims[len(dims)-2], dims[len(dims)-1]
+	flat := tensors.MustCopyFlatData[float32](t)
+	repeated := make([]float32, 0, len(flat)*numRepeats)
+	for start := 0; start < len(flat); start += headDim {
+		head := flat[start : start+headDim]
+		for range numRepeats {
+			repeated = append(repeated, head...)
+		}
+	}
+	dims[len(dims)-2] = numHeads * numRepeats
+	return tensors.FromFlatDataAndDimensions(repeated, dims...)
+}
+
+func TestMultiHeadAttentionGroupedQuery(t *testing.T) {
+	backend := graphtest.BuildTestBackend()
+	const numHeads, numKVHeads, headDim = 4, 2, 3
+	query := tensors.FromValue([][][]float32{{{1, 0.5}, {-1, 2}, {0.3, 0.1}}})
+	key := tensors.FromValue([][][]float32{{{0.2, 1, 3}, {1, -1, 0}}})
+	value := tensors.FromValue([][][]float32{{{1, 2}, {3, 4}}})
+	attentionFn := func(numKVHeads int) func(ctx *context.Context, query, key, value *Node) *Node {
+		return func(ctx *context.Context, query, key, value *Node) *Node {
+			return MultiHeadAttention(ctx, query, key, value, numHeads, headDim).
+				SetNumKVHeads(numKVHeads).Done()
+		}
+	}
+
+	// Grouped-query attention with random weights.
+	ctxGQA := context.New()
+	ctxGQA = ctxGQA.WithInitializer(initializers.RandomNormalFn(ctxGQA, 1.0))
+	gotGQA := context.MustExecOnce(backend, ctxGQA, attentionFn(numKVHeads), query, key, value)
+	require.NoError(t, gotGQA.Shape().Check(F32, 1, 3, 2))
+	keyWeights := ctxGQA.GetVariableByScopeAndName("/MultiHeadAttention/key/dense", "weights")
+	require.NotNil(t, keyWeights)
+	require.NoError(t, keyWeights.Shape().CheckDims(3, numKVHeads, headDim))
+
+	// Standard multi-head attention, with the key/value projections repeated for each group should match.
+	ctxMHA := context.New()
+	for v := range ctxGQA.IterVariables() {
+		value := v.MustValue()
+		if v.Scope() == "/MultiHeadAttention/key/dense" || v.Scope() == "/MultiHeadAttention/value/dense" {
+			value = repeatHeadsOnHost(value, numHeads/numKVHeads)
+		}
+		ctxMHA.InAbsPath(v.Scope()).VariableWithValue(v.Name(), value)
+	}
+	gotMHA := context.MustExecOnce(backend, ctxMHA.Reuse(), attentionFn(numHeads), query, key, value)
+	require.True(t, gotGQA.InDelta(gotMHA, 1e-4), "GQA=%s, MHA=%s", gotGQA, gotMHA)
+}
+
+func TestMultiHeadAttentionBlocked(t *testing.T) {
+	backend := graphtest.BuildTestBackend()
+	const batchSize, seqLen, numHeads, headDim = 2, 5, 4, 3
+	x := tensors.FromValue([][][]float32{
+		{{1, 0.5}, {-1, 2}, {0.3, 0.1}, {2, -2}, {0.7, 0.4}},
+		{{0, 1}, {1, 0}, {-0.5, 0.5}, {1.5, 1}, {-1, -1}},
+	})
+	keyMask := tensors.FromValue([][]bool{
+		{true, true, false, true, true},
+		{true, false, false, true, false},
+	})
+	matrixMask := tensors.FromValue([][][]bool{
+		{{true, false, true, false, true}, {false, false, false, false, false}, {true, true, true, true, true},
+			{false, true, false, true, false}, {true, false, false, false, false}},
+		{{false, false, false, false, true}, {true, true, false, false, false}, {true, false, true, false, true},
+			{false, true, true, true, false}, {true, true, true, true, true}},
+	})
+	testCases := []struct {
+		name   string
+		config func(b *MultiHeadAttentionBuilder, keyMask, matrixMask *Node) *MultiHeadAttentionBuilder
+	}{
+		{"no mask", func(b *MultiHeadAttentionBuilder, _, _ *Node) *MultiHeadAttentionBuilder { return b }},
+		{"key/query mask and causal", func(b *MultiHeadAttentionBuilder, keyMask, _ *Node) *MultiHeadAttentionBuilder {
+			return b.SetKeyMask(keyMask).SetQueryMask(keyMask).UseCausalMask()
+		}},
+		{"matrix mask", func(b *MultiHeadAttentionBuilder, _, matrixMask *Node) *MultiHeadAttentionBuilder {
+			return b.SetQueryKeyMatrixMask(matrixMask)
+		}},
+		{"grouped-query", func(b *MultiHeadAttentionBuilder, keyMask, _ *Node) *MultiHeadAttentionBuilder {
+			return b.SetNumKVHeads(2).SetKeyMask(keyMask)
+		}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.New()
+			ctx = ctx.WithInitializer(initializers.RandomNormalFn(ctx, 1.0))
+			attentionFn := func(keyBlockSize int) func(ctx *context.Context, x, keyMask, matrixMask *Node) *Node {
+				return func(ctx *context.Context, x, keyMask, matrixMask *Node) *Node {
+					b := MultiHeadAttention(ctx, x, x, x, numHeads, headDim)
+					return tc.config(b, keyMask, matrixMask).UseBlockedAttention(keyBlockSize).Done()
+				}
+			}
+			want := context.MustExecOnce(backend, ctx, attentionFn(0), x, keyMask, matrixMask)
+			for _, keyBlockSize := range []int{1, 2, 3, seqLen} {
+				got := context.MustExecOnce(backend, ctx.Reuse(), attentionFn(keyBlockSize), x, keyMask, matrixMask)
+				require.Truef(t, got.InDelta(want, 1e-4), "keyBlockSize=%d: got %s, want %s", keyBlockSize, got, want)
+			}
+		})
+	}
+}
+
+func TestMultiHeadAttentionCausalOffset(t *testing.T) {
+	backend := graphtest.BuildTestBackend()
+	const seqLen, numHeads, headDim = 5, 2, 3
+	x := tensors.FromValue([][][]float32{
+		{{1, 0.5}, {-1, 2}, {0.3, 0.1}, {2, -2}, {0.7, 0.4}},
+		{{0, 1}, {1, 0}, {-0.5, 0.5}, {1.5, 1}, {-1, -1}},
+	})
+	ctx := context.New()
+	ctx = ctx.WithInitializer(initializers.RandomNormalFn(ctx, 1.0))
+	full := context.MustExecOnce(backend, ctx, func(ctx *context.Context, x *Node) *Node {
+		return MultiHeadAttention(ctx, x, x, x, numHeads, headDim).UseCausalMask().Done()
+	}, x)
+
+	// Decoding one query at a time, with the keys/values of all the elements so far (as in a KV cache).
+	for pos := range seqLen {
+		got := context.MustExecOnce(backend, ctx.Reuse(), func(ctx *context.Context, x *Node) *Node {
+			query := Slice(x, AxisRange(), AxisElem(pos), AxisRange())
+			keys := Slice(x, AxisRange(), AxisRange(0, pos+1), AxisRange())
+			return MultiHeadAttention(ctx, query, keys, keys, numHeads, headDim).UseCausalMask().Done()
+		}, x)
+		want := MustExecOnce(backend, func(full *Node) *Node {
+			return Slice(full, AxisRange(), AxisElem(pos), AxisRange())
+		}, full)
+		require.Truef(t, got.InDelta(want, 1e-4), "pos=%d: got %s, want %s", pos, got, want)
+	}
+
+	// Decoding two queries at a time, with a fixed size buffer of keys/values, filled with garbage after the
+	// current position.
+	garbage := tensors.FromValue([][][]float32{
+		{{9, 9}, {9, 9}, {9, 9}, {9, 9}, {9, 9}},
+		{{-9, 9}, {-9, 9}, {-9, 9}, {-9, 9}, {-9, 9}},
+	})
+	for _, offsetValue := range []int32{0, 1, 3} {
+		got := context.MustExecOnce(backend, ctx.Reuse(), func(ctx *context.Context, x, garbage, offset *Node) *Node {
+			query := Slice(x, AxisRange(), AxisRange(int(offsetValue), int(offsetValue)+2), AxisRange())
+			positions := Iota(x.Graph(), shapes.Make(dtypes.Int32, x.Shape().Dimensions...), 1)
+			keys := Where(LessThan(positions, AddScalar(offset, 2)), x, garbage)
+			return MultiHeadAttention(ctx, query, keys, keys, numHeads, headDim).UseCausalMaskWithOffset(offset).Done()
+		}, x, garbage, offsetValue)
+		want := MustExecOnce(backend, func(full *Node) *Node {
+			return Slice(full, AxisRange(), AxisRange(int(offsetValue), int(offsetValue)+2), AxisRange())
+		}, full)
+		require.Truef(t, got.InDelta(want, 1e-4), "offset=%d: got %s, want %s", offsetValue, got, want)
+	}
+}
+
+func TestMultiHeadAttentionSlidingWindow(t *testing.T) {
+	backend := graphtest.BuildTestBackend()
+	const batchSize, seqLen, numHeads, headDim, windowSize = 2, 9, 2, 3, 2
+	xValues := make([][][]float32, batchSize)
+	for batchIdx := range xValues {
+		xValues[batchIdx] = make([][]float32, seqLen)
+		for pos := range xValues[batchIdx] {
+			xValues[batchIdx][pos] = []float32{float32(math.Sin(float64(pos + batchIdx))), float32(pos%3) - 1}
+		}
+	}
+	x := tensors.FromValue(xValues)
+	for _, numGlobalTokens := range []int{0, 2} {
+		for _, causal := range []bool{false, true} {
+			// Equivalent mask built explicitly.
+			matrixMask := make([][][]bool, batchSize)
+			for batchIdx := range matrixMask {
+				matrixMask[batchIdx] = make([][]bool, seqLen)
+				for q := range seqLen {
+					matrixMask[batchIdx][q] = make([]bool, seqLen)
+					for k := range seqLen {
+						visible := (q-k <= windowSize && k-q <= windowSize) || q < numGlobalTokens || k < numGlobalTokens
+						if causal {
+							visible = visible && k <= q
+						}
+						matrixMask[batchIdx][q][k] = visible
+					}
+				}
+			}
+			ctx := context.New()
+			ctx = ctx.WithInitializer(initializers.RandomNormalFn(ctx, 1.0))
+			want := context.MustExecOnce(backend, ctx, func(ctx *context.Context, x, matrixMask *Node) *Node {
+				return MultiHeadAttention(ctx, x, x, x, numHeads, headDim).SetQueryKeyMatrixMask(matrixMask).Done()
+			}, x, matrixMask)
+			for _, keyBlockSize := range []int{0, 1, 2, 3, seqLen} {
+				got := context.MustExecOnce(backend, ctx.Reuse(), func(ctx *context.Context, x *Node) *Node {
+					b := MultiHeadAttention(ctx, x, x, x, numHeads, headDim).
+						UseSlidingWindow(windowSize).
+						SetNumGlobalTokens(numGlobalTokens).
+						UseBlockedAttention(keyBlockSize)
+					if causal {
+						b = b.UseCausalMask()
+					}
+					return b.Done()
+				}, x)
+				require.Truef(t, got.InDelta(want, 1e-4), "numGlobalTokens=%d, causal=%v, keyBlockSize=%d: got %s, want %s",
+					numGlobalTokens, causal, keyBlockSize, got, want)
+			}
+		}
+	}
+}
+
+func TestMultiHeadAttentionTemperatureAndSoftCap(t *testing.T) {
+	backend := graphtest.BuildTestBackend()
+	const batchSize, seqLen, numHeads, headDim = 2, 5, 2, 3
+	xValues := make([][][]float32, batchSize)
+	for batchIdx := range xValues {
+		xValues[batchIdx] = make([][]float32, seqLen)
+		for pos := range xValues[batchIdx] {
+			xValues[batchIdx][pos] = []float32{float32(math.Sin(float64(pos + batchIdx))), float32(pos%3) - 1}
+		}
+	}
+	x := tensors.FromValue(xValues)
+	ctx := context.New()
+	ctx = ctx.WithInitializer(initializers.RandomNormalFn(ctx, 3.0))
+	coefficientsFn := func(temperature, softCap float64) *tensors.Tensor {
+		return context.MustExecOnceN(backend, ctx.Reuse(), func(ctx *context.Context, x *Node) []*Node {
+			output, coefficients := MultiHeadAttention(ctx, x, x, x, numHeads, headDim).
+				SetTemperature(temperature).
+				SetLogitSoftCap(softCap).
+				DoneWithCoefficients()
+			return []*Node{output, coefficients}
+		}, x)[1]
+	}
+	_ = context.MustExecOnce(backend, ctx, func(ctx *context.Context, x *Node) *Node {
+		return MultiHeadAttention(ctx, x, x, x, numHeads, headDim).Done()
+	}, x)
+
+	// A very high temperature or a very low soft-cap make the attention uniform.
+	uniform := tensors.FromShape(coefficientsFn(1, 0).Shape())
+	tensors.MutableFlatData(uniform, func(flat []float32) {
+		for ii := range flat {
+			flat[ii] = 1.0 / seqLen
+		}
+	})
+	require.False(t, coefficientsFn(1, 0).InDelta(uniform, 1e-3))
+	require.True(t, coefficientsFn(1e6, 0).InDelta(uniform, 1e-3))
+	require.True(t, coefficientsFn(1, 1e-6).InDelta(uniform, 1e-3))
+
+	// Blocked attention gives the same results.
+	for _, causal := range []bool{false, true} {
+		outputFn := func(keyBlockSize int) *tensors.Tensor {
+			return context.MustExecOnce(backend, ctx.Reuse(), func(ctx *context.Context, x *Node) *Node {
+				b := MultiHeadAttention(ctx, x, x, x, numHeads, headDim).
+					SetTemperature(0.5).
+					SetLogitSoftCap(2).
+					UseBlockedAttention(keyBlockSize)
+				if causal {
+					b = b.UseCausalMask()
+				}
+				return b.Done()
+			}, x)
+		}
+		want := outputFn(0)
+		got := outputFn(2)
+		require.Truef(t, got.InDelta(want, 1e-4), "causal=%v: got %s, want %s", causal, got, want)
+	}
+}
+
 // buildSyntheticAttentionModelFn builds a model graph building function that does a regression on the elements
 // of a sequence, with a learnable positional embedding.
 //
@@ -21,6 +21,12 @@ type RMSNormBuilder struct {
 	epsilon           float64
 }
 
+var (
+	// ParamRMSNormEpsilon is the context parameter that defines the default RMS normalization epsilon value.
+	// The default is 1e-6.
+	ParamRMSNormEpsilon = "rms_norm_epsilon"
+)
+
 // RMSNorm starts the configuration of an RMS normalization operation,
 // as described in https://arxiv.org/abs/1910.07467.
 //
@@ -38,7 +44,7 @@ func RMSNorm(ctx *context.Context, operand *Node) *RMSNormBuilder {
 		operand:           operand,
 		useScale:          true,
 		normalizationAxes: []int{-1},
-		epsilon:           1e-6,
+		epsilon:           context.GetParamOr(ctx, ParamRMSNormEpsilon, 1e-6),
 	}
 }
 
@@ -49,7 +55,7 @@ func (rms *RMSNormBuilder) WithScale(useScale bool) *RMSNormBuilder {
 }
 
 // WithEpsilon sets the epsilon value used in RMSNorm configuration and returns the updated builder.
-// The default value is 1e-6.
+// It defaults to the value given by [ParamRMSNormEpsilon], or 1e-6 if not set.
 func (rms *RMSNormBuilder) WithEpsilon(epsilon float64) *RMSNormBuilder {
 	rms.epsilon = epsilon
 	return rms
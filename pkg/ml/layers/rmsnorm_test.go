@@ -38,3 +38,14 @@ func TestRMSNorm(t *testing.T) {
 	require.NotNil(t, scaleVar)
 	require.NoError(t, scaleVar.Shape().CheckDims(3, 2))
 }
+
+func TestRMSNormEpsilonFromContext(t *testing.T) {
+	backend := graphtest.BuildTestBackend()
+	ctx := context.New()
+	ctx.SetParam(ParamRMSNormEpsilon, 1.0)
+	got := context.MustExecOnce(backend, ctx, func(ctx *context.Context, x *Node) *Node {
+		return RMSNorm(ctx, x).WithScale(false).Done()
+	}, [][]float32{{3, 4}})
+	// RMS = sqrt((9+16)/2 + 1) = sqrt(13.5)
+	require.InDeltaSlice(t, []float32{0.8164966, 1.0886621}, got.Value().([][]float32)[0], 1e-5)
+}
@@ -35,6 +35,9 @@ func (r *Trainer) AccumulateGradients(numAccumulatingSteps int) error {
 	if _, ok := r.optimizer.(OptimizeWithGradients); !ok {
 		return errors.Errorf("optimizer %T does not implement OptimizeWithGradients -- to use AccumulateGradients use an optmizer that supports it (e.g.: SGD, Adam)", r.optimizer)
 	}
+	if r.lossScaling {
+		return errors.New("AccumulateGradients cannot be used with DynamicLossScaling")
+	}
 	r.accumulateGradients = true
 	r.accumulateGradientsSteps = numAccumulatingSteps
 	r.accumulateGradientsCurrentStep = 0
@@ -109,6 +112,7 @@ func (r *Trainer) accumulateStepGraphImpl(spec any, ctx *context.Context, inputs
 
 	// Calculate and accumulate gradients: grads is a slice of gradients for each trainable variable, in
 	// the same order as the variables are iterated.
+	r.applyFrozenVariables(ctx)
 	grads := ctx.BuildTrainableVariablesGradientsGraph(loss)
 	numTrainable := len(grads)
 	varIdx := 0
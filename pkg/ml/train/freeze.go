@@ -0,0 +1,98 @@
+package train
+
+import (
+	"regexp"
+
+	"github.com/gomlx/gomlx/pkg/ml/context"
+	"github.com/pkg/errors"
+)
+
+// Freeze marks the variables whose scope and name (see context.Variable.ScopeAndName, e.g.: "/encoder/dense/weights")
+// match any of the given regular expressions as frozen: they are not updated by the optimizer, as if they were not
+// trainable. This can be used for cheap adaptation runs, e.g.: freezing the encoder of a model.
+//
+// Freezing is applied every time a training graph is built (after the model function is called), so it also
+// works for variables not yet created. Frozen variables can be exempted with Unfreeze.
+//
+// It resets the computation graphs (see ResetComputationGraphs), so it can be called in between training steps.
+func (r *Trainer) Freeze(patterns ...string) error {
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return errors.Wrapf(err, "Trainer.Freeze failed to compile pattern %q", pattern)
+		}
+		r.frozenPatterns = append(r.frozenPatterns, re)
+	}
+	r.ResetComputationGraphs()
+	return nil
+}
+
+// Unfreeze exempts the variables whose scope and name match any of the given regular expressions from Freeze.
+// E.g.: to train only the LoRA adapters of a model:
+//
+//	err := trainer.Freeze(".*")
+//	if err == nil { err = trainer.Unfreeze("/lora_[ab]$") }
+//
+// Notice it doesn't make trainable variables that were created as not trainable (e.g. batch normalization moving
+// averages), it only reverts Freeze.
+//
+// It resets the computation graphs (see ResetComputationGraphs), so it can be called in between training steps.
+func (r *Trainer) Unfreeze(patterns ...string) error {
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return errors.Wrapf(err, "Trainer.Unfreeze failed to compile pattern %q", pattern)
+		}
+		r.unfrozenPatterns = append(r.unfrozenPatterns, re)
+	}
+	r.ResetComputationGraphs()
+	return nil
+}
+
+// ClearFrozen removes all patterns given to Freeze and Unfreeze, and restores the variables frozen by them
+// to trainable.
+func (r *Trainer) ClearFrozen() {
+	r.frozenPatterns = nil
+	r.unfrozenPatterns = nil
+	r.applyFrozenVariables(r.context)
+	r.ResetComputationGraphs()
+}
+
+// IsFrozen returns whether the variable is frozen by the patterns given to Freeze and Unfreeze.
+func (r *Trainer) IsFrozen(v *context.Variable) bool {
+	scopeAndName := v.ScopeAndName()
+	frozen := false
+	for _, re := range r.frozenPatterns {
+		if re.MatchString(scopeAndName) {
+			frozen = true
+			break
+		}
+	}
+	if !frozen {
+		return false
+	}
+	for _, re := range r.unfrozenPatterns {
+		if re.MatchString(scopeAndName) {
+			return false
+		}
+	}
+	return true
+}
+
+// applyFrozenVariables marks frozen variables as not trainable, and restores variables previously frozen
+// that are no longer frozen.
+//
+// It must be called after the model function creates the variables and before the optimizer builds the gradients.
+func (r *Trainer) applyFrozenVariables(ctx *context.Context) {
+	for v := range ctx.IterVariables() {
+		if r.IsFrozen(v) {
+			if v.Trainable {
+				v.SetTrainable(false)
+				r.frozenVariables[v] = true
+			}
+		} else if r.frozenVariables[v] {
+			v.SetTrainable(true)
+			delete(r.frozenVariables, v)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package train
+
+import (
+	"testing"
+
+	. "github.com/gomlx/gomlx/pkg/core/graph"
+	"github.com/gomlx/gomlx/pkg/core/graph/graphtest"
+	"github.com/gomlx/gomlx/pkg/core/tensors"
+	"github.com/gomlx/gomlx/pkg/ml/context"
+	"github.com/gomlx/gomlx/pkg/ml/train/losses"
+	"github.com/gomlx/gomlx/pkg/ml/train/optimizers"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/gomlx/gomlx/backends/default"
+)
+
+func TestTrainer_Freeze(t *testing.T) {
+	backend := graphtest.BuildTestBackend()
+	ctx := context.New().Checked(false)
+	modelFn := func(ctx *context.Context, spec any, inputs []*Node) []*Node {
+		g := inputs[0].Graph()
+		a := ctx.In("model").VariableWithValue("a", float32(0)).ValueGraph(g)
+		b := ctx.In("model").VariableWithValue("b", float32(0)).ValueGraph(g)
+		return []*Node{Add(a, b)}
+	}
+	optimizer := optimizers.StochasticGradientDescent().WithDecay(false).WithLearningRate(1).Done()
+	trainer := NewTrainer(backend, ctx, modelFn, losses.MeanAbsoluteError, optimizer, nil, nil)
+	input := tensors.FromScalar(float32(0))
+	label := tensors.FromScalar(float32(10))
+	trainStep := func() (a, b float32) {
+		_, err := trainer.TrainStep(nil, []*tensors.Tensor{input}, []*tensors.Tensor{label})
+		require.NoError(t, err)
+		a = ctx.GetVariableByScopeAndName("/model", "a").MustValue().Value().(float32)
+		b = ctx.GetVariableByScopeAndName("/model", "b").MustValue().Value().(float32)
+		return
+	}
+
+	// Only "b" is trained.
+	require.NoError(t, trainer.Freeze("^/model/"))
+	require.NoError(t, trainer.Unfreeze("/b$"))
+	a, b := trainStep()
+	require.Equal(t, float32(0), a)
+	require.Equal(t, float32(1), b)
+	require.False(t, ctx.GetVariableByScopeAndName("/model", "a").Trainable)
+
+	// Clearing the frozen patterns restores "a" as trainable.
+	trainer.ClearFrozen()
+	require.True(t, ctx.GetVariableByScopeAndName("/model", "a").Trainable)
+	a, b = trainStep()
+	require.Equal(t, float32(1), a)
+	require.Equal(t, float32(2), b)
+
+	require.Error(t, trainer.Freeze("[invalid"))
+}
@@ -124,7 +124,8 @@ const (
 //
 // Useful for projects where more than one loss matches the problem underlying optimization goal.
 //
-// It returns an error if the configured loss is unknown.
+// It returns an error if the configured loss is unknown, or if its hyperparameters (e.g. ParamLabelSmoothing) are
+// invalid.
 func LossFromContext(ctx *context.Context) (LossFn, error) {
 	lossName := context.GetParamOr(ctx, ParamLoss, "mae")
 	lossType, err := TypeString(lossName)
@@ -148,10 +149,15 @@ func LossFromContext(ctx *context.Context) (LossFn, error) {
 		return BinaryCrossentropyLogits, nil
 	case TypeCategoricalCross:
 		return CategoricalCrossEntropy, nil
-	case TypeCategoricalCrossLogits:
-		return CategoricalCrossEntropyLogits, nil
-	case TypeSparseCrossLogits:
-		return SparseCategoricalCrossEntropyLogits, nil
+	case TypeCategoricalCrossLogits, TypeSparseCrossLogits:
+		labelSmoothing := context.GetParamOr(ctx, ParamLabelSmoothing, 0.0)
+		if err := validateLabelSmoothing(labelSmoothing); err != nil {
+			return nil, errors.WithMessagef(err, "invalid value for hyperparameter %q", ParamLabelSmoothing)
+		}
+		if lossType == TypeCategoricalCrossLogits {
+			return MakeCategoricalCrossEntropyLogits(labelSmoothing), nil
+		}
+		return MakeSparseCategoricalCrossEntropyLogits(labelSmoothing), nil
 	case TypeTriplet:
 		return MakeTripletLossFromContext(ctx), nil
 	case TypeEuclidean:
@@ -378,6 +384,83 @@ func SparseCategoricalCrossEntropyLogits(labels, logits []*Node) *Node {
 	return categoricalCrossEntropyLogitsImpl(labelsValues, logits0, labels[1:])
 }
 
+var (
+	// ParamLabelSmoothing is the name of the hyperparameter that defines the label smoothing epsilon used by
+	// the categorical cross-entropy losses created by LossFromContext. See MakeCategoricalCrossEntropyLogits and
+	// MakeSparseCategoricalCrossEntropyLogits.
+	//
+	// It defaults to 0.0, meaning no smoothing.
+	ParamLabelSmoothing = "label_smoothing"
+)
+
+// smoothLabels mixes the labels (a distribution over the last axis) with the uniform distribution:
+// labels * (1-epsilon) + epsilon/numCategories.
+func smoothLabels(labels *Node, epsilon float64) *Node {
+	if epsilon == 0 {
+		return labels
+	}
+	numCategories := labels.Shape().Dimensions[labels.Rank()-1]
+	return AddScalar(MulScalar(labels, 1-epsilon), epsilon/float64(numCategories))
+}
+
+// validateLabelSmoothing returns an error if the label smoothing epsilon is not in the range [0, 1).
+func validateLabelSmoothing(epsilon float64) error {
+	if epsilon < 0 || epsilon >= 1 {
+		return errors.Errorf("label smoothing epsilon must be in the range [0, 1), got %g", epsilon)
+	}
+	return nil
+}
+
+// checkLabelSmoothing panics if the label smoothing epsilon is not in the range [0, 1).
+func checkLabelSmoothing(epsilon float64) {
+	if err := validateLabelSmoothing(epsilon); err != nil {
+		panic(err)
+	}
+}
+
+// MakeCategoricalCrossEntropyLogits returns a CategoricalCrossEntropyLogits loss function with label smoothing:
+// the labels are mixed with the uniform distribution before calculating the cross-entropy, that is, they
+// become `labels * (1-labelSmoothing) + labelSmoothing/numCategories`.
+//
+// labelSmoothing must be in the range [0, 1), and 0.1 is a common value, e.g. in translation models.
+// If 0, it is the same as CategoricalCrossEntropyLogits.
+//
+// See "Rethinking the Inception Architecture for Computer Vision", https://arxiv.org/abs/1512.00567.
+func MakeCategoricalCrossEntropyLogits(labelSmoothing float64) LossFn {
+	checkLabelSmoothing(labelSmoothing)
+	return func(labels, logits []*Node) *Node {
+		return categoricalCrossEntropyLogitsImpl(smoothLabels(labels[0], labelSmoothing), logits[0], labels[1:])
+	}
+}
+
+// MakeSparseCategoricalCrossEntropyLogits returns a SparseCategoricalCrossEntropyLogits loss function with
+// label smoothing: the one-hot encoded labels are mixed with the uniform distribution before calculating the
+// cross-entropy, that is, they become `onehot(labels) * (1-labelSmoothing) + labelSmoothing/numCategories`.
+//
+// labelSmoothing must be in the range [0, 1), and 0.1 is a common value, e.g. in translation models.
+// If 0, it is the same as SparseCategoricalCrossEntropyLogits.
+func MakeSparseCategoricalCrossEntropyLogits(labelSmoothing float64) LossFn {
+	checkLabelSmoothing(labelSmoothing)
+	if labelSmoothing == 0 {
+		return SparseCategoricalCrossEntropyLogits
+	}
+	return func(labels, logits []*Node) *Node {
+		// Convert the labels to their dense (one-hot) form and smooth them.
+		labels0 := labels[0]
+		logits0 := logits[0]
+		if !labels0.DType().IsInt() {
+			Panicf("labels indices dtype (%s), it must be integer", labels0.DType())
+		}
+		if labels0.Rank() != logits0.Rank() || labels0.Shape().Dimensions[labels0.Rank()-1] != 1 {
+			Panicf("labels(%s) and logits(%s) must have the same rank, and labels last dimension must be 1",
+				labels0.Shape(), logits0.Shape())
+		}
+		numCategories := logits0.Shape().Dimensions[logits0.Rank()-1]
+		denseLabels := OneHot(Squeeze(labels0, -1), numCategories, logits0.DType())
+		return categoricalCrossEntropyLogitsImpl(smoothLabels(denseLabels, labelSmoothing), logits0, labels[1:])
+	}
+}
+
 // CategoricalCrossEntropyLogits returns the cross-entropy loss of the logits, given the labels.
 // The labels are provided in "dense" format, they should have the exact same shape as logits, and be set 1 for
 // the true (labeled) category, and 0 for the others -- or any other distribution that sum to 1.
@@ -472,6 +555,91 @@ func CategoricalCrossEntropy(labels, predictions []*Node) *Node {
 	return loss
 }
 
+// KLDivergenceLogits returns the Kullback-Leibler divergence KL(teacher || student) between the categorical
+// distributions given by the teacher logits (labels[0]) and the student logits (logits[0]), summed over the
+// last axis (the categories). It's the usual loss for knowledge distillation, see MakeDistillationLoss.
+//
+// No gradient flows back to the teacher logits.
+//
+// labels can have 2 optional extra values (in any order):
+//
+//   - mask: a boolean mask of shape [batchSize] (or the shape of the logits without the last axis), set to true
+//     for values to be used, and false for those to be ignored. Typically used for padding.
+//   - weights: a float value of shape [batchSize] (or the shape of the logits without the last axis) with the
+//     relative weights to be applied to each example.
+func KLDivergenceLogits(labels, logits []*Node) *Node {
+	return klDivergenceLogitsImpl(labels[0], logits[0], labels[1:], 1.0)
+}
+
+// klDivergenceLogitsImpl implements KLDivergenceLogits, with the logits divided by the temperature, and the loss
+// multiplied by temperature^2.
+func klDivergenceLogitsImpl(teacherLogits, logits *Node, extras []*Node, temperature float64) *Node {
+	if !teacherLogits.Shape().Equal(logits.Shape()) {
+		Panicf("teacher logits (labels[0]=%s) and logits (%s) must have the same shapes",
+			teacherLogits.Shape(), logits.Shape())
+	}
+	weightsShape := shapes.Make(logits.DType(), logits.Shape().Dimensions[:logits.Rank()-1]...)
+	weights, mask := CheckExtraLabelsForWeightsAndMask(weightsShape, extras)
+
+	teacherLogits = StopGradient(teacherLogits)
+	if temperature != 1.0 {
+		teacherLogits = DivScalar(teacherLogits, temperature)
+		logits = DivScalar(logits, temperature)
+	}
+	teacherLogProbs := LogSoftmax(teacherLogits)
+	loss := ReduceSum(Mul(Exp(teacherLogProbs), Sub(teacherLogProbs, LogSoftmax(logits))), -1)
+	if temperature != 1.0 {
+		// Keeps the gradients magnitude independent of the temperature, see Hinton et al.
+		loss = MulScalar(loss, temperature*temperature)
+	}
+
+	// Factor in weights and mask.
+	if weights != nil {
+		loss = Mul(loss, weights)
+	}
+	if mask != nil {
+		loss = Where(mask, loss, ZerosLike(loss))
+		if !loss.IsScalar() {
+			loss = MaskedReduceAllMean(loss, mask)
+		}
+	} else if !loss.IsScalar() {
+		loss = ReduceAllMean(loss)
+	}
+	return loss
+}
+
+var (
+	// ParamDistillationTemperature is the name of the hyperparameter that defines the temperature used
+	// by MakeDistillationLossFromContext.
+	// It defaults to 1.0
+	ParamDistillationTemperature = "distillation_temperature"
+)
+
+// MakeDistillationLoss returns a knowledge distillation loss function, as in "Distilling the Knowledge in a
+// Neural Network", https://arxiv.org/abs/1503.02531: the KL divergence between the teacher and student
+// distributions (see KLDivergenceLogits), both softened by the given temperature, and scaled by temperature^2.
+//
+// The teacher logits are given as labels[0] (they can be generated offline, or by the teacher model in the same
+// graph), and the student logits as predictions[0]. It accepts the same optional mask and weights as
+// KLDivergenceLogits.
+//
+// To also train on the hard labels, or to match the teacher hidden states, add those loss terms with
+// train.AddLoss, e.g.: `train.AddLoss(ctx, MeanSquaredError([]*Node{teacherHidden}, []*Node{studentHidden}))`.
+func MakeDistillationLoss(temperature float64) LossFn {
+	if temperature <= 0 {
+		Panicf("MakeDistillationLoss requires temperature > 0, got %g", temperature)
+	}
+	return func(labels, logits []*Node) *Node {
+		return klDivergenceLogitsImpl(labels[0], logits[0], labels[1:], temperature)
+	}
+}
+
+// MakeDistillationLossFromContext calls MakeDistillationLoss using the temperature configured by the
+// hyperparameter ParamDistillationTemperature in the context.
+func MakeDistillationLossFromContext(ctx *context.Context) LossFn {
+	return MakeDistillationLoss(context.GetParamOr(ctx, ParamDistillationTemperature, 1.0))
+}
+
 // MakeHuberLoss returns a Huber loss function: it's similar to an L2 (MeanSquaredLoss) close to the target,
 // and it becomes L1 (linear) away from the target.
 //
@@ -18,10 +18,12 @@ package losses
 
 import (
 	"fmt"
+	"math"
 	"testing"
 
 	. "github.com/gomlx/gomlx/pkg/core/graph"
 	"github.com/gomlx/gomlx/pkg/core/graph/graphtest"
+	"github.com/gomlx/gomlx/pkg/ml/context"
 	"github.com/stretchr/testify/require"
 
 	_ "github.com/gomlx/gomlx/backends/default"
@@ -225,6 +227,86 @@ func TestCategoricalCrossEntropy(t *testing.T) {
 		}, float32(0+10)/2, true)
 }
 
+func TestLabelSmoothing(t *testing.T) {
+	// With epsilon=0.3 and 3 categories, one-hot labels become 0.1 for the wrong categories and 0.8 for the right one.
+	logits := [][]float32{{0, 1, 2}, {2, 0, 0}}
+	logSoftmax := [][]float64{
+		{-2.40761, -1.40761, -0.40761},
+		{-0.23954, -2.23954, -2.23954},
+	}
+	want := float32(-(0.1*logSoftmax[0][0] + 0.8*logSoftmax[0][1] + 0.1*logSoftmax[0][2] +
+		0.1*logSoftmax[1][0] + 0.1*logSoftmax[1][1] + 0.8*logSoftmax[1][2]) / 2)
+
+	testSomeFunc[float32](t, "MakeSparseCategoricalCrossEntropyLogits",
+		func(g *Graph) (input, output *Node) {
+			labels := Const(g, [][]int32{{1}, {2}})
+			input = Const(g, logits)
+			output = MakeSparseCategoricalCrossEntropyLogits(0.3)([]*Node{labels}, []*Node{input})
+			return
+		}, want, true)
+
+	testSomeFunc[float32](t, "MakeCategoricalCrossEntropyLogits",
+		func(g *Graph) (input, output *Node) {
+			labels := Const(g, [][]float32{{0, 1, 0}, {0, 0, 1}, {1, 0, 0}})
+			mask := Const(g, []bool{true, true, false})
+			input = Const(g, append(logits, []float32{0, 0, 0}))
+			output = MakeCategoricalCrossEntropyLogits(0.3)([]*Node{labels, mask}, []*Node{input})
+			return
+		}, want, true)
+
+	// Without smoothing, it should be the same as the usual cross-entropy.
+	testSomeFunc[float32](t, "MakeSparseCategoricalCrossEntropyLogits(0)",
+		func(g *Graph) (input, output *Node) {
+			labels := Const(g, [][]int32{{1}, {2}})
+			input = Const(g, logits)
+			output = MakeSparseCategoricalCrossEntropyLogits(0)([]*Node{labels}, []*Node{input})
+			return
+		}, float32(-(logSoftmax[0][1]+logSoftmax[1][2])/2), true)
+
+	// Invalid values: the constructors panic, LossFromContext returns an error.
+	require.Panics(t, func() { MakeSparseCategoricalCrossEntropyLogits(1) })
+	require.Panics(t, func() { MakeCategoricalCrossEntropyLogits(-0.1) })
+	for _, lossName := range []string{TypeCategoricalCrossLogits.String(), TypeSparseCrossLogits.String()} {
+		ctx := context.New()
+		ctx.SetParams(map[string]any{ParamLoss: lossName, ParamLabelSmoothing: 0.1})
+		lossFn, err := LossFromContext(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, lossFn)
+
+		ctx.SetParam(ParamLabelSmoothing, 1.5)
+		require.NotPanics(t, func() { lossFn, err = LossFromContext(ctx) })
+		require.Error(t, err, "loss %q", lossName)
+		require.Nil(t, lossFn)
+	}
+}
+
+func TestDistillationLoss(t *testing.T) {
+	// Teacher probabilities (with temperature 1) are [0.25, 0.75], student's are [0.5, 0.5].
+	teacherLogits := [][]float32{{0, float32(math.Log(3))}, {1, 2}}
+	klDiv := func(temperature float64) float64 {
+		pTeacher := 1 / (1 + math.Pow(3, 1/temperature))
+		qTeacher := 1 - pTeacher
+		return (pTeacher*math.Log(pTeacher/0.5) + qTeacher*math.Log(qTeacher/0.5)) * temperature * temperature
+	}
+
+	testSomeFunc[float32](t, "KLDivergenceLogits",
+		func(g *Graph) (input, output *Node) {
+			input = Const(g, [][]float32{{0, 0}, {1, 2}})
+			labels := Const(g, teacherLogits)
+			mask := Const(g, []bool{true, false})
+			output = KLDivergenceLogits([]*Node{labels, mask}, []*Node{input})
+			return
+		}, float32(klDiv(1)), true)
+
+	testSomeFunc[float32](t, "MakeDistillationLoss",
+		func(g *Graph) (input, output *Node) {
+			input = Const(g, [][]float32{{0, 0}, {1, 2}})
+			labels := Const(g, teacherLogits)
+			output = MakeDistillationLoss(2.0)([]*Node{labels}, []*Node{input})
+			return
+		}, float32(klDiv(2)/2), true)
+}
+
 func TestHuberLoss(t *testing.T) {
 	graphtest.RunTestGraphFn(t, "MakeHuberLoss", func(g *Graph) (inputs, outputs []*Node) {
 		inputs = []*Node{
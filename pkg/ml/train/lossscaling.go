@@ -0,0 +1,148 @@
+package train
+
+import (
+	"github.com/gomlx/gomlx/internal/exceptions"
+	"github.com/gomlx/gomlx/pkg/core/graph"
+	"github.com/gomlx/gomlx/pkg/ml/context"
+	"github.com/gomlx/gomlx/pkg/ml/train/optimizers"
+	"github.com/gomlx/gopjrt/dtypes"
+	"github.com/pkg/errors"
+)
+
+const (
+	// LossScalingScope is the scope under which the dynamic loss scaling variables are stored.
+	LossScalingScope = "loss_scaling"
+
+	// LossScaleVarName is the name of the variable holding the current loss scale, under LossScalingScope.
+	LossScaleVarName = "scale"
+
+	// LossScalingGoodStepsVarName is the name of the variable holding the number of consecutive steps with
+	// finite gradients, under LossScalingScope.
+	LossScalingGoodStepsVarName = "good_steps"
+)
+
+// DynamicLossScaling configures the trainer to use dynamic loss scaling, used for mixed-precision training.
+//
+// In mixed-precision training the variables (the "master weights") are kept in float32, and the model converts them
+// (and the inputs) to float16 or bfloat16 (e.g. with graph.ConvertDType) to do the heavy computations. The gradients
+// flow back through the conversion, so they are accumulated and applied in float32. But the small gradients
+// underflow in float16: to prevent that, the loss is multiplied by a "loss scale" before the gradients are
+// calculated, and the gradients are divided by the same scale before being applied.
+//
+// The loss scale starts at initialScale (e.g. 2^15), and it is halved whenever any of the gradients is not finite
+// (NaN or +/-Inf), in which case the training step is skipped: the trainable variables and the optimizer state
+// (e.g. Adam's moments) are not updated. The counters (e.g. the global step) and the learning rate are still
+// updated, so the learning-rate schedules keep advancing.
+// After growthInterval (e.g. 2000) consecutive steps with finite gradients, the loss scale is doubled.
+//
+// The current loss scale is stored in the variable LossScaleVarName under the LossScalingScope.
+//
+// It requires an optimizer that implements OptimizeWithGradients (e.g.: SGD, Adam), and it doesn't work with
+// AccumulateGradients.
+func (r *Trainer) DynamicLossScaling(initialScale float64, growthInterval int) error {
+	if r.optimizer == nil {
+		return errors.New("optimizer is nil!?")
+	}
+	if _, ok := r.optimizer.(OptimizeWithGradients); !ok {
+		return errors.Errorf("optimizer %T does not implement OptimizeWithGradients -- to use DynamicLossScaling use an optmizer that supports it (e.g.: SGD, Adam)", r.optimizer)
+	}
+	if r.accumulateGradients {
+		return errors.New("DynamicLossScaling cannot be used with AccumulateGradients")
+	}
+	if initialScale < 1 {
+		return errors.Errorf("DynamicLossScaling initialScale must be >= 1, got %g", initialScale)
+	}
+	if growthInterval < 1 {
+		return errors.Errorf("DynamicLossScaling growthInterval must be >= 1, got %d", growthInterval)
+	}
+	r.lossScaling = true
+	r.lossScalingInitialScale = initialScale
+	r.lossScalingGrowthInterval = growthInterval
+	r.ResetComputationGraphs()
+	return nil
+}
+
+// lossScalingVariables returns the variables with the current loss scale and the number of consecutive steps with
+// finite gradients, creating them if needed.
+func (r *Trainer) lossScalingVariables(ctx *context.Context) (scaleVar, goodStepsVar *context.Variable) {
+	ctx = ctx.Checked(false).InAbsPath(context.ScopeSeparator + LossScalingScope)
+	scaleVar = ctx.VariableWithValue(LossScaleVarName, float32(r.lossScalingInitialScale)).SetTrainable(false)
+	goodStepsVar = ctx.VariableWithValue(LossScalingGoodStepsVarName, int64(0)).SetTrainable(false)
+	return
+}
+
+// lossScalingUpdateGraph calculates the gradients of the scaled loss, and applies them with the optimizer if they
+// are all finite. It also updates the loss scale.
+//
+// It is used by trainStepGraph in place of the optimizer's UpdateGraph if DynamicLossScaling is configured.
+func (r *Trainer) lossScalingUpdateGraph(ctx *context.Context, g *graph.Graph, loss *graph.Node) {
+	if !loss.Shape().IsScalar() {
+		exceptions.Panicf("DynamicLossScaling requires a scalar loss, got loss.shape=%s instead", loss.Shape())
+	}
+	scaleVar, goodStepsVar := r.lossScalingVariables(ctx)
+	scale := scaleVar.ValueGraph(g)
+
+	// Loss scaling is done in at least float32.
+	lossDType := loss.DType()
+	if lossDType == dtypes.Float16 || lossDType == dtypes.BFloat16 {
+		lossDType = dtypes.Float32
+		loss = graph.ConvertDType(loss, lossDType)
+	}
+	scaledLoss := graph.Mul(loss, graph.ConvertDType(scale, lossDType))
+	grads := ctx.BuildTrainableVariablesGradientsGraph(scaledLoss)
+	allFinite := graph.Const(g, true)
+	for ii, grad := range grads {
+		grad = graph.Div(grad, graph.ConvertDType(scale, grad.DType()))
+		isFinite := graph.LogicalAll(graph.IsFinite(grad))
+		allFinite = graph.LogicalAnd(allFinite, isFinite)
+		// Non-finite gradients are zeroed, so they don't propagate NaNs into the optimizer state.
+		grads[ii] = graph.Where(isFinite, grad, graph.ZerosLike(grad))
+	}
+
+	// Apply the gradients, and revert the changes made by the optimizer if any of the gradients is not finite.
+	changedBefore := make(map[*context.Variable]bool)
+	for v := range ctx.IterVariables() {
+		if v.ChangedInGraph(g) {
+			changedBefore[v] = true
+		}
+	}
+	r.optimizer.(OptimizeWithGradients).UpdateGraphWithGradients(ctx, grads, lossDType)
+	for v := range ctx.IterVariables() {
+		if changedBefore[v] || !v.ChangedInGraph(g) || !lossScalingRevertsVariable(v) {
+			continue
+		}
+		v.SetValueGraph(graph.Where(allFinite, v.ValueGraph(g), v.InitialValueGraph(g)))
+	}
+
+	// Update loss scale.
+	goodSteps := graph.Where(allFinite, graph.OnePlus(goodStepsVar.ValueGraph(g)), graph.ScalarZero(g, dtypes.Int64))
+	grow := graph.GreaterOrEqual(goodSteps, graph.Scalar(g, dtypes.Int64, r.lossScalingGrowthInterval))
+	goodSteps = graph.Where(grow, graph.ScalarZero(g, dtypes.Int64), goodSteps)
+	newScale := graph.Where(allFinite,
+		graph.Where(grow, graph.MulScalar(scale, 2), scale),
+		graph.Max(graph.DivScalar(scale, 2), graph.ScalarOne(g, scale.DType())))
+	scaleVar.SetValueGraph(newScale)
+	goodStepsVar.SetValueGraph(goodSteps)
+}
+
+// lossScalingRevertsVariable returns whether the update of the variable by the optimizer is reverted in a step
+// skipped by DynamicLossScaling: it is the case for the trainable variables and the optimizer state, but not for
+// the counters (integer variables, like the global step) and the learning rate.
+func lossScalingRevertsVariable(v *context.Variable) bool {
+	if v.Trainable {
+		return true
+	}
+	return v.Shape().DType.IsFloat() && v.Name() != optimizers.ParamLearningRate
+}
+
+// LossScale returns the current loss scale, if DynamicLossScaling is being used. Otherwise, it returns 0.
+func (r *Trainer) LossScale() float64 {
+	if !r.lossScaling {
+		return 0
+	}
+	v := r.context.GetVariableByScopeAndName(context.ScopeSeparator+LossScalingScope, LossScaleVarName)
+	if v == nil {
+		return r.lossScalingInitialScale
+	}
+	return float64(v.MustValue().Value().(float32))
+}
@@ -0,0 +1,90 @@
+package train
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/gomlx/gomlx/pkg/core/graph"
+	"github.com/gomlx/gomlx/pkg/core/graph/graphtest"
+	"github.com/gomlx/gomlx/pkg/core/tensors"
+	"github.com/gomlx/gomlx/pkg/ml/context"
+	"github.com/gomlx/gomlx/pkg/ml/train/losses"
+	"github.com/gomlx/gomlx/pkg/ml/train/optimizers"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/gomlx/gomlx/backends/default"
+)
+
+func TestTrainer_DynamicLossScaling(t *testing.T) {
+	backend := graphtest.BuildTestBackend()
+	ctx := context.New().Checked(false)
+	modelFn := func(ctx *context.Context, spec any, inputs []*Node) []*Node {
+		a := ctx.In("model").VariableWithValue("a", float32(0)).ValueGraph(inputs[0].Graph())
+		return []*Node{Mul(a, inputs[0])}
+	}
+	optimizer := optimizers.StochasticGradientDescent().WithDecay(false).WithLearningRate(1).Done()
+	trainer := NewTrainer(backend, ctx, modelFn, losses.MeanAbsoluteError, optimizer, nil, nil)
+	require.NoError(t, trainer.DynamicLossScaling(1024, 2))
+	require.Equal(t, 1024.0, trainer.LossScale())
+	trainStep := func(x float32) (a float32) {
+		_, err := trainer.TrainStep(nil, []*tensors.Tensor{tensors.FromScalar(x)}, []*tensors.Tensor{tensors.FromScalar(float32(10))})
+		require.NoError(t, err)
+		return ctx.GetVariableByScopeAndName("/model", "a").MustValue().Value().(float32)
+	}
+
+	// Gradients are unscaled before being applied, and the scale grows after 2 steps.
+	require.Equal(t, float32(1), trainStep(1))
+	require.Equal(t, 1024.0, trainer.LossScale())
+	require.Equal(t, float32(2), trainStep(1))
+	require.Equal(t, 2048.0, trainer.LossScale())
+	globalStep := optimizers.GetGlobalStep(ctx)
+
+	// Overflowing gradients: the step is skipped and the scale is halved, but the global step still advances.
+	require.Equal(t, float32(2), trainStep(1e38))
+	require.Equal(t, 1024.0, trainer.LossScale())
+	require.Equal(t, globalStep+1, optimizers.GetGlobalStep(ctx))
+	require.Equal(t, float32(3), trainStep(1))
+	require.Equal(t, globalStep+2, optimizers.GetGlobalStep(ctx))
+
+	require.Error(t, trainer.AccumulateGradients(2))
+}
+
+func TestTrainer_DynamicLossScalingWithAdam(t *testing.T) {
+	backend := graphtest.BuildTestBackend()
+	ctx := context.New().Checked(false)
+	modelFn := func(ctx *context.Context, spec any, inputs []*Node) []*Node {
+		a := ctx.In("model").VariableWithValue("a", []float32{0, 0}).ValueGraph(inputs[0].Graph())
+		return []*Node{ReduceAllSum(Mul(a, inputs[0]))}
+	}
+	trainer := NewTrainer(backend, ctx, modelFn, losses.MeanAbsoluteError, optimizers.Adam().Done(), nil, nil)
+	require.NoError(t, trainer.DynamicLossScaling(1024, 100))
+	trainStep := func(x float32) {
+		_, err := trainer.TrainStep(nil, []*tensors.Tensor{tensors.FromValue([]float32{x, 1})},
+			[]*tensors.Tensor{tensors.FromScalar(float32(10))})
+		require.NoError(t, err)
+	}
+	// Values of the model variable and of its moments in the optimizer.
+	state := func() map[string]any {
+		values := make(map[string]any)
+		for v := range ctx.IterVariables() {
+			if strings.HasPrefix(v.Scope(), "/model") || strings.HasPrefix(v.Scope(), "/AdamOptimizer/") {
+				values[v.ScopeAndName()] = v.MustValue().Value()
+			}
+		}
+		return values
+	}
+
+	trainStep(1)
+	before := state()
+	require.Len(t, before, 3) // The variable and its 2 moments.
+	globalStep := optimizers.GetGlobalStep(ctx)
+
+	// Overflowing gradients: the variable and the moments are reverted, the global step advances.
+	trainStep(1e38)
+	require.Equal(t, before, state())
+	require.Equal(t, globalStep+1, optimizers.GetGlobalStep(ctx))
+	require.Equal(t, 512.0, trainer.LossScale())
+
+	trainStep(1)
+	require.NotEqual(t, before, state())
+}
@@ -0,0 +1,107 @@
+package texteval
+
+import (
+	"math"
+)
+
+// BLEUMaxOrder is the maximum order of the n-grams used by CorpusBLEU.
+const BLEUMaxOrder = 4
+
+// BLEUScore is the result of CorpusBLEU.
+type BLEUScore struct {
+	// Score is the BLEU score, the geometric mean of the Precisions multiplied by the BrevityPenalty.
+	Score float64
+
+	// Precisions of the n-grams of each order, from 1 to BLEUMaxOrder.
+	Precisions [BLEUMaxOrder]float64
+
+	// BrevityPenalty is 1 if the hypotheses are longer than the references, and exp(1 - r/h) otherwise.
+	BrevityPenalty float64
+
+	// HypothesisLength and ReferenceLength are the number of words of the hypotheses and of the references
+	// closest in length to each hypothesis.
+	HypothesisLength, ReferenceLength int
+}
+
+// CorpusBLEU returns the BLEU score (Papineni et al., 2002) of the hypotheses, where references[i] holds one or more
+// references of hypotheses[i].
+//
+// As in sacreBLEU, the statistics of the n-grams and the lengths are summed over the corpus before computing the
+// score, the counts of the n-grams of each hypothesis are clipped to their maximum count in any of its references,
+// and orders without matches are smoothed with the "exp" method (from the NIST mteval script): their precision is
+// 1/(2^k·total), for the k-th order without matches.
+func CorpusBLEU(hypotheses []string, references [][]string, opts *Options) (*BLEUScore, error) {
+	if err := checkCorpus("CorpusBLEU", hypotheses, references); err != nil {
+		return nil, err
+	}
+	var matches, totals [BLEUMaxOrder]int
+	score := &BLEUScore{}
+	for i, hypothesis := range hypotheses {
+		hypTokens := opts.tokenize(hypothesis)
+		refsTokens := make([][]string, len(references[i]))
+		for refIdx, reference := range references[i] {
+			refsTokens[refIdx] = opts.tokenize(reference)
+		}
+		score.HypothesisLength += len(hypTokens)
+		score.ReferenceLength += closestReferenceLength(len(hypTokens), refsTokens)
+
+		for order := 1; order <= BLEUMaxOrder; order++ {
+			hypCounts := ngramCounts(hypTokens, order)
+			maxRefCounts := make(map[string]int)
+			for _, refTokens := range refsTokens {
+				for ngram, count := range ngramCounts(refTokens, order) {
+					maxRefCounts[ngram] = max(maxRefCounts[ngram], count)
+				}
+			}
+			matches[order-1] += ngramMatches(hypCounts, maxRefCounts)
+			totals[order-1] += max(len(hypTokens)-order+1, 0)
+		}
+	}
+
+	smoothing := 1.0
+	var sumLogs float64
+	for order := range BLEUMaxOrder {
+		switch {
+		case totals[order] == 0:
+			// No n-grams of this order: the precision (and the score) is 0.
+		case matches[order] == 0:
+			smoothing *= 2
+			score.Precisions[order] = 1 / (smoothing * float64(totals[order]))
+		default:
+			score.Precisions[order] = float64(matches[order]) / float64(totals[order])
+		}
+		sumLogs += math.Log(score.Precisions[order])
+	}
+
+	switch {
+	case score.HypothesisLength == 0:
+		score.BrevityPenalty = 0
+	case score.HypothesisLength >= score.ReferenceLength:
+		score.BrevityPenalty = 1
+	default:
+		score.BrevityPenalty = math.Exp(1 - float64(score.ReferenceLength)/float64(score.HypothesisLength))
+	}
+	score.Score = score.BrevityPenalty * math.Exp(sumLogs/BLEUMaxOrder)
+	return score, nil
+}
+
+// closestReferenceLength returns the length of the reference closest to the length of the hypothesis, the
+// shortest one in case of ties.
+func closestReferenceLength(hypLength int, refsTokens [][]string) int {
+	closest := -1
+	for _, refTokens := range refsTokens {
+		length := len(refTokens)
+		diff, closestDiff := abs(length-hypLength), abs(closest-hypLength)
+		if closest < 0 || diff < closestDiff || (diff == closestDiff && length < closest) {
+			closest = length
+		}
+	}
+	return closest
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,58 @@
+package texteval
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCorpusBLEU(t *testing.T) {
+	// Identical texts.
+	score, err := CorpusBLEU([]string{"the cat sat on the mat"}, [][]string{{"the cat sat on the mat"}}, nil)
+	require.NoError(t, err)
+	assert.InDelta(t, 1.0, score.Score, 1e-9)
+	assert.Equal(t, 1.0, score.BrevityPenalty)
+
+	// Precisions: 5/6, 3/5, 1/4 and, with no 4-gram matches, the smoothed 1/(2·3).
+	score, err = CorpusBLEU([]string{"the cat sat on the mat"}, [][]string{{"the cat is on the mat"}}, nil)
+	require.NoError(t, err)
+	assert.InDeltaSlice(t, []float64{5.0 / 6, 3.0 / 5, 1.0 / 4, 1.0 / 6}, score.Precisions[:], 1e-9)
+	assert.InDelta(t, math.Pow(1.0/48, 0.25), score.Score, 1e-9)
+	assert.Equal(t, 6, score.HypothesisLength)
+	assert.Equal(t, 6, score.ReferenceLength)
+
+	// Short hypothesis: brevity penalty, and no 3-grams, so the score is 0.
+	score, err = CorpusBLEU([]string{"the cat"}, [][]string{{"the cat is on the mat"}}, nil)
+	require.NoError(t, err)
+	assert.InDelta(t, math.Exp(-2), score.BrevityPenalty, 1e-9)
+	assert.Equal(t, 0.0, score.Score)
+
+	// Multiple references: the counts are clipped to the maximum count in any reference, and the length is
+	// the one of the closest reference.
+	score, err = CorpusBLEU([]string{"the the the the"}, [][]string{{"the cat", "the the mat"}}, nil)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.5, score.Precisions[0], 1e-9)
+	assert.Equal(t, 3, score.ReferenceLength)
+
+	// The statistics are summed over the corpus: the second sentence alone has no 4-grams.
+	score, err = CorpusBLEU([]string{"the cat sat on the mat", "a dog"},
+		[][]string{{"the cat sat on the mat"}, {"a dog"}}, nil)
+	require.NoError(t, err)
+	assert.InDelta(t, 1.0, score.Score, 1e-9)
+
+	// Case sensitivity.
+	score, err = CorpusBLEU([]string{"The Cat sat on the mat."}, [][]string{{"the cat sat on the mat ."}},
+		&Options{Lowercase: true})
+	require.NoError(t, err)
+	assert.InDelta(t, 1.0, score.Score, 1e-9)
+
+	// Empty corpus or hypotheses.
+	score, err = CorpusBLEU(nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, score.Score)
+	score, err = CorpusBLEU([]string{""}, [][]string{{"a b"}}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, score.Score)
+}
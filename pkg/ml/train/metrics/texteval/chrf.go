@@ -0,0 +1,129 @@
+package texteval
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	// ChrFCharOrder is the maximum order of the character n-grams used by CorpusChrF.
+	ChrFCharOrder = 6
+
+	// ChrFBeta is the weight of the recall relative to the precision in the chrF score.
+	ChrFBeta = 2
+)
+
+// ChrFScore is the result of CorpusChrF.
+type ChrFScore struct {
+	// Score is the chrF (or chrF++) score, the F-score with β=ChrFBeta of the Precision and Recall.
+	Score float64
+
+	// Precision and Recall averaged over the orders of the n-grams.
+	Precision, Recall float64
+}
+
+// CorpusChrF returns the chrF score (Popović, 2015) of the hypotheses, where references[i] holds one or more
+// references of hypotheses[i]. With Options.ChrFWordOrder set to 2, it returns the chrF++ score (Popović, 2017).
+//
+// As in sacreBLEU, the character n-grams, of orders 1 to ChrFCharOrder, ignore the white spaces, and the
+// statistics of the n-grams are summed over the corpus, using for each hypothesis the reference with its best
+// chrF score, before computing the score.
+func CorpusChrF(hypotheses []string, references [][]string, opts *Options) (*ChrFScore, error) {
+	if err := checkCorpus("CorpusChrF", hypotheses, references); err != nil {
+		return nil, err
+	}
+	var wordOrder int
+	if opts != nil {
+		wordOrder = opts.ChrFWordOrder
+	}
+	var totals []chrFStats
+	for i, hypothesis := range hypotheses {
+		hypNGrams := chrFNGrams(hypothesis, wordOrder, opts)
+		var best []chrFStats
+		var bestScore float64
+		for refIdx, reference := range references[i] {
+			refNGrams := chrFNGrams(reference, wordOrder, opts)
+			stats := make([]chrFStats, len(hypNGrams))
+			for order := range hypNGrams {
+				stats[order] = chrFStats{
+					hypotheses: sumCounts(hypNGrams[order]),
+					references: sumCounts(refNGrams[order]),
+					matches:    ngramMatches(hypNGrams[order], refNGrams[order]),
+				}
+			}
+			if score := newChrFScore(stats).Score; refIdx == 0 || score > bestScore {
+				best, bestScore = stats, score
+			}
+		}
+		if totals == nil {
+			totals = make([]chrFStats, len(best))
+		}
+		for order, stats := range best {
+			totals[order].hypotheses += stats.hypotheses
+			totals[order].references += stats.references
+			totals[order].matches += stats.matches
+		}
+	}
+	return newChrFScore(totals), nil
+}
+
+// chrFStats are the statistics of the n-grams of one order.
+type chrFStats struct {
+	hypotheses, references, matches int
+}
+
+// newChrFScore returns the score for the statistics of each order, averaging the precisions and recalls over
+// the orders with n-grams both in the hypotheses and the references.
+func newChrFScore(stats []chrFStats) *ChrFScore {
+	score := &ChrFScore{}
+	var effectiveOrder int
+	for _, s := range stats {
+		if s.hypotheses > 0 && s.references > 0 {
+			score.Precision += float64(s.matches) / float64(s.hypotheses)
+			score.Recall += float64(s.matches) / float64(s.references)
+			effectiveOrder++
+		}
+	}
+	if effectiveOrder == 0 {
+		return score
+	}
+	score.Precision /= float64(effectiveOrder)
+	score.Recall /= float64(effectiveOrder)
+	score.Score = fScore(score.Precision, score.Recall, ChrFBeta)
+	return score
+}
+
+// chrFNGrams returns the counts of the character n-grams of orders 1 to ChrFCharOrder, followed by the counts of the
+// word n-grams of orders 1 to wordOrder, of the text.
+func chrFNGrams(text string, wordOrder int, opts *Options) []map[string]int {
+	ngrams := make([]map[string]int, 0, ChrFCharOrder+wordOrder)
+	chars := text
+	if opts != nil && opts.Lowercase {
+		chars = strings.ToLower(chars)
+	}
+	chars = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, chars)
+	charTokens := strings.Split(chars, "")
+	for order := 1; order <= ChrFCharOrder; order++ {
+		ngrams = append(ngrams, ngramCounts(charTokens, order))
+	}
+	if wordOrder > 0 {
+		words := opts.tokenize(text)
+		for order := 1; order <= wordOrder; order++ {
+			ngrams = append(ngrams, ngramCounts(words, order))
+		}
+	}
+	return ngrams
+}
+
+func sumCounts(counts map[string]int) int {
+	var sum int
+	for _, count := range counts {
+		sum += count
+	}
+	return sum
+}
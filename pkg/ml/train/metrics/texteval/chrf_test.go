@@ -0,0 +1,49 @@
+package texteval
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCorpusChrF(t *testing.T) {
+	score, err := CorpusChrF([]string{"the cat sat"}, [][]string{{"the cat sat"}}, nil)
+	require.NoError(t, err)
+	assert.InDelta(t, 1.0, score.Score, 1e-9)
+
+	// White spaces are ignored.
+	score, err = CorpusChrF([]string{"a b"}, [][]string{{"ab"}}, nil)
+	require.NoError(t, err)
+	assert.InDelta(t, 1.0, score.Score, 1e-9)
+
+	// Orders 1 and 2 only: precisions 2/2 and 1/1, recalls 2/3 and 1/2.
+	score, err = CorpusChrF([]string{"ab"}, [][]string{{"abc"}}, nil)
+	require.NoError(t, err)
+	assert.InDelta(t, 1.0, score.Precision, 1e-9)
+	assert.InDelta(t, 7.0/12, score.Recall, 1e-9)
+	assert.InDelta(t, 7.0/11, score.Score, 1e-9)
+
+	// The best reference is used.
+	score, err = CorpusChrF([]string{"ab"}, [][]string{{"xyz", "abc"}}, nil)
+	require.NoError(t, err)
+	assert.InDelta(t, 7.0/11, score.Score, 1e-9)
+
+	// chrF++: the word unigram "ab" doesn't match "abc".
+	score, err = CorpusChrF([]string{"ab"}, [][]string{{"abc"}}, &Options{ChrFWordOrder: 2})
+	require.NoError(t, err)
+	assert.InDelta(t, 2.0/3, score.Precision, 1e-9)
+	assert.InDelta(t, 7.0/18, score.Recall, 1e-9)
+
+	// Case sensitivity.
+	score, err = CorpusChrF([]string{"AB"}, [][]string{{"ab"}}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, score.Score)
+	score, err = CorpusChrF([]string{"AB"}, [][]string{{"ab"}}, &Options{Lowercase: true})
+	require.NoError(t, err)
+	assert.InDelta(t, 1.0, score.Score, 1e-9)
+
+	score, err = CorpusChrF(nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, score.Score)
+}
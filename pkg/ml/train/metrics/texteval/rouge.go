@@ -0,0 +1,125 @@
+package texteval
+
+import (
+	"strings"
+	"unicode"
+)
+
+// PRF holds a precision, a recall and their F1 score (harmonic mean).
+type PRF struct {
+	Precision, Recall, F float64
+}
+
+// ROUGEScore is the result of CorpusROUGE.
+type ROUGEScore struct {
+	// ROUGE1 and ROUGE2 are based on the overlap of the unigrams and of the bigrams.
+	ROUGE1, ROUGE2 PRF
+
+	// ROUGEL is based on the longest common subsequence of words.
+	ROUGEL PRF
+}
+
+// CorpusROUGE returns the ROUGE-1, ROUGE-2 and ROUGE-L scores (Lin, 2004) of the hypotheses, where references[i]
+// holds one or more references of hypotheses[i].
+//
+// As in Google's rouge_score, the scores are computed for each hypothesis, using, for each score, the reference
+// with the best F1, and averaged over the corpus. The tokens without letters or digits (punctuation) are ignored.
+func CorpusROUGE(hypotheses []string, references [][]string, opts *Options) (*ROUGEScore, error) {
+	if err := checkCorpus("CorpusROUGE", hypotheses, references); err != nil {
+		return nil, err
+	}
+	score := &ROUGEScore{}
+	if len(hypotheses) == 0 {
+		return score, nil
+	}
+	for i, hypothesis := range hypotheses {
+		hypTokens := rougeTokenize(hypothesis, opts)
+		var best ROUGEScore
+		for refIdx, reference := range references[i] {
+			refTokens := rougeTokenize(reference, opts)
+			current := ROUGEScore{
+				ROUGE1: rougeN(hypTokens, refTokens, 1),
+				ROUGE2: rougeN(hypTokens, refTokens, 2),
+				ROUGEL: rougeL(hypTokens, refTokens),
+			}
+			if refIdx == 0 || current.ROUGE1.F > best.ROUGE1.F {
+				best.ROUGE1 = current.ROUGE1
+			}
+			if refIdx == 0 || current.ROUGE2.F > best.ROUGE2.F {
+				best.ROUGE2 = current.ROUGE2
+			}
+			if refIdx == 0 || current.ROUGEL.F > best.ROUGEL.F {
+				best.ROUGEL = current.ROUGEL
+			}
+		}
+		score.ROUGE1.add(best.ROUGE1)
+		score.ROUGE2.add(best.ROUGE2)
+		score.ROUGEL.add(best.ROUGEL)
+	}
+	n := float64(len(hypotheses))
+	score.ROUGE1.scale(1 / n)
+	score.ROUGE2.scale(1 / n)
+	score.ROUGEL.scale(1 / n)
+	return score, nil
+}
+
+func (s *PRF) add(other PRF) {
+	s.Precision += other.Precision
+	s.Recall += other.Recall
+	s.F += other.F
+}
+
+func (s *PRF) scale(factor float64) {
+	s.Precision *= factor
+	s.Recall *= factor
+	s.F *= factor
+}
+
+// newPRF returns the scores for the given number of matches, out of hypTotal and refTotal.
+func newPRF(matches, hypTotal, refTotal int) PRF {
+	var s PRF
+	if hypTotal > 0 {
+		s.Precision = float64(matches) / float64(hypTotal)
+	}
+	if refTotal > 0 {
+		s.Recall = float64(matches) / float64(refTotal)
+	}
+	s.F = fScore(s.Precision, s.Recall, 1)
+	return s
+}
+
+// rougeTokenize tokenizes the text, dropping the tokens without letters or digits.
+func rougeTokenize(text string, opts *Options) []string {
+	tokens := opts.tokenize(text)
+	words := tokens[:0]
+	for _, token := range tokens {
+		if strings.IndexFunc(token, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) >= 0 {
+			words = append(words, token)
+		}
+	}
+	return words
+}
+
+// rougeN returns the ROUGE-N scores, based on the overlap of the n-grams of the given order.
+func rougeN(hypTokens, refTokens []string, order int) PRF {
+	matches := ngramMatches(ngramCounts(hypTokens, order), ngramCounts(refTokens, order))
+	return newPRF(matches, max(len(hypTokens)-order+1, 0), max(len(refTokens)-order+1, 0))
+}
+
+// rougeL returns the ROUGE-L scores, based on the length of the longest common subsequence.
+func rougeL(hypTokens, refTokens []string) PRF {
+	// Dynamic programming, keeping only the previous row of the table.
+	prev := make([]int, len(refTokens)+1)
+	current := make([]int, len(refTokens)+1)
+	for _, hypToken := range hypTokens {
+		for j, refToken := range refTokens {
+			if hypToken == refToken {
+				current[j+1] = prev[j] + 1
+			} else {
+				current[j+1] = max(prev[j+1], current[j])
+			}
+		}
+		prev, current = current, prev
+	}
+	return newPRF(prev[len(refTokens)], len(hypTokens), len(refTokens))
+}
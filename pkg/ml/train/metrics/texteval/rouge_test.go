@@ -0,0 +1,37 @@
+package texteval
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCorpusROUGE(t *testing.T) {
+	score, err := CorpusROUGE([]string{"the cat sat on the mat"}, [][]string{{"the cat is on the mat"}}, nil)
+	require.NoError(t, err)
+	assert.InDelta(t, 5.0/6, score.ROUGE1.F, 1e-9)
+	assert.InDelta(t, 3.0/5, score.ROUGE2.F, 1e-9)
+	// Longest common subsequence: "the cat on the mat".
+	assert.InDelta(t, 5.0/6, score.ROUGEL.F, 1e-9)
+
+	// Different lengths: precision and recall differ.
+	score, err = CorpusROUGE([]string{"the cat"}, [][]string{{"the cat is on the mat"}}, nil)
+	require.NoError(t, err)
+	assert.InDelta(t, 1.0, score.ROUGE1.Precision, 1e-9)
+	assert.InDelta(t, 2.0/6, score.ROUGE1.Recall, 1e-9)
+	assert.InDelta(t, 0.5, score.ROUGE1.F, 1e-9)
+	assert.InDelta(t, 1.0/5, score.ROUGE2.Recall, 1e-9)
+
+	// The best reference is used, punctuation is ignored, and the scores are averaged over the corpus.
+	score, err = CorpusROUGE([]string{"The cat.", "a dog"}, [][]string{{"a bird", "the cat"}, {"a fish"}},
+		&Options{Lowercase: true})
+	require.NoError(t, err)
+	assert.InDelta(t, (1.0+0.5)/2, score.ROUGE1.F, 1e-9)
+	assert.InDelta(t, (1.0+0.0)/2, score.ROUGE2.F, 1e-9)
+	assert.InDelta(t, (1.0+0.5)/2, score.ROUGEL.F, 1e-9)
+
+	score, err = CorpusROUGE([]string{""}, [][]string{{"a b"}}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, ROUGEScore{}, *score)
+}
@@ -0,0 +1,129 @@
+// Package texteval implements the usual corpus-level metrics of generated text against reference texts, to
+// evaluate translation and summarization models: BLEU (CorpusBLEU), ROUGE-1/2/L (CorpusROUGE) and chrF/chrF++
+// (CorpusChrF).
+//
+// They follow the definitions of the reference implementations (sacreBLEU for BLEU and chrF, Google's rouge_score
+// for ROUGE), but the scores depend on the tokenization, so they are only comparable when computed with the same
+// Options. All scores are in the range [0, 1]: the values usually reported are multiplied by 100.
+//
+// The metrics are computed in Go, on the host: the texts are usually the output of the detokenization of the
+// generated tokens.
+package texteval
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// Tokenizer splits a text into words.
+type Tokenizer func(text string) []string
+
+// Options of the metrics. The zero value (or a nil *Options) uses the defaults.
+type Options struct {
+	// Tokenizer used to split the texts into words. Defaults to DefaultTokenizer.
+	Tokenizer Tokenizer
+
+	// Lowercase the texts before tokenization, for case-insensitive scores.
+	Lowercase bool
+
+	// ChrFWordOrder is the maximum order of the word n-grams used by CorpusChrF, in addition to the character
+	// n-grams: 0 (the default) for chrF, 2 for chrF++.
+	ChrFWordOrder int
+}
+
+// tokenize the text according to the options.
+func (opts *Options) tokenize(text string) []string {
+	if opts == nil {
+		return DefaultTokenizer(text)
+	}
+	if opts.Lowercase {
+		text = strings.ToLower(text)
+	}
+	if opts.Tokenizer == nil {
+		return DefaultTokenizer(text)
+	}
+	return opts.Tokenizer(text)
+}
+
+// DefaultTokenizer splits the text on white spaces, and separates the punctuation and the symbols as tokens of
+// their own, as the "13a" and "intl" tokenizers of sacreBLEU: e.g. "Hello, world!" is split into "Hello", ",",
+// "world" and "!". Periods and commas between digits are kept, so numbers like "3.14" or "1,000" are not split.
+func DefaultTokenizer(text string) []string {
+	runes := []rune(text)
+	var tokens []string
+	start := -1
+	flush := func(end int) {
+		if start >= 0 {
+			tokens = append(tokens, string(runes[start:end]))
+			start = -1
+		}
+	}
+	for i, r := range runes {
+		switch {
+		case unicode.IsSpace(r):
+			flush(i)
+		case (r == '.' || r == ',') && i > 0 && i+1 < len(runes) && unicode.IsDigit(runes[i-1]) &&
+			unicode.IsDigit(runes[i+1]):
+			// Decimal or thousands separator: part of the number.
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			flush(i)
+			tokens = append(tokens, string(r))
+		default:
+			if start < 0 {
+				start = i
+			}
+		}
+	}
+	flush(len(runes))
+	return tokens
+}
+
+// WhitespaceTokenizer splits the text on white spaces only, for texts that are already tokenized.
+func WhitespaceTokenizer(text string) []string {
+	return strings.Fields(text)
+}
+
+// checkCorpus checks that there are references for each hypothesis.
+func checkCorpus(metric string, hypotheses []string, references [][]string) error {
+	if len(hypotheses) != len(references) {
+		return errors.Errorf("%s: got %d hypotheses but %d lists of references", metric, len(hypotheses),
+			len(references))
+	}
+	for i, refs := range references {
+		if len(refs) == 0 {
+			return errors.Errorf("%s: no references given for the hypothesis #%d", metric, i)
+		}
+	}
+	return nil
+}
+
+// ngramCounts counts the n-grams of the given order of the tokens.
+func ngramCounts(tokens []string, order int) map[string]int {
+	counts := make(map[string]int)
+	for i := 0; i+order <= len(tokens); i++ {
+		counts[strings.Join(tokens[i:i+order], "\x00")]++
+	}
+	return counts
+}
+
+// ngramMatches returns the number of n-grams of the hypothesis matched in the reference, clipping the count of
+// each n-gram to its count in the reference.
+func ngramMatches(hypothesis, reference map[string]int) int {
+	var matches int
+	for ngram, count := range hypothesis {
+		matches += min(count, reference[ngram])
+	}
+	return matches
+}
+
+// fScore returns the weighted harmonic mean of the precision and the recall, with recall beta times as
+// important as the precision.
+func fScore(precision, recall, beta float64) float64 {
+	if precision == 0 && recall == 0 {
+		return 0
+	}
+	beta2 := beta * beta
+	return (1 + beta2) * precision * recall / (beta2*precision + recall)
+}
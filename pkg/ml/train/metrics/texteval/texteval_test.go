@@ -0,0 +1,29 @@
+package texteval
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultTokenizer(t *testing.T) {
+	assert.Equal(t,
+		[]string{"Hello", ",", "world", "!", "It", "costs", "$", "3.14", "(", "1,000", "units", ")", "."},
+		DefaultTokenizer("Hello, world! It costs $3.14 (1,000 units)."))
+	assert.Equal(t, []string{"a", ".", "b", ",", "c"}, DefaultTokenizer("a. b,c"))
+	assert.Empty(t, DefaultTokenizer(" \t\n"))
+	assert.Equal(t, []string{"Hello,", "world!"}, WhitespaceTokenizer(" Hello,  world! "))
+
+	opts := &Options{Lowercase: true, Tokenizer: WhitespaceTokenizer}
+	assert.Equal(t, []string{"hello,", "world!"}, opts.tokenize("Hello, World!"))
+}
+
+func TestCheckCorpus(t *testing.T) {
+	_, err := CorpusBLEU([]string{"a", "b"}, [][]string{{"a"}}, nil)
+	require.Error(t, err)
+	_, err = CorpusROUGE([]string{"a"}, [][]string{{}}, nil)
+	require.Error(t, err)
+	_, err = CorpusChrF([]string{"a"}, nil, nil)
+	require.Error(t, err)
+}
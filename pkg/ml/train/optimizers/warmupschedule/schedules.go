@@ -0,0 +1,228 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package warmupschedule implements a linear warmup of the learning rate, optionally followed by
+// a linear or an inverse square-root decay -- the usual schedules used to train and fine-tune
+// transformer models.
+//
+// See New for details and example of usage. For a cosine annealing schedule, see package cosineschedule.
+package warmupschedule
+
+import (
+	"github.com/gomlx/gomlx/internal/exceptions"
+	. "github.com/gomlx/gomlx/pkg/core/graph"
+	"github.com/gomlx/gomlx/pkg/ml/context"
+	"github.com/gomlx/gomlx/pkg/ml/train"
+	"github.com/gomlx/gomlx/pkg/ml/train/optimizers"
+	"github.com/gomlx/gopjrt/dtypes"
+)
+
+// DecayType defines how the learning rate changes after the warmup steps.
+type DecayType string
+
+const (
+	// DecayConstant keeps the learning rate constant after the warmup.
+	DecayConstant DecayType = "constant"
+
+	// DecayLinear linearly decreases the learning rate after the warmup, reaching the minimum
+	// learning rate at the last training step.
+	DecayLinear DecayType = "linear"
+
+	// DecayInverseSqrt decreases the learning rate proportionally to the inverse square-root of the
+	// training step, as in "Attention Is All You Need" (https://arxiv.org/abs/1706.03762):
+	// `learning_rate * sqrt(warmup_steps / step)`.
+	DecayInverseSqrt DecayType = "inverse_sqrt"
+)
+
+var (
+	// ParamWarmUpSteps is the number of warmup steps: during these initial steps the learning rate
+	// linearly increases from 0 to the learning rate defined by optimizers.ParamLearningRate.
+	//
+	// If both ParamWarmUpSteps is 0 and ParamDecay is "constant" (the defaults), the schedule is disabled.
+	//
+	//  Requires calling `New().FromContext().Done()` at the start of your model.
+	//
+	//  This only affects training; there is no effect during inference or evaluation.
+	ParamWarmUpSteps = "warmup_schedule_steps"
+
+	// ParamDecay defines how the learning rate changes after the warmup steps: "constant" (default),
+	// "linear" or "inverse_sqrt". See DecayType.
+	ParamDecay = "warmup_schedule_decay"
+
+	// ParamMinLearningRate is the minimum value of the learning rate during the decay.
+	// Defaults to 0.0.
+	ParamMinLearningRate = "warmup_schedule_min_learning_rate"
+)
+
+// Config of the warmup schedule strategy.
+// New creates it and once configured, call Config.Done to add it into the computation graph.
+type Config struct {
+	graph                         *Graph
+	ctx                           *context.Context
+	dtype                         dtypes.DType
+	learningRate, minLearningRate float64
+	warmUpSteps                   int
+	decay                         DecayType
+}
+
+// New creates a configuration to apply a linear warmup schedule for the learning rate, optionally
+// followed by a decay (see DecayType).
+// The schedule only affects training; there is no effect during inference or evaluation.
+//
+// It returns a Config that can be configured. When finished configuring, call
+// `Done` and it will generate the computation graph that updates the learning rate at every
+// training step.
+//
+// Example with a warmup of 4000 steps followed by an inverse square-root decay.
+// We assume that the learning rate is set in the context as the parameter "learning_rate"
+// (== optimizers.ParamLearningRate):
+//
+//	func MyModelGraph(cxt *context.Context, inputs []*Node) *Node {
+//		...
+//		g := inputs[0].Graph()
+//		warmupschedule.New(ctx, g, dtypes.Float32).
+//			WarmUpSteps(4000).
+//			Decay(warmupschedule.DecayInverseSqrt).Done()
+//	}
+//
+// Or more simply, pass the hyperparameters in the context (see ParamWarmUpSteps, ParamDecay, and
+// ParamMinLearningRate):
+//
+//	func modelGraph(cxt *context.Context, inputs []*Node) *Node {
+//		...
+//		g := inputs[0].Graph()
+//		warmupschedule.New(ctx, g, dtypes.Float32).FromContext().Done()
+//	}
+func New(ctx *context.Context, graph *Graph, dtype dtypes.DType) *Config {
+	return &Config{
+		ctx:   ctx,
+		graph: graph,
+		dtype: dtype,
+		decay: DecayConstant,
+	}
+}
+
+// FromContext configures the warmup schedule from the context, using the keys
+// [ParamWarmUpSteps], [ParamDecay] and [ParamMinLearningRate].
+func (opt *Config) FromContext() *Config {
+	opt.WarmUpSteps(context.GetParamOr(opt.ctx, ParamWarmUpSteps, 0))
+	opt.Decay(DecayType(context.GetParamOr(opt.ctx, ParamDecay, string(DecayConstant))))
+	opt.LearningRate(context.GetParamOr(opt.ctx, optimizers.ParamLearningRate, 0.0))
+	opt.MinLearningRate(context.GetParamOr(opt.ctx, ParamMinLearningRate, 0.0))
+	return opt
+}
+
+// WarmUpSteps sets the number of steps to linearly increase the learning rate from 0 to the
+// learning rate defined by ParamLearningRate.
+//
+// The default is 0, which means no warmup.
+func (opt *Config) WarmUpSteps(warmUpSteps int) *Config {
+	if warmUpSteps < 0 {
+		exceptions.Panicf("warmUpSteps must be >= 0, but got %d", warmUpSteps)
+	}
+	opt.warmUpSteps = warmUpSteps
+	return opt
+}
+
+// Decay sets how the learning rate changes after the warmup. The default is DecayConstant.
+//
+// Note: DecayLinear depends on the train.Loop object to report how many steps the model is going to be trained for.
+// This works fine if one is using Loop.RunSteps, but if one is using Loop.RunEpochs, the number of steps
+// is not known until the end of the first epoch.
+func (opt *Config) Decay(decay DecayType) *Config {
+	switch decay {
+	case DecayConstant, DecayLinear, DecayInverseSqrt:
+	default:
+		exceptions.Panicf("unknown warmup schedule decay %q, valid values are %q, %q and %q",
+			decay, DecayConstant, DecayLinear, DecayInverseSqrt)
+	}
+	opt.decay = decay
+	return opt
+}
+
+// MinLearningRate is the lowest value the learning rate will decay to. Defaults to 0.0.
+func (opt *Config) MinLearningRate(minLearningRate float64) *Config {
+	if minLearningRate < 0 {
+		exceptions.Panicf("minLearningRate must be >= 0, but got %g", minLearningRate)
+	}
+	opt.minLearningRate = minLearningRate
+	return opt
+}
+
+// LearningRate at the end of the warmup.
+// If not given, it will try to read from the context params (keyed by ParamLearningRate).
+// If neither is set, it will fail and return an error in the context and graph.
+func (opt *Config) LearningRate(learningRate float64) *Config {
+	if learningRate < 0 {
+		exceptions.Panicf("learningRate must be >= 0, but got %g", learningRate)
+	}
+	opt.learningRate = learningRate
+	return opt
+}
+
+const (
+	Scope = "warmup_schedule"
+
+	// DefaultLastStep is the default value for the last step of the training while one is not yet known.
+	DefaultLastStep = 1_000_000_000
+)
+
+// Done finalizes the configuration of New and generates the computation
+// graph code to implement it.
+//
+// If invalid options are given, an error is raised in the Graph.
+func (opt *Config) Done() {
+	ctx := opt.ctx.Checked(false)
+	graph := opt.graph
+
+	if !ctx.IsTraining(opt.graph) || (opt.warmUpSteps <= 0 && opt.decay == DecayConstant) {
+		// Nothing to do.
+		return
+	}
+
+	lrValue := opt.learningRate
+	if lrValue <= 0 {
+		lrValue = context.GetParamOr(opt.ctx, optimizers.ParamLearningRate, 0.0)
+		if lrValue == 0 {
+			exceptions.Panicf("learning rate not configured for New and also "+
+				"not set in the context as parameter %q", optimizers.ParamLearningRate)
+			return
+		}
+	}
+	lrMinValue := max(opt.minLearningRate, 0.0)
+
+	// Current training step: the schedule keeps its own "global step" counter.
+	step := optimizers.IncrementGlobalStepGraph(ctx.In(optimizers.Scope).In(Scope), graph, opt.dtype)
+	step = MinusOne(step) // The value before the increment.
+
+	// Learning rate after the warmup.
+	var lr *Node
+	switch opt.decay {
+	case DecayConstant:
+		lr = Scalar(graph, opt.dtype, lrValue)
+	case DecayLinear:
+		lastStep := train.GetTrainLastStepVar(ctx).ValueGraph(graph)
+		lastStep = Where(IsNegative(lastStep), Const(graph, DefaultLastStep), lastStep)
+		decaySteps := MaxScalar(ConvertDType(SubScalar(lastStep, opt.warmUpSteps), opt.dtype), 1)
+		ratio := Div(SubScalar(step, opt.warmUpSteps), decaySteps)
+		ratio = ClipScalar(ratio, 0, 1) // From 0.0 to 1.0
+		lr = AddScalar(MulScalar(OneMinus(ratio), lrValue-lrMinValue), lrMinValue)
+	case DecayInverseSqrt:
+		warmUpSteps := float64(max(opt.warmUpSteps, 1))
+		ratio := Rsqrt(DivScalar(MaxScalar(step, warmUpSteps), warmUpSteps)) // sqrt(warmUpSteps/step)
+		lr = Max(MulScalar(ratio, lrValue), Scalar(graph, opt.dtype, lrMinValue))
+	}
+
+	// Calculate and merge warmup schedule.
+	if opt.warmUpSteps > 0 {
+		ratio := DivScalar(step, opt.warmUpSteps)
+		warmUpLR := MulScalar(ratio, lrValue)
+		// Apply the warmup learning rate only if in the warmup period.
+		lr = Where(LessThan(ratio, ScalarOne(graph, opt.dtype)), warmUpLR, lr)
+	}
+
+	// Update learning rate.
+	lrVar := optimizers.LearningRateVarWithValue(ctx, opt.dtype, lrValue)
+	lrVar.SetValueGraph(lr)
+}
@@ -0,0 +1,102 @@
+// Copyright 2025 The GoMLX Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package warmupschedule_test
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/gomlx/gomlx/pkg/core/graph"
+	"github.com/gomlx/gomlx/pkg/core/graph/graphtest"
+	"github.com/gomlx/gomlx/pkg/core/tensors"
+	"github.com/gomlx/gomlx/pkg/ml/context"
+	"github.com/gomlx/gomlx/pkg/ml/train"
+	"github.com/gomlx/gomlx/pkg/ml/train/optimizers"
+	"github.com/gomlx/gomlx/pkg/ml/train/optimizers/warmupschedule"
+	"github.com/gomlx/gopjrt/dtypes"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/gomlx/gomlx/backends/default"
+)
+
+func TestWarmUpSchedule(t *testing.T) {
+	backend := graphtest.BuildTestBackend()
+	const warmUpSteps = 10
+	const minLearningRate = 0.01
+	const baseLearningRate = 1.0
+
+	// checkSchedule runs the schedule for numSteps, and compares with wantFn.
+	checkSchedule := func(t *testing.T, ctx *context.Context, configFn func(*warmupschedule.Config),
+		numSteps int, wantFn func(step int) float64) {
+		exec, err := context.NewExec(backend, ctx, func(ctx *context.Context, graph *Graph) *Node {
+			ctx.SetTraining(graph, true)
+			cfg := warmupschedule.New(ctx, graph, dtypes.Float32)
+			configFn(cfg)
+			cfg.Done()
+			return optimizers.LearningRateVar(ctx, dtypes.Float32, 1e3).ValueGraph(graph)
+		})
+		require.NoError(t, err)
+		for ii := range numSteps {
+			lrT, err := exec.Exec1()
+			require.NoErrorf(t, err, "failed for step %d", ii)
+			lr := tensors.ToScalar[float32](lrT)
+			wantLR := wantFn(ii)
+			require.InDeltaf(t, float32(wantLR), lr, 1e-4, "wantLR=%g, lr=%g, step=%d", wantLR, lr, ii)
+		}
+	}
+
+	t.Run("constant", func(t *testing.T) {
+		ctx := context.New().Checked(false)
+		checkSchedule(t, ctx, func(cfg *warmupschedule.Config) {
+			cfg.WarmUpSteps(warmUpSteps).LearningRate(baseLearningRate)
+		}, 3*warmUpSteps, func(step int) float64 {
+			if step < warmUpSteps {
+				return baseLearningRate * float64(step) / warmUpSteps
+			}
+			return baseLearningRate
+		})
+	})
+
+	t.Run("inverse_sqrt", func(t *testing.T) {
+		ctx := context.New().Checked(false)
+		checkSchedule(t, ctx, func(cfg *warmupschedule.Config) {
+			cfg.WarmUpSteps(warmUpSteps).
+				LearningRate(baseLearningRate).
+				MinLearningRate(minLearningRate).
+				Decay(warmupschedule.DecayInverseSqrt)
+		}, 20*warmUpSteps, func(step int) float64 {
+			if step < warmUpSteps {
+				return baseLearningRate * float64(step) / warmUpSteps
+			}
+			return max(baseLearningRate*math.Sqrt(float64(warmUpSteps)/float64(step)), minLearningRate)
+		})
+	})
+
+	t.Run("linear with context configuration", func(t *testing.T) {
+		ctx := context.New().Checked(false)
+		const numSteps = 110
+		ctx.SetParam(optimizers.ParamLearningRate, baseLearningRate)
+		ctx.SetParam(warmupschedule.ParamWarmUpSteps, warmUpSteps)
+		ctx.SetParam(warmupschedule.ParamDecay, string(warmupschedule.DecayLinear))
+		ctx.SetParam(warmupschedule.ParamMinLearningRate, minLearningRate)
+		lastStepVar := train.GetTrainLastStepVar(ctx)
+		lastStepVar.MustSetValue(tensors.FromScalar(int64(numSteps)))
+		checkSchedule(t, ctx, func(cfg *warmupschedule.Config) {
+			cfg.FromContext()
+		}, numSteps, func(step int) float64 {
+			if step < warmUpSteps {
+				return baseLearningRate * float64(step) / warmUpSteps
+			}
+			ratio := float64(step-warmUpSteps) / float64(numSteps-warmUpSteps)
+			return (1-ratio)*(baseLearningRate-minLearningRate) + minLearningRate
+		})
+	})
+
+	t.Run("invalid decay", func(t *testing.T) {
+		require.Panics(t, func() {
+			warmupschedule.New(context.New(), nil, dtypes.Float32).Decay("exponential")
+		})
+	})
+}
@@ -26,6 +26,7 @@ import (
 	"fmt"
 	"io"
 	"iter"
+	"regexp"
 	"slices"
 
 	"github.com/gomlx/gomlx/backends"
@@ -92,6 +93,15 @@ type Trainer struct {
 	accumulateGradientsExecMap         map[any]*context.Exec
 	accumulateGradientsAndApplyExecMap map[any]*context.Exec
 
+	// Frozen variables, see Freeze and Unfreeze.
+	frozenPatterns, unfrozenPatterns []*regexp.Regexp
+	frozenVariables                  map[*context.Variable]bool
+
+	// Dynamic loss scaling, see DynamicLossScaling.
+	lossScaling               bool
+	lossScalingInitialScale   float64
+	lossScalingGrowthInterval int
+
 	// Eval data
 	evalStepExecMap map[any]*context.Exec
 	evalMetrics     []metrics.Interface
@@ -190,6 +200,7 @@ func NewTrainer(backend backends.Backend, ctx *context.Context,
 
 		accumulateGradientsExecMap:         make(map[any]*context.Exec),
 		accumulateGradientsAndApplyExecMap: make(map[any]*context.Exec),
+		frozenVariables:                    make(map[*context.Variable]bool),
 	}
 
 	// Delete variables that should forcefully be reinitialized every time the model is retrained.
@@ -417,7 +428,12 @@ func (r *Trainer) trainStepGraph(spec any, ctx *context.Context, inputs, labels
 	}
 
 	// Optimizer: it will create graph for gradient.
-	r.optimizer.UpdateGraph(ctx, g, loss)
+	r.applyFrozenVariables(ctx)
+	if r.lossScaling {
+		r.lossScalingUpdateGraph(ctx, g, loss)
+	} else {
+		r.optimizer.UpdateGraph(ctx, g, loss)
+	}
 
 	// Execute registered ContextGraphFn hooks for current graph.
 	ExecPerStepUpdateGraphFn(ctx, g)